@@ -53,23 +53,41 @@ import (
 	"github.com/tsingson/tink/golang/core/registry"
 )
 
+// aeadPrimitiveName is the name of the primitive in the Tink catalogue.
+const aeadPrimitiveName = "Aead"
+
 func init() {
-	if err := registry.RegisterKeyManager(newAESCTRHMACAEADKeyManager()); err != nil {
+	if err := registry.TinkCatalogue().Add(aeadPrimitiveName, aesCTRHMACAEADTypeURL, 0, func(*registry.Registry) registry.KeyManager { return newAESCTRHMACAEADKeyManager() }); err != nil {
+		panic(fmt.Sprintf("aead.init() failed: %v", err))
+	}
+
+	if err := registry.TinkCatalogue().Add(aeadPrimitiveName, aesGCMTypeURL, 0, func(*registry.Registry) registry.KeyManager { return newAESGCMKeyManager() }); err != nil {
+		panic(fmt.Sprintf("aead.init() failed: %v", err))
+	}
+
+	if err := registry.TinkCatalogue().Add(aeadPrimitiveName, aesGCMSIVTypeURL, 0, func(*registry.Registry) registry.KeyManager { return newAESGCMSIVKeyManager() }); err != nil {
 		panic(fmt.Sprintf("aead.init() failed: %v", err))
 	}
 
-	if err := registry.RegisterKeyManager(newAESGCMKeyManager()); err != nil {
+	if err := registry.TinkCatalogue().Add(aeadPrimitiveName, aesEAXTypeURL, 0, func(*registry.Registry) registry.KeyManager { return newAESEAXKeyManager() }); err != nil {
 		panic(fmt.Sprintf("aead.init() failed: %v", err))
 	}
 
-	if err := registry.RegisterKeyManager(newChaCha20Poly1305KeyManager()); err != nil {
+	if err := registry.TinkCatalogue().Add(aeadPrimitiveName, chaCha20Poly1305TypeURL, 0, func(*registry.Registry) registry.KeyManager { return newChaCha20Poly1305KeyManager() }); err != nil {
 		panic(fmt.Sprintf("aead.init() failed: %v", err))
 	}
 
-	if err := registry.RegisterKeyManager(newXChaCha20Poly1305KeyManager()); err != nil {
+	if err := registry.TinkCatalogue().Add(aeadPrimitiveName, xChaCha20Poly1305TypeURL, 0, func(*registry.Registry) registry.KeyManager { return newXChaCha20Poly1305KeyManager() }); err != nil {
+		panic(fmt.Sprintf("aead.init() failed: %v", err))
+	}
+	if err := registry.TinkCatalogue().Add(aeadPrimitiveName, kmsEnvelopeAEADTypeURL, 0, func(r *registry.Registry) registry.KeyManager { return newKMSEnvelopeAEADKeyManager(r) }); err != nil {
 		panic(fmt.Sprintf("aead.init() failed: %v", err))
 	}
-	if err := registry.RegisterKeyManager(newKMSEnvelopeAEADKeyManager()); err != nil {
+	if err := registry.TinkCatalogue().Add(aeadPrimitiveName, kmsAEADTypeURL, 0, func(r *registry.Registry) registry.KeyManager { return newKMSAEADKeyManager(r) }); err != nil {
+		panic(fmt.Sprintf("aead.init() failed: %v", err))
+	}
+
+	if err := registry.RegisterPrimitiveWrapper(new(wrapper)); err != nil {
 		panic(fmt.Sprintf("aead.init() failed: %v", err))
 	}
 }
@@ -0,0 +1,75 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package aead
+
+import (
+	"context"
+
+	"github.com/tsingson/tink/golang/tink"
+)
+
+// NewAEADWithContext returns a tink.AEADWithContext for the given AEAD. If the AEAD
+// already implements tink.AEADWithContext it is returned as is. Otherwise its operations
+// are only started if the context is not done yet, as they cannot be interrupted.
+func NewAEADWithContext(a tink.AEAD) tink.AEADWithContext {
+	if ac, ok := a.(tink.AEADWithContext); ok {
+		return ac
+	}
+	return &contextAEAD{a: a}
+}
+
+// NewAEADWithFixedContext returns a tink.AEAD that performs the operations of the given
+// AEADWithContext with the given context. This allows to use it where a tink.AEAD is
+// expected, e.g. as a master key.
+func NewAEADWithFixedContext(ctx context.Context, a tink.AEADWithContext) tink.AEAD {
+	return &fixedContextAEAD{ctx: ctx, a: a}
+}
+
+// contextAEAD adds context checks to an AEAD that does not support contexts.
+type contextAEAD struct {
+	a tink.AEAD
+}
+
+// EncryptWithContext encrypts the plaintext unless ctx is done.
+func (c *contextAEAD) EncryptWithContext(ctx context.Context, pt, ad []byte) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return c.a.Encrypt(pt, ad)
+}
+
+// DecryptWithContext decrypts the ciphertext unless ctx is done.
+func (c *contextAEAD) DecryptWithContext(ctx context.Context, ct, ad []byte) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return c.a.Decrypt(ct, ad)
+}
+
+// fixedContextAEAD is an AEAD using the same context for all operations.
+type fixedContextAEAD struct {
+	ctx context.Context
+	a   tink.AEADWithContext
+}
+
+// Encrypt encrypts the plaintext with the fixed context.
+func (f *fixedContextAEAD) Encrypt(pt, ad []byte) ([]byte, error) {
+	return f.a.EncryptWithContext(f.ctx, pt, ad)
+}
+
+// Decrypt decrypts the ciphertext with the fixed context.
+func (f *fixedContextAEAD) Decrypt(ct, ad []byte) ([]byte, error) {
+	return f.a.DecryptWithContext(f.ctx, ct, ad)
+}
@@ -0,0 +1,153 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package aead_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/tsingson/tink/golang/aead"
+	"github.com/tsingson/tink/golang/subtle/random"
+	"github.com/tsingson/tink/golang/tink"
+
+	subtleAEAD "github.com/tsingson/tink/golang/subtle/aead"
+)
+
+type ctxKey struct{}
+
+// recordingAEAD is a tink.AEADWithContext that records the contexts it is called with.
+type recordingAEAD struct {
+	a        tink.AEAD
+	contexts []context.Context
+}
+
+func (r *recordingAEAD) EncryptWithContext(ctx context.Context, pt, ad []byte) ([]byte, error) {
+	r.contexts = append(r.contexts, ctx)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return r.a.Encrypt(pt, ad)
+}
+
+func (r *recordingAEAD) DecryptWithContext(ctx context.Context, ct, ad []byte) ([]byte, error) {
+	r.contexts = append(r.contexts, ctx)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return r.a.Decrypt(ct, ad)
+}
+
+// remoteAEAD is a recordingAEAD that also implements tink.AEAD, like the KMS AEADs.
+type remoteAEAD struct {
+	recordingAEAD
+}
+
+func (r *remoteAEAD) Encrypt(pt, ad []byte) ([]byte, error) {
+	return r.EncryptWithContext(context.Background(), pt, ad)
+}
+
+func (r *remoteAEAD) Decrypt(ct, ad []byte) ([]byte, error) {
+	return r.DecryptWithContext(context.Background(), ct, ad)
+}
+
+func newSubtleAESGCM(t *testing.T) tink.AEAD {
+	t.Helper()
+	a, err := subtleAEAD.NewAESGCM(random.GetRandomBytes(16))
+	if err != nil {
+		t.Fatalf("subtleAEAD.NewAESGCM() err = %v, want nil", err)
+	}
+	return a
+}
+
+func TestNewAEADWithContext(t *testing.T) {
+	a := aead.NewAEADWithContext(newSubtleAESGCM(t))
+	ctx := context.Background()
+	pt := []byte("plaintext")
+	ct, err := a.EncryptWithContext(ctx, pt, nil)
+	if err != nil {
+		t.Fatalf("a.EncryptWithContext() err = %v, want nil", err)
+	}
+	got, err := a.DecryptWithContext(ctx, ct, nil)
+	if err != nil || !bytes.Equal(got, pt) {
+		t.Fatalf("a.DecryptWithContext() = %q, %v, want %q, nil", got, err, pt)
+	}
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, err := a.EncryptWithContext(cancelled, pt, nil); err != context.Canceled {
+		t.Errorf("a.EncryptWithContext() err = %v, want %v", err, context.Canceled)
+	}
+	if _, err := a.DecryptWithContext(cancelled, ct, nil); err != context.Canceled {
+		t.Errorf("a.DecryptWithContext() err = %v, want %v", err, context.Canceled)
+	}
+
+	remote := &remoteAEAD{recordingAEAD{a: newSubtleAESGCM(t)}}
+	if got := aead.NewAEADWithContext(remote); got != tink.AEADWithContext(remote) {
+		t.Errorf("aead.NewAEADWithContext() did not return the context-aware AEAD as is")
+	}
+}
+
+func TestNewAEADWithFixedContext(t *testing.T) {
+	r := &recordingAEAD{a: newSubtleAESGCM(t)}
+	ctx := context.WithValue(context.Background(), ctxKey{}, "request")
+	a := aead.NewAEADWithFixedContext(ctx, r)
+	pt := []byte("plaintext")
+	ct, err := a.Encrypt(pt, nil)
+	if err != nil {
+		t.Fatalf("a.Encrypt() err = %v, want nil", err)
+	}
+	if _, err := a.Decrypt(ct, nil); err != nil {
+		t.Fatalf("a.Decrypt() err = %v, want nil", err)
+	}
+	if len(r.contexts) != 2 || r.contexts[0] != ctx || r.contexts[1] != ctx {
+		t.Errorf("operations were not called with the fixed context")
+	}
+}
+
+func TestKMSEnvelopeAEADWithContext(t *testing.T) {
+	remote := &remoteAEAD{recordingAEAD{a: newSubtleAESGCM(t)}}
+	a := aead.NewKMSEnvelopeAEAD(*aead.AES128GCMKeyTemplate(), remote)
+	ctx := context.WithValue(context.Background(), ctxKey{}, "request")
+	pt := []byte("plaintext")
+	ct, err := a.EncryptWithContext(ctx, pt, nil)
+	if err != nil {
+		t.Fatalf("a.EncryptWithContext() err = %v, want nil", err)
+	}
+	got, err := a.DecryptWithContext(ctx, ct, nil)
+	if err != nil || !bytes.Equal(got, pt) {
+		t.Fatalf("a.DecryptWithContext() = %q, %v, want %q, nil", got, err, pt)
+	}
+	for i, c := range remote.contexts {
+		if c.Value(ctxKey{}) != "request" {
+			t.Errorf("remote call %d was not made with the given context", i)
+		}
+	}
+
+	// The non-context methods use a background context.
+	got, err = a.Decrypt(ct, nil)
+	if err != nil || !bytes.Equal(got, pt) {
+		t.Fatalf("a.Decrypt() = %q, %v, want %q, nil", got, err, pt)
+	}
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, err := a.EncryptWithContext(cancelled, pt, nil); err == nil {
+		t.Errorf("a.EncryptWithContext() with cancelled context: err = nil, want error")
+	}
+	if _, err := a.DecryptWithContext(cancelled, ct, nil); err == nil {
+		t.Errorf("a.DecryptWithContext() with cancelled context: err = nil, want error")
+	}
+}
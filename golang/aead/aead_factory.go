@@ -17,10 +17,10 @@ package aead
 import (
 	"fmt"
 
-	"github.com/tsingson/tink/golang/core/cryptofmt"
 	"github.com/tsingson/tink/golang/core/primitiveset"
 	"github.com/tsingson/tink/golang/core/registry"
 	"github.com/tsingson/tink/golang/keyset"
+	"github.com/tsingson/tink/golang/monitoring"
 	"github.com/tsingson/tink/golang/tink"
 )
 
@@ -35,6 +35,50 @@ func NewWithKeyManager(h *keyset.Handle, km registry.KeyManager) (tink.AEAD, err
 	if err != nil {
 		return nil, fmt.Errorf("aead_factory: cannot obtain primitive set: %s", err)
 	}
+	return wrap(registry.Default(), ps)
+}
+
+// NewWithRegistry returns an AEAD primitive from the given keyset handle, using the key
+// managers of the given registry.
+func NewWithRegistry(h *keyset.Handle, r *registry.Registry) (tink.AEAD, error) {
+	ps, err := h.PrimitivesWithRegistry(r)
+	if err != nil {
+		return nil, fmt.Errorf("aead_factory: cannot obtain primitive set: %s", err)
+	}
+	return wrap(r, ps)
+}
+
+// wrap combines the primitives in ps into an AEAD using the primitive wrapper
+// registered in r.
+func wrap(r *registry.Registry, ps *primitiveset.PrimitiveSet) (tink.AEAD, error) {
+	p, err := r.Wrap(aeadPrimitiveName, ps)
+	if err != nil {
+		return nil, fmt.Errorf("aead_factory: cannot wrap primitive set: %s", err)
+	}
+	ret, ok := p.(tink.AEAD)
+	if !ok {
+		return nil, fmt.Errorf("aead_factory: not an AEAD primitive")
+	}
+	return ret, nil
+}
+
+// wrapper is the registry.PrimitiveWrapper for the AEAD primitive.
+type wrapper struct{}
+
+// Asserts that wrapper implements the PrimitiveWrapper interface.
+var _ registry.PrimitiveWrapper = (*wrapper)(nil)
+
+// PrimitiveName returns the name of the AEAD primitive.
+func (w *wrapper) PrimitiveName() string {
+	return aeadPrimitiveName
+}
+
+// Wrap returns an AEAD that uses the primitives in the given primitive set, which must
+// all be AEAD primitives.
+func (w *wrapper) Wrap(ps *primitiveset.PrimitiveSet) (interface{}, error) {
+	if !ps.All(func(p interface{}) bool { _, ok := p.(tink.AEAD); return ok }) {
+		return nil, fmt.Errorf("aead_factory: not an AEAD primitive")
+	}
 	return newPrimitiveSet(ps), nil
 }
 
@@ -59,46 +103,26 @@ func (a *primitiveSet) Encrypt(pt, ad []byte) ([]byte, error) {
 	primary := a.ps.Primary
 	var p = (primary.Primitive).(tink.AEAD)
 	ct, err := p.Encrypt(pt, ad)
+	a.ps.Log(primary, aeadPrimitiveName, monitoring.Encrypt, len(pt), err == nil)
 	if err != nil {
 		return nil, err
 	}
-	var ret []byte
-	ret = append(ret, primary.Prefix...)
-	ret = append(ret, ct...)
-	return ret, nil
+	return primary.Prefixed(ct), nil
 }
 
 // Decrypt decrypts the given ciphertext and authenticates it with the given
 // additional authenticated data. It returns the corresponding plaintext if the
 // ciphertext is authenticated.
 func (a *primitiveSet) Decrypt(ct, ad []byte) ([]byte, error) {
-	// try non-raw keys
-	prefixSize := cryptofmt.NonRawPrefixSize
-	if len(ct) > prefixSize {
-		prefix := ct[:prefixSize]
-		ctNoPrefix := ct[prefixSize:]
-		entries, err := a.ps.EntriesForPrefix(string(prefix))
+	for _, c := range a.ps.Candidates(ct) {
+		var p = (c.Entry.Primitive).(tink.AEAD)
+		pt, err := p.Decrypt(c.Output, ad)
 		if err == nil {
-			for i := 0; i < len(entries); i++ {
-				var p = (entries[i].Primitive).(tink.AEAD)
-				pt, err := p.Decrypt(ctNoPrefix, ad)
-				if err == nil {
-					return pt, nil
-				}
-			}
-		}
-	}
-	// try raw keys
-	entries, err := a.ps.RawEntries()
-	if err == nil {
-		for i := 0; i < len(entries); i++ {
-			var p = (entries[i].Primitive).(tink.AEAD)
-			pt, err := p.Decrypt(ct, ad)
-			if err == nil {
-				return pt, nil
-			}
+			a.ps.Log(c.Entry, aeadPrimitiveName, monitoring.Decrypt, len(pt), true)
+			return pt, nil
 		}
 	}
 	// nothing worked
+	a.ps.LogFailure(ct, aeadPrimitiveName, monitoring.Decrypt, 0)
 	return nil, fmt.Errorf("aead_factory: decryption failed")
 }
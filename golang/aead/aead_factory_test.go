@@ -22,6 +22,7 @@ import (
 
 	"github.com/tsingson/tink/golang/aead"
 	"github.com/tsingson/tink/golang/core/cryptofmt"
+	"github.com/tsingson/tink/golang/core/registry"
 	"github.com/tsingson/tink/golang/subtle/random"
 	"github.com/tsingson/tink/golang/testkeyset"
 	"github.com/tsingson/tink/golang/testutil"
@@ -94,6 +95,45 @@ func TestFactoryRawKeyAsPrimary(t *testing.T) {
 	}
 }
 
+func TestFactoryWithRegistry(t *testing.T) {
+	keysetHandle, err := testkeyset.NewHandle(testutil.NewTestAESGCMKeyset(tinkpb.OutputPrefixType_TINK))
+	if err != nil {
+		t.Fatalf("testkeyset.NewHandle() err = %v, want nil", err)
+	}
+	r := registry.New()
+	if err := r.RegisterKeyManager(new(testutil.DummyAEADKeyManager)); err != nil {
+		t.Fatalf("r.RegisterKeyManager() err = %v, want nil", err)
+	}
+	a, err := aead.NewWithRegistry(keysetHandle, r)
+	if err != nil {
+		t.Fatalf("aead.NewWithRegistry() err = %v, want nil", err)
+	}
+	if _, err := a.Encrypt([]byte("plaintext"), nil); err == nil || !strings.Contains(err.Error(), "dummy aead") {
+		t.Errorf("a.Encrypt() err = %v, want dummy aead error", err)
+	}
+	// The default registry must still produce a working AES-GCM primitive.
+	a, err = aead.New(keysetHandle)
+	if err != nil {
+		t.Fatalf("aead.New() err = %v, want nil", err)
+	}
+	if _, err := a.Encrypt([]byte("plaintext"), nil); err != nil {
+		t.Errorf("a.Encrypt() err = %v, want nil", err)
+	}
+	if _, err := aead.NewWithRegistry(keysetHandle, nil); err == nil {
+		t.Errorf("aead.NewWithRegistry() with nil registry err = nil, want error")
+	}
+}
+
+func TestFactoryWithWrongPrimitive(t *testing.T) {
+	keysetHandle, err := testkeyset.NewHandle(testutil.NewTestHMACKeyset(16, tinkpb.OutputPrefixType_TINK))
+	if err != nil {
+		t.Fatalf("testkeyset.NewHandle() err = %v, want nil", err)
+	}
+	if _, err := aead.New(keysetHandle); err == nil {
+		t.Errorf("aead.New() err = nil, want error for a MAC keyset")
+	}
+}
+
 func validateAEADFactoryCipher(encryptCipher tink.AEAD,
 	decryptCipher tink.AEAD,
 	expectedPrefix string) error {
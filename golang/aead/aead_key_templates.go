@@ -19,9 +19,12 @@ import (
 
 	ctrpb "github.com/tsingson/tink/proto/aes_ctr_go_proto"
 	ctrhmacpb "github.com/tsingson/tink/proto/aes_ctr_hmac_aead_go_proto"
+	eaxpb "github.com/tsingson/tink/proto/aes_eax_go_proto"
 	gcmpb "github.com/tsingson/tink/proto/aes_gcm_go_proto"
+	gcmsivpb "github.com/tsingson/tink/proto/aes_gcm_siv_go_proto"
 	commonpb "github.com/tsingson/tink/proto/common_go_proto"
 	hmacpb "github.com/tsingson/tink/proto/hmac_go_proto"
+	kmsaeadpb "github.com/tsingson/tink/proto/kms_aead_go_proto"
 	kmsenvpb "github.com/tsingson/tink/proto/kms_envelope_go_proto"
 	tinkpb "github.com/tsingson/tink/proto/tink_go_proto"
 )
@@ -41,6 +44,32 @@ func AES256GCMKeyTemplate() *tinkpb.KeyTemplate {
 	return createAESGCMKeyTemplate(32)
 }
 
+// AES128GCMSIVKeyTemplate is a KeyTemplate that generates an AES-GCM-SIV key with the following parameters:
+//   - Key size: 16 bytes
+func AES128GCMSIVKeyTemplate() *tinkpb.KeyTemplate {
+	return createAESGCMSIVKeyTemplate(16)
+}
+
+// AES256GCMSIVKeyTemplate is a KeyTemplate that generates an AES-GCM-SIV key with the following parameters:
+//   - Key size: 32 bytes
+func AES256GCMSIVKeyTemplate() *tinkpb.KeyTemplate {
+	return createAESGCMSIVKeyTemplate(32)
+}
+
+// AES128EAXKeyTemplate is a KeyTemplate that generates an AES-EAX key with the following parameters:
+//   - Key size: 16 bytes
+//   - IV size: 16 bytes
+func AES128EAXKeyTemplate() *tinkpb.KeyTemplate {
+	return createAESEAXKeyTemplate(16, 16)
+}
+
+// AES256EAXKeyTemplate is a KeyTemplate that generates an AES-EAX key with the following parameters:
+//   - Key size: 32 bytes
+//   - IV size: 16 bytes
+func AES256EAXKeyTemplate() *tinkpb.KeyTemplate {
+	return createAESEAXKeyTemplate(32, 16)
+}
+
 // AES128CTRHMACSHA256KeyTemplate is a KeyTemplate that generates an AES-CTR-HMAC-AEAD key with the following parameters:
 //  - AES key size: 16 bytes
 //  - AES CTR IV size: 16 bytes
@@ -93,6 +122,21 @@ func KMSEnvelopeAEADKeyTemplate(uri string, dekT *tinkpb.KeyTemplate) *tinkpb.Ke
 	}
 }
 
+// KMSAEADKeyTemplate is a KeyTemplate that generates a KMSAEAD key referencing the given key in remote KMS.
+// Keys created from this template hold no key material: encryption and decryption are delegated
+// to the KMS client registered for uri.
+func KMSAEADKeyTemplate(uri string) *tinkpb.KeyTemplate {
+	f := &kmsaeadpb.KmsAeadKeyFormat{
+		KeyUri: uri,
+	}
+	serializedFormat, _ := proto.Marshal(f)
+	return &tinkpb.KeyTemplate{
+		Value:            serializedFormat,
+		TypeUrl:          kmsAEADTypeURL,
+		OutputPrefixType: tinkpb.OutputPrefixType_TINK,
+	}
+}
+
 // createAESGCMKeyTemplate creates a new AES-GCM key template with the given key
 // size in bytes.
 func createAESGCMKeyTemplate(keySize uint32) *tinkpb.KeyTemplate {
@@ -106,6 +150,35 @@ func createAESGCMKeyTemplate(keySize uint32) *tinkpb.KeyTemplate {
 	}
 }
 
+// createAESGCMSIVKeyTemplate creates a new AES-GCM-SIV key template with the given key
+// size in bytes.
+func createAESGCMSIVKeyTemplate(keySize uint32) *tinkpb.KeyTemplate {
+	format := &gcmsivpb.AesGcmSivKeyFormat{
+		KeySize: keySize,
+	}
+	serializedFormat, _ := proto.Marshal(format)
+	return &tinkpb.KeyTemplate{
+		TypeUrl:          aesGCMSIVTypeURL,
+		Value:            serializedFormat,
+		OutputPrefixType: tinkpb.OutputPrefixType_TINK,
+	}
+}
+
+// createAESEAXKeyTemplate creates a new AES-EAX key template with the given key
+// and IV sizes in bytes.
+func createAESEAXKeyTemplate(keySize, ivSize uint32) *tinkpb.KeyTemplate {
+	format := &eaxpb.AesEaxKeyFormat{
+		KeySize: keySize,
+		Params:  &eaxpb.AesEaxParams{IvSize: ivSize},
+	}
+	serializedFormat, _ := proto.Marshal(format)
+	return &tinkpb.KeyTemplate{
+		TypeUrl:          aesEAXTypeURL,
+		Value:            serializedFormat,
+		OutputPrefixType: tinkpb.OutputPrefixType_TINK,
+	}
+}
+
 func createAESCTRHMACAEADKeyTemplate(aesKeySize, ivSize, hmacKeySize, tagSize uint32, hash commonpb.HashType) *tinkpb.KeyTemplate {
 	format := &ctrhmacpb.AesCtrHmacAeadKeyFormat{
 		AesCtrKeyFormat: &ctrpb.AesCtrKeyFormat{
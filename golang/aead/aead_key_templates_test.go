@@ -27,8 +27,11 @@ import (
 	"github.com/tsingson/tink/golang/testutil"
 	"github.com/tsingson/tink/golang/tink"
 	ctrhmacpb "github.com/tsingson/tink/proto/aes_ctr_hmac_aead_go_proto"
+	eaxpb "github.com/tsingson/tink/proto/aes_eax_go_proto"
 	gcmpb "github.com/tsingson/tink/proto/aes_gcm_go_proto"
+	gcmsivpb "github.com/tsingson/tink/proto/aes_gcm_siv_go_proto"
 	commonpb "github.com/tsingson/tink/proto/common_go_proto"
+	kmsaeadpb "github.com/tsingson/tink/proto/kms_aead_go_proto"
 	tinkpb "github.com/tsingson/tink/proto/tink_go_proto"
 )
 
@@ -115,6 +118,81 @@ func checkAESCTRHMACAEADKeyTemplate(template *tinkpb.KeyTemplate, keySize, ivSiz
 	return nil
 }
 
+func TestAESGCMSIVKeyTemplates(t *testing.T) {
+	var testCases = []struct {
+		name     string
+		template *tinkpb.KeyTemplate
+		keySize  uint32
+	}{
+		{"AES128GCMSIV", aead.AES128GCMSIVKeyTemplate(), 16},
+		{"AES256GCMSIV", aead.AES256GCMSIVKeyTemplate(), 32},
+	}
+	for _, tc := range testCases {
+		if tc.template.TypeUrl != testutil.AESGCMSIVTypeURL {
+			t.Errorf("%s: incorrect type url", tc.name)
+		}
+		keyFormat := new(gcmsivpb.AesGcmSivKeyFormat)
+		if err := proto.Unmarshal(tc.template.Value, keyFormat); err != nil {
+			t.Errorf("%s: cannot deserialize key format: %s", tc.name, err)
+			continue
+		}
+		if keyFormat.KeySize != tc.keySize {
+			t.Errorf("%s: incorrect key size, expect %d, got %d", tc.name, tc.keySize, keyFormat.KeySize)
+		}
+		if err := testEncryptDecrypt(tc.template, testutil.AESGCMSIVTypeURL); err != nil {
+			t.Errorf("%s: %s", tc.name, err)
+		}
+	}
+}
+
+func TestAESEAXKeyTemplates(t *testing.T) {
+	var testCases = []struct {
+		name     string
+		template *tinkpb.KeyTemplate
+		keySize  uint32
+	}{
+		{"AES128EAX", aead.AES128EAXKeyTemplate(), 16},
+		{"AES256EAX", aead.AES256EAXKeyTemplate(), 32},
+	}
+	for _, tc := range testCases {
+		if tc.template.TypeUrl != testutil.AESEAXTypeURL {
+			t.Errorf("%s: incorrect type url", tc.name)
+		}
+		keyFormat := new(eaxpb.AesEaxKeyFormat)
+		if err := proto.Unmarshal(tc.template.Value, keyFormat); err != nil {
+			t.Errorf("%s: cannot deserialize key format: %s", tc.name, err)
+			continue
+		}
+		if keyFormat.KeySize != tc.keySize {
+			t.Errorf("%s: incorrect key size, expect %d, got %d", tc.name, tc.keySize, keyFormat.KeySize)
+		}
+		if keyFormat.Params.IvSize != 16 {
+			t.Errorf("%s: incorrect IV size, expect 16, got %d", tc.name, keyFormat.Params.IvSize)
+		}
+		if err := testEncryptDecrypt(tc.template, testutil.AESEAXTypeURL); err != nil {
+			t.Errorf("%s: %s", tc.name, err)
+		}
+	}
+}
+
+func TestKMSAEADKeyTemplate(t *testing.T) {
+	uri := "fake-kms-aead://template"
+	template := aead.KMSAEADKeyTemplate(uri)
+	if template.TypeUrl != testutil.KMSAEADTypeURL {
+		t.Errorf("incorrect type url: %v, expected %v", template.TypeUrl, testutil.KMSAEADTypeURL)
+	}
+	keyFormat := new(kmsaeadpb.KmsAeadKeyFormat)
+	if err := proto.Unmarshal(template.Value, keyFormat); err != nil {
+		t.Fatalf("cannot deserialize key format: %s", err)
+	}
+	if keyFormat.KeyUri != uri {
+		t.Errorf("incorrect key URI, expect %q, got %q", uri, keyFormat.KeyUri)
+	}
+	if err := testEncryptDecrypt(template, testutil.KMSAEADTypeURL); err != nil {
+		t.Errorf("%v", err)
+	}
+}
+
 func TestChaCha20Poly1305KeyTemplate(t *testing.T) {
 	template := aead.ChaCha20Poly1305KeyTemplate()
 	if template.TypeUrl != testutil.ChaCha20Poly1305TypeURL {
@@ -28,6 +28,24 @@ func TestAeadInit(t *testing.T) {
 		t.Errorf("unexpected error: %s", err)
 	}
 
+	// Check for AES-GCM-SIV key manager.
+	_, err = registry.GetKeyManager(testutil.AESGCMSIVTypeURL)
+	if err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+
+	// Check for AES-EAX key manager.
+	_, err = registry.GetKeyManager(testutil.AESEAXTypeURL)
+	if err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+
+	// Check for KMS AEAD key manager.
+	_, err = registry.GetKeyManager(testutil.KMSAEADTypeURL)
+	if err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+
 	// Check for ChaCha20Poly1305 key manager.
 	_, err = registry.GetKeyManager(testutil.ChaCha20Poly1305TypeURL)
 	if err != nil {
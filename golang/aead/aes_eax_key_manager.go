@@ -0,0 +1,148 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package aead
+
+import (
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+
+	"github.com/tsingson/tink/golang/core/registry"
+	"github.com/tsingson/tink/golang/keyset"
+	"github.com/tsingson/tink/golang/subtle/aead"
+	"github.com/tsingson/tink/golang/subtle/random"
+	eaxpb "github.com/tsingson/tink/proto/aes_eax_go_proto"
+	tinkpb "github.com/tsingson/tink/proto/tink_go_proto"
+)
+
+const (
+	aesEAXKeyVersion = 0
+	aesEAXTypeURL    = "type.googleapis.com/google.crypto.tink.AesEaxKey"
+)
+
+// common errors
+var errInvalidAESEAXKey = fmt.Errorf("aes_eax_key_manager: invalid key")
+var errInvalidAESEAXKeyFormat = fmt.Errorf("aes_eax_key_manager: invalid key format")
+
+// aesEAXKeyManager is an implementation of KeyManager interface.
+// It generates new AESEAXKey keys and produces new instances of AESEAX subtle.
+type aesEAXKeyManager struct{}
+
+// Assert that aesEAXKeyManager implements the KeyManager interface.
+var _ registry.KeyManager = (*aesEAXKeyManager)(nil)
+
+// newAESEAXKeyManager creates a new aesEAXKeyManager.
+func newAESEAXKeyManager() *aesEAXKeyManager {
+	return new(aesEAXKeyManager)
+}
+
+// Primitive creates an AESEAX subtle for the given serialized AESEAXKey proto.
+func (km *aesEAXKeyManager) Primitive(serializedKey []byte) (interface{}, error) {
+	if len(serializedKey) == 0 {
+		return nil, errInvalidAESEAXKey
+	}
+	key := new(eaxpb.AesEaxKey)
+	if err := proto.Unmarshal(serializedKey, key); err != nil {
+		return nil, errInvalidAESEAXKey
+	}
+	if err := km.validateKey(key); err != nil {
+		return nil, err
+	}
+	ret, err := aead.NewAESEAX(key.KeyValue, int(key.Params.IvSize))
+	if err != nil {
+		return nil, fmt.Errorf("aes_eax_key_manager: cannot create new primitive: %s", err)
+	}
+	return ret, nil
+}
+
+// NewKey creates a new key according to specification the given serialized AESEAXKeyFormat.
+func (km *aesEAXKeyManager) NewKey(serializedKeyFormat []byte) (proto.Message, error) {
+	if len(serializedKeyFormat) == 0 {
+		return nil, errInvalidAESEAXKeyFormat
+	}
+	keyFormat := new(eaxpb.AesEaxKeyFormat)
+	if err := proto.Unmarshal(serializedKeyFormat, keyFormat); err != nil {
+		return nil, errInvalidAESEAXKeyFormat
+	}
+	if err := km.validateKeyFormat(keyFormat); err != nil {
+		return nil, fmt.Errorf("aes_eax_key_manager: invalid key format: %s", err)
+	}
+	keyValue := random.GetRandomBytes(keyFormat.KeySize)
+	return &eaxpb.AesEaxKey{
+		Version:  aesEAXKeyVersion,
+		Params:   keyFormat.Params,
+		KeyValue: keyValue,
+	}, nil
+}
+
+// NewKeyData creates a new KeyData according to specification in the given serialized
+// AESEAXKeyFormat.
+// It should be used solely by the key management API.
+func (km *aesEAXKeyManager) NewKeyData(serializedKeyFormat []byte) (*tinkpb.KeyData, error) {
+	key, err := km.NewKey(serializedKeyFormat)
+	if err != nil {
+		return nil, err
+	}
+	serializedKey, err := proto.Marshal(key)
+	if err != nil {
+		return nil, err
+	}
+	return &tinkpb.KeyData{
+		TypeUrl:         aesEAXTypeURL,
+		Value:           serializedKey,
+		KeyMaterialType: tinkpb.KeyData_SYMMETRIC,
+	}, nil
+}
+
+// DoesSupport indicates if this key manager supports the given key type.
+func (km *aesEAXKeyManager) DoesSupport(typeURL string) bool {
+	return typeURL == aesEAXTypeURL
+}
+
+// TypeURL returns the key type of keys managed by this key manager.
+func (km *aesEAXKeyManager) TypeURL() string {
+	return aesEAXTypeURL
+}
+
+// validateKey validates the given AESEAXKey.
+func (km *aesEAXKeyManager) validateKey(key *eaxpb.AesEaxKey) error {
+	err := keyset.ValidateKeyVersion(key.Version, aesEAXKeyVersion)
+	if err != nil {
+		return fmt.Errorf("aes_eax_key_manager: %s", err)
+	}
+	if err := km.validateParams(uint32(len(key.KeyValue)), key.Params); err != nil {
+		return fmt.Errorf("aes_eax_key_manager: %s", err)
+	}
+	return nil
+}
+
+// validateKeyFormat validates the given AESEAXKeyFormat.
+func (km *aesEAXKeyManager) validateKeyFormat(format *eaxpb.AesEaxKeyFormat) error {
+	if err := km.validateParams(format.KeySize, format.Params); err != nil {
+		return fmt.Errorf("aes_eax_key_manager: %s", err)
+	}
+	return nil
+}
+
+// validateParams validates the key size and the AESEAXParams.
+func (km *aesEAXKeyManager) validateParams(keySize uint32, params *eaxpb.AesEaxParams) error {
+	if err := aead.ValidateAESKeySize(keySize); err != nil {
+		return err
+	}
+	if params == nil {
+		return fmt.Errorf("missing params")
+	}
+	return aead.ValidateAESEAXIVSize(params.IvSize)
+}
@@ -0,0 +1,226 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package aead_test
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+
+	"github.com/tsingson/tink/golang/core/registry"
+	subteAEAD "github.com/tsingson/tink/golang/subtle/aead"
+	"github.com/tsingson/tink/golang/subtle/random"
+	"github.com/tsingson/tink/golang/testutil"
+	eaxpb "github.com/tsingson/tink/proto/aes_eax_go_proto"
+	tinkpb "github.com/tsingson/tink/proto/tink_go_proto"
+)
+
+var eaxIVSizes = []uint32{12, 16}
+
+func TestAESEAXGetPrimitiveBasic(t *testing.T) {
+	keyManager, err := registry.GetKeyManager(testutil.AESEAXTypeURL)
+	if err != nil {
+		t.Errorf("cannot obtain AES-EAX key manager: %s", err)
+	}
+	for _, keySize := range keySizes {
+		for _, ivSize := range eaxIVSizes {
+			key := newAESEAXKey(testutil.AESEAXKeyVersion, keySize, ivSize)
+			serializedKey, _ := proto.Marshal(key)
+			p, err := keyManager.Primitive(serializedKey)
+			if err != nil {
+				t.Errorf("unexpected error: %s", err)
+			}
+			if err := validateAESEAXPrimitive(p, key); err != nil {
+				t.Errorf("%s", err)
+			}
+		}
+	}
+}
+
+func TestAESEAXGetPrimitiveWithInvalidInput(t *testing.T) {
+	keyManager, err := registry.GetKeyManager(testutil.AESEAXTypeURL)
+	if err != nil {
+		t.Errorf("cannot obtain AES-EAX key manager: %s", err)
+	}
+	testKeys := []proto.Message{
+		// not a AESEAXKey
+		newAESEAXKeyFormat(32, 16),
+		// bad key size
+		newAESEAXKey(testutil.AESEAXKeyVersion, 17, 16),
+		newAESEAXKey(testutil.AESEAXKeyVersion, 24, 16),
+		newAESEAXKey(testutil.AESEAXKeyVersion, 33, 16),
+		// bad IV size
+		newAESEAXKey(testutil.AESEAXKeyVersion, 16, 8),
+		newAESEAXKey(testutil.AESEAXKeyVersion, 16, 32),
+		// missing params
+		&eaxpb.AesEaxKey{Version: testutil.AESEAXKeyVersion, KeyValue: random.GetRandomBytes(16)},
+		// bad version
+		newAESEAXKey(testutil.AESEAXKeyVersion+1, 16, 16),
+	}
+	for i := 0; i < len(testKeys); i++ {
+		serializedKey, _ := proto.Marshal(testKeys[i])
+		if _, err := keyManager.Primitive(serializedKey); err == nil {
+			t.Errorf("expect an error in test case %d", i)
+		}
+	}
+	if _, err := keyManager.Primitive(nil); err == nil {
+		t.Errorf("expect an error when input is nil")
+	}
+	if _, err := keyManager.Primitive([]byte{}); err == nil {
+		t.Errorf("expect an error when input is empty")
+	}
+}
+
+func TestAESEAXNewKeyMultipleTimes(t *testing.T) {
+	keyManager, err := registry.GetKeyManager(testutil.AESEAXTypeURL)
+	if err != nil {
+		t.Errorf("cannot obtain AES-EAX key manager: %s", err)
+	}
+	serializedFormat, _ := proto.Marshal(newAESEAXKeyFormat(32, 16))
+	keys := make(map[string]bool)
+	nTest := 26
+	for i := 0; i < nTest; i++ {
+		key, _ := keyManager.NewKey(serializedFormat)
+		serializedKey, _ := proto.Marshal(key)
+		keys[string(serializedKey)] = true
+
+		keyData, _ := keyManager.NewKeyData(serializedFormat)
+		keys[string(keyData.Value)] = true
+	}
+	if len(keys) != nTest*2 {
+		t.Errorf("key is repeated")
+	}
+}
+
+func TestAESEAXNewKeyDataBasic(t *testing.T) {
+	keyManager, err := registry.GetKeyManager(testutil.AESEAXTypeURL)
+	if err != nil {
+		t.Errorf("cannot obtain AES-EAX key manager: %s", err)
+	}
+	for _, keySize := range keySizes {
+		for _, ivSize := range eaxIVSizes {
+			serializedFormat, _ := proto.Marshal(newAESEAXKeyFormat(keySize, ivSize))
+			keyData, err := keyManager.NewKeyData(serializedFormat)
+			if err != nil {
+				t.Errorf("unexpected error: %s", err)
+			}
+			if keyData.TypeUrl != testutil.AESEAXTypeURL {
+				t.Errorf("incorrect type url")
+			}
+			if keyData.KeyMaterialType != tinkpb.KeyData_SYMMETRIC {
+				t.Errorf("incorrect key material type")
+			}
+			key := new(eaxpb.AesEaxKey)
+			if err := proto.Unmarshal(keyData.Value, key); err != nil {
+				t.Errorf("incorrect key value")
+			}
+			if uint32(len(key.KeyValue)) != keySize || key.Params.IvSize != ivSize {
+				t.Errorf("key does not match the format")
+			}
+			p, err := keyManager.Primitive(keyData.Value)
+			if err != nil {
+				t.Errorf("unexpected error: %s", err)
+			}
+			if err := validateAESEAXPrimitive(p, key); err != nil {
+				t.Errorf("%s", err)
+			}
+		}
+	}
+}
+
+func TestAESEAXNewKeyWithInvalidInput(t *testing.T) {
+	keyManager, err := registry.GetKeyManager(testutil.AESEAXTypeURL)
+	if err != nil {
+		t.Errorf("cannot obtain AES-EAX key manager: %s", err)
+	}
+	badFormats := []proto.Message{
+		// not AESEAXKeyFormat
+		newAESEAXKey(testutil.AESEAXKeyVersion, 16, 16),
+		// invalid key size
+		newAESEAXKeyFormat(15, 16),
+		newAESEAXKeyFormat(24, 16),
+		// invalid IV size
+		newAESEAXKeyFormat(16, 0),
+		newAESEAXKeyFormat(16, 24),
+		// missing params
+		&eaxpb.AesEaxKeyFormat{KeySize: 16},
+	}
+	for i := 0; i < len(badFormats); i++ {
+		serializedFormat, _ := proto.Marshal(badFormats[i])
+		if _, err := keyManager.NewKey(serializedFormat); err == nil {
+			t.Errorf("expect an error in test case %d", i)
+		}
+		if _, err := keyManager.NewKeyData(serializedFormat); err == nil {
+			t.Errorf("expect an error in test case %d", i)
+		}
+	}
+	if _, err := keyManager.NewKey(nil); err == nil {
+		t.Errorf("expect an error when input is nil")
+	}
+	if _, err := keyManager.NewKey([]byte{}); err == nil {
+		t.Errorf("expect an error when input is empty")
+	}
+}
+
+func TestAESEAXDoesSupport(t *testing.T) {
+	keyManager, err := registry.GetKeyManager(testutil.AESEAXTypeURL)
+	if err != nil {
+		t.Errorf("cannot obtain AES-EAX key manager: %s", err)
+	}
+	if !keyManager.DoesSupport(testutil.AESEAXTypeURL) {
+		t.Errorf("AESEAXKeyManager must support %s", testutil.AESEAXTypeURL)
+	}
+	if keyManager.DoesSupport("some bad type") {
+		t.Errorf("AESEAXKeyManager must support only %s", testutil.AESEAXTypeURL)
+	}
+	if keyManager.TypeURL() != testutil.AESEAXTypeURL {
+		t.Errorf("incorrect key type")
+	}
+}
+
+func newAESEAXKey(keyVersion, keySize, ivSize uint32) *eaxpb.AesEaxKey {
+	return &eaxpb.AesEaxKey{
+		Version:  keyVersion,
+		Params:   &eaxpb.AesEaxParams{IvSize: ivSize},
+		KeyValue: random.GetRandomBytes(keySize),
+	}
+}
+
+func newAESEAXKeyFormat(keySize, ivSize uint32) *eaxpb.AesEaxKeyFormat {
+	return &eaxpb.AesEaxKeyFormat{
+		Params:  &eaxpb.AesEaxParams{IvSize: ivSize},
+		KeySize: keySize,
+	}
+}
+
+func validateAESEAXPrimitive(p interface{}, key *eaxpb.AesEaxKey) error {
+	cipher := p.(*subteAEAD.AESEAX)
+	if !bytes.Equal(cipher.Key, key.KeyValue) || uint32(cipher.IVSize) != key.Params.IvSize {
+		return fmt.Errorf("key and primitive don't match")
+	}
+	pt := random.GetRandomBytes(32)
+	aad := random.GetRandomBytes(32)
+	ct, err := cipher.Encrypt(pt, aad)
+	if err != nil {
+		return fmt.Errorf("encryption failed")
+	}
+	decrypted, err := cipher.Decrypt(ct, aad)
+	if err != nil || !bytes.Equal(decrypted, pt) {
+		return fmt.Errorf("decryption failed")
+	}
+	return nil
+}
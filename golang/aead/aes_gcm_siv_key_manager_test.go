@@ -0,0 +1,212 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package aead_test
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+
+	"github.com/tsingson/tink/golang/core/registry"
+	subteAEAD "github.com/tsingson/tink/golang/subtle/aead"
+	"github.com/tsingson/tink/golang/subtle/random"
+	"github.com/tsingson/tink/golang/testutil"
+	gcmsivpb "github.com/tsingson/tink/proto/aes_gcm_siv_go_proto"
+	tinkpb "github.com/tsingson/tink/proto/tink_go_proto"
+)
+
+func TestAESGCMSIVGetPrimitiveBasic(t *testing.T) {
+	keyManager, err := registry.GetKeyManager(testutil.AESGCMSIVTypeURL)
+	if err != nil {
+		t.Errorf("cannot obtain AES-GCM-SIV key manager: %s", err)
+	}
+	for _, keySize := range keySizes {
+		key := newAESGCMSIVKey(testutil.AESGCMSIVKeyVersion, keySize)
+		serializedKey, _ := proto.Marshal(key)
+		p, err := keyManager.Primitive(serializedKey)
+		if err != nil {
+			t.Errorf("unexpected error: %s", err)
+		}
+		if err := validateAESGCMSIVPrimitive(p, key); err != nil {
+			t.Errorf("%s", err)
+		}
+	}
+}
+
+func TestAESGCMSIVGetPrimitiveWithInvalidInput(t *testing.T) {
+	keyManager, err := registry.GetKeyManager(testutil.AESGCMSIVTypeURL)
+	if err != nil {
+		t.Errorf("cannot obtain AES-GCM-SIV key manager: %s", err)
+	}
+	testKeys := genInvalidAESGCMSIVKeys()
+	for i := 0; i < len(testKeys); i++ {
+		serializedKey, _ := proto.Marshal(testKeys[i])
+		if _, err := keyManager.Primitive(serializedKey); err == nil {
+			t.Errorf("expect an error in test case %d", i)
+		}
+	}
+	if _, err := keyManager.Primitive(nil); err == nil {
+		t.Errorf("expect an error when input is nil")
+	}
+	if _, err := keyManager.Primitive([]byte{}); err == nil {
+		t.Errorf("expect an error when input is empty")
+	}
+}
+
+func TestAESGCMSIVNewKeyMultipleTimes(t *testing.T) {
+	keyManager, err := registry.GetKeyManager(testutil.AESGCMSIVTypeURL)
+	if err != nil {
+		t.Errorf("cannot obtain AES-GCM-SIV key manager: %s", err)
+	}
+	serializedFormat, _ := proto.Marshal(&gcmsivpb.AesGcmSivKeyFormat{KeySize: 32})
+	keys := make(map[string]bool)
+	nTest := 26
+	for i := 0; i < nTest; i++ {
+		key, _ := keyManager.NewKey(serializedFormat)
+		serializedKey, _ := proto.Marshal(key)
+		keys[string(serializedKey)] = true
+
+		keyData, _ := keyManager.NewKeyData(serializedFormat)
+		keys[string(keyData.Value)] = true
+	}
+	if len(keys) != nTest*2 {
+		t.Errorf("key is repeated")
+	}
+}
+
+func TestAESGCMSIVNewKeyDataBasic(t *testing.T) {
+	keyManager, err := registry.GetKeyManager(testutil.AESGCMSIVTypeURL)
+	if err != nil {
+		t.Errorf("cannot obtain AES-GCM-SIV key manager: %s", err)
+	}
+	for _, keySize := range keySizes {
+		format := &gcmsivpb.AesGcmSivKeyFormat{KeySize: keySize}
+		serializedFormat, _ := proto.Marshal(format)
+		keyData, err := keyManager.NewKeyData(serializedFormat)
+		if err != nil {
+			t.Errorf("unexpected error: %s", err)
+		}
+		if keyData.TypeUrl != testutil.AESGCMSIVTypeURL {
+			t.Errorf("incorrect type url")
+		}
+		if keyData.KeyMaterialType != tinkpb.KeyData_SYMMETRIC {
+			t.Errorf("incorrect key material type")
+		}
+		key := new(gcmsivpb.AesGcmSivKey)
+		if err := proto.Unmarshal(keyData.Value, key); err != nil {
+			t.Errorf("incorrect key value")
+		}
+		if uint32(len(key.KeyValue)) != keySize {
+			t.Errorf("incorrect key size")
+		}
+		if err := validateAESGCMSIVPrimitive(mustNewAESGCMSIV(t, key.KeyValue), key); err != nil {
+			t.Errorf("%s", err)
+		}
+	}
+}
+
+func TestAESGCMSIVNewKeyWithInvalidInput(t *testing.T) {
+	keyManager, err := registry.GetKeyManager(testutil.AESGCMSIVTypeURL)
+	if err != nil {
+		t.Errorf("cannot obtain AES-GCM-SIV key manager: %s", err)
+	}
+	badFormats := []proto.Message{
+		// not AESGCMSIVKeyFormat
+		newAESGCMSIVKey(testutil.AESGCMSIVKeyVersion, 16),
+		// invalid key size
+		&gcmsivpb.AesGcmSivKeyFormat{KeySize: 15},
+		&gcmsivpb.AesGcmSivKeyFormat{KeySize: 24},
+		&gcmsivpb.AesGcmSivKeyFormat{KeySize: 31},
+	}
+	for i := 0; i < len(badFormats); i++ {
+		serializedFormat, _ := proto.Marshal(badFormats[i])
+		if _, err := keyManager.NewKey(serializedFormat); err == nil {
+			t.Errorf("expect an error in test case %d", i)
+		}
+		if _, err := keyManager.NewKeyData(serializedFormat); err == nil {
+			t.Errorf("expect an error in test case %d", i)
+		}
+	}
+	if _, err := keyManager.NewKey(nil); err == nil {
+		t.Errorf("expect an error when input is nil")
+	}
+	if _, err := keyManager.NewKey([]byte{}); err == nil {
+		t.Errorf("expect an error when input is empty")
+	}
+}
+
+func TestAESGCMSIVDoesSupport(t *testing.T) {
+	keyManager, err := registry.GetKeyManager(testutil.AESGCMSIVTypeURL)
+	if err != nil {
+		t.Errorf("cannot obtain AES-GCM-SIV key manager: %s", err)
+	}
+	if !keyManager.DoesSupport(testutil.AESGCMSIVTypeURL) {
+		t.Errorf("AESGCMSIVKeyManager must support %s", testutil.AESGCMSIVTypeURL)
+	}
+	if keyManager.DoesSupport("some bad type") {
+		t.Errorf("AESGCMSIVKeyManager must support only %s", testutil.AESGCMSIVTypeURL)
+	}
+	if keyManager.TypeURL() != testutil.AESGCMSIVTypeURL {
+		t.Errorf("incorrect key type")
+	}
+}
+
+func newAESGCMSIVKey(keyVersion, keySize uint32) *gcmsivpb.AesGcmSivKey {
+	return &gcmsivpb.AesGcmSivKey{
+		Version:  keyVersion,
+		KeyValue: random.GetRandomBytes(keySize),
+	}
+}
+
+func genInvalidAESGCMSIVKeys() []proto.Message {
+	return []proto.Message{
+		// not a AESGCMSIVKey
+		&gcmsivpb.AesGcmSivKeyFormat{KeySize: 32},
+		// bad key size
+		newAESGCMSIVKey(testutil.AESGCMSIVKeyVersion, 17),
+		newAESGCMSIVKey(testutil.AESGCMSIVKeyVersion, 24),
+		newAESGCMSIVKey(testutil.AESGCMSIVKeyVersion, 33),
+		// bad version
+		newAESGCMSIVKey(testutil.AESGCMSIVKeyVersion+1, 16),
+	}
+}
+
+func mustNewAESGCMSIV(t *testing.T, key []byte) *subteAEAD.AESGCMSIV {
+	p, err := subteAEAD.NewAESGCMSIV(key)
+	if err != nil {
+		t.Fatalf("subteAEAD.NewAESGCMSIV() failed: %s", err)
+	}
+	return p
+}
+
+func validateAESGCMSIVPrimitive(p interface{}, key *gcmsivpb.AesGcmSivKey) error {
+	cipher := p.(*subteAEAD.AESGCMSIV)
+	if !bytes.Equal(cipher.Key, key.KeyValue) {
+		return fmt.Errorf("key and primitive don't match")
+	}
+	pt := random.GetRandomBytes(32)
+	aad := random.GetRandomBytes(32)
+	ct, err := cipher.Encrypt(pt, aad)
+	if err != nil {
+		return fmt.Errorf("encryption failed")
+	}
+	decrypted, err := cipher.Decrypt(ct, aad)
+	if err != nil || !bytes.Equal(decrypted, pt) {
+		return fmt.Errorf("decryption failed")
+	}
+	return nil
+}
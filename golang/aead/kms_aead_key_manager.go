@@ -0,0 +1,143 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package aead
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+
+	"github.com/tsingson/tink/golang/core/registry"
+	"github.com/tsingson/tink/golang/keyset"
+	kmsaeadpb "github.com/tsingson/tink/proto/kms_aead_go_proto"
+	tinkpb "github.com/tsingson/tink/proto/tink_go_proto"
+)
+
+const (
+	kmsAEADKeyVersion = 0
+	kmsAEADTypeURL    = "type.googleapis.com/google.crypto.tink.KmsAeadKey"
+)
+
+// common errors
+var errInvalidKMSAEADKey = errors.New("kms_aead_key_manager: invalid key")
+var errInvalidKMSAEADKeyFormat = errors.New("kms_aead_key_manager: invalid key format")
+
+// kmsAEADKeyManager is an implementation of KeyManager interface.
+// It generates new KMSAEADKey keys, which only reference a key held in a remote KMS,
+// and produces the AEAD primitives returned by the KMS client registered for the key URI.
+type kmsAEADKeyManager struct {
+	registry *registry.Registry
+}
+
+// Assert that kmsAEADKeyManager implements the KeyManager interface.
+var _ registry.KeyManager = (*kmsAEADKeyManager)(nil)
+
+// newKMSAEADKeyManager creates a new kmsAEADKeyManager that resolves key URIs
+// through the KMS clients of the given registry.
+func newKMSAEADKeyManager(r *registry.Registry) *kmsAEADKeyManager {
+	return &kmsAEADKeyManager{registry: r}
+}
+
+// Primitive resolves the key URI in the given serialized KMSAEADKey proto through
+// the registered KMS clients and returns the remote AEAD.
+func (km *kmsAEADKeyManager) Primitive(serializedKey []byte) (interface{}, error) {
+	if len(serializedKey) == 0 {
+		return nil, errInvalidKMSAEADKey
+	}
+	key := new(kmsaeadpb.KmsAeadKey)
+	if err := proto.Unmarshal(serializedKey, key); err != nil {
+		return nil, errInvalidKMSAEADKey
+	}
+	if err := km.validateKey(key); err != nil {
+		return nil, err
+	}
+	uri := key.Params.KeyUri
+	kmsClient, err := km.registry.GetKMSClient(uri)
+	if err != nil {
+		return nil, fmt.Errorf("kms_aead_key_manager: %s", err)
+	}
+	ret, err := kmsClient.GetAEAD(uri)
+	if err != nil {
+		return nil, fmt.Errorf("kms_aead_key_manager: cannot get remote AEAD: %s", err)
+	}
+	return ret, nil
+}
+
+// NewKey creates a new key according to specification the given serialized KMSAEADKeyFormat.
+func (km *kmsAEADKeyManager) NewKey(serializedKeyFormat []byte) (proto.Message, error) {
+	if len(serializedKeyFormat) == 0 {
+		return nil, errInvalidKMSAEADKeyFormat
+	}
+	keyFormat := new(kmsaeadpb.KmsAeadKeyFormat)
+	if err := proto.Unmarshal(serializedKeyFormat, keyFormat); err != nil {
+		return nil, errInvalidKMSAEADKeyFormat
+	}
+	if err := km.validateKeyFormat(keyFormat); err != nil {
+		return nil, err
+	}
+	return &kmsaeadpb.KmsAeadKey{
+		Version: kmsAEADKeyVersion,
+		Params:  keyFormat,
+	}, nil
+}
+
+// NewKeyData creates a new KeyData according to specification in the given serialized
+// KMSAEADKeyFormat.
+// It should be used solely by the key management API.
+func (km *kmsAEADKeyManager) NewKeyData(serializedKeyFormat []byte) (*tinkpb.KeyData, error) {
+	key, err := km.NewKey(serializedKeyFormat)
+	if err != nil {
+		return nil, err
+	}
+	serializedKey, err := proto.Marshal(key)
+	if err != nil {
+		return nil, err
+	}
+	return &tinkpb.KeyData{
+		TypeUrl:         kmsAEADTypeURL,
+		Value:           serializedKey,
+		KeyMaterialType: tinkpb.KeyData_REMOTE,
+	}, nil
+}
+
+// DoesSupport indicates if this key manager supports the given key type.
+func (km *kmsAEADKeyManager) DoesSupport(typeURL string) bool {
+	return typeURL == kmsAEADTypeURL
+}
+
+// TypeURL returns the key type of keys managed by this key manager.
+func (km *kmsAEADKeyManager) TypeURL() string {
+	return kmsAEADTypeURL
+}
+
+// validateKey validates the given KMSAEADKey.
+func (km *kmsAEADKeyManager) validateKey(key *kmsaeadpb.KmsAeadKey) error {
+	if err := keyset.ValidateKeyVersion(key.Version, kmsAEADKeyVersion); err != nil {
+		return fmt.Errorf("kms_aead_key_manager: %s", err)
+	}
+	if key.Params == nil {
+		return errInvalidKMSAEADKey
+	}
+	return km.validateKeyFormat(key.Params)
+}
+
+// validateKeyFormat validates the given KMSAEADKeyFormat.
+func (km *kmsAEADKeyManager) validateKeyFormat(format *kmsaeadpb.KmsAeadKeyFormat) error {
+	if format.KeyUri == "" {
+		return errors.New("kms_aead_key_manager: missing key URI")
+	}
+	return nil
+}
@@ -0,0 +1,231 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package aead_test
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+
+	"github.com/tsingson/tink/golang/aead"
+	"github.com/tsingson/tink/golang/core/registry"
+	"github.com/tsingson/tink/golang/keyset"
+	subteAEAD "github.com/tsingson/tink/golang/subtle/aead"
+	"github.com/tsingson/tink/golang/subtle/random"
+	"github.com/tsingson/tink/golang/testkeyset"
+	"github.com/tsingson/tink/golang/testutil"
+	"github.com/tsingson/tink/golang/tink"
+	kmsaeadpb "github.com/tsingson/tink/proto/kms_aead_go_proto"
+	tinkpb "github.com/tsingson/tink/proto/tink_go_proto"
+)
+
+const fakeKMSPrefix = "fake-kms-aead://"
+
+// fakeKMSClient is a KMSClient that keeps one AES-GCM key per key URI in memory.
+type fakeKMSClient struct {
+	mu   sync.Mutex
+	keys map[string]tink.AEAD
+}
+
+var _ registry.KMSClient = (*fakeKMSClient)(nil)
+
+func (c *fakeKMSClient) Supported(keyURI string) bool {
+	return strings.HasPrefix(keyURI, fakeKMSPrefix)
+}
+
+func (c *fakeKMSClient) LoadCredentials(credentialPath string) (interface{}, error) {
+	return c, nil
+}
+
+func (c *fakeKMSClient) LoadDefaultCredentials() (interface{}, error) {
+	return c, nil
+}
+
+func (c *fakeKMSClient) GetAEAD(keyURI string) (tink.AEAD, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if a, ok := c.keys[keyURI]; ok {
+		return a, nil
+	}
+	a, err := subteAEAD.NewAESGCM(random.GetRandomBytes(32))
+	if err != nil {
+		return nil, err
+	}
+	c.keys[keyURI] = a
+	return a, nil
+}
+
+func init() {
+	registry.RegisterKMSClient(&fakeKMSClient{keys: make(map[string]tink.AEAD)})
+}
+
+func TestKMSAEADGetPrimitiveBasic(t *testing.T) {
+	km, err := registry.GetKeyManager(testutil.KMSAEADTypeURL)
+	if err != nil {
+		t.Fatalf("cannot obtain KMS AEAD key manager: %s", err)
+	}
+	key := newKMSAEADKey(testutil.KMSAEADKeyVersion, fakeKMSPrefix+"basic")
+	serializedKey, _ := proto.Marshal(key)
+	p, err := km.Primitive(serializedKey)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	pt := random.GetRandomBytes(32)
+	aad := random.GetRandomBytes(32)
+	ct, err := p.(tink.AEAD).Encrypt(pt, aad)
+	if err != nil {
+		t.Fatalf("encryption failed: %s", err)
+	}
+	// a primitive for the same key URI must decrypt the ciphertext
+	p, err = km.Primitive(serializedKey)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	decrypted, err := p.(tink.AEAD).Decrypt(ct, aad)
+	if err != nil || !bytes.Equal(decrypted, pt) {
+		t.Errorf("decryption failed: %s", err)
+	}
+}
+
+func TestKMSAEADGetPrimitiveWithInvalidInput(t *testing.T) {
+	km, err := registry.GetKeyManager(testutil.KMSAEADTypeURL)
+	if err != nil {
+		t.Fatalf("cannot obtain KMS AEAD key manager: %s", err)
+	}
+	testKeys := []proto.Message{
+		// no KMS client supports the URI
+		newKMSAEADKey(testutil.KMSAEADKeyVersion, "unknown-kms://key"),
+		// empty URI
+		newKMSAEADKey(testutil.KMSAEADKeyVersion, ""),
+		// missing params
+		&kmsaeadpb.KmsAeadKey{Version: testutil.KMSAEADKeyVersion},
+		// bad version
+		newKMSAEADKey(testutil.KMSAEADKeyVersion+1, fakeKMSPrefix+"basic"),
+	}
+	for i, key := range testKeys {
+		serializedKey, _ := proto.Marshal(key)
+		if _, err := km.Primitive(serializedKey); err == nil {
+			t.Errorf("expect an error in test case %d", i)
+		}
+	}
+	if _, err := km.Primitive(nil); err == nil {
+		t.Errorf("expect an error when input is nil")
+	}
+	if _, err := km.Primitive([]byte{}); err == nil {
+		t.Errorf("expect an error when input is empty")
+	}
+}
+
+func TestKMSAEADNewKeyData(t *testing.T) {
+	km, err := registry.GetKeyManager(testutil.KMSAEADTypeURL)
+	if err != nil {
+		t.Fatalf("cannot obtain KMS AEAD key manager: %s", err)
+	}
+	uri := fakeKMSPrefix + "new-key-data"
+	serializedFormat, _ := proto.Marshal(&kmsaeadpb.KmsAeadKeyFormat{KeyUri: uri})
+	keyData, err := km.NewKeyData(serializedFormat)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if keyData.TypeUrl != testutil.KMSAEADTypeURL {
+		t.Errorf("incorrect type url")
+	}
+	if keyData.KeyMaterialType != tinkpb.KeyData_REMOTE {
+		t.Errorf("incorrect key material type")
+	}
+	key := new(kmsaeadpb.KmsAeadKey)
+	if err := proto.Unmarshal(keyData.Value, key); err != nil {
+		t.Fatalf("incorrect key value")
+	}
+	if key.Version != testutil.KMSAEADKeyVersion || key.Params.KeyUri != uri {
+		t.Errorf("key does not match the format")
+	}
+	// invalid formats
+	serializedFormat, _ = proto.Marshal(&kmsaeadpb.KmsAeadKeyFormat{})
+	if _, err := km.NewKeyData(serializedFormat); err == nil {
+		t.Errorf("expect an error when the key URI is empty")
+	}
+	if _, err := km.NewKey(nil); err == nil {
+		t.Errorf("expect an error when input is nil")
+	}
+	if !km.DoesSupport(testutil.KMSAEADTypeURL) || km.DoesSupport(testutil.KMSEnvelopeAEADTypeURL) {
+		t.Errorf("KMSAEADKeyManager must support only %s", testutil.KMSAEADTypeURL)
+	}
+	if km.TypeURL() != testutil.KMSAEADTypeURL {
+		t.Errorf("incorrect key type")
+	}
+}
+
+func TestKMSAEADRotationFromLocalKey(t *testing.T) {
+	kh, err := keyset.NewHandle(aead.AES128GCMKeyTemplate())
+	if err != nil {
+		t.Fatalf("keyset.NewHandle() failed: %s", err)
+	}
+	a, err := aead.New(kh)
+	if err != nil {
+		t.Fatalf("aead.New() failed: %s", err)
+	}
+	pt := []byte("plaintext")
+	aad := []byte("aad")
+	localCT, err := a.Encrypt(pt, aad)
+	if err != nil {
+		t.Fatalf("encryption failed: %s", err)
+	}
+
+	// rotate to a key held in KMS; data encrypted with the local key stays readable
+	manager := keyset.NewManagerFromHandle(kh)
+	if err := manager.Rotate(aead.KMSAEADKeyTemplate(fakeKMSPrefix + "rotation")); err != nil {
+		t.Fatalf("manager.Rotate() failed: %s", err)
+	}
+	kh, err = manager.Handle()
+	if err != nil {
+		t.Fatalf("manager.Handle() failed: %s", err)
+	}
+	a, err = aead.New(kh)
+	if err != nil {
+		t.Fatalf("aead.New() failed: %s", err)
+	}
+	remoteCT, err := a.Encrypt(pt, aad)
+	if err != nil {
+		t.Fatalf("encryption failed: %s", err)
+	}
+	for _, ct := range [][]byte{localCT, remoteCT} {
+		decrypted, err := a.Decrypt(ct, aad)
+		if err != nil || !bytes.Equal(decrypted, pt) {
+			t.Errorf("decryption failed: %s", err)
+		}
+	}
+
+	// the new primary key is remote and holds no key material
+	ks := testkeyset.KeysetMaterial(kh)
+	for _, key := range ks.Key {
+		if key.KeyId != ks.PrimaryKeyId {
+			continue
+		}
+		if key.KeyData.TypeUrl != testutil.KMSAEADTypeURL || key.KeyData.KeyMaterialType != tinkpb.KeyData_REMOTE {
+			t.Errorf("primary key is not the KMS AEAD key")
+		}
+	}
+}
+
+func newKMSAEADKey(version uint32, uri string) *kmsaeadpb.KmsAeadKey {
+	return &kmsaeadpb.KmsAeadKey{
+		Version: version,
+		Params:  &kmsaeadpb.KmsAeadKeyFormat{KeyUri: uri},
+	}
+}
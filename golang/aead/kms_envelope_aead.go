@@ -16,6 +16,7 @@ package aead
 
 import (
 	"bytes"
+	"context"
 	"encoding/binary"
 	"errors"
 	"fmt"
@@ -34,22 +35,79 @@ const (
 // KMSEnvelopeAEAD represents an instance of Envelope AEAD.
 type KMSEnvelopeAEAD struct {
 	dekTemplate *tinkpb.KeyTemplate
-	remote      tink.AEAD
+	remote      tink.AEADWithContext
+	registry    *registry.Registry
+	cache       *dekCache
 }
 
-var _ tink.AEAD = (*KMSEnvelopeAEAD)(nil)
+var (
+	_ tink.AEAD            = (*KMSEnvelopeAEAD)(nil)
+	_ tink.AEADWithContext = (*KMSEnvelopeAEAD)(nil)
+)
 
-// NewKMSEnvelopeAEAD creates an new instance of KMSEnvelopeAEAD
+// NewKMSEnvelopeAEAD creates an new instance of KMSEnvelopeAEAD.
+// If remote implements tink.AEADWithContext, the contexts given to EncryptWithContext and
+// DecryptWithContext are passed on to it.
 func NewKMSEnvelopeAEAD(kt tinkpb.KeyTemplate, remote tink.AEAD) *KMSEnvelopeAEAD {
+	return newKMSEnvelopeAEAD(registry.Default(), kt, remote)
+}
+
+// NewKMSEnvelopeAEADWithDEKCache creates an new instance of KMSEnvelopeAEAD that caches
+// DEKs as configured, to reduce the number of calls to the remote AEAD. The format of the
+// ciphertexts is the same as without caching.
+//
+// The serialized DEKs held by the cache and the key bytes of their AEAD primitives are
+// zeroed when they are evicted, once no operation uses them anymore. Only DEKs of the
+// AES-GCM, AES-GCM-SIV, ChaCha20Poly1305 and XChaCha20Poly1305 key types are cached, since
+// the primitives of other key types keep expanded keys that cannot be zeroed. Note that reusing a DEK for many messages reduces the security margin
+// of the DEK's AEAD, e.g. against nonce collisions, and that a compromised DEK exposes all
+// its messages.
+func NewKMSEnvelopeAEADWithDEKCache(kt tinkpb.KeyTemplate, remote tink.AEAD, config DEKCacheConfig) *KMSEnvelopeAEAD {
+	a := newKMSEnvelopeAEAD(registry.Default(), kt, remote)
+	if cachesEncryptionKey(config) || config.MaxDecryptionKeys > 0 {
+		a.cache = newDEKCache(config)
+	}
+	return a
+}
+
+// newKMSEnvelopeAEAD creates a KMSEnvelopeAEAD whose DEKs are handled by the given registry.
+func newKMSEnvelopeAEAD(r *registry.Registry, kt tinkpb.KeyTemplate, remote tink.AEAD) *KMSEnvelopeAEAD {
 	return &KMSEnvelopeAEAD{
-		remote:      remote,
+		remote:      NewAEADWithContext(remote),
 		dekTemplate: &kt,
+		registry:    r,
 	}
 }
 
 // Encrypt implements the tink.AEAD interface for encryption.
 func (a *KMSEnvelopeAEAD) Encrypt(pt, aad []byte) ([]byte, error) {
-	dekM, err := registry.NewKey(a.dekTemplate)
+	return a.EncryptWithContext(context.Background(), pt, aad)
+}
+
+// EncryptWithContext implements the tink.AEADWithContext interface for encryption.
+// The context is used for the encryption of the DEK by the remote AEAD.
+func (a *KMSEnvelopeAEAD) EncryptWithContext(ctx context.Context, pt, aad []byte) ([]byte, error) {
+	e, err := a.encryptionKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := e.primitive.Encrypt(pt, aad)
+	a.releaseKey(e)
+	if err != nil {
+		return nil, err
+	}
+	return buildCipherText(e.encryptedDEK, payload)
+}
+
+// encryptionKey returns the DEK for the encryption of a message, either from the DEK
+// cache or by generating a new DEK. The DEK must be released with releaseKey after use.
+func (a *KMSEnvelopeAEAD) encryptionKey(ctx context.Context) (*dekCacheEntry, error) {
+	if a.cache != nil {
+		if e := a.cache.encryptionKey(); e != nil {
+			return e, nil
+		}
+	}
+	dekM, err := a.registry.NewKey(a.dekTemplate)
 	if err != nil {
 		return nil, err
 	}
@@ -57,29 +115,76 @@ func (a *KMSEnvelopeAEAD) Encrypt(pt, aad []byte) ([]byte, error) {
 	if err != nil {
 		return nil, err
 	}
-	encryptedDEK, err := a.remote.Encrypt(dek, []byte{})
+	encryptedDEK, err := a.remote.EncryptWithContext(ctx, dek, []byte{})
 	if err != nil {
 		return nil, err
 	}
-	p, err := registry.Primitive(a.dekTemplate.TypeUrl, dek)
+	primitive, err := a.dekPrimitive(dek)
 	if err != nil {
 		return nil, err
 	}
-	primitive, ok := p.(tink.AEAD)
-	if !ok {
-		return nil, errors.New("kms_envelope_aead: failed to convert AEAD primitive")
+	e := newDEKCacheEntry(encryptedDEK, dek, primitive)
+	if a.cache != nil {
+		a.cache.addEncryptionKey(e)
 	}
+	return e, nil
+}
 
-	payload, err := primitive.Encrypt(pt, aad)
+// decryptionKey returns the given encrypted DEK with its primitive, either from the DEK
+// cache or by decrypting it with the remote AEAD. The DEK must be released with
+// releaseKey after use.
+func (a *KMSEnvelopeAEAD) decryptionKey(ctx context.Context, encryptedDEK []byte) (*dekCacheEntry, error) {
+	if a.cache != nil {
+		if e := a.cache.decryptionKey(encryptedDEK); e != nil {
+			return e, nil
+		}
+	}
+	dek, err := a.remote.DecryptWithContext(ctx, encryptedDEK, []byte{})
+	if err != nil {
+		return nil, err
+	}
+	primitive, err := a.dekPrimitive(dek)
 	if err != nil {
 		return nil, err
 	}
-	return buildCipherText(encryptedDEK, payload)
+	e := newDEKCacheEntry(encryptedDEK, dek, primitive)
+	if a.cache != nil {
+		a.cache.addDecryptionKey(e)
+	}
+	return e, nil
+}
+
+// releaseKey ends the use of a DEK returned by encryptionKey or decryptionKey.
+func (a *KMSEnvelopeAEAD) releaseKey(e *dekCacheEntry) {
+	if a.cache != nil {
+		a.cache.release(e)
+	}
+}
 
+// dekPrimitive returns the AEAD primitive for the given serialized DEK.
+func (a *KMSEnvelopeAEAD) dekPrimitive(dek []byte) (tink.AEAD, error) {
+	p, err := a.registry.Primitive(a.dekTemplate.TypeUrl, dek)
+	if err != nil {
+		return nil, fmt.Errorf("kms_envelope_aead: %s", err)
+	}
+	primitive, ok := p.(tink.AEAD)
+	if !ok {
+		return nil, errors.New("kms_envelope_aead: failed to convert AEAD primitive")
+	}
+	return primitive, nil
 }
 
 // Decrypt implements the tink.AEAD interface for decryption.
 func (a *KMSEnvelopeAEAD) Decrypt(ct, aad []byte) ([]byte, error) {
+	return a.DecryptWithContext(context.Background(), ct, aad)
+}
+
+// DecryptWithContext implements the tink.AEADWithContext interface for decryption.
+// The context is used for the decryption of the DEK by the remote AEAD.
+func (a *KMSEnvelopeAEAD) DecryptWithContext(ctx context.Context, ct, aad []byte) ([]byte, error) {
+	if len(ct) < lenDEK {
+		return nil, errors.New("kms_envelope_aead: invalid ciphertext")
+	}
 	b := bytes.NewBuffer(ct)
 	bLen := b.Len()
 
@@ -101,19 +206,21 @@ func (a *KMSEnvelopeAEAD) Decrypt(ct, aad []byte) ([]byte, error) {
 		return nil, errors.New("kms_envelope_aead: invalid ciphertext")
 	}
 
-	dek, err := a.remote.Decrypt(encryptedDEK, []byte{})
+	e, err := a.decryptionKey(ctx, encryptedDEK)
 	if err != nil {
 		return nil, err
 	}
-	p, err := registry.Primitive(a.dekTemplate.TypeUrl, dek)
-	if err != nil {
-		return nil, fmt.Errorf("kms_envelope_aead: %s", err)
-	}
-	primitive, ok := p.(tink.AEAD)
-	if !ok {
-		return nil, errors.New("kms_envelope_aead: failed to convert AEAD primitive")
+	defer a.releaseKey(e)
+	return e.primitive.Decrypt(payload, aad)
+}
+
+// ClearDEKCache evicts all DEKs cached by a KMSEnvelopeAEAD created with
+// NewKMSEnvelopeAEADWithDEKCache and zeroes them
+// once no operation uses them anymore.
+func (a *KMSEnvelopeAEAD) ClearDEKCache() {
+	if a.cache != nil {
+		a.cache.clear()
 	}
-	return primitive.Decrypt(payload, aad)
 }
 
 // buildCipherText builds the cipher text by appending the length DEK, encrypted DEK
@@ -33,14 +33,17 @@ const (
 
 // kmsEnvelopeAEADKeyManager is an implementation of KeyManager interface.
 // It generates new KMSEnvelopeAEADKey keys and produces new instances of KMSEnvelopeAEAD subtle.
-type kmsEnvelopeAEADKeyManager struct{}
+type kmsEnvelopeAEADKeyManager struct {
+	registry *registry.Registry
+}
 
 // Assert that kmsEnvelopeAEADKeyManager implements the KeyManager interface.
 var _ registry.KeyManager = (*kmsEnvelopeAEADKeyManager)(nil)
 
-// newKMSEnvelopeAEADKeyManager creates a new aesGcmKeyManager.
-func newKMSEnvelopeAEADKeyManager() *kmsEnvelopeAEADKeyManager {
-	return new(kmsEnvelopeAEADKeyManager)
+// newKMSEnvelopeAEADKeyManager creates a new kmsEnvelopeAEADKeyManager that resolves
+// KEK URIs and DEK key types through the given registry.
+func newKMSEnvelopeAEADKeyManager(r *registry.Registry) *kmsEnvelopeAEADKeyManager {
+	return &kmsEnvelopeAEADKeyManager{registry: r}
 }
 
 // Primitive creates an KMSEnvelopeAEAD subtle for the given serialized KMSEnvelopeAEADKey proto.
@@ -56,7 +59,7 @@ func (km *kmsEnvelopeAEADKeyManager) Primitive(serializedKey []byte) (interface{
 		return nil, err
 	}
 	uri := key.Params.KekUri
-	kmsClient, err := registry.GetKMSClient(uri)
+	kmsClient, err := km.registry.GetKMSClient(uri)
 	if err != nil {
 		return nil, err
 	}
@@ -65,7 +68,7 @@ func (km *kmsEnvelopeAEADKeyManager) Primitive(serializedKey []byte) (interface{
 		return nil, errors.New("kms_envelope_aead_key_manager: invalid aead backend")
 	}
 
-	return NewKMSEnvelopeAEAD(*key.Params.DekTemplate, backend), nil
+	return newKMSEnvelopeAEAD(km.registry, *key.Params.DekTemplate, backend), nil
 }
 
 // NewKey creates a new key according to specification the given serialized KMSEnvelopeAEADKeyFormat.
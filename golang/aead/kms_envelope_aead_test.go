@@ -0,0 +1,184 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package aead_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+	"time"
+
+	"github.com/tsingson/tink/golang/aead"
+	"github.com/tsingson/tink/golang/tink"
+)
+
+// countingAEAD counts the calls to the wrapped AEAD and keeps the plaintexts it encrypts.
+type countingAEAD struct {
+	a           tink.AEAD
+	encryptions int
+	decryptions int
+	plaintexts  [][]byte
+}
+
+func (c *countingAEAD) Encrypt(pt, ad []byte) ([]byte, error) {
+	c.encryptions++
+	c.plaintexts = append(c.plaintexts, pt)
+	return c.a.Encrypt(pt, ad)
+}
+
+func (c *countingAEAD) Decrypt(ct, ad []byte) ([]byte, error) {
+	c.decryptions++
+	return c.a.Decrypt(ct, ad)
+}
+
+// encryptedDEK returns the encrypted DEK of the given envelope ciphertext.
+func encryptedDEK(ct []byte) []byte {
+	n := binary.BigEndian.Uint32(ct[:4])
+	return ct[4 : 4+n]
+}
+
+func encryptMessages(t *testing.T, a tink.AEAD, n int) [][]byte {
+	t.Helper()
+	var cts [][]byte
+	for i := 0; i < n; i++ {
+		ct, err := a.Encrypt([]byte("plaintext"), []byte("ad"))
+		if err != nil {
+			t.Fatalf("a.Encrypt() err = %v, want nil", err)
+		}
+		cts = append(cts, ct)
+	}
+	return cts
+}
+
+func decryptMessages(t *testing.T, a tink.AEAD, cts [][]byte) {
+	t.Helper()
+	for _, ct := range cts {
+		pt, err := a.Decrypt(ct, []byte("ad"))
+		if err != nil || !bytes.Equal(pt, []byte("plaintext")) {
+			t.Fatalf("a.Decrypt() = %q, %v, want %q, nil", pt, err, "plaintext")
+		}
+	}
+}
+
+func TestKMSEnvelopeAEADWithoutDEKCache(t *testing.T) {
+	remote := &countingAEAD{a: newSubtleAESGCM(t)}
+	a := aead.NewKMSEnvelopeAEADWithDEKCache(*aead.AES128GCMKeyTemplate(), remote, aead.DEKCacheConfig{})
+	cts := encryptMessages(t, a, 3)
+	decryptMessages(t, a, cts)
+	decryptMessages(t, a, cts)
+	if remote.encryptions != 3 || remote.decryptions != 6 {
+		t.Errorf("remote calls = %d encryptions, %d decryptions, want 3, 6", remote.encryptions, remote.decryptions)
+	}
+}
+
+func TestKMSEnvelopeAEADEncryptionDEKCache(t *testing.T) {
+	remote := &countingAEAD{a: newSubtleAESGCM(t)}
+	a := aead.NewKMSEnvelopeAEADWithDEKCache(*aead.AES128GCMKeyTemplate(), remote, aead.DEKCacheConfig{MaxEncryptions: 3})
+	cts := encryptMessages(t, a, 7)
+	if remote.encryptions != 3 {
+		t.Errorf("remote encryptions = %d, want 3", remote.encryptions)
+	}
+	if !bytes.Equal(encryptedDEK(cts[0]), encryptedDEK(cts[2])) || bytes.Equal(encryptedDEK(cts[2]), encryptedDEK(cts[3])) {
+		t.Errorf("DEKs were not reused for exactly 3 messages")
+	}
+
+	// The ciphertext format is unchanged.
+	decryptMessages(t, aead.NewKMSEnvelopeAEAD(*aead.AES128GCMKeyTemplate(), remote), cts)
+
+	a.ClearDEKCache()
+	for i, dek := range remote.plaintexts {
+		if !bytes.Equal(dek, make([]byte, len(dek))) {
+			t.Errorf("DEK %d was not zeroed", i)
+		}
+	}
+	encryptMessages(t, a, 1)
+	if remote.encryptions != 4 {
+		t.Errorf("remote encryptions = %d, want 4", remote.encryptions)
+	}
+}
+
+func TestKMSEnvelopeAEADEncryptionDEKCacheMaxAge(t *testing.T) {
+	remote := &countingAEAD{a: newSubtleAESGCM(t)}
+	a := aead.NewKMSEnvelopeAEADWithDEKCache(*aead.AES128GCMKeyTemplate(), remote, aead.DEKCacheConfig{
+		MaxEncryptions:    100,
+		MaxDecryptionKeys: 10,
+		MaxAge:            50 * time.Millisecond,
+	})
+	cts := encryptMessages(t, a, 2)
+	decryptMessages(t, a, cts)
+	if remote.encryptions != 1 || remote.decryptions != 1 {
+		t.Errorf("remote calls = %d encryptions, %d decryptions, want 1, 1", remote.encryptions, remote.decryptions)
+	}
+	time.Sleep(100 * time.Millisecond)
+	encryptMessages(t, a, 1)
+	decryptMessages(t, a, cts)
+	if remote.encryptions != 2 || remote.decryptions != 2 {
+		t.Errorf("remote calls = %d encryptions, %d decryptions, want 2, 2", remote.encryptions, remote.decryptions)
+	}
+}
+
+func TestKMSEnvelopeAEADEncryptionDEKCacheMaxAgeOnly(t *testing.T) {
+	remote := &countingAEAD{a: newSubtleAESGCM(t)}
+	a := aead.NewKMSEnvelopeAEADWithDEKCache(*aead.AES128GCMKeyTemplate(), remote, aead.DEKCacheConfig{
+		MaxAge: 50 * time.Millisecond,
+	})
+	encryptMessages(t, a, 5)
+	if remote.encryptions != 1 {
+		t.Errorf("remote encryptions = %d, want 1", remote.encryptions)
+	}
+	time.Sleep(100 * time.Millisecond)
+	encryptMessages(t, a, 1)
+	if remote.encryptions != 2 {
+		t.Errorf("remote encryptions = %d, want 2", remote.encryptions)
+	}
+}
+
+func TestKMSEnvelopeAEADDecryptShortCiphertext(t *testing.T) {
+	a := aead.NewKMSEnvelopeAEAD(*aead.AES128GCMKeyTemplate(), newSubtleAESGCM(t))
+	for _, ct := range [][]byte{nil, {}, {0}, {0, 0, 0}, {0, 0, 0, 1}} {
+		if _, err := a.Decrypt(ct, []byte("ad")); err == nil {
+			t.Errorf("a.Decrypt(%x) err = nil, want error", ct)
+		}
+	}
+}
+
+func TestKMSEnvelopeAEADDecryptionDEKCache(t *testing.T) {
+	remote := &countingAEAD{a: newSubtleAESGCM(t)}
+	a := aead.NewKMSEnvelopeAEADWithDEKCache(*aead.AES128GCMKeyTemplate(), remote, aead.DEKCacheConfig{MaxDecryptionKeys: 2})
+	cts := encryptMessages(t, a, 3)
+
+	decryptMessages(t, a, cts[:2])
+	decryptMessages(t, a, cts[:2])
+	if remote.decryptions != 2 {
+		t.Errorf("remote decryptions = %d, want 2", remote.decryptions)
+	}
+	// Evicts the DEK of cts[0], the least recently used one.
+	decryptMessages(t, a, cts[2:])
+	decryptMessages(t, a, cts[1:])
+	if remote.decryptions != 3 {
+		t.Errorf("remote decryptions = %d, want 3", remote.decryptions)
+	}
+	decryptMessages(t, a, cts[:1])
+	if remote.decryptions != 4 {
+		t.Errorf("remote decryptions = %d, want 4", remote.decryptions)
+	}
+
+	// A tampered payload is not accepted with a cached DEK.
+	tampered := append([]byte{}, cts[0]...)
+	tampered[len(tampered)-1] ^= 1
+	if _, err := a.Decrypt(tampered, []byte("ad")); err == nil {
+		t.Errorf("a.Decrypt() err = nil, want error for tampered ciphertext")
+	}
+}
@@ -0,0 +1,245 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package aead
+
+import (
+	"container/list"
+	"sync"
+	"time"
+
+	"github.com/tsingson/tink/golang/subtle/aead"
+	"github.com/tsingson/tink/golang/tink"
+)
+
+// DEKCacheConfig configures the caching of data encryption keys (DEKs) by a KMSEnvelopeAEAD.
+// Caching reduces the number of calls to the remote KMS at the cost of keeping DEKs in memory.
+// The zero value disables caching.
+type DEKCacheConfig struct {
+	// MaxEncryptions is the number of messages that are encrypted with the same DEK before
+	// a new one is generated. A value of 1 generates a new DEK for every message, as does
+	// 0 unless MaxAge is set, in which case the number of messages is not limited.
+	MaxEncryptions int
+
+	// MaxDecryptionKeys is the number of decrypted DEKs that are kept to decrypt further
+	// ciphertexts with the same encrypted DEK. The least recently used DEK is evicted
+	// first. A value of 0 decrypts the DEK of every ciphertext with the remote KMS.
+	MaxDecryptionKeys int
+
+	// MaxAge is how long a DEK is used for encryption and kept for decryption.
+	// A value of 0 does not limit the age of cached DEKs.
+	MaxAge time.Duration
+}
+
+// dekCacheEntry is a DEK held by a dekCache.
+type dekCacheEntry struct {
+	encryptedDEK []byte
+	// dek is the serialized DEK and key the key bytes that primitive uses. Both are zeroed
+	// once the entry has been evicted and no operation uses primitive anymore.
+	dek       []byte
+	key       []byte
+	primitive tink.AEAD
+	created   time.Time
+	uses      int
+	// refs is the number of operations using primitive. It is guarded by the mutex of the
+	// cache, like evicted.
+	refs    int
+	evicted bool
+}
+
+// newDEKCacheEntry returns an entry for the given DEK and its primitive.
+func newDEKCacheEntry(encryptedDEK, dek []byte, primitive tink.AEAD) *dekCacheEntry {
+	return &dekCacheEntry{
+		encryptedDEK: encryptedDEK,
+		dek:          dek,
+		key:          primitiveKey(primitive),
+		primitive:    primitive,
+	}
+}
+
+// primitiveKey returns the key bytes that the given primitive reads on every operation,
+// or nil if the primitive keeps a copy of its key that cannot be zeroed.
+func primitiveKey(p tink.AEAD) []byte {
+	switch a := p.(type) {
+	case *aead.AESGCM:
+		return a.Key
+	case *aead.AESGCMSIV:
+		return a.Key
+	case *aead.ChaCha20Poly1305:
+		return a.Key
+	case *aead.XChaCha20Poly1305:
+		return a.Key
+	default:
+		return nil
+	}
+}
+
+// wipe zeroes the serialized DEK and the key bytes of the primitive of the entry and
+// releases the primitive.
+func (e *dekCacheEntry) wipe() {
+	for i := range e.dek {
+		e.dek[i] = 0
+	}
+	for i := range e.key {
+		e.key[i] = 0
+	}
+	e.primitive = nil
+}
+
+// dekCache holds the DEK used for encryption and the most recently used decrypted DEKs
+// of a KMSEnvelopeAEAD.
+type dekCache struct {
+	config DEKCacheConfig
+	now    func() time.Time
+
+	mu         sync.Mutex
+	encryption *dekCacheEntry
+	decryption map[string]*list.Element // encrypted DEK -> element holding a *dekCacheEntry
+	lru        *list.List               // most recently used at the front
+}
+
+func newDEKCache(config DEKCacheConfig) *dekCache {
+	return &dekCache{
+		config:     config,
+		now:        time.Now,
+		decryption: make(map[string]*list.Element),
+		lru:        list.New(),
+	}
+}
+
+// cachesEncryptionKey returns whether the given configuration reuses DEKs for encryption.
+func cachesEncryptionKey(config DEKCacheConfig) bool {
+	return config.MaxEncryptions > 1 || (config.MaxEncryptions == 0 && config.MaxAge > 0)
+}
+
+func (c *dekCache) expired(e *dekCacheEntry) bool {
+	return c.config.MaxAge > 0 && c.now().Sub(e.created) >= c.config.MaxAge
+}
+
+// evict marks the given entry as evicted and wipes it unless an operation still uses it,
+// in which case release wipes it. The caller must hold c.mu.
+func (c *dekCache) evict(e *dekCacheEntry) {
+	e.evicted = true
+	if e.refs == 0 {
+		e.wipe()
+	}
+}
+
+// release ends an operation that uses the given entry, which was returned by
+// encryptionKey or decryptionKey or passed to addEncryptionKey or addDecryptionKey.
+func (c *dekCache) release(e *dekCacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e.refs--
+	if e.evicted && e.refs == 0 {
+		e.wipe()
+	}
+}
+
+// encryptionKey returns the cached DEK for the encryption of one more message, or nil if
+// a new DEK must be generated. The entry must be released after use.
+func (c *dekCache) encryptionKey() *dekCacheEntry {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e := c.encryption
+	if e == nil {
+		return nil
+	}
+	if (c.config.MaxEncryptions > 0 && e.uses >= c.config.MaxEncryptions) || c.expired(e) {
+		c.evict(e)
+		c.encryption = nil
+		return nil
+	}
+	e.uses++
+	e.refs++
+	return e
+}
+
+// addEncryptionKey replaces the cached DEK for encryption with the given entry, which is
+// being used for one message and must be released after use. DEKs whose key bytes cannot
+// be zeroed are not cached.
+func (c *dekCache) addEncryptionKey(e *dekCacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e.refs = 1
+	if !cachesEncryptionKey(c.config) || e.key == nil {
+		e.evicted = true
+		return
+	}
+	e.created = c.now()
+	e.uses = 1
+	if c.encryption != nil {
+		c.evict(c.encryption)
+	}
+	c.encryption = e
+}
+
+// decryptionKey returns the cached DEK with the given encrypted form, or nil if there is
+// none. The entry must be released after use.
+func (c *dekCache) decryptionKey(encryptedDEK []byte) *dekCacheEntry {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	elem, ok := c.decryption[string(encryptedDEK)]
+	if !ok {
+		return nil
+	}
+	e := elem.Value.(*dekCacheEntry)
+	if c.expired(e) {
+		c.removeDecryptionKey(elem)
+		return nil
+	}
+	c.lru.MoveToFront(elem)
+	e.refs++
+	return e
+}
+
+// addDecryptionKey caches the given decrypted DEK, evicting the least recently used one
+// if the cache is full. The entry is being used for one message and must be released after
+// use. DEKs whose key bytes cannot be zeroed are not cached.
+func (c *dekCache) addDecryptionKey(e *dekCacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e.refs = 1
+	if c.config.MaxDecryptionKeys <= 0 || e.key == nil {
+		e.evicted = true
+		return
+	}
+	e.created = c.now()
+	if elem, ok := c.decryption[string(e.encryptedDEK)]; ok {
+		c.removeDecryptionKey(elem)
+	}
+	c.decryption[string(e.encryptedDEK)] = c.lru.PushFront(e)
+	for c.lru.Len() > c.config.MaxDecryptionKeys {
+		c.removeDecryptionKey(c.lru.Back())
+	}
+}
+
+func (c *dekCache) removeDecryptionKey(elem *list.Element) {
+	e := c.lru.Remove(elem).(*dekCacheEntry)
+	delete(c.decryption, string(e.encryptedDEK))
+	c.evict(e)
+}
+
+// clear evicts all cached DEKs.
+func (c *dekCache) clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.encryption != nil {
+		c.evict(c.encryption)
+		c.encryption = nil
+	}
+	for c.lru.Len() > 0 {
+		c.removeDecryptionKey(c.lru.Back())
+	}
+}
@@ -0,0 +1,113 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package aead
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/tsingson/tink/golang/subtle/aead"
+)
+
+func newTestDEKCacheEntry(t *testing.T, encryptedDEK string) (*dekCacheEntry, *aead.AESGCM) {
+	t.Helper()
+	key := bytes.Repeat([]byte{0x42}, 16)
+	primitive, err := aead.NewAESGCM(append([]byte(nil), key...))
+	if err != nil {
+		t.Fatalf("aead.NewAESGCM() err = %v, want nil", err)
+	}
+	return newDEKCacheEntry([]byte(encryptedDEK), key, primitive), primitive
+}
+
+func isZero(b []byte) bool {
+	for _, v := range b {
+		if v != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func TestDEKCacheZeroesEvictedPrimitiveKeys(t *testing.T) {
+	c := newDEKCache(DEKCacheConfig{MaxEncryptions: 2, MaxDecryptionKeys: 1})
+
+	enc, encPrimitive := newTestDEKCacheEntry(t, "encryption")
+	c.addEncryptionKey(enc)
+	c.release(enc)
+	dec, decPrimitive := newTestDEKCacheEntry(t, "decryption")
+	c.addDecryptionKey(dec)
+	c.release(dec)
+	if isZero(encPrimitive.Key) || isZero(decPrimitive.Key) {
+		t.Fatal("the key bytes of cached primitives are zero")
+	}
+
+	c.clear()
+	if !isZero(encPrimitive.Key) || !isZero(enc.dek) {
+		t.Errorf("encryption DEK = %x, primitive key = %x, want zeros", enc.dek, encPrimitive.Key)
+	}
+	if !isZero(decPrimitive.Key) || !isZero(dec.dek) {
+		t.Errorf("decryption DEK = %x, primitive key = %x, want zeros", dec.dek, decPrimitive.Key)
+	}
+}
+
+func TestDEKCacheZeroesEvictedPrimitiveKeysAfterRelease(t *testing.T) {
+	c := newDEKCache(DEKCacheConfig{MaxDecryptionKeys: 1})
+
+	old, oldPrimitive := newTestDEKCacheEntry(t, "old")
+	c.addDecryptionKey(old)
+	c.release(old)
+	if e := c.decryptionKey([]byte("old")); e != old {
+		t.Fatalf("c.decryptionKey() = %v, want %v", e, old)
+	}
+	// Evicts old while it is in use.
+	e, _ := newTestDEKCacheEntry(t, "new")
+	c.addDecryptionKey(e)
+	c.release(e)
+	if isZero(oldPrimitive.Key) {
+		t.Fatal("the key bytes of an evicted primitive in use are zero")
+	}
+	if _, err := old.primitive.Encrypt([]byte("plaintext"), nil); err != nil {
+		t.Errorf("old.primitive.Encrypt() err = %v, want nil", err)
+	}
+
+	c.release(old)
+	if !isZero(oldPrimitive.Key) || !isZero(old.dek) {
+		t.Errorf("DEK = %x, primitive key = %x, want zeros", old.dek, oldPrimitive.Key)
+	}
+	if old.primitive != nil {
+		t.Errorf("old.primitive = %v, want nil", old.primitive)
+	}
+}
+
+func TestDEKCacheDoesNotCacheKeysThatCannotBeZeroed(t *testing.T) {
+	c := newDEKCache(DEKCacheConfig{MaxEncryptions: 2, MaxDecryptionKeys: 1})
+	primitive, err := aead.NewAESEAX(bytes.Repeat([]byte{0x42}, 16), 16)
+	if err != nil {
+		t.Fatalf("aead.NewAESEAX() err = %v, want nil", err)
+	}
+
+	enc := newDEKCacheEntry([]byte("encryption"), []byte{0x42}, primitive)
+	c.addEncryptionKey(enc)
+	c.release(enc)
+	if e := c.encryptionKey(); e != nil {
+		t.Errorf("c.encryptionKey() = %v, want nil", e)
+	}
+	dec := newDEKCacheEntry([]byte("decryption"), []byte{0x42}, primitive)
+	c.addDecryptionKey(dec)
+	c.release(dec)
+	if e := c.decryptionKey([]byte("decryption")); e != nil {
+		t.Errorf("c.decryptionKey() = %v, want nil", e)
+	}
+}
@@ -0,0 +1,251 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package aead
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+
+	"github.com/tsingson/tink/golang/core/registry"
+	"github.com/tsingson/tink/golang/tink"
+	tinkpb "github.com/tsingson/tink/proto/tink_go_proto"
+)
+
+const (
+	// multiEnvelopeVersion is the version of the header of multi-KEK envelope ciphertexts.
+	multiEnvelopeVersion = 1
+	// keyURIHashSize is the size of the SHA-256 hash identifying a KEK in the header.
+	keyURIHashSize = sha256.Size
+	// maxKEKs is the maximum number of KEKs of a MultiKMSEnvelopeAEAD.
+	maxKEKs = 255
+)
+
+var errInvalidMultiEnvelopeCiphertext = errors.New("kms_multi_envelope_aead: invalid ciphertext")
+
+// MultiKMSEnvelopeAEAD is an envelope AEAD that wraps the DEK of each message under
+// several key encryption keys (KEKs) held in remote KMSs, so that a ciphertext can be
+// decrypted as long as any one of them is available.
+//
+// Ciphertexts start with a header consisting of
+//   - a version byte,
+//   - the number of wrapped DEKs as one byte,
+//   - for each KEK, the SHA-256 hash of its key URI, the length of the wrapped DEK as a
+//     big-endian uint32 and the wrapped DEK,
+//
+// followed by the payload encrypted with the DEK. The header is authenticated as part of
+// the associated data of the payload.
+type MultiKMSEnvelopeAEAD struct {
+	dekTemplate *tinkpb.KeyTemplate
+	keyURIs     []string
+	registry    *registry.Registry
+}
+
+var (
+	_ tink.AEAD            = (*MultiKMSEnvelopeAEAD)(nil)
+	_ tink.AEADWithContext = (*MultiKMSEnvelopeAEAD)(nil)
+)
+
+// NewMultiKMSEnvelopeAEAD creates a MultiKMSEnvelopeAEAD whose DEKs are generated with the
+// given key template and wrapped under each of the KEKs with the given URIs. The KEKs are
+// obtained from the KMS clients registered with registry.RegisterKMSClient when they are
+// used. Encryption requires all KEKs; decryption tries them in the given order.
+func NewMultiKMSEnvelopeAEAD(kt tinkpb.KeyTemplate, keyURIs []string) (*MultiKMSEnvelopeAEAD, error) {
+	return NewMultiKMSEnvelopeAEADWithRegistry(kt, keyURIs, registry.Default())
+}
+
+// NewMultiKMSEnvelopeAEADWithRegistry is like NewMultiKMSEnvelopeAEAD, but uses the DEK key
+// managers and the KMS clients of the given registry.
+func NewMultiKMSEnvelopeAEADWithRegistry(kt tinkpb.KeyTemplate, keyURIs []string, r *registry.Registry) (*MultiKMSEnvelopeAEAD, error) {
+	if r == nil {
+		return nil, errors.New("kms_multi_envelope_aead: invalid registry")
+	}
+	if len(keyURIs) == 0 || len(keyURIs) > maxKEKs {
+		return nil, fmt.Errorf("kms_multi_envelope_aead: the number of key URIs must be between 1 and %d", maxKEKs)
+	}
+	seen := make(map[string]bool)
+	for _, uri := range keyURIs {
+		if seen[uri] {
+			return nil, fmt.Errorf("kms_multi_envelope_aead: duplicate key URI %s", uri)
+		}
+		seen[uri] = true
+	}
+	return &MultiKMSEnvelopeAEAD{
+		dekTemplate: &kt,
+		keyURIs:     append([]string{}, keyURIs...),
+		registry:    r,
+	}, nil
+}
+
+// Encrypt implements the tink.AEAD interface for encryption.
+func (a *MultiKMSEnvelopeAEAD) Encrypt(pt, aad []byte) ([]byte, error) {
+	return a.EncryptWithContext(context.Background(), pt, aad)
+}
+
+// EncryptWithContext implements the tink.AEADWithContext interface for encryption.
+// The context is used for the encryption of the DEK under each KEK.
+func (a *MultiKMSEnvelopeAEAD) EncryptWithContext(ctx context.Context, pt, aad []byte) ([]byte, error) {
+	dekM, err := a.registry.NewKey(a.dekTemplate)
+	if err != nil {
+		return nil, err
+	}
+	dek, err := proto.Marshal(dekM)
+	if err != nil {
+		return nil, err
+	}
+	var header bytes.Buffer
+	header.WriteByte(multiEnvelopeVersion)
+	header.WriteByte(byte(len(a.keyURIs)))
+	for _, uri := range a.keyURIs {
+		kek, err := a.kek(uri)
+		if err != nil {
+			return nil, err
+		}
+		wrappedDEK, err := kek.EncryptWithContext(ctx, dek, []byte{})
+		if err != nil {
+			return nil, fmt.Errorf("kms_multi_envelope_aead: cannot wrap DEK with %s: %s", uri, err)
+		}
+		hash := sha256.Sum256([]byte(uri))
+		header.Write(hash[:])
+		lenBuf := make([]byte, lenDEK)
+		binary.BigEndian.PutUint32(lenBuf, uint32(len(wrappedDEK)))
+		header.Write(lenBuf)
+		header.Write(wrappedDEK)
+	}
+	primitive, err := a.dekPrimitive(dek)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := primitive.Encrypt(pt, payloadAssociatedData(header.Bytes(), aad))
+	if err != nil {
+		return nil, err
+	}
+	return append(header.Bytes(), payload...), nil
+}
+
+// Decrypt implements the tink.AEAD interface for decryption.
+func (a *MultiKMSEnvelopeAEAD) Decrypt(ct, aad []byte) ([]byte, error) {
+	return a.DecryptWithContext(context.Background(), ct, aad)
+}
+
+// DecryptWithContext implements the tink.AEADWithContext interface for decryption.
+// The DEK is unwrapped with the first KEK, in the configured order, that has a wrapped
+// DEK in the ciphertext and is available. The context is used for these unwrapping
+// attempts.
+func (a *MultiKMSEnvelopeAEAD) DecryptWithContext(ctx context.Context, ct, aad []byte) ([]byte, error) {
+	wrappedDEKs, headerLen, err := parseMultiEnvelopeHeader(ct)
+	if err != nil {
+		return nil, err
+	}
+	var errs []string
+	for _, uri := range a.keyURIs {
+		wrappedDEK, ok := wrappedDEKs[sha256.Sum256([]byte(uri))]
+		if !ok {
+			continue
+		}
+		kek, err := a.kek(uri)
+		if err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+		dek, err := kek.DecryptWithContext(ctx, wrappedDEK, []byte{})
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("cannot unwrap DEK with %s: %s", uri, err))
+			if ctx.Err() != nil {
+				break
+			}
+			continue
+		}
+		primitive, err := a.dekPrimitive(dek)
+		if err != nil {
+			return nil, err
+		}
+		return primitive.Decrypt(ct[headerLen:], payloadAssociatedData(ct[:headerLen], aad))
+	}
+	if len(errs) == 0 {
+		return nil, errors.New("kms_multi_envelope_aead: no DEK wrapped with a configured KEK")
+	}
+	return nil, fmt.Errorf("kms_multi_envelope_aead: cannot unwrap DEK: %v", errs)
+}
+
+// kek returns the AEAD of the KEK with the given URI.
+func (a *MultiKMSEnvelopeAEAD) kek(uri string) (tink.AEADWithContext, error) {
+	client, err := a.registry.GetKMSClient(uri)
+	if err != nil {
+		return nil, fmt.Errorf("kms_multi_envelope_aead: %s", err)
+	}
+	kek, err := client.GetAEAD(uri)
+	if err != nil {
+		return nil, fmt.Errorf("kms_multi_envelope_aead: cannot get KEK %s: %s", uri, err)
+	}
+	return NewAEADWithContext(kek), nil
+}
+
+// dekPrimitive returns the AEAD primitive for the given serialized DEK.
+func (a *MultiKMSEnvelopeAEAD) dekPrimitive(dek []byte) (tink.AEAD, error) {
+	p, err := a.registry.Primitive(a.dekTemplate.TypeUrl, dek)
+	if err != nil {
+		return nil, fmt.Errorf("kms_multi_envelope_aead: %s", err)
+	}
+	primitive, ok := p.(tink.AEAD)
+	if !ok {
+		return nil, errors.New("kms_multi_envelope_aead: failed to convert AEAD primitive")
+	}
+	return primitive, nil
+}
+
+// parseMultiEnvelopeHeader returns the wrapped DEKs by key URI hash and the length of the
+// header of the given ciphertext. A header that wraps the DEK twice for the same key URI
+// hash is invalid.
+func parseMultiEnvelopeHeader(ct []byte) (map[[keyURIHashSize]byte][]byte, int, error) {
+	if len(ct) < 2 || ct[0] != multiEnvelopeVersion || ct[1] == 0 {
+		return nil, 0, errInvalidMultiEnvelopeCiphertext
+	}
+	n := int(ct[1])
+	pos := 2
+	wrappedDEKs := make(map[[keyURIHashSize]byte][]byte, n)
+	for i := 0; i < n; i++ {
+		if len(ct)-pos < keyURIHashSize+lenDEK {
+			return nil, 0, errInvalidMultiEnvelopeCiphertext
+		}
+		var hash [keyURIHashSize]byte
+		copy(hash[:], ct[pos:pos+keyURIHashSize])
+		pos += keyURIHashSize
+		l := binary.BigEndian.Uint32(ct[pos : pos+lenDEK])
+		pos += lenDEK
+		if l == 0 || uint64(l) > uint64(len(ct)-pos) {
+			return nil, 0, errInvalidMultiEnvelopeCiphertext
+		}
+		if _, ok := wrappedDEKs[hash]; ok {
+			return nil, 0, errInvalidMultiEnvelopeCiphertext
+		}
+		wrappedDEKs[hash] = ct[pos : pos+int(l)]
+		pos += int(l)
+	}
+	return wrappedDEKs, pos, nil
+}
+
+// payloadAssociatedData returns the associated data of the payload, which binds the
+// header to the payload. The header is self-delimiting, so the concatenation is unambiguous.
+func payloadAssociatedData(header, aad []byte) []byte {
+	ret := make([]byte, 0, len(header)+len(aad))
+	ret = append(ret, header...)
+	return append(ret, aad...)
+}
@@ -0,0 +1,225 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package aead_test
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/tsingson/tink/golang/aead"
+	"github.com/tsingson/tink/golang/core/registry"
+	"github.com/tsingson/tink/golang/tink"
+)
+
+// regionKMSClient is a KMSClient for the key URIs with a given prefix, whose KMS can be
+// made unavailable.
+type regionKMSClient struct {
+	prefix string
+	kek    tink.AEAD
+	down   bool
+}
+
+var _ registry.KMSClient = (*regionKMSClient)(nil)
+
+func (c *regionKMSClient) Supported(keyURI string) bool {
+	return strings.HasPrefix(keyURI, c.prefix)
+}
+
+func (c *regionKMSClient) LoadCredentials(credentialPath string) (interface{}, error) {
+	return c, nil
+}
+
+func (c *regionKMSClient) LoadDefaultCredentials() (interface{}, error) {
+	return c, nil
+}
+
+func (c *regionKMSClient) GetAEAD(keyURI string) (tink.AEAD, error) {
+	return &regionAEAD{c}, nil
+}
+
+type regionAEAD struct {
+	c *regionKMSClient
+}
+
+func (a *regionAEAD) Encrypt(pt, ad []byte) ([]byte, error) {
+	if a.c.down {
+		return nil, errors.New("region unavailable")
+	}
+	return a.c.kek.Encrypt(pt, ad)
+}
+
+func (a *regionAEAD) Decrypt(ct, ad []byte) ([]byte, error) {
+	if a.c.down {
+		return nil, errors.New("region unavailable")
+	}
+	return a.c.kek.Decrypt(ct, ad)
+}
+
+func newMultiKMSRegistry(t *testing.T) (*registry.Registry, *regionKMSClient, *regionKMSClient) {
+	t.Helper()
+	r := registry.New()
+	east := &regionKMSClient{prefix: "east-kms://", kek: newSubtleAESGCM(t)}
+	west := &regionKMSClient{prefix: "west-kms://", kek: newSubtleAESGCM(t)}
+	r.RegisterKMSClient(east)
+	r.RegisterKMSClient(west)
+	return r, east, west
+}
+
+func TestMultiKMSEnvelopeAEAD(t *testing.T) {
+	r, east, west := newMultiKMSRegistry(t)
+	uris := []string{"east-kms://key", "west-kms://key"}
+	a, err := aead.NewMultiKMSEnvelopeAEADWithRegistry(*aead.AES128GCMKeyTemplate(), uris, r)
+	if err != nil {
+		t.Fatalf("aead.NewMultiKMSEnvelopeAEADWithRegistry() err = %v, want nil", err)
+	}
+	pt := []byte("plaintext")
+	ad := []byte("ad")
+	ct, err := a.Encrypt(pt, ad)
+	if err != nil {
+		t.Fatalf("a.Encrypt() err = %v, want nil", err)
+	}
+
+	for _, tc := range []struct {
+		name       string
+		eastDown   bool
+		westDown   bool
+		wantDecErr bool
+	}{
+		{name: "all available"},
+		{name: "east unavailable", eastDown: true},
+		{name: "west unavailable", westDown: true},
+		{name: "all unavailable", eastDown: true, westDown: true, wantDecErr: true},
+	} {
+		east.down, west.down = tc.eastDown, tc.westDown
+		got, err := a.Decrypt(ct, ad)
+		if tc.wantDecErr {
+			if err == nil {
+				t.Errorf("%s: a.Decrypt() err = nil, want error", tc.name)
+			}
+			continue
+		}
+		if err != nil || !bytes.Equal(got, pt) {
+			t.Errorf("%s: a.Decrypt() = %q, %v, want %q, nil", tc.name, got, err, pt)
+		}
+	}
+	east.down, west.down = false, false
+
+	// Encryption requires all KEKs.
+	west.down = true
+	if _, err := a.Encrypt(pt, ad); err == nil {
+		t.Errorf("a.Encrypt() with unavailable KEK: err = nil, want error")
+	}
+	west.down = false
+
+	// A reader that only knows one of the KEKs can decrypt.
+	westOnly, err := aead.NewMultiKMSEnvelopeAEADWithRegistry(*aead.AES128GCMKeyTemplate(), uris[1:], r)
+	if err != nil {
+		t.Fatalf("aead.NewMultiKMSEnvelopeAEADWithRegistry() err = %v, want nil", err)
+	}
+	if got, err := westOnly.Decrypt(ct, ad); err != nil || !bytes.Equal(got, pt) {
+		t.Errorf("westOnly.Decrypt() = %q, %v, want %q, nil", got, err, pt)
+	}
+	other, err := aead.NewMultiKMSEnvelopeAEADWithRegistry(*aead.AES128GCMKeyTemplate(), []string{"west-kms://other"}, r)
+	if err != nil {
+		t.Fatalf("aead.NewMultiKMSEnvelopeAEADWithRegistry() err = %v, want nil", err)
+	}
+	if _, err := other.Decrypt(ct, ad); err == nil {
+		t.Errorf("other.Decrypt() err = nil, want error for a ciphertext without its KEK")
+	}
+
+	if _, err := a.Decrypt(ct, []byte("wrong ad")); err == nil {
+		t.Errorf("a.Decrypt() err = nil, want error for wrong associated data")
+	}
+}
+
+func TestMultiKMSEnvelopeAEADRejectsModifiedCiphertext(t *testing.T) {
+	r, _, _ := newMultiKMSRegistry(t)
+	a, err := aead.NewMultiKMSEnvelopeAEADWithRegistry(*aead.AES128GCMKeyTemplate(), []string{"east-kms://key", "west-kms://key"}, r)
+	if err != nil {
+		t.Fatalf("aead.NewMultiKMSEnvelopeAEADWithRegistry() err = %v, want nil", err)
+	}
+	ct, err := a.Encrypt([]byte("plaintext"), nil)
+	if err != nil {
+		t.Fatalf("a.Encrypt() err = %v, want nil", err)
+	}
+	// Every truncation and every flipped bit must be rejected without panicking.
+	for i := 0; i < len(ct); i++ {
+		if _, err := a.Decrypt(ct[:i], nil); err == nil {
+			t.Errorf("a.Decrypt() of ciphertext truncated to %d bytes: err = nil, want error", i)
+		}
+		modified := append([]byte{}, ct...)
+		modified[i] ^= 1
+		if _, err := a.Decrypt(modified, nil); err == nil {
+			t.Errorf("a.Decrypt() of ciphertext modified at byte %d: err = nil, want error", i)
+		}
+	}
+}
+
+func TestMultiKMSEnvelopeAEADRejectsDuplicateKeyURIHash(t *testing.T) {
+	r, east, _ := newMultiKMSRegistry(t)
+	template := aead.AES128GCMKeyTemplate()
+	a, err := aead.NewMultiKMSEnvelopeAEADWithRegistry(*template, []string{"east-kms://key"}, r)
+	if err != nil {
+		t.Fatalf("aead.NewMultiKMSEnvelopeAEADWithRegistry() err = %v, want nil", err)
+	}
+	ct, err := a.Encrypt([]byte("plaintext"), nil)
+	if err != nil {
+		t.Fatalf("a.Encrypt() err = %v, want nil", err)
+	}
+	// Build an otherwise valid ciphertext whose header holds the single entry twice.
+	l := int(binary.BigEndian.Uint32(ct[2+sha256.Size:]))
+	entry := ct[2 : 2+sha256.Size+4+l]
+	dek, err := east.kek.Decrypt(entry[sha256.Size+4:], []byte{})
+	if err != nil {
+		t.Fatalf("east.kek.Decrypt() err = %v, want nil", err)
+	}
+	p, err := r.Primitive(template.TypeUrl, dek)
+	if err != nil {
+		t.Fatalf("r.Primitive() err = %v, want nil", err)
+	}
+	header := append(append([]byte{ct[0], 2}, entry...), entry...)
+	payload, err := p.(tink.AEAD).Encrypt([]byte("plaintext"), header)
+	if err != nil {
+		t.Fatalf("Encrypt() err = %v, want nil", err)
+	}
+	if _, err := a.Decrypt(append(header, payload...), nil); err == nil {
+		t.Errorf("a.Decrypt() of a header with a duplicate key URI hash: err = nil, want error")
+	}
+}
+
+func TestNewMultiKMSEnvelopeAEADWithInvalidInput(t *testing.T) {
+	r, _, _ := newMultiKMSRegistry(t)
+	kt := *aead.AES128GCMKeyTemplate()
+	if _, err := aead.NewMultiKMSEnvelopeAEADWithRegistry(kt, nil, r); err == nil {
+		t.Errorf("aead.NewMultiKMSEnvelopeAEADWithRegistry() with no key URIs: err = nil, want error")
+	}
+	if _, err := aead.NewMultiKMSEnvelopeAEADWithRegistry(kt, []string{"east-kms://key", "east-kms://key"}, r); err == nil {
+		t.Errorf("aead.NewMultiKMSEnvelopeAEADWithRegistry() with duplicate key URIs: err = nil, want error")
+	}
+	if _, err := aead.NewMultiKMSEnvelopeAEADWithRegistry(kt, []string{"east-kms://key"}, nil); err == nil {
+		t.Errorf("aead.NewMultiKMSEnvelopeAEADWithRegistry() with nil registry: err = nil, want error")
+	}
+	a, err := aead.NewMultiKMSEnvelopeAEADWithRegistry(kt, []string{"unknown-kms://key"}, r)
+	if err != nil {
+		t.Fatalf("aead.NewMultiKMSEnvelopeAEADWithRegistry() err = %v, want nil", err)
+	}
+	if _, err := a.Encrypt([]byte("plaintext"), nil); err == nil {
+		t.Errorf("a.Encrypt() without KMS client: err = nil, want error")
+	}
+}
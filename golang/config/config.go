@@ -0,0 +1,120 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+// Package config applies Tink registry configurations, as described by the
+// RegistryConfig protocol buffer, to the key manager registry.
+//
+// A RegistryConfig lists the key types an application uses. Register looks up
+// the key manager of each of them in its catalogue, limits the registry to
+// them and enforces the new_key_allowed setting of each entry, so that legacy
+// key types can still be used to decrypt or verify existing data but cannot be
+// used to create new keys. Named built-in configurations are available via Get.
+//
+// Example:
+//
+//	c, err := config.Get(config.TinkLatest)
+//	if err != nil {
+//		// handle error
+//	}
+//	if err := config.Register(c); err != nil {
+//		// handle error
+//	}
+package config
+
+import (
+	"fmt"
+
+	"github.com/tsingson/tink/golang/core/registry"
+	configpb "github.com/tsingson/tink/proto/config_go_proto"
+
+	// The primitive packages register the Tink key managers.
+	_ "github.com/tsingson/tink/golang/aead"
+	_ "github.com/tsingson/tink/golang/daead"
+	_ "github.com/tsingson/tink/golang/hybrid"
+	_ "github.com/tsingson/tink/golang/keywrap"
+	_ "github.com/tsingson/tink/golang/mac"
+	_ "github.com/tsingson/tink/golang/signature"
+	_ "github.com/tsingson/tink/golang/streamingaead"
+)
+
+// TinkCatalogue is the name of the catalogue of the key managers provided by Tink.
+const TinkCatalogue = registry.TinkCatalogueName
+
+// Register applies the given configuration to the default registry, which is then limited
+// to the configured key types: key managers are no longer created from the catalogues on
+// first use. Each entry must name a key type of the given primitive that is available from
+// the given catalogue, with a key manager of at least the given version; that key manager
+// is registered. New keys of a key type are disallowed if its entry has new_key_allowed
+// unset; once disallowed, they cannot be allowed again. The configuration is validated as
+// a whole and applied fully or not at all. Configurations are cumulative, so applying
+// several of them makes the key types of all of them available.
+func Register(c *configpb.RegistryConfig) error {
+	return RegisterWithRegistry(registry.Default(), c)
+}
+
+// RegisterWithRegistry applies the given configuration to the given registry.
+// See Register.
+func RegisterWithRegistry(r *registry.Registry, c *configpb.RegistryConfig) error {
+	kts, err := validate(r, c)
+	if err != nil {
+		return err
+	}
+	if err := r.Configure(kts); err != nil {
+		return fmt.Errorf("config: %s: %s", c.ConfigName, err)
+	}
+	return nil
+}
+
+// validate checks the given configuration against r and returns the key type
+// configurations it describes.
+func validate(r *registry.Registry, c *configpb.RegistryConfig) ([]registry.KeyTypeConfig, error) {
+	if r == nil || c == nil || c.ConfigName == "" {
+		return nil, fmt.Errorf("config: invalid registry config")
+	}
+	var kts []registry.KeyTypeConfig
+	denied := make(map[string]bool)
+	for _, e := range c.Entry {
+		km, err := validateEntry(r, e)
+		if err != nil {
+			return nil, fmt.Errorf("config: %s: %s", c.ConfigName, err)
+		}
+		if e.NewKeyAllowed && (denied[e.TypeUrl] || !r.NewKeyAllowed(e.TypeUrl)) {
+			return nil, fmt.Errorf("config: %s: new keys of type %s are already disallowed", c.ConfigName, e.TypeUrl)
+		}
+		if !e.NewKeyAllowed {
+			denied[e.TypeUrl] = true
+		}
+		kts = append(kts, registry.KeyTypeConfig{KeyManager: km, NewKeyAllowed: e.NewKeyAllowed})
+	}
+	return kts, nil
+}
+
+// validateEntry returns the key manager for the given entry from the catalogue it names.
+func validateEntry(r *registry.Registry, e *configpb.KeyTypeEntry) (registry.KeyManager, error) {
+	if e == nil || e.TypeUrl == "" || e.PrimitiveName == "" {
+		return nil, fmt.Errorf("invalid key type entry")
+	}
+	c, err := registry.GetCatalogue(e.CatalogueName)
+	if err != nil {
+		return nil, err
+	}
+	km, err := c.KeyManager(r, e.TypeUrl, e.PrimitiveName, e.KeyManagerVersion)
+	if err != nil {
+		return nil, err
+	}
+	if km.TypeURL() != e.TypeUrl {
+		return nil, fmt.Errorf("key manager of catalogue %s is for type %s, not %s", c.Name(), km.TypeURL(), e.TypeUrl)
+	}
+	return km, nil
+}
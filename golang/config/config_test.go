@@ -0,0 +1,353 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package config_test
+
+import (
+	"testing"
+
+	"github.com/tsingson/tink/golang/aead"
+	"github.com/tsingson/tink/golang/config"
+	"github.com/tsingson/tink/golang/core/registry"
+	"github.com/tsingson/tink/golang/keyset"
+	"github.com/tsingson/tink/golang/testutil"
+	configpb "github.com/tsingson/tink/proto/config_go_proto"
+)
+
+var builtinConfigs = []string{
+	config.Tink10,
+	config.TinkLatest,
+	config.AEADLatest,
+	config.DeterministicAEADLatest,
+	config.HybridLatest,
+	config.KeyWrapLatest,
+	config.MACLatest,
+	config.SignatureLatest,
+	config.StreamingAEADLatest,
+}
+
+func TestBuiltinConfigs(t *testing.T) {
+	for _, name := range builtinConfigs {
+		c, err := config.Get(name)
+		if err != nil {
+			t.Errorf("config.Get(%q) failed: %s", name, err)
+			continue
+		}
+		if c.ConfigName != name || len(c.Entry) == 0 {
+			t.Errorf("config %q: unexpected config %v", name, c)
+		}
+		for _, e := range c.Entry {
+			if !e.NewKeyAllowed {
+				t.Errorf("config %q: new keys of type %s should be allowed", name, e.TypeUrl)
+			}
+		}
+		if err := config.Register(c); err != nil {
+			t.Errorf("config.Register(%q) failed: %s", name, err)
+		}
+	}
+	if _, err := config.Get("UNKNOWN"); err == nil {
+		t.Errorf("expect an error when the config name is unknown")
+	}
+}
+
+func TestLatestConfigsCoverTinkLatest(t *testing.T) {
+	all, err := config.Get(config.TinkLatest)
+	if err != nil {
+		t.Fatalf("config.Get() failed: %s", err)
+	}
+	typeURLs := make(map[string]bool)
+	for _, name := range builtinConfigs[2:] {
+		c, err := config.Get(name)
+		if err != nil {
+			t.Fatalf("config.Get(%q) failed: %s", name, err)
+		}
+		for _, e := range c.Entry {
+			typeURLs[e.TypeUrl] = true
+		}
+	}
+	if len(typeURLs) != len(all.Entry) {
+		t.Errorf("primitive configs contain %d key types, %s contains %d", len(typeURLs), config.TinkLatest, len(all.Entry))
+	}
+	for _, e := range all.Entry {
+		if !typeURLs[e.TypeUrl] {
+			t.Errorf("key type %s is missing from the primitive configs", e.TypeUrl)
+		}
+	}
+}
+
+func TestRegisterWithInvalidConfig(t *testing.T) {
+	validEntry := func() *configpb.KeyTypeEntry {
+		return &configpb.KeyTypeEntry{
+			PrimitiveName: "Aead",
+			TypeUrl:       testutil.AESGCMTypeURL,
+			NewKeyAllowed: true,
+			CatalogueName: config.TinkCatalogue,
+		}
+	}
+	invalidEntries := []func(e *configpb.KeyTypeEntry){
+		func(e *configpb.KeyTypeEntry) { e.PrimitiveName = "" },
+		func(e *configpb.KeyTypeEntry) { e.PrimitiveName = "Mac" },
+		func(e *configpb.KeyTypeEntry) { e.TypeUrl = "" },
+		func(e *configpb.KeyTypeEntry) { e.TypeUrl = "some url" },
+		func(e *configpb.KeyTypeEntry) { e.CatalogueName = "Custom" },
+		func(e *configpb.KeyTypeEntry) { e.KeyManagerVersion = 1 },
+	}
+	for i, modify := range invalidEntries {
+		e := validEntry()
+		modify(e)
+		c := &configpb.RegistryConfig{ConfigName: "TEST", Entry: []*configpb.KeyTypeEntry{e}}
+		if err := config.Register(c); err == nil {
+			t.Errorf("expect an error in test case %d", i)
+		}
+	}
+	if err := config.Register(nil); err == nil {
+		t.Errorf("expect an error when the config is nil")
+	}
+	if err := config.Register(&configpb.RegistryConfig{}); err == nil {
+		t.Errorf("expect an error when the config has no name")
+	}
+	// primitive and catalogue names are case-insensitive
+	e := validEntry()
+	e.PrimitiveName = "AEAD"
+	e.CatalogueName = "tink"
+	c := &configpb.RegistryConfig{ConfigName: "TEST", Entry: []*configpb.KeyTypeEntry{e}}
+	if err := config.Register(c); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+}
+
+func TestRegisterWithCustomCatalogue(t *testing.T) {
+	const typeURL = "type.googleapis.com/custom.AeadKey"
+	c := registry.NewCatalogue("Custom")
+	if err := c.Add("Aead", typeURL, 1, func(*registry.Registry) registry.KeyManager { return new(customKeyManager) }); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if err := registry.RegisterCatalogue(c); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	e := &configpb.KeyTypeEntry{
+		PrimitiveName:     "Aead",
+		TypeUrl:           typeURL,
+		KeyManagerVersion: 1,
+		NewKeyAllowed:     true,
+		CatalogueName:     "custom",
+	}
+	cfg := &configpb.RegistryConfig{ConfigName: "CUSTOM", Entry: []*configpb.KeyTypeEntry{e}}
+	if err := config.Register(cfg); err != nil {
+		t.Fatalf("config.Register() failed: %s", err)
+	}
+	if _, err := registry.GetKeyManager(typeURL); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+	e.KeyManagerVersion = 2
+	if err := config.Register(cfg); err == nil {
+		t.Errorf("expect an error when the key manager version is not available")
+	}
+}
+
+func TestRegisterLimitsRegistry(t *testing.T) {
+	r := registry.New()
+	// used before the registry is configured
+	if _, err := r.GetKeyManager(testutil.AESGCMTypeURL); err != nil {
+		t.Fatalf("r.GetKeyManager() failed: %s", err)
+	}
+	mac, err := config.Get(config.MACLatest)
+	if err != nil {
+		t.Fatalf("config.Get() failed: %s", err)
+	}
+	if err := config.RegisterWithRegistry(r, mac); err != nil {
+		t.Fatalf("config.RegisterWithRegistry() failed: %s", err)
+	}
+	if _, err := r.GetKeyManager(testutil.HMACTypeURL); err != nil {
+		t.Errorf("r.GetKeyManager() of a configured key type failed: %s", err)
+	}
+	for _, typeURL := range []string{testutil.AESGCMTypeURL, testutil.AESEAXTypeURL} {
+		if _, err := r.GetKeyManager(typeURL); err == nil {
+			t.Errorf("r.GetKeyManager(%q) of a key type outside the config succeeded", typeURL)
+		}
+	}
+	if got := r.SupportedTypeURLs(); len(got) != len(mac.Entry) {
+		t.Errorf("r.SupportedTypeURLs() = %v, want the %d configured key types", got, len(mac.Entry))
+	}
+	if _, err := r.NewKeyData(aead.AES128GCMKeyTemplate()); err == nil {
+		t.Errorf("r.NewKeyData() of a key type outside the config succeeded")
+	}
+
+	// configurations are cumulative
+	a, err := config.Get(config.AEADLatest)
+	if err != nil {
+		t.Fatalf("config.Get() failed: %s", err)
+	}
+	if err := config.RegisterWithRegistry(r, a); err != nil {
+		t.Fatalf("config.RegisterWithRegistry() failed: %s", err)
+	}
+	for _, typeURL := range []string{testutil.HMACTypeURL, testutil.AESGCMTypeURL} {
+		if _, err := r.GetKeyManager(typeURL); err != nil {
+			t.Errorf("r.GetKeyManager(%q) failed: %s", typeURL, err)
+		}
+	}
+}
+
+func TestRegisterUsesNamedCatalogue(t *testing.T) {
+	km := new(otherHMACKeyManager)
+	c := registry.NewCatalogue("OtherHMAC")
+	if err := c.Add("Mac", testutil.HMACTypeURL, 0, func(*registry.Registry) registry.KeyManager { return km }); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if err := registry.RegisterCatalogue(c); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	r := registry.New()
+	cfg := &configpb.RegistryConfig{
+		ConfigName: "OTHER_HMAC",
+		Entry: []*configpb.KeyTypeEntry{{
+			PrimitiveName: "Mac",
+			TypeUrl:       testutil.HMACTypeURL,
+			NewKeyAllowed: true,
+			CatalogueName: "OtherHMAC",
+		}},
+	}
+	if err := config.RegisterWithRegistry(r, cfg); err != nil {
+		t.Fatalf("config.RegisterWithRegistry() failed: %s", err)
+	}
+	got, err := r.GetKeyManager(testutil.HMACTypeURL)
+	if err != nil {
+		t.Fatalf("r.GetKeyManager() failed: %s", err)
+	}
+	if got != registry.KeyManager(km) {
+		t.Errorf("r.GetKeyManager() = %T, want the key manager of the named catalogue", got)
+	}
+}
+
+func TestRegisterIsAtomic(t *testing.T) {
+	r := registry.New()
+	entry := func(typeURL, primitive string, newKeyAllowed bool) *configpb.KeyTypeEntry {
+		return &configpb.KeyTypeEntry{
+			PrimitiveName: primitive,
+			TypeUrl:       typeURL,
+			NewKeyAllowed: newKeyAllowed,
+			CatalogueName: config.TinkCatalogue,
+		}
+	}
+	legacy := &configpb.RegistryConfig{
+		ConfigName: "LEGACY_GCM",
+		Entry:      []*configpb.KeyTypeEntry{entry(testutil.AESGCMTypeURL, "Aead", false)},
+	}
+	if err := config.RegisterWithRegistry(r, legacy); err != nil {
+		t.Fatalf("config.RegisterWithRegistry() failed: %s", err)
+	}
+	invalid := []*configpb.RegistryConfig{
+		{
+			ConfigName: "ALLOW_GCM",
+			Entry: []*configpb.KeyTypeEntry{
+				entry(testutil.HMACTypeURL, "Mac", false),
+				entry(testutil.AESGCMTypeURL, "Aead", true),
+			},
+		},
+		{
+			ConfigName: "DENY_THEN_ALLOW_EAX",
+			Entry: []*configpb.KeyTypeEntry{
+				entry(testutil.HMACTypeURL, "Mac", false),
+				entry(testutil.AESEAXTypeURL, "Aead", false),
+				entry(testutil.AESEAXTypeURL, "Aead", true),
+			},
+		},
+	}
+	for _, c := range invalid {
+		if err := config.RegisterWithRegistry(r, c); err == nil {
+			t.Errorf("config %s: expect an error when new keys are allowed again", c.ConfigName)
+		}
+	}
+	// nothing of the invalid configs was applied
+	for _, typeURL := range []string{testutil.HMACTypeURL, testutil.AESEAXTypeURL} {
+		if _, err := r.GetKeyManager(typeURL); err == nil {
+			t.Errorf("r.GetKeyManager(%q) succeeded after a failed config", typeURL)
+		}
+		if !r.NewKeyAllowed(typeURL) {
+			t.Errorf("r.NewKeyAllowed(%q) = false after a failed config", typeURL)
+		}
+	}
+}
+
+// otherHMACKeyManager is a key manager for the HMAC key type from a catalogue
+// other than the Tink catalogue.
+type otherHMACKeyManager struct {
+	testutil.DummyAEADKeyManager
+}
+
+func (km *otherHMACKeyManager) TypeURL() string {
+	return testutil.HMACTypeURL
+}
+
+// customKeyManager is a key manager for a key type outside of the Tink catalogue.
+type customKeyManager struct {
+	testutil.DummyAEADKeyManager
+}
+
+func (km *customKeyManager) DoesSupport(typeURL string) bool {
+	return typeURL == km.TypeURL()
+}
+
+func (km *customKeyManager) TypeURL() string {
+	return "type.googleapis.com/custom.AeadKey"
+}
+
+// TestNewKeyNotAllowed disallows new AES-EAX keys for the rest of the test
+// binary, so it must run last.
+func TestNewKeyNotAllowed(t *testing.T) {
+	kh, err := keyset.NewHandle(aead.AES128EAXKeyTemplate())
+	if err != nil {
+		t.Fatalf("keyset.NewHandle() failed: %s", err)
+	}
+	a, err := aead.New(kh)
+	if err != nil {
+		t.Fatalf("aead.New() failed: %s", err)
+	}
+	ct, err := a.Encrypt([]byte("plaintext"), []byte("aad"))
+	if err != nil {
+		t.Fatalf("encryption failed: %s", err)
+	}
+
+	c := &configpb.RegistryConfig{
+		ConfigName: "LEGACY_EAX",
+		Entry: []*configpb.KeyTypeEntry{{
+			PrimitiveName: "Aead",
+			TypeUrl:       testutil.AESEAXTypeURL,
+			NewKeyAllowed: false,
+			CatalogueName: config.TinkCatalogue,
+		}},
+	}
+	if err := config.Register(c); err != nil {
+		t.Fatalf("config.Register() failed: %s", err)
+	}
+	if registry.NewKeyAllowed(testutil.AESEAXTypeURL) {
+		t.Errorf("new keys of type %s should not be allowed", testutil.AESEAXTypeURL)
+	}
+	if _, err := keyset.NewHandle(aead.AES128EAXKeyTemplate()); err == nil {
+		t.Errorf("expect an error when creating a key whose type disallows new keys")
+	}
+	// existing keys keep working
+	a, err = aead.New(kh)
+	if err != nil {
+		t.Fatalf("aead.New() failed: %s", err)
+	}
+	if _, err := a.Decrypt(ct, []byte("aad")); err != nil {
+		t.Errorf("decryption failed: %s", err)
+	}
+	// new keys cannot be allowed again
+	latest, _ := config.Get(config.AEADLatest)
+	if err := config.Register(latest); err == nil {
+		t.Errorf("expect an error when allowing new keys again")
+	}
+}
@@ -0,0 +1,127 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package config
+
+import (
+	"fmt"
+
+	"github.com/tsingson/tink/golang/core/registry"
+	configpb "github.com/tsingson/tink/proto/config_go_proto"
+)
+
+// Names of the built-in configurations.
+const (
+	// Tink10 contains the key types available in Tink 1.0.
+	Tink10 = "TINK_1_0"
+	// TinkLatest contains all key types provided by Tink.
+	TinkLatest = "TINK_LATEST"
+	// AEADLatest contains all AEAD key types.
+	AEADLatest = "AEAD_LATEST"
+	// DeterministicAEADLatest contains all deterministic AEAD key types.
+	DeterministicAEADLatest = "DETERMINISTIC_AEAD_LATEST"
+	// HybridLatest contains all hybrid encryption key types, and the AEAD key types
+	// they use to encrypt the payload.
+	HybridLatest = "HYBRID_LATEST"
+	// KeyWrapLatest contains all key wrapping key types.
+	KeyWrapLatest = "KEY_WRAP_LATEST"
+	// MACLatest contains all MAC key types.
+	MACLatest = "MAC_LATEST"
+	// SignatureLatest contains all digital signature key types.
+	SignatureLatest = "SIGNATURE_LATEST"
+	// StreamingAEADLatest contains all streaming AEAD key types.
+	StreamingAEADLatest = "STREAMING_AEAD_LATEST"
+)
+
+// Primitive names, as used in KeyTypeEntry.
+const (
+	aeadPrimitive              = "Aead"
+	deterministicAEADPrimitive = "DeterministicAead"
+	hybridDecryptPrimitive     = "HybridDecrypt"
+	hybridEncryptPrimitive     = "HybridEncrypt"
+	keyWrapPrimitive           = "KeyWrap"
+	macPrimitive               = "Mac"
+	publicKeySignPrimitive     = "PublicKeySign"
+	publicKeyVerifyPrimitive   = "PublicKeyVerify"
+	streamingAEADPrimitive     = "StreamingAead"
+)
+
+const typeURLPrefix = "type.googleapis.com/google.crypto.tink."
+
+// latest maps the primitive-specific built-in configurations to their primitives.
+var latest = map[string][]string{
+	AEADLatest:              {aeadPrimitive},
+	DeterministicAEADLatest: {deterministicAEADPrimitive},
+	HybridLatest:            {aeadPrimitive, hybridDecryptPrimitive, hybridEncryptPrimitive},
+	KeyWrapLatest:           {keyWrapPrimitive},
+	MACLatest:               {macPrimitive},
+	SignatureLatest:         {publicKeySignPrimitive, publicKeyVerifyPrimitive},
+	StreamingAEADLatest:     {streamingAEADPrimitive},
+}
+
+// Get returns a new copy of the built-in configuration with the given name.
+// The latest configurations contain all key types of the Tink catalogue.
+func Get(name string) (*configpb.RegistryConfig, error) {
+	c := &configpb.RegistryConfig{ConfigName: name}
+	tink := registry.TinkCatalogue()
+	switch name {
+	case Tink10:
+		c.Entry = tink10Entries()
+	case TinkLatest:
+		for _, primitive := range tink.Primitives() {
+			c.Entry = append(c.Entry, entries(primitive, tink.TypeURLs(primitive))...)
+		}
+	default:
+		primitives, ok := latest[name]
+		if !ok {
+			return nil, fmt.Errorf("config: unknown config %q", name)
+		}
+		for _, primitive := range primitives {
+			c.Entry = append(c.Entry, entries(primitive, tink.TypeURLs(primitive))...)
+		}
+	}
+	return c, nil
+}
+
+// tink10Entries returns the key types that were available in Tink 1.0.
+func tink10Entries() []*configpb.KeyTypeEntry {
+	var e []*configpb.KeyTypeEntry
+	e = append(e, entries(aeadPrimitive, []string{
+		typeURLPrefix + "AesCtrHmacAeadKey",
+		typeURLPrefix + "AesEaxKey",
+		typeURLPrefix + "AesGcmKey",
+		typeURLPrefix + "KmsAeadKey",
+		typeURLPrefix + "KmsEnvelopeAeadKey",
+	})...)
+	e = append(e, entries(hybridDecryptPrimitive, []string{typeURLPrefix + "EciesAeadHkdfPrivateKey"})...)
+	e = append(e, entries(hybridEncryptPrimitive, []string{typeURLPrefix + "EciesAeadHkdfPublicKey"})...)
+	e = append(e, entries(macPrimitive, []string{typeURLPrefix + "HmacKey"})...)
+	e = append(e, entries(publicKeySignPrimitive, []string{typeURLPrefix + "EcdsaPrivateKey"})...)
+	e = append(e, entries(publicKeyVerifyPrimitive, []string{typeURLPrefix + "EcdsaPublicKey"})...)
+	return e
+}
+
+func entries(primitive string, typeURLs []string) []*configpb.KeyTypeEntry {
+	e := make([]*configpb.KeyTypeEntry, len(typeURLs))
+	for i, typeURL := range typeURLs {
+		e[i] = &configpb.KeyTypeEntry{
+			PrimitiveName:     primitive,
+			TypeUrl:           typeURL,
+			KeyManagerVersion: 0,
+			NewKeyAllowed:     true,
+			CatalogueName:     TinkCatalogue,
+		}
+	}
+	return e
+}
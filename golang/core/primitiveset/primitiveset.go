@@ -21,20 +21,23 @@ import (
 	"fmt"
 
 	"github.com/tsingson/tink/golang/core/cryptofmt"
+	"github.com/tsingson/tink/golang/monitoring"
 	tinkpb "github.com/tsingson/tink/proto/tink_go_proto"
 )
 
 // Entry represents a single entry in the keyset. In addition to the actual primitive,
 // it holds the identifier and status of the primitive.
 type Entry struct {
+	KeyID      uint32
 	Primitive  interface{}
 	Prefix     string
 	PrefixType tinkpb.OutputPrefixType
 	Status     tinkpb.KeyStatusType
 }
 
-func newEntry(p interface{}, prefix string, prefixType tinkpb.OutputPrefixType, status tinkpb.KeyStatusType) *Entry {
+func newEntry(keyID uint32, p interface{}, prefix string, prefixType tinkpb.OutputPrefixType, status tinkpb.KeyStatusType) *Entry {
 	return &Entry{
+		KeyID:      keyID,
 		Primitive:  p,
 		Prefix:     prefix,
 		Status:     status,
@@ -42,6 +45,21 @@ func newEntry(p interface{}, prefix string, prefixType tinkpb.OutputPrefixType,
 	}
 }
 
+// Prefixed returns the concatenation of the output prefix of the entry and the given output
+// of its primitive.
+func (e *Entry) Prefixed(output []byte) []byte {
+	ret := make([]byte, 0, len(e.Prefix)+len(output))
+	ret = append(ret, e.Prefix...)
+	return append(ret, output...)
+}
+
+// Candidate is an entry that may have produced a given output, together with the part of
+// the output that its primitive has to process.
+type Candidate struct {
+	Entry  *Entry
+	Output []byte
+}
+
 // PrimitiveSet is used for supporting key rotation: primitives in a set correspond to keys in a
 // keyset. Users will usually work with primitive instances, which essentially wrap primitive
 // sets. For example an instance of an AEAD-primitive for a given keyset holds a set of
@@ -57,6 +75,10 @@ type PrimitiveSet struct {
 	// The primitives are stored in a map of (ciphertext prefix, list of primitives sharing the
 	// prefix). This allows quickly retrieving the primitives sharing some particular prefix.
 	Entries map[string][]*Entry
+
+	// Monitor is notified of the operations performed with the primitives. If nil, the
+	// global monitor is used.
+	Monitor monitoring.Monitor
 }
 
 // New returns an empty instance of PrimitiveSet.
@@ -81,6 +103,37 @@ func (ps *PrimitiveSet) EntriesForPrefix(prefix string) ([]*Entry, error) {
 	return result, nil
 }
 
+// Candidates returns the entries that may have produced the given output, e.g. a ciphertext
+// or a tag, in the order in which they should be tried: the entries whose prefix matches the
+// beginning of the output, paired with the output without the prefix, followed by the raw
+// entries, paired with the whole output.
+func (ps *PrimitiveSet) Candidates(output []byte) []Candidate {
+	var ret []Candidate
+	if len(output) > cryptofmt.NonRawPrefixSize {
+		prefix := string(output[:cryptofmt.NonRawPrefixSize])
+		for _, e := range ps.Entries[prefix] {
+			ret = append(ret, Candidate{Entry: e, Output: output[cryptofmt.NonRawPrefixSize:]})
+		}
+	}
+	for _, e := range ps.Entries[cryptofmt.RawPrefix] {
+		ret = append(ret, Candidate{Entry: e, Output: output})
+	}
+	return ret
+}
+
+// All reports whether every primitive in the set satisfies f. Primitive wrappers use it to
+// check that the set holds primitives of the kind they wrap.
+func (ps *PrimitiveSet) All(f func(p interface{}) bool) bool {
+	for _, entries := range ps.Entries {
+		for _, e := range entries {
+			if !f(e.Primitive) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
 // Add creates a new entry in the primitive set and returns the added entry.
 func (ps *PrimitiveSet) Add(p interface{}, key *tinkpb.Keyset_Key) (*Entry, error) {
 	if key == nil || p == nil {
@@ -93,7 +146,37 @@ func (ps *PrimitiveSet) Add(p interface{}, key *tinkpb.Keyset_Key) (*Entry, erro
 	if err != nil {
 		return nil, fmt.Errorf("primitive_set: %s", err)
 	}
-	e := newEntry(p, prefix, key.OutputPrefixType, key.Status)
+	e := newEntry(key.KeyId, p, prefix, key.OutputPrefixType, key.Status)
 	ps.Entries[prefix] = append(ps.Entries[prefix], e)
 	return e, nil
 }
+
+// Log reports an operation performed with the primitive of the given entry to the
+// monitor of the set, or to the global monitor if the set has none.
+func (ps *PrimitiveSet) Log(e *Entry, primitive, op string, numBytes int, success bool) {
+	m := ps.Monitor
+	if m == nil {
+		m = monitoring.Global()
+	}
+	if m == nil {
+		return
+	}
+	var keyID uint32
+	if e != nil {
+		keyID = e.KeyID
+	}
+	m.Log(keyID, primitive, op, numBytes, success)
+}
+
+// LogFailure reports a failed operation on the given output, e.g. a ciphertext, like Log.
+// The failure is attributed to the first key identified by the prefix of the output, or
+// to no key if there is none.
+func (ps *PrimitiveSet) LogFailure(output []byte, primitive, op string, numBytes int) {
+	var e *Entry
+	if len(output) >= cryptofmt.NonRawPrefixSize {
+		if entries := ps.Entries[string(output[:cryptofmt.NonRawPrefixSize])]; len(entries) > 0 {
+			e = entries[0]
+		}
+	}
+	ps.Log(e, primitive, op, numBytes, false)
+}
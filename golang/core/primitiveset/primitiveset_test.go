@@ -113,6 +113,50 @@ func TestPrimitiveSetBasic(t *testing.T) {
 	}
 }
 
+func TestCandidates(t *testing.T) {
+	ps := primitiveset.New()
+	keys := createKeyset()
+	entries := make([]*primitiveset.Entry, len(keys))
+	for i, key := range keys {
+		var err error
+		entries[i], err = ps.Add(testutil.DummyMAC{Name: fmt.Sprintf("Mac#%d", i)}, key)
+		if err != nil {
+			t.Fatalf("ps.Add() err = %v", err)
+		}
+	}
+	// keys 0 and 5 share a TINK prefix; keys 3 and 4 are raw
+	output := entries[0].Prefixed([]byte("output"))
+	if !reflect.DeepEqual(output, append([]byte(entries[0].Prefix), "output"...)) {
+		t.Errorf("entries[0].Prefixed() = %x, want the prefix followed by the output", output)
+	}
+	got := ps.Candidates(output)
+	want := []primitiveset.Candidate{
+		{Entry: entries[0], Output: []byte("output")},
+		{Entry: entries[5], Output: []byte("output")},
+		{Entry: entries[3], Output: output},
+		{Entry: entries[4], Output: output},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ps.Candidates() = %v, want %v", got, want)
+	}
+	// an output no longer than a prefix only matches raw keys
+	got = ps.Candidates(output[:cryptofmt.NonRawPrefixSize])
+	if len(got) != 2 || got[0].Entry != entries[3] || got[1].Entry != entries[4] {
+		t.Errorf("ps.Candidates() of a prefix = %v, want the raw entries", got)
+	}
+
+	isMAC := func(p interface{}) bool {
+		_, ok := p.(testutil.DummyMAC)
+		return ok
+	}
+	if !ps.All(isMAC) {
+		t.Errorf("ps.All() = false, want true")
+	}
+	if ps.All(func(p interface{}) bool { return false }) {
+		t.Errorf("ps.All() = true, want false")
+	}
+}
+
 func TestAddWithInvalidInput(t *testing.T) {
 	ps := primitiveset.New()
 	// nil input
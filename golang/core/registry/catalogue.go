@@ -0,0 +1,218 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package registry
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// TinkCatalogueName is the name of the catalogue of the key managers provided by Tink.
+const TinkCatalogueName = "Tink"
+
+var (
+	cataloguesMu sync.RWMutex
+	catalogues   = []*Catalogue{NewCatalogue(TinkCatalogueName)}
+)
+
+// Catalogue holds the key managers that can be registered for a set of key types,
+// together with the primitive and the version of each key manager. Key managers
+// in a catalogue are only created when their key type is first used.
+type Catalogue struct {
+	name    string
+	mu      sync.RWMutex
+	entries map[string]*catalogueEntry // typeURL -> entry
+}
+
+type catalogueEntry struct {
+	primitiveName string
+	version       uint32
+	newKeyManager func(r *Registry) KeyManager
+}
+
+// NewCatalogue creates an empty catalogue with the given name.
+func NewCatalogue(name string) *Catalogue {
+	return &Catalogue{
+		name:    name,
+		entries: make(map[string]*catalogueEntry),
+	}
+}
+
+// Name returns the name of the catalogue.
+func (c *Catalogue) Name() string {
+	return c.name
+}
+
+// Add makes the key manager created by newKeyManager available for the given key
+// type and primitive. newKeyManager is given the Registry the key manager is created
+// for, which key managers of compound key types use to resolve their components.
+// Does not allow to overwrite existing entries.
+func (c *Catalogue) Add(primitiveName, typeURL string, version uint32, newKeyManager func(r *Registry) KeyManager) error {
+	if primitiveName == "" || typeURL == "" || newKeyManager == nil {
+		return fmt.Errorf("registry.Catalogue.Add: invalid entry")
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, existed := c.entries[typeURL]; existed {
+		return fmt.Errorf("registry.Catalogue.Add: type %s already in catalogue %s", typeURL, c.name)
+	}
+	c.entries[typeURL] = &catalogueEntry{
+		primitiveName: primitiveName,
+		version:       version,
+		newKeyManager: newKeyManager,
+	}
+	return nil
+}
+
+// KeyManager creates a key manager for the given key type and Registry. The key type
+// must belong to the given primitive, and the version of its key manager must be at
+// least minVersion. Primitive names are case-insensitive.
+func (c *Catalogue) KeyManager(r *Registry, typeURL, primitiveName string, minVersion uint32) (KeyManager, error) {
+	c.mu.RLock()
+	e, existed := c.entries[typeURL]
+	c.mu.RUnlock()
+	if !existed {
+		return nil, fmt.Errorf("registry.Catalogue.KeyManager: type %s not in catalogue %s", typeURL, c.name)
+	}
+	if !strings.EqualFold(e.primitiveName, primitiveName) {
+		return nil, fmt.Errorf("registry.Catalogue.KeyManager: type %s is for primitive %s, not %s", typeURL, e.primitiveName, primitiveName)
+	}
+	if e.version < minVersion {
+		return nil, fmt.Errorf("registry.Catalogue.KeyManager: version %d of type %s is older than the requested version %d", e.version, typeURL, minVersion)
+	}
+	return e.newKeyManager(r), nil
+}
+
+// PrimitiveName returns the primitive of the given key type.
+func (c *Catalogue) PrimitiveName(typeURL string) (string, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	e, existed := c.entries[typeURL]
+	if !existed {
+		return "", fmt.Errorf("registry.Catalogue.PrimitiveName: type %s not in catalogue %s", typeURL, c.name)
+	}
+	return e.primitiveName, nil
+}
+
+// TypeURLs returns the sorted type URLs of the key types of the given primitive,
+// or of all key types if primitiveName is empty.
+func (c *Catalogue) TypeURLs(primitiveName string) []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	var typeURLs []string
+	for typeURL, e := range c.entries {
+		if primitiveName == "" || strings.EqualFold(e.primitiveName, primitiveName) {
+			typeURLs = append(typeURLs, typeURL)
+		}
+	}
+	sort.Strings(typeURLs)
+	return typeURLs
+}
+
+// Primitives returns the sorted names of the primitives in the catalogue.
+func (c *Catalogue) Primitives() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	seen := make(map[string]bool)
+	var primitives []string
+	for _, e := range c.entries {
+		if !seen[e.primitiveName] {
+			seen[e.primitiveName] = true
+			primitives = append(primitives, e.primitiveName)
+		}
+	}
+	sort.Strings(primitives)
+	return primitives
+}
+
+func (c *Catalogue) contains(typeURL string) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	_, existed := c.entries[typeURL]
+	return existed
+}
+
+func (c *Catalogue) newKeyManager(r *Registry, typeURL string) (KeyManager, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	e, existed := c.entries[typeURL]
+	if !existed {
+		return nil, false
+	}
+	return e.newKeyManager(r), true
+}
+
+// RegisterCatalogue registers the given catalogue. Catalogue names are case-insensitive,
+// and do not allow to overwrite existing catalogues.
+func RegisterCatalogue(c *Catalogue) error {
+	if c == nil || c.name == "" {
+		return fmt.Errorf("registry.RegisterCatalogue: invalid catalogue")
+	}
+	cataloguesMu.Lock()
+	defer cataloguesMu.Unlock()
+	for _, existing := range catalogues {
+		if strings.EqualFold(existing.name, c.name) {
+			return fmt.Errorf("registry.RegisterCatalogue: catalogue %s already registered", c.name)
+		}
+	}
+	catalogues = append(catalogues, c)
+	return nil
+}
+
+// GetCatalogue returns the catalogue with the given case-insensitive name.
+func GetCatalogue(name string) (*Catalogue, error) {
+	cataloguesMu.RLock()
+	defer cataloguesMu.RUnlock()
+	for _, c := range catalogues {
+		if strings.EqualFold(c.name, name) {
+			return c, nil
+		}
+	}
+	return nil, fmt.Errorf("registry.GetCatalogue: unknown catalogue %s", name)
+}
+
+// TinkCatalogue returns the catalogue of the key managers provided by Tink.
+// The primitive packages add their key managers to it when they are imported.
+func TinkCatalogue() *Catalogue {
+	cataloguesMu.RLock()
+	defer cataloguesMu.RUnlock()
+	return catalogues[0]
+}
+
+// catalogueKeyManager creates a key manager for typeURL and r from the first registered
+// catalogue that contains it.
+func catalogueKeyManager(r *Registry, typeURL string) (KeyManager, bool) {
+	cataloguesMu.RLock()
+	defer cataloguesMu.RUnlock()
+	for _, c := range catalogues {
+		if km, ok := c.newKeyManager(r, typeURL); ok {
+			return km, true
+		}
+	}
+	return nil, false
+}
+
+func inCatalogue(typeURL string) bool {
+	cataloguesMu.RLock()
+	defer cataloguesMu.RUnlock()
+	for _, c := range catalogues {
+		if c.contains(typeURL) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,166 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package registry_test
+
+import (
+	"testing"
+
+	"github.com/tsingson/tink/golang/core/registry"
+	"github.com/tsingson/tink/golang/testutil"
+)
+
+// customKeyManager is a key manager for a key type outside of the Tink catalogue.
+type customKeyManager struct {
+	testutil.DummyAEADKeyManager
+	typeURL string
+}
+
+func (km *customKeyManager) DoesSupport(typeURL string) bool {
+	return typeURL == km.typeURL
+}
+
+func (km *customKeyManager) TypeURL() string {
+	return km.typeURL
+}
+
+func TestCatalogue(t *testing.T) {
+	const (
+		aeadURL = "type.googleapis.com/custom.AeadKey"
+		macURL  = "type.googleapis.com/custom.MacKey"
+	)
+	c := registry.NewCatalogue("Custom")
+	if c.Name() != "Custom" {
+		t.Errorf("incorrect catalogue name: %s", c.Name())
+	}
+	newAEAD := func(*registry.Registry) registry.KeyManager { return &customKeyManager{typeURL: aeadURL} }
+	newMAC := func(*registry.Registry) registry.KeyManager { return &customKeyManager{typeURL: macURL} }
+	if err := c.Add("Aead", aeadURL, 1, newAEAD); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if err := c.Add("Mac", macURL, 0, newMAC); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if err := c.Add("Aead", aeadURL, 2, newAEAD); err == nil {
+		t.Errorf("expect an error when adding a type twice")
+	}
+	if err := c.Add("", "some url", 0, newAEAD); err == nil {
+		t.Errorf("expect an error when the primitive name is empty")
+	}
+	if err := c.Add("Aead", "some url", 0, nil); err == nil {
+		t.Errorf("expect an error when the factory is nil")
+	}
+
+	km, err := c.KeyManager(registry.Default(), aeadURL, "AEAD", 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if km.TypeURL() != aeadURL {
+		t.Errorf("incorrect key manager")
+	}
+	if _, err := c.KeyManager(registry.Default(), aeadURL, "Mac", 0); err == nil {
+		t.Errorf("expect an error when the primitive does not match")
+	}
+	if _, err := c.KeyManager(registry.Default(), aeadURL, "Aead", 2); err == nil {
+		t.Errorf("expect an error when the version is too old")
+	}
+	if _, err := c.KeyManager(registry.Default(), "some url", "Aead", 0); err == nil {
+		t.Errorf("expect an error when the type is not in the catalogue")
+	}
+	if p, err := c.PrimitiveName(macURL); err != nil || p != "Mac" {
+		t.Errorf("PrimitiveName(%s) = %q, %v", macURL, p, err)
+	}
+	if got := c.TypeURLs("aead"); len(got) != 1 || got[0] != aeadURL {
+		t.Errorf("TypeURLs(aead) = %v", got)
+	}
+	if got := c.TypeURLs(""); len(got) != 2 {
+		t.Errorf("TypeURLs() = %v", got)
+	}
+	if got := c.Primitives(); len(got) != 2 || got[0] != "Aead" || got[1] != "Mac" {
+		t.Errorf("Primitives() = %v", got)
+	}
+}
+
+func TestRegisterCatalogue(t *testing.T) {
+	const typeURL = "type.googleapis.com/lazy.AeadKey"
+	created := 0
+	c := registry.NewCatalogue("Lazy")
+	err := c.Add("Aead", typeURL, 0, func(*registry.Registry) registry.KeyManager {
+		created++
+		return &customKeyManager{typeURL: typeURL}
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if _, err := registry.GetKeyManager(typeURL); err == nil {
+		t.Errorf("expect an error before the catalogue is registered")
+	}
+	if err := registry.RegisterCatalogue(c); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if err := registry.RegisterCatalogue(registry.NewCatalogue("LAZY")); err == nil {
+		t.Errorf("expect an error when registering a catalogue twice")
+	}
+	if err := registry.RegisterCatalogue(nil); err == nil {
+		t.Errorf("expect an error when the catalogue is nil")
+	}
+	if got, err := registry.GetCatalogue("lazy"); err != nil || got != c {
+		t.Errorf("GetCatalogue(lazy) = %v, %v", got, err)
+	}
+	if _, err := registry.GetCatalogue("unknown"); err == nil {
+		t.Errorf("expect an error when the catalogue is unknown")
+	}
+	if created != 0 {
+		t.Errorf("key managers must only be created on first use")
+	}
+	for i := 0; i < 2; i++ {
+		km, err := registry.GetKeyManager(typeURL)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if km.TypeURL() != typeURL {
+			t.Errorf("incorrect key manager")
+		}
+	}
+	if created != 1 {
+		t.Errorf("key manager was created %d times, want 1", created)
+	}
+	if err := registry.RegisterKeyManager(&customKeyManager{typeURL: typeURL}); err == nil {
+		t.Errorf("expect an error when registering a key manager for a catalogued type")
+	}
+	supported := make(map[string]bool)
+	for _, u := range registry.SupportedTypeURLs() {
+		supported[u] = true
+	}
+	if !supported[typeURL] || !supported[testutil.AESGCMTypeURL] {
+		t.Errorf("SupportedTypeURLs() is missing key types")
+	}
+}
+
+func TestTinkCatalogue(t *testing.T) {
+	tink, err := registry.GetCatalogue("tink")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if tink != registry.TinkCatalogue() || tink.Name() != registry.TinkCatalogueName {
+		t.Errorf("incorrect Tink catalogue")
+	}
+	if p, err := tink.PrimitiveName(testutil.AESGCMTypeURL); err != nil || p != "Aead" {
+		t.Errorf("PrimitiveName(%s) = %q, %v", testutil.AESGCMTypeURL, p, err)
+	}
+	if p, err := tink.PrimitiveName(testutil.HMACTypeURL); err != nil || p != "Mac" {
+		t.Errorf("PrimitiveName(%s) = %q, %v", testutil.HMACTypeURL, p, err)
+	}
+
+}
@@ -0,0 +1,35 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package registry
+
+import "github.com/tsingson/tink/golang/core/primitiveset"
+
+// PrimitiveWrapper combines the primitives of a keyset into a single primitive of the same
+// kind. The combined primitive uses the primary key for operations that produce output,
+// prefixed with the primary's identifier, and selects the keys matching the identifier
+// of its input, followed by the raw keys, for operations that consume output.
+//
+// The primitive packages register a PrimitiveWrapper for their primitive when imported.
+// Custom primitives can register their own to get the same keyset rotation semantics:
+// primitiveset.Entry.Prefixed prefixes the output of the primary, PrimitiveSet.Candidates
+// selects the entries to try on an input and PrimitiveSet.All checks the primitive types.
+type PrimitiveWrapper interface {
+	// PrimitiveName returns the name of the wrapped primitive, e.g. "Aead". It is the same
+	// name under which the key managers of the primitive are added to a Catalogue.
+	PrimitiveName() string
+
+	// Wrap returns a primitive that delegates to the primitives in the given primitive set.
+	Wrap(ps *primitiveset.PrimitiveSet) (interface{}, error)
+}
@@ -0,0 +1,107 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package registry_test
+
+import (
+	"testing"
+
+	_ "github.com/tsingson/tink/golang/aead"
+	"github.com/tsingson/tink/golang/core/primitiveset"
+	"github.com/tsingson/tink/golang/core/registry"
+	"github.com/tsingson/tink/golang/testutil"
+	"github.com/tsingson/tink/golang/tink"
+	tinkpb "github.com/tsingson/tink/proto/tink_go_proto"
+)
+
+type countingWrapper struct {
+	name  string
+	calls int
+}
+
+func (w *countingWrapper) PrimitiveName() string {
+	return w.name
+}
+
+func (w *countingWrapper) Wrap(ps *primitiveset.PrimitiveSet) (interface{}, error) {
+	w.calls++
+	return ps.Primary.Primitive, nil
+}
+
+func newDummyAEADPrimitiveSet(t *testing.T) *primitiveset.PrimitiveSet {
+	t.Helper()
+	ps := primitiveset.New()
+	entry, err := ps.Add(new(testutil.DummyAEAD), testutil.NewDummyKey(1, tinkpb.KeyStatusType_ENABLED, tinkpb.OutputPrefixType_TINK))
+	if err != nil {
+		t.Fatalf("ps.Add() err = %v, want nil", err)
+	}
+	ps.Primary = entry
+	return ps
+}
+
+func TestRegisterPrimitiveWrapper(t *testing.T) {
+	w := &countingWrapper{name: "CountingPrimitive"}
+	if err := registry.RegisterPrimitiveWrapper(w); err != nil {
+		t.Fatalf("registry.RegisterPrimitiveWrapper() err = %v, want nil", err)
+	}
+	if err := registry.RegisterPrimitiveWrapper(w); err == nil {
+		t.Errorf("registry.RegisterPrimitiveWrapper() err = nil, want error for duplicate primitive")
+	}
+	if err := registry.RegisterPrimitiveWrapper(&countingWrapper{name: "Aead"}); err == nil {
+		t.Errorf("registry.RegisterPrimitiveWrapper() err = nil, want error for the AEAD primitive")
+	}
+
+	ps := newDummyAEADPrimitiveSet(t)
+	p, err := registry.Wrap("CountingPrimitive", ps)
+	if err != nil {
+		t.Fatalf("registry.Wrap() err = %v, want nil", err)
+	}
+	if _, ok := p.(*testutil.DummyAEAD); !ok || w.calls != 1 {
+		t.Errorf("registry.Wrap() = %T with %d calls, want *testutil.DummyAEAD with 1 call", p, w.calls)
+	}
+	if _, err := registry.Wrap("UnknownPrimitive", ps); err == nil {
+		t.Errorf("registry.Wrap() err = nil, want error for unknown primitive")
+	}
+	if _, err := registry.Wrap("CountingPrimitive", nil); err == nil {
+		t.Errorf("registry.Wrap() err = nil, want error for nil primitive set")
+	}
+}
+
+func TestWrapWithRegistryInstance(t *testing.T) {
+	ps := newDummyAEADPrimitiveSet(t)
+	r := registry.New()
+
+	// Falls back to the wrapper of the default Registry.
+	p, err := r.Wrap("Aead", ps)
+	if err != nil {
+		t.Fatalf("r.Wrap() err = %v, want nil", err)
+	}
+	if _, ok := p.(tink.AEAD); !ok {
+		t.Errorf("r.Wrap() = %T, want tink.AEAD", p)
+	}
+
+	w := &countingWrapper{name: "Aead"}
+	if err := r.RegisterPrimitiveWrapper(w); err != nil {
+		t.Fatalf("r.RegisterPrimitiveWrapper() err = %v, want nil", err)
+	}
+	if _, err := r.Wrap("Aead", ps); err != nil {
+		t.Fatalf("r.Wrap() err = %v, want nil", err)
+	}
+	if _, err := registry.Wrap("Aead", ps); err != nil {
+		t.Fatalf("registry.Wrap() err = %v, want nil", err)
+	}
+	if w.calls != 1 {
+		t.Errorf("w.calls = %d, want 1", w.calls)
+	}
+}
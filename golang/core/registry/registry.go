@@ -24,87 +24,273 @@
 // via primitive factories, which in the background query the Registry for specific
 // KeyManagers. Registry is public though, to enable configurations with custom
 // primitives and KeyManagers.
+//
+// The package-level functions operate on a default Registry shared by the whole process.
+// Isolated registries, for example with fake key managers in tests or with separate KMS
+// clients per tenant, can be created with New and passed to keyset.Handle.PrimitivesWithRegistry
+// and to the NewWithRegistry functions of the primitive packages.
+//
+// Key managers are usually not registered directly: the primitive packages add them to the
+// Tink Catalogue when imported, and the Registry creates and registers them on first use.
+// Additional catalogues can be registered with RegisterCatalogue.
+//
+// The primitive packages also register a PrimitiveWrapper, which the primitive factories
+// use through Wrap to combine the primitives of all keys in a keyset into one primitive.
 package registry
 
 import (
 	"fmt"
+	"sort"
 	"sync"
 
 	"github.com/golang/protobuf/proto"
 
+	"github.com/tsingson/tink/golang/core/primitiveset"
 	tinkpb "github.com/tsingson/tink/proto/tink_go_proto"
 )
 
-var (
+// Registry holds the key managers and KMS clients used to create keys and primitives.
+// Key managers from registered catalogues are registered on first use.
+type Registry struct {
 	keyManagersMu sync.RWMutex
-	keyManagers   = make(map[string]KeyManager) // typeURL -> KeyManager
-	kmsClientsMu  sync.RWMutex
-	kmsClients    = []KMSClient{}
-)
+	keyManagers   map[string]KeyManager // typeURL -> KeyManager
+	newKeyDenied  map[string]bool       // typeURL -> whether new keys are disallowed
+	fromCatalogue map[string]bool       // typeURL -> whether the key manager was created from a catalogue
+	// configured is whether the Registry has been limited by Configure, after which key
+	// managers are no longer created from catalogues.
+	configured   bool
+	kmsClientsMu sync.RWMutex
+	kmsClients   []KMSClient
+	wrappersMu   sync.RWMutex
+	wrappers     map[string]PrimitiveWrapper // primitive name -> PrimitiveWrapper
+	// overridable is whether explicitly registered key managers can take the place
+	// of catalogued ones that have not been used yet.
+	overridable bool
+}
+
+var defaultRegistry = &Registry{
+	keyManagers:   make(map[string]KeyManager),
+	newKeyDenied:  make(map[string]bool),
+	fromCatalogue: make(map[string]bool),
+	wrappers:      make(map[string]PrimitiveWrapper),
+}
+
+// New creates an empty Registry. Key managers from registered catalogues and primitive
+// wrappers registered in the default Registry are still available, but unlike in the
+// default Registry they can be replaced: key managers by registering one for the same
+// key type before it is first used, and primitive wrappers by registering one for the
+// same primitive.
+func New() *Registry {
+	return &Registry{
+		keyManagers:   make(map[string]KeyManager),
+		newKeyDenied:  make(map[string]bool),
+		fromCatalogue: make(map[string]bool),
+		wrappers:      make(map[string]PrimitiveWrapper),
+		overridable:   true,
+	}
+}
+
+// Default returns the Registry used by the package-level functions.
+func Default() *Registry {
+	return defaultRegistry
+}
 
 // RegisterKeyManager registers the given key manager.
-// Does not allow to overwrite existing key managers.
-func RegisterKeyManager(km KeyManager) error {
-	keyManagersMu.Lock()
-	defer keyManagersMu.Unlock()
+// Does not allow to overwrite existing key managers. The default Registry also
+// treats the key managers available from a registered catalogue as existing.
+func (r *Registry) RegisterKeyManager(km KeyManager) error {
 	typeURL := km.TypeURL()
-	if _, existed := keyManagers[typeURL]; existed {
+	if !r.overridable && inCatalogue(typeURL) {
+		return fmt.Errorf("registry.RegisterKeyManager: type %s already registered", typeURL)
+	}
+	r.keyManagersMu.Lock()
+	defer r.keyManagersMu.Unlock()
+	if _, existed := r.keyManagers[typeURL]; existed {
 		return fmt.Errorf("registry.RegisterKeyManager: type %s already registered", typeURL)
 	}
-	keyManagers[typeURL] = km
+	r.keyManagers[typeURL] = km
 	return nil
 }
 
 // GetKeyManager returns the key manager for the given typeURL if existed.
-func GetKeyManager(typeURL string) (KeyManager, error) {
-	keyManagersMu.RLock()
-	defer keyManagersMu.RUnlock()
-	km, existed := keyManagers[typeURL]
+// Key managers from registered catalogues are registered on first use, unless the
+// Registry has been limited by Configure.
+func (r *Registry) GetKeyManager(typeURL string) (KeyManager, error) {
+	r.keyManagersMu.RLock()
+	km, existed := r.keyManagers[typeURL]
+	configured := r.configured
+	r.keyManagersMu.RUnlock()
+	if existed {
+		return km, nil
+	}
+	errUnsupported := fmt.Errorf("registry.GetKeyManager: unsupported key type: %s", typeURL)
+	if configured {
+		return nil, errUnsupported
+	}
+	km, existed = catalogueKeyManager(r, typeURL)
 	if !existed {
-		return nil, fmt.Errorf("registry.GetKeyManager: unsupported key type: %s", typeURL)
+		return nil, errUnsupported
 	}
+	r.keyManagersMu.Lock()
+	defer r.keyManagersMu.Unlock()
+	if registered, existed := r.keyManagers[typeURL]; existed {
+		return registered, nil
+	}
+	if r.configured {
+		return nil, errUnsupported
+	}
+	r.keyManagers[typeURL] = km
+	r.fromCatalogue[typeURL] = true
 	return km, nil
 }
 
+// KeyTypeConfig is the configuration of a key type in a Registry: the key manager of the
+// key type and whether new keys of the type can be generated.
+type KeyTypeConfig struct {
+	KeyManager    KeyManager
+	NewKeyAllowed bool
+}
+
+// Configure limits the Registry to the explicitly registered key managers and the key
+// managers of the given key types, which replace any key manager registered for the same
+// type. Afterwards, key managers are no longer created from the registered catalogues on
+// first use, and the ones created before are removed unless configured. Configurations are
+// cumulative: configuring a Registry again adds key types.
+//
+// New keys of a key type are disallowed if its configuration says so; once disallowed, they
+// cannot be allowed again. If the given configuration would do that, Configure returns an
+// error without changing the Registry.
+func (r *Registry) Configure(kts []KeyTypeConfig) error {
+	r.keyManagersMu.Lock()
+	defer r.keyManagersMu.Unlock()
+	denied := make(map[string]bool)
+	for _, kt := range kts {
+		if kt.KeyManager == nil {
+			return fmt.Errorf("registry.Configure: invalid key type config")
+		}
+		typeURL := kt.KeyManager.TypeURL()
+		if kt.NewKeyAllowed && (r.newKeyDenied[typeURL] || denied[typeURL]) {
+			return fmt.Errorf("registry.Configure: new keys of type %s are already disallowed", typeURL)
+		}
+		if !kt.NewKeyAllowed {
+			denied[typeURL] = true
+		}
+	}
+	if !r.configured {
+		for typeURL := range r.fromCatalogue {
+			delete(r.keyManagers, typeURL)
+		}
+		r.fromCatalogue = make(map[string]bool)
+		r.configured = true
+	}
+	for _, kt := range kts {
+		r.keyManagers[kt.KeyManager.TypeURL()] = kt.KeyManager
+	}
+	for typeURL := range denied {
+		r.newKeyDenied[typeURL] = true
+	}
+	return nil
+}
+
+// SupportedTypeURLs returns the sorted type URLs of all key types that are either
+// registered or, unless the Registry has been limited by Configure, available from a
+// registered catalogue.
+func (r *Registry) SupportedTypeURLs() []string {
+	supported := make(map[string]bool)
+	r.keyManagersMu.RLock()
+	for typeURL := range r.keyManagers {
+		supported[typeURL] = true
+	}
+	configured := r.configured
+	r.keyManagersMu.RUnlock()
+	if !configured {
+		cataloguesMu.RLock()
+		for _, c := range catalogues {
+			for _, typeURL := range c.TypeURLs("") {
+				supported[typeURL] = true
+			}
+		}
+		cataloguesMu.RUnlock()
+	}
+	typeURLs := make([]string, 0, len(supported))
+	for typeURL := range supported {
+		typeURLs = append(typeURLs, typeURL)
+	}
+	sort.Strings(typeURLs)
+	return typeURLs
+}
+
+// SetNewKeyAllowed controls whether new keys of the given registered key type can be
+// generated. Keys of a type for which new keys are not allowed can still be used to
+// instantiate primitives, which allows legacy key types to be phased out. Once
+// disallowed, new keys cannot be allowed again.
+func (r *Registry) SetNewKeyAllowed(typeURL string, allowed bool) error {
+	if _, err := r.GetKeyManager(typeURL); err != nil {
+		return fmt.Errorf("registry.SetNewKeyAllowed: unsupported key type: %s", typeURL)
+	}
+	r.keyManagersMu.Lock()
+	defer r.keyManagersMu.Unlock()
+	if allowed && r.newKeyDenied[typeURL] {
+		return fmt.Errorf("registry.SetNewKeyAllowed: new keys of type %s are already disallowed", typeURL)
+	}
+	if !allowed {
+		r.newKeyDenied[typeURL] = true
+	}
+	return nil
+}
+
+// NewKeyAllowed returns whether new keys of the given key type can be generated.
+func (r *Registry) NewKeyAllowed(typeURL string) bool {
+	r.keyManagersMu.RLock()
+	defer r.keyManagersMu.RUnlock()
+	return !r.newKeyDenied[typeURL]
+}
+
 // NewKeyData generates a new KeyData for the given key template.
-func NewKeyData(kt *tinkpb.KeyTemplate) (*tinkpb.KeyData, error) {
+func (r *Registry) NewKeyData(kt *tinkpb.KeyTemplate) (*tinkpb.KeyData, error) {
 	if kt == nil {
 		return nil, fmt.Errorf("registry.NewKeyData: invalid key template")
 	}
-	km, err := GetKeyManager(kt.TypeUrl)
+	km, err := r.GetKeyManager(kt.TypeUrl)
 	if err != nil {
 		return nil, err
 	}
+	if !r.NewKeyAllowed(kt.TypeUrl) {
+		return nil, fmt.Errorf("registry.NewKeyData: new keys of type %s are not allowed", kt.TypeUrl)
+	}
 	return km.NewKeyData(kt.Value)
 }
 
 // NewKey generates a new key for the given key template.
-func NewKey(kt *tinkpb.KeyTemplate) (proto.Message, error) {
+func (r *Registry) NewKey(kt *tinkpb.KeyTemplate) (proto.Message, error) {
 	if kt == nil {
 		return nil, fmt.Errorf("registry.NewKey: invalid key template")
 	}
-	km, err := GetKeyManager(kt.TypeUrl)
+	km, err := r.GetKeyManager(kt.TypeUrl)
 	if err != nil {
 		return nil, err
 	}
+	if !r.NewKeyAllowed(kt.TypeUrl) {
+		return nil, fmt.Errorf("registry.NewKey: new keys of type %s are not allowed", kt.TypeUrl)
+	}
 	return km.NewKey(kt.Value)
 }
 
 // PrimitiveFromKeyData creates a new primitive for the key given in the given KeyData.
-func PrimitiveFromKeyData(kd *tinkpb.KeyData) (interface{}, error) {
+func (r *Registry) PrimitiveFromKeyData(kd *tinkpb.KeyData) (interface{}, error) {
 	if kd == nil {
 		return nil, fmt.Errorf("registry.PrimitiveFromKeyData: invalid key data")
 	}
-	return Primitive(kd.TypeUrl, kd.Value)
+	return r.Primitive(kd.TypeUrl, kd.Value)
 }
 
 // Primitive creates a new primitive for the given serialized key using the KeyManager
 // identified by the given typeURL.
-func Primitive(typeURL string, sk []byte) (interface{}, error) {
+func (r *Registry) Primitive(typeURL string, sk []byte) (interface{}, error) {
 	if len(sk) == 0 {
 		return nil, fmt.Errorf("registry.Primitive: invalid serialized key")
 	}
-	km, err := GetKeyManager(typeURL)
+	km, err := r.GetKeyManager(typeURL)
 	if err != nil {
 		return nil, err
 	}
@@ -112,20 +298,130 @@ func Primitive(typeURL string, sk []byte) (interface{}, error) {
 }
 
 // RegisterKMSClient is used to register a new KMS client
-func RegisterKMSClient(k KMSClient) {
-	kmsClientsMu.Lock()
-	defer kmsClientsMu.Unlock()
-	kmsClients = append(kmsClients, k)
+func (r *Registry) RegisterKMSClient(k KMSClient) {
+	r.kmsClientsMu.Lock()
+	defer r.kmsClientsMu.Unlock()
+	r.kmsClients = append(r.kmsClients, k)
 }
 
 // GetKMSClient fetches a KMSClient by a given URI.
-func GetKMSClient(keyURI string) (KMSClient, error) {
-	kmsClientsMu.RLock()
-	defer kmsClientsMu.RUnlock()
-	for _, k := range kmsClients {
+func (r *Registry) GetKMSClient(keyURI string) (KMSClient, error) {
+	r.kmsClientsMu.RLock()
+	defer r.kmsClientsMu.RUnlock()
+	for _, k := range r.kmsClients {
 		if k.Supported(keyURI) {
 			return k, nil
 		}
 	}
 	return nil, fmt.Errorf("KMS client supporting %s not found", keyURI)
 }
+
+// RegisterPrimitiveWrapper registers the given primitive wrapper.
+// Does not allow to overwrite existing primitive wrappers.
+func (r *Registry) RegisterPrimitiveWrapper(w PrimitiveWrapper) error {
+	name := w.PrimitiveName()
+	r.wrappersMu.Lock()
+	defer r.wrappersMu.Unlock()
+	if _, existed := r.wrappers[name]; existed {
+		return fmt.Errorf("registry.RegisterPrimitiveWrapper: primitive %s already registered", name)
+	}
+	r.wrappers[name] = w
+	return nil
+}
+
+// Wrap combines the primitives in the given primitive set into a single primitive using
+// the primitive wrapper registered for primitiveName. Registries created with New fall
+// back to the primitive wrappers of the default Registry.
+func (r *Registry) Wrap(primitiveName string, ps *primitiveset.PrimitiveSet) (interface{}, error) {
+	if ps == nil {
+		return nil, fmt.Errorf("registry.Wrap: invalid primitive set")
+	}
+	w, existed := r.primitiveWrapper(primitiveName)
+	if !existed && r != defaultRegistry {
+		w, existed = defaultRegistry.primitiveWrapper(primitiveName)
+	}
+	if !existed {
+		return nil, fmt.Errorf("registry.Wrap: unsupported primitive: %s", primitiveName)
+	}
+	return w.Wrap(ps)
+}
+
+func (r *Registry) primitiveWrapper(primitiveName string) (PrimitiveWrapper, bool) {
+	r.wrappersMu.RLock()
+	defer r.wrappersMu.RUnlock()
+	w, existed := r.wrappers[primitiveName]
+	return w, existed
+}
+
+// RegisterKeyManager registers the given key manager in the default Registry.
+// Does not allow to overwrite existing key managers, including those available
+// from a registered catalogue.
+func RegisterKeyManager(km KeyManager) error {
+	return defaultRegistry.RegisterKeyManager(km)
+}
+
+// GetKeyManager returns the key manager for the given typeURL if existed.
+// Key managers from registered catalogues are registered on first use.
+func GetKeyManager(typeURL string) (KeyManager, error) {
+	return defaultRegistry.GetKeyManager(typeURL)
+}
+
+// SupportedTypeURLs returns the sorted type URLs of all key types that are either
+// registered or available from a registered catalogue.
+func SupportedTypeURLs() []string {
+	return defaultRegistry.SupportedTypeURLs()
+}
+
+// SetNewKeyAllowed controls whether new keys of the given registered key type can be
+// generated. See Registry.SetNewKeyAllowed.
+func SetNewKeyAllowed(typeURL string, allowed bool) error {
+	return defaultRegistry.SetNewKeyAllowed(typeURL, allowed)
+}
+
+// NewKeyAllowed returns whether new keys of the given key type can be generated.
+func NewKeyAllowed(typeURL string) bool {
+	return defaultRegistry.NewKeyAllowed(typeURL)
+}
+
+// NewKeyData generates a new KeyData for the given key template.
+func NewKeyData(kt *tinkpb.KeyTemplate) (*tinkpb.KeyData, error) {
+	return defaultRegistry.NewKeyData(kt)
+}
+
+// NewKey generates a new key for the given key template.
+func NewKey(kt *tinkpb.KeyTemplate) (proto.Message, error) {
+	return defaultRegistry.NewKey(kt)
+}
+
+// PrimitiveFromKeyData creates a new primitive for the key given in the given KeyData.
+func PrimitiveFromKeyData(kd *tinkpb.KeyData) (interface{}, error) {
+	return defaultRegistry.PrimitiveFromKeyData(kd)
+}
+
+// Primitive creates a new primitive for the given serialized key using the KeyManager
+// identified by the given typeURL.
+func Primitive(typeURL string, sk []byte) (interface{}, error) {
+	return defaultRegistry.Primitive(typeURL, sk)
+}
+
+// RegisterKMSClient is used to register a new KMS client
+func RegisterKMSClient(k KMSClient) {
+	defaultRegistry.RegisterKMSClient(k)
+}
+
+// GetKMSClient fetches a KMSClient by a given URI.
+func GetKMSClient(keyURI string) (KMSClient, error) {
+	return defaultRegistry.GetKMSClient(keyURI)
+}
+
+// RegisterPrimitiveWrapper registers the given primitive wrapper in the default Registry.
+// Does not allow to overwrite existing primitive wrappers.
+func RegisterPrimitiveWrapper(w PrimitiveWrapper) error {
+	return defaultRegistry.RegisterPrimitiveWrapper(w)
+}
+
+// Wrap combines the primitives in the given primitive set into a single primitive using
+// the primitive wrapper registered in the default Registry for primitiveName.
+func Wrap(primitiveName string, ps *primitiveset.PrimitiveSet) (interface{}, error) {
+	return defaultRegistry.Wrap(primitiveName, ps)
+}
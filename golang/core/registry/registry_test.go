@@ -117,6 +117,64 @@ func TestNewKey(t *testing.T) {
 	}
 }
 
+// legacyKeyManager is a key manager for a key type that is being phased out.
+type legacyKeyManager struct {
+	testutil.DummyAEADKeyManager
+}
+
+const legacyTypeURL = "type.googleapis.com/google.crypto.tink.LegacyKey"
+
+func (km *legacyKeyManager) NewKey(serializedKeyFormat []byte) (proto.Message, error) {
+	return new(gcmpb.AesGcmKey), nil
+}
+
+func (km *legacyKeyManager) NewKeyData(serializedKeyFormat []byte) (*tinkpb.KeyData, error) {
+	return &tinkpb.KeyData{TypeUrl: legacyTypeURL}, nil
+}
+
+func (km *legacyKeyManager) DoesSupport(typeURL string) bool {
+	return typeURL == legacyTypeURL
+}
+
+func (km *legacyKeyManager) TypeURL() string {
+	return legacyTypeURL
+}
+
+func TestSetNewKeyAllowed(t *testing.T) {
+	if err := registry.SetNewKeyAllowed("some url", false); err == nil {
+		t.Errorf("expect an error when the type url is not registered")
+	}
+	if err := registry.RegisterKeyManager(new(legacyKeyManager)); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	template := &tinkpb.KeyTemplate{TypeUrl: legacyTypeURL}
+	if err := registry.SetNewKeyAllowed(legacyTypeURL, true); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+	if _, err := registry.NewKeyData(template); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+	if err := registry.SetNewKeyAllowed(legacyTypeURL, false); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+	if registry.NewKeyAllowed(legacyTypeURL) {
+		t.Errorf("new keys of type %s should not be allowed", legacyTypeURL)
+	}
+	if _, err := registry.NewKeyData(template); err == nil {
+		t.Errorf("expect an error when new keys are not allowed")
+	}
+	if _, err := registry.NewKey(template); err == nil {
+		t.Errorf("expect an error when new keys are not allowed")
+	}
+	// existing keys can still be used
+	if _, err := registry.Primitive(legacyTypeURL, []byte{0}); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+	if err := registry.SetNewKeyAllowed(legacyTypeURL, true); err == nil {
+		t.Errorf("expect an error when allowing new keys again")
+	}
+}
+
 func TestPrimitiveFromKeyData(t *testing.T) {
 	// hmac keydata
 	keyData := testutil.NewHMACKeyData(commonpb.HashType_SHA256, 16)
@@ -180,3 +238,54 @@ func TestRegisterKmsClient(t *testing.T) {
 	}
 
 }
+
+func TestRegistryInstance(t *testing.T) {
+	r := registry.New()
+	// a fake key manager can take the place of a catalogued one before it is used
+	if err := r.RegisterKeyManager(new(testutil.DummyAEADKeyManager)); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if err := r.RegisterKeyManager(new(testutil.DummyAEADKeyManager)); err == nil {
+		t.Errorf("expect an error when registering a type twice")
+	}
+	key, _ := proto.Marshal(testutil.NewAESGCMKey(0, 16))
+	p, err := r.Primitive(testutil.AESGCMTypeURL, key)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if _, ok := p.(*testutil.DummyAEAD); !ok {
+		t.Errorf("the registry must use its own key manager")
+	}
+	p, err = registry.Primitive(testutil.AESGCMTypeURL, key)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if _, ok := p.(*testutil.DummyAEAD); ok {
+		t.Errorf("the default registry must not be affected")
+	}
+	// other key types are still available from the catalogues
+	if _, err := r.NewKeyData(mac.HMACSHA256Tag128KeyTemplate()); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+
+	// new key settings are per registry
+	if err := r.SetNewKeyAllowed(testutil.HMACTypeURL, false); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if _, err := r.NewKeyData(mac.HMACSHA256Tag128KeyTemplate()); err == nil {
+		t.Errorf("expect an error when new keys are not allowed")
+	}
+	if !registry.NewKeyAllowed(testutil.HMACTypeURL) {
+		t.Errorf("the default registry must not be affected")
+	}
+
+	// KMS clients are per registry
+	kms := &testutil.DummyKMSClient{}
+	r.RegisterKMSClient(kms)
+	if _, err := r.GetKMSClient("dummy"); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+	if registry.Default() == r {
+		t.Errorf("New must not return the default registry")
+	}
+}
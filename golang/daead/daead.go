@@ -20,37 +20,39 @@
 // package main
 //
 // import (
-//     "fmt"
 //
-//     "github.com/tsingson/tink/golang/daead"
-//     "github.com/tsingson/tink/golang/keyset"
+//	"fmt"
+//
+//	"github.com/tsingson/tink/golang/daead"
+//	"github.com/tsingson/tink/golang/keyset"
+//
 // )
 //
 // func main() {
 //
-//     kh, err := keyset.NewHandle(daead.AESSIVKeyTemplate())
-//     if err != nil {
-//         // handle the error
-//     }
+//	kh, err := keyset.NewHandle(daead.AESSIVKeyTemplate())
+//	if err != nil {
+//	    // handle the error
+//	}
 //
-//     d := daead.New(kh)
+//	d := daead.New(kh)
 //
-//     ct1 , err := d.EncryptDeterministically([]byte("this data needs to be encrypted"), []byte("additional data"))
-//     if err != nil {
-//         // handle error
-//     }
+//	ct1 , err := d.EncryptDeterministically([]byte("this data needs to be encrypted"), []byte("additional data"))
+//	if err != nil {
+//	    // handle error
+//	}
 //
-//     pt , err := d.DecryptDeterministically(ct, []byte("additional data"))
-//     if err != nil {
-//         // handle error
-//     }
+//	pt , err := d.DecryptDeterministically(ct, []byte("additional data"))
+//	if err != nil {
+//	    // handle error
+//	}
 //
-//     ct2 , err := d.EncryptDeterministically([]byte("this data needs to be encrypted"), []byte("additional data"))
-//     if err != nil {
-//         // handle error
-//     }
+//	ct2 , err := d.EncryptDeterministically([]byte("this data needs to be encrypted"), []byte("additional data"))
+//	if err != nil {
+//	    // handle error
+//	}
 //
-//     // ct1 will be equal to ct2
+//	// ct1 will be equal to ct2
 //
 // }
 package daead
@@ -61,8 +63,15 @@ import (
 	"github.com/tsingson/tink/golang/core/registry"
 )
 
+// daeadPrimitiveName is the name of the primitive in the Tink catalogue.
+const daeadPrimitiveName = "DeterministicAead"
+
 func init() {
-	if err := registry.RegisterKeyManager(newAESSIVKeyManager()); err != nil {
+	if err := registry.TinkCatalogue().Add(daeadPrimitiveName, aesSIVTypeURL, 0, func(*registry.Registry) registry.KeyManager { return newAESSIVKeyManager() }); err != nil {
+		panic(fmt.Sprintf("daead.init() failed: %v", err))
+	}
+
+	if err := registry.RegisterPrimitiveWrapper(new(wrapper)); err != nil {
 		panic(fmt.Sprintf("daead.init() failed: %v", err))
 	}
 }
@@ -17,10 +17,10 @@ package daead
 import (
 	"fmt"
 
-	"github.com/tsingson/tink/golang/core/cryptofmt"
 	"github.com/tsingson/tink/golang/core/primitiveset"
 	"github.com/tsingson/tink/golang/core/registry"
 	"github.com/tsingson/tink/golang/keyset"
+	"github.com/tsingson/tink/golang/monitoring"
 	"github.com/tsingson/tink/golang/tink"
 )
 
@@ -35,9 +35,51 @@ func NewWithKeyManager(h *keyset.Handle, km registry.KeyManager) (tink.Determini
 	if err != nil {
 		return nil, fmt.Errorf("daead_factory: cannot obtain primitive set: %s", err)
 	}
-	ret := new(primitiveSet)
-	ret.ps = ps
-	return tink.DeterministicAEAD(ret), nil
+	return wrap(registry.Default(), ps)
+}
+
+// NewWithRegistry returns a DeterministicAEAD primitive from the given keyset handle, using the key
+// managers of the given registry.
+func NewWithRegistry(h *keyset.Handle, r *registry.Registry) (tink.DeterministicAEAD, error) {
+	ps, err := h.PrimitivesWithRegistry(r)
+	if err != nil {
+		return nil, fmt.Errorf("daead_factory: cannot obtain primitive set: %s", err)
+	}
+	return wrap(r, ps)
+}
+
+// wrap combines the primitives in ps into a DeterministicAEAD using the primitive wrapper
+// registered in r.
+func wrap(r *registry.Registry, ps *primitiveset.PrimitiveSet) (tink.DeterministicAEAD, error) {
+	p, err := r.Wrap(daeadPrimitiveName, ps)
+	if err != nil {
+		return nil, fmt.Errorf("daead_factory: cannot wrap primitive set: %s", err)
+	}
+	ret, ok := p.(tink.DeterministicAEAD)
+	if !ok {
+		return nil, fmt.Errorf("daead_factory: not a DeterministicAEAD primitive")
+	}
+	return ret, nil
+}
+
+// wrapper is the registry.PrimitiveWrapper for the DeterministicAEAD primitive.
+type wrapper struct{}
+
+// Asserts that wrapper implements the PrimitiveWrapper interface.
+var _ registry.PrimitiveWrapper = (*wrapper)(nil)
+
+// PrimitiveName returns the name of the DeterministicAEAD primitive.
+func (w *wrapper) PrimitiveName() string {
+	return daeadPrimitiveName
+}
+
+// Wrap returns a DeterministicAEAD that uses the primitives in the given primitive set, which must
+// all be DeterministicAEAD primitives.
+func (w *wrapper) Wrap(ps *primitiveset.PrimitiveSet) (interface{}, error) {
+	if !ps.All(func(p interface{}) bool { _, ok := p.(tink.DeterministicAEAD); return ok }) {
+		return nil, fmt.Errorf("daead_factory: not a DeterministicAEAD primitive")
+	}
+	return newPrimitiveSet(ps), nil
 }
 
 // primitiveSet is an DeterministicAEAD implementation that uses the underlying primitive set
@@ -49,53 +91,38 @@ type primitiveSet struct {
 // Asserts that primitiveSet implements the DeterministicAEAD interface.
 var _ tink.DeterministicAEAD = (*primitiveSet)(nil)
 
+func newPrimitiveSet(ps *primitiveset.PrimitiveSet) *primitiveSet {
+	ret := new(primitiveSet)
+	ret.ps = ps
+	return ret
+}
+
 // EncryptDeterministically deterministically encrypts plaintext with additionalData as additional authenticated data.
 // It returns the concatenation of the primary's identifier and the ciphertext.
 func (d *primitiveSet) EncryptDeterministically(pt, aad []byte) ([]byte, error) {
 	primary := d.ps.Primary
 	p := (primary.Primitive).(tink.DeterministicAEAD)
 	ct, err := p.EncryptDeterministically(pt, aad)
+	d.ps.Log(primary, daeadPrimitiveName, monitoring.Encrypt, len(pt), err == nil)
 	if err != nil {
 		return nil, err
 	}
-
-	var ret []byte
-	ret = append(ret, primary.Prefix...)
-	ret = append(ret, ct...)
-	return ret, nil
+	return primary.Prefixed(ct), nil
 }
 
 // DecryptDeterministically deterministically decrypts ciphertext with additionalData as
 // additional authenticated data. It returns the corresponding plaintext if the
 // ciphertext is authenticated.
 func (d *primitiveSet) DecryptDeterministically(ct, aad []byte) ([]byte, error) {
-	// try non-raw keys
-	prefixSize := cryptofmt.NonRawPrefixSize
-	if len(ct) > prefixSize {
-		prefix := ct[:prefixSize]
-		ctNoPrefix := ct[prefixSize:]
-		entries, err := d.ps.EntriesForPrefix(string(prefix))
+	for _, c := range d.ps.Candidates(ct) {
+		var p = (c.Entry.Primitive).(tink.DeterministicAEAD)
+		pt, err := p.DecryptDeterministically(c.Output, aad)
 		if err == nil {
-			for i := 0; i < len(entries); i++ {
-				p := (entries[i].Primitive).(tink.DeterministicAEAD)
-				pt, err := p.DecryptDeterministically(ctNoPrefix, aad)
-				if err == nil {
-					return pt, nil
-				}
-			}
-		}
-	}
-	// try raw keys
-	entries, err := d.ps.RawEntries()
-	if err == nil {
-		for i := 0; i < len(entries); i++ {
-			p := (entries[i].Primitive).(tink.DeterministicAEAD)
-			pt, err := p.DecryptDeterministically(ct, aad)
-			if err == nil {
-				return pt, nil
-			}
+			d.ps.Log(c.Entry, daeadPrimitiveName, monitoring.Decrypt, len(pt), true)
+			return pt, nil
 		}
 	}
 	// nothing worked
+	d.ps.LogFailure(ct, daeadPrimitiveName, monitoring.Decrypt, 0)
 	return nil, fmt.Errorf("daead_factory: decryption failed")
 }
@@ -19,6 +19,7 @@ import (
 	"testing"
 
 	"github.com/tsingson/tink/golang/aead"
+	"github.com/tsingson/tink/golang/core/registry"
 	subtle "github.com/tsingson/tink/golang/subtle/hybrid"
 	"github.com/tsingson/tink/golang/subtle/random"
 	"github.com/tsingson/tink/golang/testutil"
@@ -41,7 +42,7 @@ func modifyDecrypt(t *testing.T, c string, k *tinkpb.KeyTemplate) {
 	salt := random.GetRandomBytes(8)
 	pt := random.GetRandomBytes(4)
 	context := random.GetRandomBytes(4)
-	rDem, err := newRegisterECIESAEADHKDFDemHelper(k)
+	rDem, err := newRegisterECIESAEADHKDFDemHelper(registry.Default(), k)
 	if err != nil {
 		t.Fatalf("error generating a DEM helper :%s", err)
 	}
@@ -19,6 +19,7 @@ import (
 	"testing"
 
 	"github.com/tsingson/tink/golang/aead"
+	"github.com/tsingson/tink/golang/core/registry"
 	subtle "github.com/tsingson/tink/golang/subtle/hybrid"
 	"github.com/tsingson/tink/golang/subtle/random"
 	tinkpb "github.com/tsingson/tink/proto/tink_go_proto"
@@ -37,7 +38,7 @@ func basicMultipleEncrypts(t *testing.T, c string, k *tinkpb.KeyTemplate) {
 	salt := []byte("some salt")
 	pt := random.GetRandomBytes(20)
 	context := []byte("context info")
-	rDem, err := newRegisterECIESAEADHKDFDemHelper(k)
+	rDem, err := newRegisterECIESAEADHKDFDemHelper(registry.Default(), k)
 	if err != nil {
 		t.Fatalf("error generating a DEM helper :%s", err)
 	}
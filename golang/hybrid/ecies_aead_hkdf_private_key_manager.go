@@ -39,14 +39,17 @@ var errInvalidECIESAEADHKDFPrivateKeyKeyFormat = fmt.Errorf("ecies_aead_hkdf_pri
 
 // eciesAEADHKDFPrivateKeyKeyManager is an implementation of PrivateKeyManager interface.
 // It generates new ECIESAEADHKDFPrivateKeyKey keys and produces new instances of ECIESAEADHKDFPrivateKey subtle.
-type eciesAEADHKDFPrivateKeyKeyManager struct{}
+type eciesAEADHKDFPrivateKeyKeyManager struct {
+	registry *registry.Registry
+}
 
 // Assert that eciesAEADHKDFPrivateKeyKeyManager implements the PrivateKeyManager interface.
 var _ registry.PrivateKeyManager = (*eciesAEADHKDFPrivateKeyKeyManager)(nil)
 
-// newECIESAEADHKDFPrivateKeyKeyManager creates a new aesGcmKeyManager.
-func newECIESAEADHKDFPrivateKeyKeyManager() *eciesAEADHKDFPrivateKeyKeyManager {
-	return new(eciesAEADHKDFPrivateKeyKeyManager)
+// newECIESAEADHKDFPrivateKeyKeyManager creates a new aesGcmKeyManager whose DEM keys
+// are handled by the given registry.
+func newECIESAEADHKDFPrivateKeyKeyManager(r *registry.Registry) *eciesAEADHKDFPrivateKeyKeyManager {
+	return &eciesAEADHKDFPrivateKeyKeyManager{registry: r}
 }
 
 // Primitive creates an ECIESAEADHKDFPrivateKey subtle for the given serialized ECIESAEADHKDFPrivateKey proto.
@@ -66,7 +69,7 @@ func (km *eciesAEADHKDFPrivateKeyKeyManager) Primitive(serializedKey []byte) (in
 		return nil, err
 	}
 	pvt := subtle.GetECPrivateKey(curve, key.KeyValue)
-	rDem, err := newRegisterECIESAEADHKDFDemHelper(key.PublicKey.Params.DemParams.AeadDem)
+	rDem, err := newRegisterECIESAEADHKDFDemHelper(km.registry, key.PublicKey.Params.DemParams.AeadDem)
 	if err != nil {
 		return nil, err
 	}
@@ -156,15 +159,15 @@ func (km *eciesAEADHKDFPrivateKeyKeyManager) validateKey(key *eahpb.EciesAeadHkd
 	if err := keyset.ValidateKeyVersion(key.Version, eciesAEADHKDFPrivateKeyKeyVersion); err != nil {
 		return fmt.Errorf("ecies_aead_hkdf_private_key_manager: invalid key: %s", err)
 	}
-	return checkECIESAEADHKDFParams(key.PublicKey.Params)
+	return checkECIESAEADHKDFParams(km.registry, key.PublicKey.Params)
 }
 
 // validateKeyFormat validates the given ECDSAKeyFormat.
 func (km *eciesAEADHKDFPrivateKeyKeyManager) validateKeyFormat(format *eahpb.EciesAeadHkdfKeyFormat) error {
-	return checkECIESAEADHKDFParams(format.Params)
+	return checkECIESAEADHKDFParams(km.registry, format.Params)
 }
 
-func checkECIESAEADHKDFParams(params *eahpb.EciesAeadHkdfParams) error {
+func checkECIESAEADHKDFParams(r *registry.Registry, params *eahpb.EciesAeadHkdfParams) error {
 	_, err := subtle.GetCurve(params.KemParams.CurveType.String())
 	if err != nil {
 		return err
@@ -176,7 +179,7 @@ func checkECIESAEADHKDFParams(params *eahpb.EciesAeadHkdfParams) error {
 	if strings.Compare(params.EcPointFormat.String(), "EcPointFormat_UNKNOWN_FORMAT") == 0 {
 		return errors.New("unknown EC point format")
 	}
-	km, err := registry.GetKeyManager(params.DemParams.AeadDem.TypeUrl)
+	km, err := r.GetKeyManager(params.DemParams.AeadDem.TypeUrl)
 	if err != nil {
 		return err
 	}
@@ -40,14 +40,17 @@ var errInvalidECIESAEADHKDFPublicKeyKeyFormat = fmt.Errorf("ecies_aead_hkdf_publ
 
 // eciesAEADHKDFPublicKeyKeyManager is an implementation of KeyManager interface.
 // It generates new ECIESAEADHKDFPublicKeyKey keys and produces new instances of ECIESAEADHKDFPublicKey subtle.
-type eciesAEADHKDFPublicKeyKeyManager struct{}
+type eciesAEADHKDFPublicKeyKeyManager struct {
+	registry *registry.Registry
+}
 
 // Assert that eciesAEADHKDFPublicKeyKeyManager implements the KeyManager interface.
 var _ registry.KeyManager = (*eciesAEADHKDFPublicKeyKeyManager)(nil)
 
-// newECIESAEADHKDFPublicKeyKeyManager creates a new aesGcmKeyManager.
-func newECIESAEADHKDFPublicKeyKeyManager() *eciesAEADHKDFPublicKeyKeyManager {
-	return new(eciesAEADHKDFPublicKeyKeyManager)
+// newECIESAEADHKDFPublicKeyKeyManager creates a new aesGcmKeyManager whose DEM keys
+// are handled by the given registry.
+func newECIESAEADHKDFPublicKeyKeyManager(r *registry.Registry) *eciesAEADHKDFPublicKeyKeyManager {
+	return &eciesAEADHKDFPublicKeyKeyManager{registry: r}
 }
 
 // Primitive creates an ECIESAEADHKDFPublicKey subtle for the given serialized ECIESAEADHKDFPublicKey proto.
@@ -73,7 +76,7 @@ func (km *eciesAEADHKDFPublicKeyKeyManager) Primitive(serializedKey []byte) (int
 			Y: new(big.Int).SetBytes(key.Y),
 		},
 	}
-	rDem, err := newRegisterECIESAEADHKDFDemHelper(key.Params.DemParams.AeadDem)
+	rDem, err := newRegisterECIESAEADHKDFDemHelper(km.registry, key.Params.DemParams.AeadDem)
 	if err != nil {
 		return nil, err
 	}
@@ -99,7 +102,7 @@ func (km *eciesAEADHKDFPublicKeyKeyManager) validateKey(key *eahpb.EciesAeadHkdf
 	if err := keyset.ValidateKeyVersion(key.Version, eciesAEADHKDFPublicKeyKeyVersion); err != nil {
 		return fmt.Errorf("ecies_aead_hkdf_public_key_manager: invalid key: %s", err)
 	}
-	return checkECIESAEADHKDFParams(key.Params)
+	return checkECIESAEADHKDFParams(km.registry, key.Params)
 }
 
 // NewKey is not implemented for public key manager.
@@ -60,11 +60,24 @@ import (
 	"github.com/tsingson/tink/golang/core/registry"
 )
 
+// Names of the primitives whose key managers this package adds to the Tink catalogue.
+const (
+	hybridDecryptPrimitiveName = "HybridDecrypt"
+	hybridEncryptPrimitiveName = "HybridEncrypt"
+)
+
 func init() {
-	if err := registry.RegisterKeyManager(newECIESAEADHKDFPrivateKeyKeyManager()); err != nil {
+	if err := registry.TinkCatalogue().Add(hybridDecryptPrimitiveName, eciesAEADHKDFPrivateKeyTypeURL, 0, func(r *registry.Registry) registry.KeyManager { return newECIESAEADHKDFPrivateKeyKeyManager(r) }); err != nil {
+		panic(fmt.Sprintf("hybrid.init() failed: %v", err))
+	}
+	if err := registry.TinkCatalogue().Add(hybridEncryptPrimitiveName, eciesAEADHKDFPublicKeyTypeURL, 0, func(r *registry.Registry) registry.KeyManager { return newECIESAEADHKDFPublicKeyKeyManager(r) }); err != nil {
+		panic(fmt.Sprintf("hybrid.init() failed: %v", err))
+	}
+
+	if err := registry.RegisterPrimitiveWrapper(new(decryptWrapper)); err != nil {
 		panic(fmt.Sprintf("hybrid.init() failed: %v", err))
 	}
-	if err := registry.RegisterKeyManager(newECIESAEADHKDFPublicKeyKeyManager()); err != nil {
+	if err := registry.RegisterPrimitiveWrapper(new(encryptWrapper)); err != nil {
 		panic(fmt.Sprintf("hybrid.init() failed: %v", err))
 	}
 }
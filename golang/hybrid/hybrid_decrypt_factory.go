@@ -17,10 +17,10 @@ package hybrid
 import (
 	"fmt"
 
-	"github.com/tsingson/tink/golang/core/cryptofmt"
 	"github.com/tsingson/tink/golang/core/primitiveset"
 	"github.com/tsingson/tink/golang/core/registry"
 	"github.com/tsingson/tink/golang/keyset"
+	"github.com/tsingson/tink/golang/monitoring"
 	"github.com/tsingson/tink/golang/tink"
 )
 
@@ -35,6 +35,50 @@ func NewHybridDecryptWithKeyManager(h *keyset.Handle, km registry.KeyManager) (t
 	if err != nil {
 		return nil, fmt.Errorf("hybrid_factory: cannot obtain primitive set: %s", err)
 	}
+	return wrapDecrypt(registry.Default(), ps)
+}
+
+// NewHybridDecryptWithRegistry returns a HybridDecrypt primitive from the given keyset handle, using the key
+// managers of the given registry.
+func NewHybridDecryptWithRegistry(h *keyset.Handle, r *registry.Registry) (tink.HybridDecrypt, error) {
+	ps, err := h.PrimitivesWithRegistry(r)
+	if err != nil {
+		return nil, fmt.Errorf("hybrid_factory: cannot obtain primitive set: %s", err)
+	}
+	return wrapDecrypt(r, ps)
+}
+
+// wrapDecrypt combines the primitives in ps into a HybridDecrypt using the primitive wrapper
+// registered in r.
+func wrapDecrypt(r *registry.Registry, ps *primitiveset.PrimitiveSet) (tink.HybridDecrypt, error) {
+	p, err := r.Wrap(hybridDecryptPrimitiveName, ps)
+	if err != nil {
+		return nil, fmt.Errorf("hybrid_factory: cannot wrap primitive set: %s", err)
+	}
+	ret, ok := p.(tink.HybridDecrypt)
+	if !ok {
+		return nil, fmt.Errorf("hybrid_factory: not a HybridDecrypt primitive")
+	}
+	return ret, nil
+}
+
+// decryptWrapper is the registry.PrimitiveWrapper for the HybridDecrypt primitive.
+type decryptWrapper struct{}
+
+// Asserts that decryptWrapper implements the PrimitiveWrapper interface.
+var _ registry.PrimitiveWrapper = (*decryptWrapper)(nil)
+
+// PrimitiveName returns the name of the HybridDecrypt primitive.
+func (w *decryptWrapper) PrimitiveName() string {
+	return hybridDecryptPrimitiveName
+}
+
+// Wrap returns a HybridDecrypt that uses the primitives in the given primitive set, which must
+// all be HybridDecrypt primitives.
+func (w *decryptWrapper) Wrap(ps *primitiveset.PrimitiveSet) (interface{}, error) {
+	if !ps.All(func(p interface{}) bool { _, ok := p.(tink.HybridDecrypt); return ok }) {
+		return nil, fmt.Errorf("hybrid_factory: not a HybridDecrypt primitive")
+	}
 	return newDecryptPrimitiveSet(ps), nil
 }
 
@@ -57,33 +101,15 @@ func newDecryptPrimitiveSet(ps *primitiveset.PrimitiveSet) *decryptPrimitiveSet
 // additional authenticated data. It returns the corresponding plaintext if the
 // ciphertext is authenticated.
 func (a *decryptPrimitiveSet) Decrypt(ct, ad []byte) ([]byte, error) {
-	// try non-raw keys
-	prefixSize := cryptofmt.NonRawPrefixSize
-	if len(ct) > prefixSize {
-		prefix := ct[:prefixSize]
-		ctNoPrefix := ct[prefixSize:]
-		entries, err := a.ps.EntriesForPrefix(string(prefix))
+	for _, c := range a.ps.Candidates(ct) {
+		var p = (c.Entry.Primitive).(tink.HybridDecrypt)
+		pt, err := p.Decrypt(c.Output, ad)
 		if err == nil {
-			for i := 0; i < len(entries); i++ {
-				var p = (entries[i].Primitive).(tink.HybridDecrypt)
-				pt, err := p.Decrypt(ctNoPrefix, ad)
-				if err == nil {
-					return pt, nil
-				}
-			}
-		}
-	}
-	// try raw keys
-	entries, err := a.ps.RawEntries()
-	if err == nil {
-		for i := 0; i < len(entries); i++ {
-			var p = (entries[i].Primitive).(tink.HybridDecrypt)
-			pt, err := p.Decrypt(ct, ad)
-			if err == nil {
-				return pt, nil
-			}
+			a.ps.Log(c.Entry, hybridDecryptPrimitiveName, monitoring.Decrypt, len(pt), true)
+			return pt, nil
 		}
 	}
 	// nothing worked
+	a.ps.LogFailure(ct, hybridDecryptPrimitiveName, monitoring.Decrypt, 0)
 	return nil, fmt.Errorf("hybrid_factory: decryption failed")
 }
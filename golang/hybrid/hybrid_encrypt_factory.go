@@ -20,6 +20,7 @@ import (
 	"github.com/tsingson/tink/golang/core/primitiveset"
 	"github.com/tsingson/tink/golang/core/registry"
 	"github.com/tsingson/tink/golang/keyset"
+	"github.com/tsingson/tink/golang/monitoring"
 	"github.com/tsingson/tink/golang/tink"
 )
 
@@ -34,6 +35,50 @@ func NewHybridEncryptWithKeyManager(h *keyset.Handle, km registry.KeyManager) (t
 	if err != nil {
 		return nil, fmt.Errorf("hybrid_factory: cannot obtain primitive set: %s", err)
 	}
+	return wrapEncrypt(registry.Default(), ps)
+}
+
+// NewHybridEncryptWithRegistry returns a HybridEncrypt primitive from the given keyset handle, using the key
+// managers of the given registry.
+func NewHybridEncryptWithRegistry(h *keyset.Handle, r *registry.Registry) (tink.HybridEncrypt, error) {
+	ps, err := h.PrimitivesWithRegistry(r)
+	if err != nil {
+		return nil, fmt.Errorf("hybrid_factory: cannot obtain primitive set: %s", err)
+	}
+	return wrapEncrypt(r, ps)
+}
+
+// wrapEncrypt combines the primitives in ps into a HybridEncrypt using the primitive wrapper
+// registered in r.
+func wrapEncrypt(r *registry.Registry, ps *primitiveset.PrimitiveSet) (tink.HybridEncrypt, error) {
+	p, err := r.Wrap(hybridEncryptPrimitiveName, ps)
+	if err != nil {
+		return nil, fmt.Errorf("hybrid_factory: cannot wrap primitive set: %s", err)
+	}
+	ret, ok := p.(tink.HybridEncrypt)
+	if !ok {
+		return nil, fmt.Errorf("hybrid_factory: not a HybridEncrypt primitive")
+	}
+	return ret, nil
+}
+
+// encryptWrapper is the registry.PrimitiveWrapper for the HybridEncrypt primitive.
+type encryptWrapper struct{}
+
+// Asserts that encryptWrapper implements the PrimitiveWrapper interface.
+var _ registry.PrimitiveWrapper = (*encryptWrapper)(nil)
+
+// PrimitiveName returns the name of the HybridEncrypt primitive.
+func (w *encryptWrapper) PrimitiveName() string {
+	return hybridEncryptPrimitiveName
+}
+
+// Wrap returns a HybridEncrypt that uses the primitives in the given primitive set, which must
+// all be HybridEncrypt primitives.
+func (w *encryptWrapper) Wrap(ps *primitiveset.PrimitiveSet) (interface{}, error) {
+	if !ps.All(func(p interface{}) bool { _, ok := p.(tink.HybridEncrypt); return ok }) {
+		return nil, fmt.Errorf("hybrid_factory: not a HybridEncrypt primitive")
+	}
 	return newEncryptPrimitiveSet(ps), nil
 }
 
@@ -57,11 +102,9 @@ func (a *encryptPrimitiveSet) Encrypt(pt, ad []byte) ([]byte, error) {
 	primary := a.ps.Primary
 	var p = (primary.Primitive).(tink.HybridEncrypt)
 	ct, err := p.Encrypt(pt, ad)
+	a.ps.Log(primary, hybridEncryptPrimitiveName, monitoring.Encrypt, len(pt), err == nil)
 	if err != nil {
 		return nil, err
 	}
-	var ret []byte
-	ret = append(ret, primary.Prefix...)
-	ret = append(ret, ct...)
-	return ret, nil
+	return primary.Prefixed(ct), nil
 }
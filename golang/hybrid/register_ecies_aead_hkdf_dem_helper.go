@@ -36,6 +36,7 @@ const (
 
 // registerECIESAEADHKDFDemHelper registers a DEM helper.
 type registerECIESAEADHKDFDemHelper struct {
+	registry         *registry.Registry
 	demKeyURL        string
 	keyData          []byte
 	symmetricKeySize uint32
@@ -45,7 +46,8 @@ type registerECIESAEADHKDFDemHelper struct {
 var _ subtle.EciesAEADHKDFDEMHelper = (*registerECIESAEADHKDFDemHelper)(nil)
 
 // newRegisterECIESAEADHKDFDemHelper initializes and returns a RegisterECIESAEADHKDFDemHelper
-func newRegisterECIESAEADHKDFDemHelper(k *tinkpb.KeyTemplate) (*registerECIESAEADHKDFDemHelper, error) {
+// whose DEM keys are handled by the given registry.
+func newRegisterECIESAEADHKDFDemHelper(reg *registry.Registry, k *tinkpb.KeyTemplate) (*registerECIESAEADHKDFDemHelper, error) {
 	var len uint32
 	var a uint32
 	var skf []byte
@@ -80,7 +82,7 @@ func newRegisterECIESAEADHKDFDemHelper(k *tinkpb.KeyTemplate) (*registerECIESAEA
 	} else {
 		return nil, fmt.Errorf("unsupported AEAD DEM key type: %s", u)
 	}
-	km, err := registry.GetKeyManager(k.TypeUrl)
+	km, err := reg.GetKeyManager(k.TypeUrl)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch KeyManager, error: %v", err)
 	}
@@ -95,6 +97,7 @@ func newRegisterECIESAEADHKDFDemHelper(k *tinkpb.KeyTemplate) (*registerECIESAEA
 	}
 
 	return &registerECIESAEADHKDFDemHelper{
+		registry:         reg,
 		demKeyURL:        u,
 		keyData:          sk,
 		symmetricKeySize: len,
@@ -142,7 +145,7 @@ func (r *registerECIESAEADHKDFDemHelper) GetAEAD(symmetricKeyValue []byte) (tink
 		return nil, fmt.Errorf("unsupported AEAD DEM key type: %s", r.demKeyURL)
 	}
 
-	p, err := registry.Primitive(r.demKeyURL, sk)
+	p, err := r.registry.Primitive(r.demKeyURL, sk)
 	if err != nil {
 		return nil, err
 	}
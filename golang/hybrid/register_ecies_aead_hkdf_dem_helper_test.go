@@ -20,6 +20,7 @@ import (
 	"testing"
 
 	"github.com/tsingson/tink/golang/aead"
+	"github.com/tsingson/tink/golang/core/registry"
 	"github.com/tsingson/tink/golang/mac"
 	"github.com/tsingson/tink/golang/signature"
 	"github.com/tsingson/tink/golang/subtle/random"
@@ -44,7 +45,7 @@ var (
 
 func TestCipherKeySize(t *testing.T) {
 	for c, l := range keyTemplates {
-		rDem, err := newRegisterECIESAEADHKDFDemHelper(c)
+		rDem, err := newRegisterECIESAEADHKDFDemHelper(registry.Default(), c)
 		if err != nil {
 			t.Fatalf("error generating a DEM helper :%s", err)
 		}
@@ -56,7 +57,7 @@ func TestCipherKeySize(t *testing.T) {
 
 func TestUnsupportedKeyTemplates(t *testing.T) {
 	for _, l := range uTemplates {
-		_, err := newRegisterECIESAEADHKDFDemHelper(l)
+		_, err := newRegisterECIESAEADHKDFDemHelper(registry.Default(), l)
 		if err == nil {
 			t.Fatalf("unsupported key template %s should have generated error", l)
 		}
@@ -67,7 +68,7 @@ func TestAead(t *testing.T) {
 	for c, _ := range keyTemplates {
 		pt := random.GetRandomBytes(20)
 		ad := random.GetRandomBytes(20)
-		rDem, err := newRegisterECIESAEADHKDFDemHelper(c)
+		rDem, err := newRegisterECIESAEADHKDFDemHelper(registry.Default(), c)
 		if err != nil {
 			t.Fatalf("error generating a DEM helper :%s", err)
 		}
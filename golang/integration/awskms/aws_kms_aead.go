@@ -18,30 +18,40 @@
 package awskms
 
 import (
+	"context"
 	"encoding/hex"
 	"errors"
 	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/kms"
+	"github.com/aws/aws-sdk-go/service/kms/kmsiface"
 
 	"github.com/tsingson/tink/golang/aead"
 	"github.com/tsingson/tink/golang/tink"
 )
 
 // AWSAEAD represents a AWS KMS service to a particular URI.
+//
+// As a tink.AEAD, the associated data is passed to AWS KMS as the hex-encoded value of
+// the "additionalData" entry of the encryption context. EncryptWithEncryptionContext and
+// DecryptWithEncryptionContext instead take the encryption context and grant tokens of
+// the request directly.
 type AWSAEAD struct {
-	keyURI string
-	kms    *kms.KMS
+	keyURI  string
+	kms     kmsiface.KMSAPI
+	timeout time.Duration
 }
 
 var (
-	_       tink.AEAD = (*AWSAEAD)(nil)
-	awsaead           = aead.New
+	_       tink.AEAD            = (*AWSAEAD)(nil)
+	_       tink.AEADWithContext = (*AWSAEAD)(nil)
+	awsaead                      = aead.New
 )
 
-// NewAWSAEAD returns a new AWS KMS service.
-func NewAWSAEAD(keyURI string, kms *kms.KMS) *AWSAEAD {
+// NewAWSAEAD returns a new AWS KMS service. kms is usually a *kms.KMS.
+func NewAWSAEAD(keyURI string, kms kmsiface.KMSAPI) *AWSAEAD {
 	return &AWSAEAD{
 		keyURI: keyURI,
 		kms:    kms,
@@ -50,19 +60,38 @@ func NewAWSAEAD(keyURI string, kms *kms.KMS) *AWSAEAD {
 
 // Encrypt AEAD encrypts the plaintext data and uses addtionaldata from authentication.
 func (a *AWSAEAD) Encrypt(plaintext, additionalData []byte) ([]byte, error) {
-	ad := hex.EncodeToString(additionalData)
+	return a.EncryptWithContext(context.Background(), plaintext, additionalData)
+}
+
+// EncryptWithContext is like Encrypt, but the request to AWS KMS is bound to ctx.
+func (a *AWSAEAD) EncryptWithContext(ctx context.Context, plaintext, additionalData []byte) ([]byte, error) {
+	return a.encrypt(ctx, plaintext, additionalDataContext(additionalData), nil)
+}
+
+// EncryptWithEncryptionContext encrypts plaintext with the given encryption context and
+// grant tokens, both of which may be empty. The same encryption context must be given
+// to decrypt the ciphertext.
+func (a *AWSAEAD) EncryptWithEncryptionContext(ctx context.Context, plaintext []byte, encryptionContext map[string]string, grantTokens []string) ([]byte, error) {
+	return a.encrypt(ctx, plaintext, aws.StringMap(encryptionContext), aws.StringSlice(grantTokens))
+}
+
+func (a *AWSAEAD) encrypt(ctx context.Context, plaintext []byte, encryptionContext map[string]*string, grantTokens []*string) ([]byte, error) {
 	req := &kms.EncryptInput{
-		KeyId:             aws.String(a.keyURI),
-		Plaintext:         plaintext,
-		EncryptionContext: map[string]*string{"additionalData": &ad},
+		KeyId:     aws.String(a.keyURI),
+		Plaintext: plaintext,
+	}
+	if len(encryptionContext) > 0 {
+		req.EncryptionContext = encryptionContext
+	}
+	if len(grantTokens) > 0 {
+		req.GrantTokens = grantTokens
 	}
-	if ad == "" {
-		req = &kms.EncryptInput{
-			KeyId:     aws.String(a.keyURI),
-			Plaintext: plaintext,
-		}
+	if a.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, a.timeout)
+		defer cancel()
 	}
-	resp, err := a.kms.Encrypt(req)
+	resp, err := a.kms.EncryptWithContext(ctx, req)
 	if err != nil {
 		return nil, err
 	}
@@ -72,22 +101,50 @@ func (a *AWSAEAD) Encrypt(plaintext, additionalData []byte) ([]byte, error) {
 
 // Decrypt AEAD decrypts the data and verified the additional data.
 func (a *AWSAEAD) Decrypt(ciphertext, additionalData []byte) ([]byte, error) {
-	ad := hex.EncodeToString(additionalData)
+	return a.DecryptWithContext(context.Background(), ciphertext, additionalData)
+}
+
+// DecryptWithContext is like Decrypt, but the request to AWS KMS is bound to ctx.
+func (a *AWSAEAD) DecryptWithContext(ctx context.Context, ciphertext, additionalData []byte) ([]byte, error) {
+	return a.decrypt(ctx, ciphertext, additionalDataContext(additionalData), nil)
+}
+
+// DecryptWithEncryptionContext decrypts ciphertext created by EncryptWithEncryptionContext
+// with the same encryption context. Grant tokens may be empty.
+func (a *AWSAEAD) DecryptWithEncryptionContext(ctx context.Context, ciphertext []byte, encryptionContext map[string]string, grantTokens []string) ([]byte, error) {
+	return a.decrypt(ctx, ciphertext, aws.StringMap(encryptionContext), aws.StringSlice(grantTokens))
+}
+
+func (a *AWSAEAD) decrypt(ctx context.Context, ciphertext []byte, encryptionContext map[string]*string, grantTokens []*string) ([]byte, error) {
 	req := &kms.DecryptInput{
-		CiphertextBlob:    ciphertext,
-		EncryptionContext: map[string]*string{"additionalData": &ad},
+		CiphertextBlob: ciphertext,
 	}
-	if ad == "" {
-		req = &kms.DecryptInput{
-			CiphertextBlob: ciphertext,
-		}
+	if len(encryptionContext) > 0 {
+		req.EncryptionContext = encryptionContext
 	}
-	resp, err := a.kms.Decrypt(req)
-	if strings.Compare(*resp.KeyId, a.keyURI) != 0 {
-		return nil, errors.New("decryption failed: wrong key id")
+	if len(grantTokens) > 0 {
+		req.GrantTokens = grantTokens
+	}
+	if a.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, a.timeout)
+		defer cancel()
 	}
+	resp, err := a.kms.DecryptWithContext(ctx, req)
 	if err != nil {
 		return nil, err
 	}
+	if strings.Compare(aws.StringValue(resp.KeyId), a.keyURI) != 0 {
+		return nil, errors.New("decryption failed: wrong key id")
+	}
 	return resp.Plaintext, nil
 }
+
+// additionalDataContext returns the encryption context to which the associated data of
+// Encrypt and Decrypt is mapped.
+func additionalDataContext(additionalData []byte) map[string]*string {
+	if len(additionalData) == 0 {
+		return nil
+	}
+	return map[string]*string{"additionalData": aws.String(hex.EncodeToString(additionalData))}
+}
@@ -16,9 +16,12 @@ package awskms
 
 import (
 	"bytes"
+	"context"
+	"encoding/hex"
 	"errors"
 	"flag"
 	"os"
+	"reflect"
 	// ignore-placeholder1
 	// ignore-placeholder2
 	"testing"
@@ -131,4 +134,106 @@ func TestBasicAeadWithoutAdditionalData(t *testing.T) {
 	}
 }
 
+const (
+	fakeKeyARN      = "arn:aws:kms:us-east-2:123456789012:key/11111111-1111-1111-1111-111111111111"
+	otherFakeKeyARN = "arn:aws:kms:us-east-2:123456789012:key/22222222-2222-2222-2222-222222222222"
+)
+
+func TestAEADWithFakeKMS(t *testing.T) {
+	a := NewAWSAEAD(fakeKeyARN, newFakeKMS())
+	if err := basicAEADTest(t, a); err != nil {
+		t.Errorf("error in basic aead tests: %v", err)
+	}
+	pt := []byte("plaintext")
+	ad := []byte("associated data")
+	ct, err := a.Encrypt(pt, ad)
+	if err != nil {
+		t.Fatalf("a.Encrypt() err = %v, want nil", err)
+	}
+	if _, err := a.Decrypt(ct, []byte("other data")); err == nil {
+		t.Errorf("a.Decrypt() with wrong associated data: err = nil, want error")
+	}
+	if _, err := a.Decrypt(ct, nil); err == nil {
+		t.Errorf("a.Decrypt() without associated data: err = nil, want error")
+	}
+
+	// The associated data is the hex-encoded additionalData entry of the encryption context.
+	ec := map[string]string{"additionalData": hex.EncodeToString(ad)}
+	got, err := a.DecryptWithEncryptionContext(context.Background(), ct, ec, nil)
+	if err != nil || !bytes.Equal(got, pt) {
+		t.Errorf("a.DecryptWithEncryptionContext() = %q, %v, want %q, nil", got, err, pt)
+	}
+}
+
+func TestEncryptionContextAndGrantTokens(t *testing.T) {
+	f := newFakeKMS()
+	a := NewAWSAEAD(fakeKeyARN, f)
+	ctx := context.Background()
+	pt := []byte("plaintext")
+	ec := map[string]string{"tenant": "tenant-1", "purpose": "backup"}
+	grantTokens := []string{"grant-token-1", "grant-token-2"}
+
+	ct, err := a.EncryptWithEncryptionContext(ctx, pt, ec, grantTokens)
+	if err != nil {
+		t.Fatalf("a.EncryptWithEncryptionContext() err = %v, want nil", err)
+	}
+	if got := f.lastGrantTokens(); !reflect.DeepEqual(got, grantTokens) {
+		t.Errorf("grant tokens of encrypt request = %v, want %v", got, grantTokens)
+	}
+	got, err := a.DecryptWithEncryptionContext(ctx, ct, map[string]string{"purpose": "backup", "tenant": "tenant-1"}, grantTokens[:1])
+	if err != nil || !bytes.Equal(got, pt) {
+		t.Errorf("a.DecryptWithEncryptionContext() = %q, %v, want %q, nil", got, err, pt)
+	}
+	if got := f.lastGrantTokens(); !reflect.DeepEqual(got, grantTokens[:1]) {
+		t.Errorf("grant tokens of decrypt request = %v, want %v", got, grantTokens[:1])
+	}
+
+	for _, wrong := range []map[string]string{
+		nil,
+		{"tenant": "tenant-2", "purpose": "backup"},
+		{"tenant": "tenant-1"},
+		{"tenant": "tenant-1", "purpose": "backup", "extra": ""},
+	} {
+		if _, err := a.DecryptWithEncryptionContext(ctx, ct, wrong, nil); err == nil {
+			t.Errorf("a.DecryptWithEncryptionContext() with context %v: err = nil, want error", wrong)
+		}
+	}
+
+	// Without grant tokens and encryption context.
+	ct, err = a.EncryptWithEncryptionContext(ctx, pt, nil, nil)
+	if err != nil {
+		t.Fatalf("a.EncryptWithEncryptionContext() err = %v, want nil", err)
+	}
+	if got := f.lastGrantTokens(); len(got) != 0 {
+		t.Errorf("grant tokens of encrypt request = %v, want none", got)
+	}
+	if got, err := a.Decrypt(ct, nil); err != nil || !bytes.Equal(got, pt) {
+		t.Errorf("a.Decrypt() = %q, %v, want %q, nil", got, err, pt)
+	}
+}
+
+func TestDecryptErrors(t *testing.T) {
+	f := newFakeKMS()
+	a := NewAWSAEAD(fakeKeyARN, f)
+	other := NewAWSAEAD(otherFakeKeyARN, f)
+
+	ct, err := other.Encrypt([]byte("plaintext"), nil)
+	if err != nil {
+		t.Fatalf("other.Encrypt() err = %v, want nil", err)
+	}
+	if _, err := a.Decrypt(ct, nil); err == nil {
+		t.Errorf("a.Decrypt() of ciphertext of another key: err = nil, want error")
+	}
+
+	// A failed request returns no response to check the key ID of.
+	if _, err := a.Decrypt([]byte("invalid ciphertext"), nil); err == nil {
+		t.Errorf("a.Decrypt() of invalid ciphertext: err = nil, want error")
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := a.DecryptWithContext(ctx, ct, nil); err == nil {
+		t.Errorf("a.DecryptWithContext() with cancelled context: err = nil, want error")
+	}
+}
+
 // ignore-placeholder5
@@ -23,11 +23,13 @@ import (
 	"os"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/credentials"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/kms"
+	"github.com/aws/aws-sdk-go/service/kms/kmsiface"
 
 	"github.com/tsingson/tink/golang/core/registry"
 	"github.com/tsingson/tink/golang/tink"
@@ -43,9 +45,10 @@ var (
 
 // AWSClient represents a client that connects to the AWS KMS backend.
 type AWSClient struct {
-	keyURI string
-	kms    *kms.KMS
-	region string
+	keyURI  string
+	kms     kmsiface.KMSAPI
+	region  string
+	timeout time.Duration
 }
 
 var _ registry.KMSClient = (*AWSClient)(nil)
@@ -120,11 +123,16 @@ func (g *AWSClient) GetAEAD(keyURI string) (tink.AEAD, error) {
 	if len(g.keyURI) > 0 && strings.Compare(strings.ToLower(g.keyURI), strings.ToLower(keyURI)) != 0 {
 		return nil, fmt.Errorf("this client is bound to %s, cannot load keys bound to %s", g.keyURI, keyURI)
 	}
-	uri, err := validateTrimKMSPrefix(g.keyURI, awsPrefix)
+	uri, err := validateTrimKMSPrefix(keyURI, awsPrefix)
 	if err != nil {
 		return nil, err
 	}
-	return NewAWSAEAD(uri, g.kms), nil
+	if g.kms == nil {
+		return nil, errors.New("the client has no session; load credentials first")
+	}
+	a := NewAWSAEAD(uri, g.kms)
+	a.timeout = g.timeout
+	return a, nil
 }
 
 func validateKMSPrefix(keyURI, prefix string) bool {
@@ -0,0 +1,244 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package awskms
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/service/kms"
+
+	"github.com/tsingson/tink/golang/aead"
+	"github.com/tsingson/tink/golang/core/registry"
+	"github.com/tsingson/tink/golang/keyset"
+)
+
+// kmsServer serves the Encrypt and Decrypt API of a fakeKMS over the JSON protocol of
+// AWS KMS. The first failures requests fail with an internal error.
+type kmsServer struct {
+	kms      *fakeKMS
+	failures int32
+	requests int32
+	delay    time.Duration
+}
+
+func (s *kmsServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	atomic.AddInt32(&s.requests, 1)
+	if atomic.AddInt32(&s.failures, -1) >= 0 {
+		writeKMSError(w, http.StatusInternalServerError, "KMSInternalException")
+		return
+	}
+	// The request is canceled only after its body was read.
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		writeKMSError(w, http.StatusBadRequest, "ValidationException")
+		return
+	}
+	select {
+	case <-time.After(s.delay):
+	case <-r.Context().Done():
+		return
+	}
+	var out interface{}
+	switch r.Header.Get("X-Amz-Target") {
+	case "TrentService.Encrypt":
+		in := new(kms.EncryptInput)
+		if err := json.Unmarshal(body, in); err != nil {
+			writeKMSError(w, http.StatusBadRequest, "ValidationException")
+			return
+		}
+		out, err = s.kms.EncryptWithContext(r.Context(), in)
+	case "TrentService.Decrypt":
+		in := new(kms.DecryptInput)
+		if err := json.Unmarshal(body, in); err != nil {
+			writeKMSError(w, http.StatusBadRequest, "ValidationException")
+			return
+		}
+		out, err = s.kms.DecryptWithContext(r.Context(), in)
+	default:
+		writeKMSError(w, http.StatusBadRequest, "UnknownOperationException")
+		return
+	}
+	if err != nil {
+		writeKMSError(w, http.StatusBadRequest, "InvalidCiphertextException")
+		return
+	}
+	w.Header().Set("Content-Type", "application/x-amz-json-1.1")
+	json.NewEncoder(w).Encode(out)
+}
+
+func writeKMSError(w http.ResponseWriter, code int, errType string) {
+	w.Header().Set("Content-Type", "application/x-amz-json-1.1")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(map[string]string{"__type": errType, "message": errType})
+}
+
+// newClientWithEndpoint starts a server for s and returns a client for it. The server
+// must be closed by the caller.
+func newClientWithEndpoint(t *testing.T, s *kmsServer, opts ...ClientOption) (*AWSClient, *httptest.Server) {
+	t.Helper()
+	srv := httptest.NewServer(s)
+	opts = append([]ClientOption{
+		WithEndpoint(srv.URL),
+		WithCredentials(credentials.NewStaticCredentials("AKID", "SECRET", "")),
+	}, opts...)
+	c, err := NewAWSClientWithOptions(awsPrefix+fakeKeyARN, opts...)
+	if err != nil {
+		srv.Close()
+		t.Fatalf("NewAWSClientWithOptions() err = %v, want nil", err)
+	}
+	return c, srv
+}
+
+func TestClientWithKMS(t *testing.T) {
+	c, err := NewAWSClientWithOptions(awsPrefix, WithKMS(newFakeKMS()))
+	if err != nil {
+		t.Fatalf("NewAWSClientWithOptions() err = %v, want nil", err)
+	}
+	r := registry.New()
+	r.RegisterKMSClient(c)
+
+	// The client is not bound to a key URI and serves all keys.
+	for _, keyURI := range []string{awsPrefix + fakeKeyARN, awsPrefix + otherFakeKeyARN} {
+		kh, err := keyset.NewHandle(aead.KMSEnvelopeAEADKeyTemplate(keyURI, aead.AES128GCMKeyTemplate()))
+		if err != nil {
+			t.Fatalf("keyset.NewHandle() err = %v, want nil", err)
+		}
+		a, err := aead.NewWithRegistry(kh, r)
+		if err != nil {
+			t.Fatalf("aead.NewWithRegistry() err = %v, want nil", err)
+		}
+		if err := basicAEADTest(t, a); err != nil {
+			t.Errorf("error in basic aead tests: %v", err)
+		}
+
+		masterKey, err := c.GetAEAD(keyURI)
+		if err != nil {
+			t.Fatalf("c.GetAEAD() err = %v, want nil", err)
+		}
+		mem := &keyset.MemReaderWriter{}
+		if err := kh.Write(mem, masterKey); err != nil {
+			t.Fatalf("kh.Write() err = %v, want nil", err)
+		}
+		if _, err := keyset.Read(mem, masterKey); err != nil {
+			t.Errorf("keyset.Read() err = %v, want nil", err)
+		}
+	}
+}
+
+func TestClientWithEndpointAndRetryPolicy(t *testing.T) {
+	s := &kmsServer{kms: newFakeKMS(), failures: 2}
+	c, srv := newClientWithEndpoint(t, s, WithRetryPolicy(RetryPolicy{MaxRetries: 2, MinDelay: time.Millisecond}))
+	defer srv.Close()
+	a, err := c.GetAEAD(awsPrefix + fakeKeyARN)
+	if err != nil {
+		t.Fatalf("c.GetAEAD() err = %v, want nil", err)
+	}
+	pt := []byte("plaintext")
+	ct, err := a.Encrypt(pt, []byte("ad"))
+	if err != nil {
+		t.Fatalf("a.Encrypt() err = %v, want nil", err)
+	}
+	if got := atomic.LoadInt32(&s.requests); got != 3 {
+		t.Errorf("number of requests = %d, want 3", got)
+	}
+	got, err := a.Decrypt(ct, []byte("ad"))
+	if err != nil || !bytes.Equal(got, pt) {
+		t.Errorf("a.Decrypt() = %q, %v, want %q, nil", got, err, pt)
+	}
+
+	// Without retries, the first failure is returned.
+	s = &kmsServer{kms: newFakeKMS(), failures: 1}
+	c, srv = newClientWithEndpoint(t, s, WithRetryPolicy(RetryPolicy{}))
+	defer srv.Close()
+	a, err = c.GetAEAD(awsPrefix + fakeKeyARN)
+	if err != nil {
+		t.Fatalf("c.GetAEAD() err = %v, want nil", err)
+	}
+	if _, err := a.Encrypt(pt, nil); err == nil {
+		t.Errorf("a.Encrypt() err = nil, want error")
+	}
+	if got := atomic.LoadInt32(&s.requests); got != 1 {
+		t.Errorf("number of requests = %d, want 1", got)
+	}
+}
+
+func TestClientWithHTTPClientAndTimeout(t *testing.T) {
+	// The AWS SDK requires an *http.Transport if a custom CA bundle is configured, so the
+	// requests are counted by the proxy function.
+	var requests int32
+	transport := &http.Transport{
+		Proxy: func(*http.Request) (*url.URL, error) {
+			atomic.AddInt32(&requests, 1)
+			return nil, nil
+		},
+	}
+	s := &kmsServer{kms: newFakeKMS()}
+	c, srv := newClientWithEndpoint(t, s, WithHTTPClient(&http.Client{Transport: transport}), WithTimeout(time.Second))
+	defer srv.Close()
+	a, err := c.GetAEAD(awsPrefix + fakeKeyARN)
+	if err != nil {
+		t.Fatalf("c.GetAEAD() err = %v, want nil", err)
+	}
+	if _, err := a.Encrypt([]byte("plaintext"), nil); err != nil {
+		t.Fatalf("a.Encrypt() err = %v, want nil", err)
+	}
+	if got := atomic.LoadInt32(&requests); got != 1 {
+		t.Errorf("number of requests sent by the HTTP client = %d, want 1", got)
+	}
+
+	s = &kmsServer{kms: newFakeKMS(), delay: time.Minute}
+	c, srv = newClientWithEndpoint(t, s, WithTimeout(10*time.Millisecond), WithRetryPolicy(RetryPolicy{}))
+	defer srv.Close()
+	a, err = c.GetAEAD(awsPrefix + fakeKeyARN)
+	if err != nil {
+		t.Fatalf("c.GetAEAD() err = %v, want nil", err)
+	}
+	start := time.Now()
+	if _, err := a.(*AWSAEAD).EncryptWithContext(context.Background(), []byte("plaintext"), nil); err == nil {
+		t.Errorf("a.EncryptWithContext() err = nil, want timeout")
+	}
+	if d := time.Since(start); d > 10*time.Second {
+		t.Errorf("a.EncryptWithContext() took %v, want it to time out", d)
+	}
+}
+
+func TestClientWithInvalidOptions(t *testing.T) {
+	if _, err := NewAWSClientWithOptions(awsPrefix, WithKMS(newFakeKMS()), WithEndpoint("http://localhost")); err == nil {
+		t.Errorf("NewAWSClientWithOptions() with WithKMS and WithEndpoint: err = nil, want error")
+	}
+	if _, err := NewAWSClientWithOptions(awsPrefix); err == nil {
+		t.Errorf("NewAWSClientWithOptions() without region: err = nil, want error")
+	}
+	if _, err := NewAWSClientWithOptions("gcp-kms://key", WithKMS(newFakeKMS())); err == nil {
+		t.Errorf("NewAWSClientWithOptions() with foreign URI: err = nil, want error")
+	}
+	c, err := NewAWSClientWithOptions(awsPrefix, WithRegion("us-east-2"))
+	if err != nil {
+		t.Fatalf("NewAWSClientWithOptions() with region: err = %v, want nil", err)
+	}
+	if !c.Supported(awsPrefix+fakeKeyARN) || c.Supported("gcp-kms://key") {
+		t.Errorf("unbound client must support exactly the AWS key URIs")
+	}
+}
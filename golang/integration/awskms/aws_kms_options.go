@@ -0,0 +1,156 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package awskms
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/client"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/kms"
+	"github.com/aws/aws-sdk-go/service/kms/kmsiface"
+
+	"github.com/tsingson/tink/golang/integration/internal/retry"
+)
+
+// RetryPolicy configures how requests to AWS KMS that failed with an error the AWS SDK
+// considers retryable are retried. The delay before the n-th retry is MinDelay * 2^(n-1),
+// but at most MaxDelay.
+type RetryPolicy = retry.Policy
+
+// retryer is a request.Retryer that retries like the default retryer of the AWS SDK,
+// but with the delays of a RetryPolicy.
+type retryer struct {
+	client.DefaultRetryer
+	policy RetryPolicy
+}
+
+func (r retryer) RetryRules(req *request.Request) time.Duration {
+	return r.policy.Delay(req.RetryCount)
+}
+
+// ClientOption configures an AWSClient created by NewAWSClientWithOptions.
+type ClientOption func(*clientOptions)
+
+type clientOptions struct {
+	endpoint    string
+	region      string
+	httpClient  *http.Client
+	credentials *credentials.Credentials
+	retryPolicy *RetryPolicy
+	timeout     time.Duration
+	kms         kmsiface.KMSAPI
+}
+
+// WithEndpoint sets the endpoint of AWS KMS, e.g. a VPC endpoint or the URL of an emulator.
+func WithEndpoint(endpoint string) ClientOption {
+	return func(o *clientOptions) { o.endpoint = endpoint }
+}
+
+// WithRegion sets the region of AWS KMS. By default, the region is taken from the key URI.
+func WithRegion(region string) ClientOption {
+	return func(o *clientOptions) { o.region = region }
+}
+
+// WithHTTPClient sets the HTTP client used for requests to AWS KMS.
+func WithHTTPClient(c *http.Client) ClientOption {
+	return func(o *clientOptions) { o.httpClient = c }
+}
+
+// WithCredentials sets the credentials used to sign requests. By default, the credentials
+// are looked up in the default locations of the AWS SDK.
+func WithCredentials(c *credentials.Credentials) ClientOption {
+	return func(o *clientOptions) { o.credentials = c }
+}
+
+// WithRetryPolicy sets how failed requests to AWS KMS are retried.
+func WithRetryPolicy(p RetryPolicy) ClientOption {
+	return func(o *clientOptions) { o.retryPolicy = &p }
+}
+
+// WithTimeout bounds the duration of each Encrypt and Decrypt call of the AEADs of the
+// client, including retries.
+func WithTimeout(timeout time.Duration) ClientOption {
+	return func(o *clientOptions) { o.timeout = timeout }
+}
+
+// WithKMS sets the AWS KMS client to use, e.g. an existing *kms.KMS or a fake for tests.
+// It cannot be combined with the options that configure the session of a new client.
+func WithKMS(kms kmsiface.KMSAPI) ClientOption {
+	return func(o *clientOptions) { o.kms = kms }
+}
+
+// NewAWSClientWithOptions returns a new client to AWS KMS configured by opts. Unlike
+// NewAWSClient, the session is established immediately, using the default credentials
+// unless WithCredentials or WithKMS is given.
+func NewAWSClientWithOptions(URI string, opts ...ClientOption) (*AWSClient, error) {
+	if !strings.HasPrefix(strings.ToLower(URI), awsPrefix) {
+		return nil, fmt.Errorf("key URI must start with %s", awsPrefix)
+	}
+	o := new(clientOptions)
+	for _, opt := range opts {
+		opt(o)
+	}
+	keyURI := URI
+	if len(URI) == len(awsPrefix) {
+		keyURI = ""
+	}
+	c := &AWSClient{
+		keyURI:  keyURI,
+		region:  o.region,
+		timeout: o.timeout,
+	}
+	if o.kms != nil {
+		if o.endpoint != "" || o.region != "" || o.httpClient != nil || o.credentials != nil || o.retryPolicy != nil {
+			return nil, errors.New("WithKMS cannot be combined with options configuring the session")
+		}
+		c.kms = o.kms
+		return c, nil
+	}
+	if c.region == "" {
+		r, err := getRegion(URI)
+		if err != nil {
+			return nil, err
+		}
+		c.region = r
+	}
+	cfg := &aws.Config{
+		Region:      aws.String(c.region),
+		Credentials: o.credentials,
+		HTTPClient:  o.httpClient,
+	}
+	if o.endpoint != "" {
+		cfg.Endpoint = aws.String(o.endpoint)
+	}
+	if o.retryPolicy != nil {
+		cfg = request.WithRetryer(cfg, retryer{
+			DefaultRetryer: client.DefaultRetryer{NumMaxRetries: o.retryPolicy.MaxRetries},
+			policy:         *o.retryPolicy,
+		})
+	}
+	s, err := session.NewSession(cfg)
+	if err != nil {
+		return nil, err
+	}
+	c.kms = kms.New(s)
+	return c, nil
+}
@@ -0,0 +1,127 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package awskms
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"sort"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/kms"
+	"github.com/aws/aws-sdk-go/service/kms/kmsiface"
+
+	"github.com/tsingson/tink/golang/subtle/aead"
+	"github.com/tsingson/tink/golang/subtle/random"
+	"github.com/tsingson/tink/golang/tink"
+)
+
+// fakeKMS implements the Encrypt and Decrypt API of AWS KMS in memory. Each key ARN
+// gets its own AES-GCM key, and the encryption context is authenticated as associated
+// data. Like AWS KMS, the ciphertext identifies the key, so Decrypt needs no key ID.
+// All other methods of kmsiface.KMSAPI panic.
+type fakeKMS struct {
+	kmsiface.KMSAPI
+
+	mu          sync.Mutex
+	keys        map[string]tink.AEAD
+	grantTokens []string // of the last request
+}
+
+func newFakeKMS() *fakeKMS {
+	return &fakeKMS{keys: make(map[string]tink.AEAD)}
+}
+
+func (f *fakeKMS) EncryptWithContext(ctx aws.Context, req *kms.EncryptInput, opts ...request.Option) (*kms.EncryptOutput, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	keyID := aws.StringValue(req.KeyId)
+	if keyID == "" || len(req.Plaintext) == 0 {
+		return nil, errors.New("ValidationException: key ID and plaintext are required")
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.grantTokens = aws.StringValueSlice(req.GrantTokens)
+	a, ok := f.keys[keyID]
+	if !ok {
+		var err error
+		if a, err = aead.NewAESGCM(random.GetRandomBytes(32)); err != nil {
+			return nil, err
+		}
+		f.keys[keyID] = a
+	}
+	ct, err := a.Encrypt(req.Plaintext, serializeContext(req.EncryptionContext))
+	if err != nil {
+		return nil, err
+	}
+	// The ciphertext blob is the length-prefixed key ARN followed by the AES-GCM ciphertext.
+	blob := make([]byte, 2, 2+len(keyID)+len(ct))
+	binary.BigEndian.PutUint16(blob, uint16(len(keyID)))
+	blob = append(append(blob, keyID...), ct...)
+	return &kms.EncryptOutput{CiphertextBlob: blob, KeyId: aws.String(keyID)}, nil
+}
+
+func (f *fakeKMS) DecryptWithContext(ctx aws.Context, req *kms.DecryptInput, opts ...request.Option) (*kms.DecryptOutput, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	blob := req.CiphertextBlob
+	if len(blob) < 2 || len(blob) < 2+int(binary.BigEndian.Uint16(blob)) {
+		return nil, errors.New("InvalidCiphertextException")
+	}
+	n := 2 + int(binary.BigEndian.Uint16(blob))
+	keyID := string(blob[2:n])
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.grantTokens = aws.StringValueSlice(req.GrantTokens)
+	a, ok := f.keys[keyID]
+	if !ok {
+		return nil, errors.New("InvalidCiphertextException")
+	}
+	pt, err := a.Decrypt(blob[n:], serializeContext(req.EncryptionContext))
+	if err != nil {
+		return nil, errors.New("InvalidCiphertextException")
+	}
+	return &kms.DecryptOutput{Plaintext: pt, KeyId: aws.String(keyID)}, nil
+}
+
+// lastGrantTokens returns the grant tokens of the last request.
+func (f *fakeKMS) lastGrantTokens() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.grantTokens
+}
+
+// serializeContext returns a canonical encoding of an encryption context.
+func serializeContext(encryptionContext map[string]*string) []byte {
+	keys := make([]string, 0, len(encryptionContext))
+	for k := range encryptionContext {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var b bytes.Buffer
+	for _, k := range keys {
+		v := aws.StringValue(encryptionContext[k])
+		binary.Write(&b, binary.BigEndian, uint32(len(k)))
+		b.WriteString(k)
+		binary.Write(&b, binary.BigEndian, uint32(len(v)))
+		b.WriteString(v)
+	}
+	return b.Bytes()
+}
@@ -17,18 +17,27 @@
 package gcpkms
 
 import (
+	"context"
 	"encoding/base64"
+	"time"
+
+	cloudkms "google.golang.org/api/cloudkms/v1"
 
 	"github.com/tsingson/tink/golang/tink"
 )
 
 // GCPAEAD represents a GCP KMS service to a particular URI.
 type GCPAEAD struct {
-	keyURI string
-	kms    cloudkms.Service
+	keyURI      string
+	kms         cloudkms.Service
+	retryPolicy RetryPolicy
+	timeout     time.Duration
 }
 
-var _ tink.AEAD = (*GCPAEAD)(nil)
+var (
+	_ tink.AEAD            = (*GCPAEAD)(nil)
+	_ tink.AEADWithContext = (*GCPAEAD)(nil)
+)
 
 // NewGCPAEAD returns a new GCP KMS service.
 func NewGCPAEAD(keyURI string, kms *cloudkms.Service) *GCPAEAD {
@@ -40,12 +49,20 @@ func NewGCPAEAD(keyURI string, kms *cloudkms.Service) *GCPAEAD {
 
 // Encrypt AEAD encrypts the plaintext data and uses addtionaldata from authentication.
 func (a *GCPAEAD) Encrypt(plaintext, additionalData []byte) ([]byte, error) {
+	return a.EncryptWithContext(context.Background(), plaintext, additionalData)
+}
 
+// EncryptWithContext is like Encrypt, but the request to GCP KMS is bound to ctx.
+func (a *GCPAEAD) EncryptWithContext(ctx context.Context, plaintext, additionalData []byte) ([]byte, error) {
 	req := &cloudkms.EncryptRequest{
 		Plaintext:                   base64.URLEncoding.EncodeToString(plaintext),
 		AdditionalAuthenticatedData: base64.URLEncoding.EncodeToString(additionalData),
 	}
-	resp, err := a.kms.Projects.Locations.KeyRings.CryptoKeys.Encrypt(a.keyURI, req).Do()
+	var resp *cloudkms.EncryptResponse
+	err := a.call(ctx, func(ctx context.Context) (err error) {
+		resp, err = a.kms.Projects.Locations.KeyRings.CryptoKeys.Encrypt(a.keyURI, req).Context(ctx).Do()
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -55,14 +72,42 @@ func (a *GCPAEAD) Encrypt(plaintext, additionalData []byte) ([]byte, error) {
 
 // Decrypt AEAD decrypts the data and verified the additional data.
 func (a *GCPAEAD) Decrypt(ciphertext, additionalData []byte) ([]byte, error) {
+	return a.DecryptWithContext(context.Background(), ciphertext, additionalData)
+}
 
+// DecryptWithContext is like Decrypt, but the request to GCP KMS is bound to ctx.
+func (a *GCPAEAD) DecryptWithContext(ctx context.Context, ciphertext, additionalData []byte) ([]byte, error) {
 	req := &cloudkms.DecryptRequest{
 		Ciphertext:                  base64.URLEncoding.EncodeToString(ciphertext),
 		AdditionalAuthenticatedData: base64.URLEncoding.EncodeToString(additionalData),
 	}
-	resp, err := a.kms.Projects.Locations.KeyRings.CryptoKeys.Decrypt(a.keyURI, req).Do()
+	var resp *cloudkms.DecryptResponse
+	err := a.call(ctx, func(ctx context.Context) (err error) {
+		resp, err = a.kms.Projects.Locations.KeyRings.CryptoKeys.Decrypt(a.keyURI, req).Context(ctx).Do()
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
 	return base64.StdEncoding.DecodeString(resp.Plaintext)
 }
+
+// call calls f, bounded by the timeout of a, and retries it according to the retry policy of a.
+func (a *GCPAEAD) call(ctx context.Context, f func(context.Context) error) error {
+	if a.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, a.timeout)
+		defer cancel()
+	}
+	for retry := 0; ; retry++ {
+		err := f(ctx)
+		if err == nil || retry >= a.retryPolicy.MaxRetries || !retryable(err) {
+			return err
+		}
+		select {
+		case <-time.After(a.retryPolicy.Delay(retry)):
+		case <-ctx.Done():
+			return err
+		}
+	}
+}
@@ -68,9 +68,11 @@ import (
 	"fmt"
 	"io/ioutil"
 	"strings"
+	"time"
 
 	"golang.org/x/oauth2"
 	"golang.org/x/oauth2/google"
+	cloudkms "google.golang.org/api/cloudkms/v1"
 
 	"github.com/tsingson/tink/golang/core/registry"
 	"github.com/tsingson/tink/golang/tink"
@@ -86,8 +88,10 @@ var (
 
 // GCPClient represents a client that connects to the GCP KMS backend.
 type GCPClient struct {
-	keyURI string
-	kms    *cloudkms.Service
+	keyURI      string
+	kms         *cloudkms.Service
+	retryPolicy RetryPolicy
+	timeout     time.Duration
 }
 
 var _ registry.KMSClient = (*GCPClient)(nil)
@@ -157,11 +161,17 @@ func (g *GCPClient) GetAEAD(keyURI string) (tink.AEAD, error) {
 	if len(g.keyURI) > 0 && strings.Compare(strings.ToLower(g.keyURI), strings.ToLower(keyURI)) != 0 {
 		return nil, fmt.Errorf("this client is bound to %s, cannot load keys bound to %s", g.keyURI, keyURI)
 	}
-	uri, err := validateTrimKMSPrefix(g.keyURI, gcpPrefix)
+	uri, err := validateTrimKMSPrefix(keyURI, gcpPrefix)
 	if err != nil {
 		return nil, err
 	}
-	return NewGCPAEAD(uri, g.kms), nil
+	if g.kms == nil {
+		return nil, errors.New("the client has no service; load credentials first")
+	}
+	a := NewGCPAEAD(uri, g.kms)
+	a.retryPolicy = g.retryPolicy
+	a.timeout = g.timeout
+	return a, nil
 }
 
 func validateKMSPrefix(keyURI, prefix string) bool {
@@ -0,0 +1,267 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package gcpkms
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	cloudkms "google.golang.org/api/cloudkms/v1"
+	"google.golang.org/api/option"
+
+	"github.com/tsingson/tink/golang/aead"
+	"github.com/tsingson/tink/golang/core/registry"
+	"github.com/tsingson/tink/golang/keyset"
+	subtleaead "github.com/tsingson/tink/golang/subtle/aead"
+	"github.com/tsingson/tink/golang/subtle/random"
+	"github.com/tsingson/tink/golang/tink"
+)
+
+const (
+	fakeKeyName      = "projects/p/locations/global/keyRings/r/cryptoKeys/k1"
+	otherFakeKeyName = "projects/p/locations/global/keyRings/r/cryptoKeys/k2"
+)
+
+// fakeKMSServer serves the encrypt and decrypt methods of Cloud KMS. Each key name gets
+// its own AES-GCM key. The first failures requests fail with HTTP status 503.
+type fakeKMSServer struct {
+	failures int32
+	requests int32
+	delay    time.Duration
+
+	mu   sync.Mutex
+	keys map[string]tink.AEAD
+}
+
+func newFakeKMSServer() *fakeKMSServer {
+	return &fakeKMSServer{keys: make(map[string]tink.AEAD)}
+}
+
+func (s *fakeKMSServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	atomic.AddInt32(&s.requests, 1)
+	if atomic.AddInt32(&s.failures, -1) >= 0 {
+		writeGoogleError(w, http.StatusServiceUnavailable, "unavailable")
+		return
+	}
+	// The request is canceled only after its body was read.
+	var req struct {
+		Plaintext                   string `json:"plaintext"`
+		Ciphertext                  string `json:"ciphertext"`
+		AdditionalAuthenticatedData string `json:"additionalAuthenticatedData"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeGoogleError(w, http.StatusBadRequest, "invalid request")
+		return
+	}
+	select {
+	case <-time.After(s.delay):
+	case <-r.Context().Done():
+		return
+	}
+	ad, err := base64.URLEncoding.DecodeString(req.AdditionalAuthenticatedData)
+	if err != nil {
+		writeGoogleError(w, http.StatusBadRequest, "invalid additional authenticated data")
+		return
+	}
+	path := strings.TrimPrefix(r.URL.Path, "/v1/")
+	switch {
+	case strings.HasSuffix(path, ":encrypt"):
+		name := strings.TrimSuffix(path, ":encrypt")
+		pt, err := base64.URLEncoding.DecodeString(req.Plaintext)
+		if err != nil {
+			writeGoogleError(w, http.StatusBadRequest, "invalid plaintext")
+			return
+		}
+		ct, err := s.key(name).Encrypt(pt, ad)
+		if err != nil {
+			writeGoogleError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		json.NewEncoder(w).Encode(&cloudkms.EncryptResponse{Name: name, Ciphertext: base64.StdEncoding.EncodeToString(ct)})
+	case strings.HasSuffix(path, ":decrypt"):
+		ct, err := base64.URLEncoding.DecodeString(req.Ciphertext)
+		if err != nil {
+			writeGoogleError(w, http.StatusBadRequest, "invalid ciphertext")
+			return
+		}
+		pt, err := s.key(strings.TrimSuffix(path, ":decrypt")).Decrypt(ct, ad)
+		if err != nil {
+			writeGoogleError(w, http.StatusBadRequest, "decryption failed")
+			return
+		}
+		json.NewEncoder(w).Encode(&cloudkms.DecryptResponse{Plaintext: base64.StdEncoding.EncodeToString(pt)})
+	default:
+		writeGoogleError(w, http.StatusNotFound, "not found")
+	}
+}
+
+func (s *fakeKMSServer) key(name string) tink.AEAD {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if a, ok := s.keys[name]; ok {
+		return a
+	}
+	a, err := subtleaead.NewAESGCM(random.GetRandomBytes(32))
+	if err != nil {
+		panic(err)
+	}
+	s.keys[name] = a
+	return a
+}
+
+func writeGoogleError(w http.ResponseWriter, code int, msg string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(map[string]interface{}{"error": map[string]interface{}{"code": code, "message": msg}})
+}
+
+func newAEADWithOptions(t *testing.T, srv *httptest.Server, opts ...ClientOption) tink.AEAD {
+	t.Helper()
+	opts = append([]ClientOption{WithEndpoint(srv.URL + "/"), WithHTTPClient(srv.Client())}, opts...)
+	c, err := NewGCPClientWithOptions(gcpPrefix+fakeKeyName, opts...)
+	if err != nil {
+		t.Fatalf("NewGCPClientWithOptions() err = %v, want nil", err)
+	}
+	a, err := c.GetAEAD(gcpPrefix + fakeKeyName)
+	if err != nil {
+		t.Fatalf("c.GetAEAD() err = %v, want nil", err)
+	}
+	return a
+}
+
+func TestClientWithEndpointAndHTTPClient(t *testing.T) {
+	srv := httptest.NewServer(newFakeKMSServer())
+	defer srv.Close()
+	c, err := NewGCPClientWithOptions(gcpPrefix, WithEndpoint(srv.URL+"/"), WithHTTPClient(srv.Client()))
+	if err != nil {
+		t.Fatalf("NewGCPClientWithOptions() err = %v, want nil", err)
+	}
+	r := registry.New()
+	r.RegisterKMSClient(c)
+
+	// The client is not bound to a key URI and serves all keys.
+	for _, keyURI := range []string{gcpPrefix + fakeKeyName, gcpPrefix + otherFakeKeyName} {
+		kh, err := keyset.NewHandle(aead.KMSEnvelopeAEADKeyTemplate(keyURI, aead.AES128GCMKeyTemplate()))
+		if err != nil {
+			t.Fatalf("keyset.NewHandle() err = %v, want nil", err)
+		}
+		a, err := aead.NewWithRegistry(kh, r)
+		if err != nil {
+			t.Fatalf("aead.NewWithRegistry() err = %v, want nil", err)
+		}
+		if err := basicAEADTest(t, a); err != nil {
+			t.Errorf("error in basic aead tests: %v", err)
+		}
+	}
+}
+
+func TestClientWithService(t *testing.T) {
+	srv := httptest.NewServer(newFakeKMSServer())
+	defer srv.Close()
+	service, err := cloudkms.NewService(context.Background(), option.WithEndpoint(srv.URL+"/"), option.WithHTTPClient(srv.Client()))
+	if err != nil {
+		t.Fatalf("cloudkms.NewService() err = %v, want nil", err)
+	}
+	c, err := NewGCPClientWithOptions(gcpPrefix+fakeKeyName, WithService(service))
+	if err != nil {
+		t.Fatalf("NewGCPClientWithOptions() err = %v, want nil", err)
+	}
+	a, err := c.GetAEAD(gcpPrefix + fakeKeyName)
+	if err != nil {
+		t.Fatalf("c.GetAEAD() err = %v, want nil", err)
+	}
+	if err := basicAEADTest(t, a); err != nil {
+		t.Errorf("error in basic aead tests: %v", err)
+	}
+	if _, err := c.GetAEAD(gcpPrefix + otherFakeKeyName); err == nil {
+		t.Errorf("c.GetAEAD() with other key URI: err = nil, want error")
+	}
+}
+
+func TestClientWithRetryPolicy(t *testing.T) {
+	s := newFakeKMSServer()
+	s.failures = 2
+	srv := httptest.NewServer(s)
+	defer srv.Close()
+	a := newAEADWithOptions(t, srv, WithRetryPolicy(RetryPolicy{MaxRetries: 2, MinDelay: time.Millisecond}))
+	pt := []byte("plaintext")
+	ct, err := a.Encrypt(pt, []byte("ad"))
+	if err != nil {
+		t.Fatalf("a.Encrypt() err = %v, want nil", err)
+	}
+	if got := atomic.LoadInt32(&s.requests); got != 3 {
+		t.Errorf("number of requests = %d, want 3", got)
+	}
+	got, err := a.Decrypt(ct, []byte("ad"))
+	if err != nil || !bytes.Equal(got, pt) {
+		t.Errorf("a.Decrypt() = %q, %v, want %q, nil", got, err, pt)
+	}
+
+	// Errors that are not retryable are returned immediately.
+	atomic.StoreInt32(&s.requests, 0)
+	if _, err := a.Decrypt(ct, []byte("other ad")); err == nil {
+		t.Errorf("a.Decrypt() with wrong associated data: err = nil, want error")
+	}
+	if got := atomic.LoadInt32(&s.requests); got != 1 {
+		t.Errorf("number of requests = %d, want 1", got)
+	}
+
+	// By default, failed requests are not retried.
+	s.failures = 1
+	atomic.StoreInt32(&s.requests, 0)
+	a = newAEADWithOptions(t, srv)
+	if _, err := a.Encrypt(pt, nil); err == nil {
+		t.Errorf("a.Encrypt() err = nil, want error")
+	}
+	if got := atomic.LoadInt32(&s.requests); got != 1 {
+		t.Errorf("number of requests = %d, want 1", got)
+	}
+}
+
+func TestClientWithTimeout(t *testing.T) {
+	s := newFakeKMSServer()
+	s.delay = time.Minute
+	srv := httptest.NewServer(s)
+	defer srv.Close()
+	a := newAEADWithOptions(t, srv, WithTimeout(10*time.Millisecond), WithRetryPolicy(RetryPolicy{MaxRetries: 3}))
+	if _, err := a.Encrypt([]byte("plaintext"), nil); err == nil {
+		t.Errorf("a.Encrypt() err = nil, want timeout")
+	}
+}
+
+func TestClientWithInvalidOptions(t *testing.T) {
+	service, err := cloudkms.New(http.DefaultClient)
+	if err != nil {
+		t.Fatalf("cloudkms.New() err = %v, want nil", err)
+	}
+	if _, err := NewGCPClientWithOptions(gcpPrefix, WithService(service), WithEndpoint("http://localhost/")); err == nil {
+		t.Errorf("NewGCPClientWithOptions() with WithService and WithEndpoint: err = nil, want error")
+	}
+	if _, err := NewGCPClientWithOptions("aws-kms://key", WithService(service)); err == nil {
+		t.Errorf("NewGCPClientWithOptions() with foreign URI: err = nil, want error")
+	}
+	if _, err := NewGCPClientWithOptions(gcpPrefix, WithCredentialsFile("/nonexistent/credentials.json")); err == nil {
+		t.Errorf("NewGCPClientWithOptions() with missing credentials file: err = nil, want error")
+	}
+}
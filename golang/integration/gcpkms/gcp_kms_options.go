@@ -0,0 +1,140 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package gcpkms
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	cloudkms "google.golang.org/api/cloudkms/v1"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/api/option"
+
+	"github.com/tsingson/tink/golang/integration/internal/retry"
+)
+
+// RetryPolicy configures how requests to Cloud KMS that failed with a retryable error,
+// i.e. HTTP status 429 or 5xx or a temporary network error, are retried. The delay
+// before the n-th retry is MinDelay * 2^(n-1), but at most MaxDelay.
+type RetryPolicy = retry.Policy
+
+// retryable returns true if a request that failed with err may succeed when retried.
+func retryable(err error) bool {
+	switch e := err.(type) {
+	case *googleapi.Error:
+		return e.Code == http.StatusTooManyRequests || e.Code >= http.StatusInternalServerError
+	case net.Error:
+		return e.Temporary()
+	}
+	return false
+}
+
+// ClientOption configures a GCPClient created by NewGCPClientWithOptions.
+type ClientOption func(*clientOptions)
+
+type clientOptions struct {
+	endpoint        string
+	httpClient      *http.Client
+	credentialsFile string
+	retryPolicy     RetryPolicy
+	timeout         time.Duration
+	service         *cloudkms.Service
+}
+
+// WithEndpoint sets the base URL of Cloud KMS, e.g. a private endpoint or the URL of an
+// emulator. The default is "https://cloudkms.googleapis.com/".
+func WithEndpoint(endpoint string) ClientOption {
+	return func(o *clientOptions) { o.endpoint = endpoint }
+}
+
+// WithHTTPClient sets the HTTP client used for requests to Cloud KMS. The client must
+// authenticate the requests itself.
+func WithHTTPClient(c *http.Client) ClientOption {
+	return func(o *clientOptions) { o.httpClient = c }
+}
+
+// WithCredentialsFile sets the credentials file used to authenticate requests. By
+// default, the application default credentials are used.
+func WithCredentialsFile(path string) ClientOption {
+	return func(o *clientOptions) { o.credentialsFile = path }
+}
+
+// WithRetryPolicy sets how failed requests to Cloud KMS are retried. By default, failed
+// requests are not retried.
+func WithRetryPolicy(p RetryPolicy) ClientOption {
+	return func(o *clientOptions) { o.retryPolicy = p }
+}
+
+// WithTimeout bounds the duration of each Encrypt and Decrypt call of the AEADs of the
+// client, including retries.
+func WithTimeout(timeout time.Duration) ClientOption {
+	return func(o *clientOptions) { o.timeout = timeout }
+}
+
+// WithService sets the Cloud KMS service to use, e.g. an existing *cloudkms.Service. It
+// cannot be combined with the options that configure a new service.
+func WithService(service *cloudkms.Service) ClientOption {
+	return func(o *clientOptions) { o.service = service }
+}
+
+// NewGCPClientWithOptions returns a new client to GCP KMS configured by opts. Unlike
+// NewGCPClient, the service is created immediately, using the application default
+// credentials unless WithCredentialsFile, WithHTTPClient or WithService is given.
+func NewGCPClientWithOptions(URI string, opts ...ClientOption) (*GCPClient, error) {
+	if !strings.HasPrefix(strings.ToLower(URI), gcpPrefix) {
+		return nil, fmt.Errorf("key URI must start with %s", gcpPrefix)
+	}
+	o := new(clientOptions)
+	for _, opt := range opts {
+		opt(o)
+	}
+	keyURI := URI
+	if len(URI) == len(gcpPrefix) {
+		keyURI = ""
+	}
+	c := &GCPClient{
+		keyURI:      keyURI,
+		retryPolicy: o.retryPolicy,
+		timeout:     o.timeout,
+	}
+	if o.service != nil {
+		if o.endpoint != "" || o.httpClient != nil || o.credentialsFile != "" {
+			return nil, errors.New("WithService cannot be combined with options configuring the service")
+		}
+		c.kms = o.service
+		return c, nil
+	}
+	var serviceOpts []option.ClientOption
+	if o.endpoint != "" {
+		serviceOpts = append(serviceOpts, option.WithEndpoint(o.endpoint))
+	}
+	if o.httpClient != nil {
+		serviceOpts = append(serviceOpts, option.WithHTTPClient(o.httpClient))
+	}
+	if o.credentialsFile != "" {
+		serviceOpts = append(serviceOpts, option.WithCredentialsFile(o.credentialsFile))
+	}
+	kmsService, err := cloudkms.NewService(context.Background(), serviceOpts...)
+	if err != nil {
+		return nil, err
+	}
+	c.kms = kmsService
+	return c, nil
+}
@@ -0,0 +1,139 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package hcvault_test
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/tsingson/tink/golang/subtle/aead"
+	"github.com/tsingson/tink/golang/subtle/random"
+	"github.com/tsingson/tink/golang/tink"
+)
+
+const ciphertextPrefix = "vault:v1:"
+
+// fakeTransit is an http.Handler that implements the encrypt and decrypt endpoints of the
+// Transit engine of Vault. Keys are created on their first use for encryption, and the
+// context is used as associated data, as for Vault keys created with derived=true.
+type fakeTransit struct {
+	token string
+
+	mu   sync.Mutex
+	keys map[string]tink.AEAD // <mount>/<name> -> key
+}
+
+func newFakeTransit(token string) *fakeTransit {
+	return &fakeTransit{
+		token: token,
+		keys:  make(map[string]tink.AEAD),
+	}
+}
+
+func (f *fakeTransit) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "unsupported method")
+		return
+	}
+	if r.Header.Get("X-Vault-Token") != f.token {
+		writeError(w, http.StatusForbidden, "permission denied")
+		return
+	}
+	var req struct {
+		Plaintext  *string `json:"plaintext"`
+		Ciphertext string  `json:"ciphertext"`
+		Context    string  `json:"context"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	ad, err := base64.StdEncoding.DecodeString(req.Context)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "failed to base64-decode context")
+		return
+	}
+	path := strings.TrimPrefix(r.URL.Path, "/v1/")
+	switch {
+	case strings.Contains(path, "/encrypt/"):
+		if req.Plaintext == nil {
+			writeError(w, http.StatusBadRequest, "missing plaintext to encrypt")
+			return
+		}
+		pt, err := base64.StdEncoding.DecodeString(*req.Plaintext)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "failed to base64-decode plaintext")
+			return
+		}
+		ct, err := f.key(strings.Replace(path, "/encrypt/", "/", 1), true).Encrypt(pt, ad)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		writeData(w, map[string]string{"ciphertext": ciphertextPrefix + base64.StdEncoding.EncodeToString(ct)})
+	case strings.Contains(path, "/decrypt/"):
+		key := f.key(strings.Replace(path, "/decrypt/", "/", 1), false)
+		if key == nil {
+			writeError(w, http.StatusBadRequest, "encryption key not found")
+			return
+		}
+		if !strings.HasPrefix(req.Ciphertext, ciphertextPrefix) {
+			writeError(w, http.StatusBadRequest, "invalid ciphertext: no prefix")
+			return
+		}
+		ct, err := base64.StdEncoding.DecodeString(req.Ciphertext[len(ciphertextPrefix):])
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid ciphertext: could not decode base64")
+			return
+		}
+		pt, err := key.Decrypt(ct, ad)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "cipher: message authentication failed")
+			return
+		}
+		writeData(w, map[string]string{"plaintext": base64.StdEncoding.EncodeToString(pt)})
+	default:
+		writeError(w, http.StatusNotFound, "unsupported path")
+	}
+}
+
+// key returns the key with the given name, creating it if create is true.
+func (f *fakeTransit) key(name string, create bool) tink.AEAD {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if a, ok := f.keys[name]; ok || !create {
+		return a
+	}
+	a, err := aead.NewAESGCM(random.GetRandomBytes(32))
+	if err != nil {
+		panic(err)
+	}
+	f.keys[name] = a
+	return a
+}
+
+func writeData(w http.ResponseWriter, data map[string]string) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"data": data})
+}
+
+func writeError(w http.ResponseWriter, code int, msg string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(map[string][]string{"errors": {msg}})
+}
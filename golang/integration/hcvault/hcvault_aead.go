@@ -0,0 +1,141 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package hcvault
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/tsingson/tink/golang/tink"
+)
+
+// HCVaultAEAD represents a key of the Transit engine of a Vault server.
+type HCVaultAEAD struct {
+	encryptURL string
+	decryptURL string
+	token      string
+	httpClient *http.Client
+}
+
+var (
+	_ tink.AEAD            = (*HCVaultAEAD)(nil)
+	_ tink.AEADWithContext = (*HCVaultAEAD)(nil)
+)
+
+// encryptRequest is the body of requests to the encrypt endpoint.
+type encryptRequest struct {
+	Plaintext string `json:"plaintext"`
+	Context   string `json:"context,omitempty"`
+}
+
+// decryptRequest is the body of requests to the decrypt endpoint.
+type decryptRequest struct {
+	Ciphertext string `json:"ciphertext"`
+	Context    string `json:"context,omitempty"`
+}
+
+// transitResponse is the body of responses of the encrypt and decrypt endpoints.
+type transitResponse struct {
+	Data struct {
+		Plaintext  string `json:"plaintext"`
+		Ciphertext string `json:"ciphertext"`
+	} `json:"data"`
+	Errors []string `json:"errors"`
+}
+
+// Encrypt AEAD encrypts the plaintext data and uses addtionaldata from authentication.
+func (a *HCVaultAEAD) Encrypt(plaintext, additionalData []byte) ([]byte, error) {
+	return a.EncryptWithContext(context.Background(), plaintext, additionalData)
+}
+
+// EncryptWithContext is like Encrypt, but the request to Vault is bound to ctx.
+func (a *HCVaultAEAD) EncryptWithContext(ctx context.Context, plaintext, additionalData []byte) ([]byte, error) {
+	req := &encryptRequest{
+		Plaintext: base64.StdEncoding.EncodeToString(plaintext),
+		Context:   base64.StdEncoding.EncodeToString(additionalData),
+	}
+	resp, err := a.do(ctx, a.encryptURL, req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Data.Ciphertext == "" {
+		return nil, fmt.Errorf("hcvault: encrypt response has no ciphertext")
+	}
+	return []byte(resp.Data.Ciphertext), nil
+}
+
+// Decrypt AEAD decrypts the data and verified the additional data.
+func (a *HCVaultAEAD) Decrypt(ciphertext, additionalData []byte) ([]byte, error) {
+	return a.DecryptWithContext(context.Background(), ciphertext, additionalData)
+}
+
+// DecryptWithContext is like Decrypt, but the request to Vault is bound to ctx.
+func (a *HCVaultAEAD) DecryptWithContext(ctx context.Context, ciphertext, additionalData []byte) ([]byte, error) {
+	if len(ciphertext) == 0 {
+		return nil, fmt.Errorf("hcvault: ciphertext is empty")
+	}
+	req := &decryptRequest{
+		Ciphertext: string(ciphertext),
+		Context:    base64.StdEncoding.EncodeToString(additionalData),
+	}
+	resp, err := a.do(ctx, a.decryptURL, req)
+	if err != nil {
+		return nil, err
+	}
+	return base64.StdEncoding.DecodeString(resp.Data.Plaintext)
+}
+
+// do sends req to url and decodes the response.
+func (a *HCVaultAEAD) do(ctx context.Context, url string, req interface{}) (*transitResponse, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	httpReq, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	httpReq = httpReq.WithContext(ctx)
+	httpReq.Header.Set("Content-Type", "application/json")
+	if a.token != "" {
+		httpReq.Header.Set("X-Vault-Token", a.token)
+	}
+	httpResp, err := a.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("hcvault: request failed: %s", err)
+	}
+	defer httpResp.Body.Close()
+	b, err := ioutil.ReadAll(httpResp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("hcvault: cannot read response: %s", err)
+	}
+	resp := new(transitResponse)
+	if err := json.Unmarshal(b, resp); err != nil && httpResp.StatusCode == http.StatusOK {
+		return nil, fmt.Errorf("hcvault: invalid response: %s", err)
+	}
+	if httpResp.StatusCode != http.StatusOK {
+		if len(resp.Errors) > 0 {
+			return nil, fmt.Errorf("hcvault: %s: %s", httpResp.Status, strings.Join(resp.Errors, "; "))
+		}
+		return nil, fmt.Errorf("hcvault: %s", httpResp.Status)
+	}
+	return resp, nil
+}
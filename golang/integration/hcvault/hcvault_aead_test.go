@@ -0,0 +1,227 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package hcvault_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/tsingson/tink/golang/aead"
+	"github.com/tsingson/tink/golang/core/registry"
+	"github.com/tsingson/tink/golang/integration/hcvault"
+	"github.com/tsingson/tink/golang/keyset"
+	"github.com/tsingson/tink/golang/tink"
+)
+
+const token = "test-token"
+
+// newServer starts a fake Transit server and returns it with the prefix of its key URIs.
+func newServer(t *testing.T) (*httptest.Server, string) {
+	t.Helper()
+	srv := httptest.NewTLSServer(newFakeTransit(token))
+	return srv, "hcvault://" + strings.TrimPrefix(srv.URL, "https://") + "/"
+}
+
+func newClient(t *testing.T, srv *httptest.Server, uriPrefix, token string) *hcvault.HCVaultClient {
+	t.Helper()
+	tlsCfg := srv.Client().Transport.(*http.Transport).TLSClientConfig
+	client, err := hcvault.NewHCVaultClient(uriPrefix, tlsCfg, token)
+	if err != nil {
+		t.Fatalf("hcvault.NewHCVaultClient() err = %v, want nil", err)
+	}
+	return client
+}
+
+func newAEAD(t *testing.T, client *hcvault.HCVaultClient, keyURI string) tink.AEAD {
+	t.Helper()
+	a, err := client.GetAEAD(keyURI)
+	if err != nil {
+		t.Fatalf("client.GetAEAD() err = %v, want nil", err)
+	}
+	return a
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	srv, prefix := newServer(t)
+	defer srv.Close()
+	keyURI := prefix + "transit/keys/key-1"
+	a := newAEAD(t, newClient(t, srv, keyURI, token), keyURI)
+
+	testCases := []struct {
+		pt, ad []byte
+	}{
+		{[]byte("plaintext"), []byte("associated data")},
+		{[]byte("plaintext"), nil},
+		{nil, []byte("associated data")},
+		{nil, nil},
+	}
+	for _, tc := range testCases {
+		ct, err := a.Encrypt(tc.pt, tc.ad)
+		if err != nil {
+			t.Fatalf("a.Encrypt(%q, %q) err = %v, want nil", tc.pt, tc.ad, err)
+		}
+		if !bytes.HasPrefix(ct, []byte("vault:v1:")) {
+			t.Errorf("a.Encrypt(%q, %q) = %q, want Vault ciphertext", tc.pt, tc.ad, ct)
+		}
+		got, err := a.Decrypt(ct, tc.ad)
+		if err != nil || !bytes.Equal(got, tc.pt) {
+			t.Errorf("a.Decrypt() = %q, %v, want %q, nil", got, err, tc.pt)
+		}
+		if _, err := a.Decrypt(ct, []byte("other data")); err == nil {
+			t.Errorf("a.Decrypt() with wrong associated data: err = nil, want error")
+		}
+		tampered := append([]byte{}, ct...)
+		tampered[len(tampered)-2] ^= 1
+		if _, err := a.Decrypt(tampered, tc.ad); err == nil {
+			t.Errorf("a.Decrypt() with tampered ciphertext: err = nil, want error")
+		}
+	}
+
+	// Another key cannot decrypt the ciphertext.
+	ct, err := a.Encrypt([]byte("plaintext"), nil)
+	if err != nil {
+		t.Fatalf("a.Encrypt() err = %v, want nil", err)
+	}
+	other := newAEAD(t, newClient(t, srv, prefix, token), prefix+"transit/keys/key-2")
+	if _, err := other.Encrypt([]byte("plaintext"), nil); err != nil {
+		t.Fatalf("other.Encrypt() err = %v, want nil", err)
+	}
+	if _, err := other.Decrypt(ct, nil); err == nil {
+		t.Errorf("other.Decrypt() err = nil, want error")
+	}
+}
+
+func TestEnvelopeEncryption(t *testing.T) {
+	srv, prefix := newServer(t)
+	defer srv.Close()
+	keyURI := prefix + "transit/keys/envelope"
+	r := registry.New()
+	r.RegisterKMSClient(newClient(t, srv, prefix, token))
+
+	kh, err := keyset.NewHandle(aead.KMSEnvelopeAEADKeyTemplate(keyURI, aead.AES128GCMKeyTemplate()))
+	if err != nil {
+		t.Fatalf("keyset.NewHandle() err = %v, want nil", err)
+	}
+	a, err := aead.NewWithRegistry(kh, r)
+	if err != nil {
+		t.Fatalf("aead.NewWithRegistry() err = %v, want nil", err)
+	}
+	pt := []byte("plaintext")
+	ad := []byte("associated data")
+	ct, err := a.Encrypt(pt, ad)
+	if err != nil {
+		t.Fatalf("a.Encrypt() err = %v, want nil", err)
+	}
+	got, err := a.Decrypt(ct, ad)
+	if err != nil || !bytes.Equal(got, pt) {
+		t.Errorf("a.Decrypt() = %q, %v, want %q, nil", got, err, pt)
+	}
+
+	// A keyset encrypted with the master key in Vault.
+	masterKey := newAEAD(t, newClient(t, srv, prefix, token), keyURI)
+	mem := &keyset.MemReaderWriter{}
+	if err := kh.Write(mem, masterKey); err != nil {
+		t.Fatalf("kh.Write() err = %v, want nil", err)
+	}
+	if _, err := keyset.Read(mem, masterKey); err != nil {
+		t.Errorf("keyset.Read() err = %v, want nil", err)
+	}
+}
+
+func TestRequestErrors(t *testing.T) {
+	srv, prefix := newServer(t)
+	defer srv.Close()
+	keyURI := prefix + "transit/keys/key-1"
+
+	a := newAEAD(t, newClient(t, srv, prefix, "wrong-token"), keyURI)
+	if _, err := a.Encrypt([]byte("plaintext"), nil); err == nil || !strings.Contains(err.Error(), "permission denied") {
+		t.Errorf("a.Encrypt() with wrong token: err = %v, want permission denied", err)
+	}
+
+	a = newAEAD(t, newClient(t, srv, prefix, token), prefix+"transit/keys/unknown")
+	if _, err := a.Decrypt([]byte("vault:v1:AAAA"), nil); err == nil {
+		t.Errorf("a.Decrypt() with unknown key: err = nil, want error")
+	}
+	if _, err := a.Decrypt(nil, nil); err == nil {
+		t.Errorf("a.Decrypt() with empty ciphertext: err = nil, want error")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	ca := a.(tink.AEADWithContext)
+	if _, err := ca.EncryptWithContext(ctx, []byte("plaintext"), nil); err == nil {
+		t.Errorf("a.EncryptWithContext() with cancelled context: err = nil, want error")
+	}
+
+	// The TLS certificate of the server is not trusted by default.
+	client, err := hcvault.NewHCVaultClient(prefix, nil, token)
+	if err != nil {
+		t.Fatalf("hcvault.NewHCVaultClient() err = %v, want nil", err)
+	}
+	if _, err := newAEAD(t, client, keyURI).Encrypt([]byte("plaintext"), nil); err == nil {
+		t.Errorf("a.Encrypt() with untrusted certificate: err = nil, want error")
+	}
+}
+
+func TestKeyURIs(t *testing.T) {
+	if _, err := hcvault.NewHCVaultClient("gcp-kms://key", nil, token); err == nil {
+		t.Errorf("hcvault.NewHCVaultClient() with foreign URI: err = nil, want error")
+	}
+	client, err := hcvault.NewHCVaultClient("hcvault://vault.example.com/transit/", nil, token)
+	if err != nil {
+		t.Fatalf("hcvault.NewHCVaultClient() err = %v, want nil", err)
+	}
+	if !client.Supported("hcvault://vault.example.com/transit/keys/key-1") {
+		t.Errorf("client.Supported() = false, want true for URI with the client prefix")
+	}
+	if client.Supported("hcvault://other.example.com/transit/keys/key-1") {
+		t.Errorf("client.Supported() = true, want false for URI of another server")
+	}
+	if _, err := client.GetAEAD("hcvault://other.example.com/transit/keys/key-1"); err == nil {
+		t.Errorf("client.GetAEAD() with unsupported URI: err = nil, want error")
+	}
+
+	client, err = hcvault.NewHCVaultClient("hcvault://", nil, token)
+	if err != nil {
+		t.Fatalf("hcvault.NewHCVaultClient() err = %v, want nil", err)
+	}
+	valid := []string{
+		"hcvault://vault.example.com/transit/keys/key-1",
+		"hcvault://vault.example.com:8200/teams/a/transit/keys/key-1",
+	}
+	for _, uri := range valid {
+		if _, err := client.GetAEAD(uri); err != nil {
+			t.Errorf("client.GetAEAD(%q) err = %v, want nil", uri, err)
+		}
+	}
+	invalid := []string{
+		"hcvault://",
+		"hcvault:///transit/keys/key-1",
+		"hcvault://vault.example.com",
+		"hcvault://vault.example.com/transit/key-1",
+		"hcvault://vault.example.com/keys/key-1",
+		"hcvault://vault.example.com/transit/keys/",
+		"hcvault://vault.example.com/transit/keys/a/b",
+	}
+	for _, uri := range invalid {
+		if _, err := client.GetAEAD(uri); err == nil {
+			t.Errorf("client.GetAEAD(%q) err = nil, want error", uri)
+		}
+	}
+}
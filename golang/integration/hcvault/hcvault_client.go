@@ -0,0 +1,147 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+// Package hcvault provides integration with the Transit secrets engine of HashiCorp Vault.
+//
+// Key URIs have the form "hcvault://host[:port]/<mount>/keys/<name>", e.g.
+// "hcvault://vault.example.com:8200/transit/keys/my-key". Encrypt and Decrypt are mapped onto
+// the encrypt and decrypt endpoints of the Transit engine mounted at <mount>, and the
+// associated data is passed as the Vault context. Vault only uses the context with keys
+// created with derived=true, so keys used with associated data must be derived keys.
+//
+// Example:
+//
+//	package main
+//
+//	import (
+//		"os"
+//
+//		"github.com/tsingson/tink/golang/aead"
+//		"github.com/tsingson/tink/golang/core/registry"
+//		"github.com/tsingson/tink/golang/integration/hcvault"
+//		"github.com/tsingson/tink/golang/keyset"
+//	)
+//
+//	const (
+//		keyURI = "hcvault://vault.example.com:8200/transit/keys/my-key"
+//	)
+//
+//	func main() {
+//		client, err := hcvault.NewHCVaultClient(keyURI, nil, os.Getenv("VAULT_TOKEN"))
+//		if err != nil {
+//			// handle error
+//		}
+//		registry.RegisterKMSClient(client)
+//
+//		dek := aead.AES128GCMKeyTemplate()
+//		kh, err := keyset.NewHandle(aead.KMSEnvelopeAEADKeyTemplate(keyURI, dek))
+//		if err != nil {
+//			// handle error
+//		}
+//		a, err := aead.New(kh)
+//		if err != nil {
+//			// handle error
+//		}
+//
+//		ct, err = a.Encrypt([]byte("secret message"), []byte("associated data"))
+//		if err != nil {
+//			// handle error
+//		}
+//	}
+package hcvault
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/tsingson/tink/golang/core/registry"
+	"github.com/tsingson/tink/golang/tink"
+)
+
+const (
+	hcvaultPrefix = "hcvault://"
+)
+
+// HCVaultClient represents a client that connects to the Transit engine of a Vault server.
+type HCVaultClient struct {
+	uriPrefix  string
+	token      string
+	httpClient *http.Client
+}
+
+var _ registry.KMSClient = (*HCVaultClient)(nil)
+
+// NewHCVaultClient returns a new client to Vault. The client supports all key URIs starting
+// with uriPrefix, which can be a single key URI or e.g. "hcvault://" for all keys.
+// Requests are sent over HTTPS using tlsCfg, or the default TLS configuration if tlsCfg is
+// nil, and are authenticated with the given Vault token.
+func NewHCVaultClient(uriPrefix string, tlsCfg *tls.Config, token string) (*HCVaultClient, error) {
+	if !strings.HasPrefix(strings.ToLower(uriPrefix), hcvaultPrefix) {
+		return nil, fmt.Errorf("hcvault: key URI must start with %s", hcvaultPrefix)
+	}
+	return &HCVaultClient{
+		uriPrefix: uriPrefix,
+		token:     token,
+		httpClient: &http.Client{
+			Transport: &http.Transport{
+				Proxy:           http.ProxyFromEnvironment,
+				TLSClientConfig: tlsCfg,
+			},
+		},
+	}, nil
+}
+
+// Supported true if this client does support keyURI
+func (c *HCVaultClient) Supported(keyURI string) bool {
+	return strings.HasPrefix(keyURI, c.uriPrefix)
+}
+
+// GetAEAD gets an AEAD backend by keyURI.
+func (c *HCVaultClient) GetAEAD(keyURI string) (tink.AEAD, error) {
+	if !c.Supported(keyURI) {
+		return nil, fmt.Errorf("hcvault: this client is bound to %s, cannot load keys bound to %s", c.uriPrefix, keyURI)
+	}
+	host, mount, name, err := parseKeyURI(keyURI)
+	if err != nil {
+		return nil, err
+	}
+	base := "https://" + host + "/v1/" + mount
+	return &HCVaultAEAD{
+		encryptURL: base + "/encrypt/" + name,
+		decryptURL: base + "/decrypt/" + name,
+		token:      c.token,
+		httpClient: c.httpClient,
+	}, nil
+}
+
+// parseKeyURI splits a key URI of the form hcvault://host/<mount>/keys/<name>.
+func parseKeyURI(keyURI string) (host, mount, name string, err error) {
+	rest := keyURI[len(hcvaultPrefix):]
+	i := strings.Index(rest, "/")
+	if i <= 0 {
+		return "", "", "", fmt.Errorf("hcvault: invalid key URI %s: missing host", keyURI)
+	}
+	host, path := rest[:i], rest[i+1:]
+	j := strings.LastIndex(path, "/keys/")
+	if j <= 0 {
+		return "", "", "", fmt.Errorf("hcvault: invalid key URI %s: path must be <mount>/keys/<name>", keyURI)
+	}
+	mount, name = path[:j], path[j+len("/keys/"):]
+	if name == "" || strings.Contains(name, "/") {
+		return "", "", "", fmt.Errorf("hcvault: invalid key URI %s: invalid key name", keyURI)
+	}
+	return host, mount, name, nil
+}
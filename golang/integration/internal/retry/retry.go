@@ -0,0 +1,55 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+// Package retry holds the retry policy shared by the KMS integrations.
+package retry
+
+import "time"
+
+const (
+	// DefaultMinDelay is the delay before the first retry if Policy.MinDelay is zero.
+	DefaultMinDelay = 100 * time.Millisecond
+	// DefaultMaxDelay is the maximum delay between retries if Policy.MaxDelay is zero.
+	DefaultMaxDelay = 10 * time.Second
+)
+
+// Policy configures how requests to a KMS that failed with a retryable error are
+// retried. The delay before the n-th retry is MinDelay * 2^(n-1), but at most MaxDelay.
+type Policy struct {
+	// MaxRetries is the maximum number of retries of a request.
+	MaxRetries int
+	// MinDelay is the delay before the first retry. If zero, 100ms is used.
+	MinDelay time.Duration
+	// MaxDelay is the maximum delay between retries. If zero, 10s is used.
+	MaxDelay time.Duration
+}
+
+// Delay returns the delay before the retry with the given 0-based index.
+func (p Policy) Delay(retry int) time.Duration {
+	min, max := p.MinDelay, p.MaxDelay
+	if min <= 0 {
+		min = DefaultMinDelay
+	}
+	if max <= 0 {
+		max = DefaultMaxDelay
+	}
+	d := min
+	for i := 0; i < retry && d < max; i++ {
+		d *= 2
+	}
+	if d > max {
+		d = max
+	}
+	return d
+}
@@ -0,0 +1,38 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package retry_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/tsingson/tink/golang/integration/internal/retry"
+)
+
+func TestPolicyDelay(t *testing.T) {
+	p := retry.Policy{MinDelay: time.Second, MaxDelay: 5 * time.Second}
+	want := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second}
+	for i, w := range want {
+		if got := p.Delay(i); got != w {
+			t.Errorf("p.Delay(%d) = %v, want %v", i, got, w)
+		}
+	}
+	if got := (retry.Policy{}).Delay(0); got != retry.DefaultMinDelay {
+		t.Errorf("default delay = %v, want %v", got, retry.DefaultMinDelay)
+	}
+	if got := (retry.Policy{}).Delay(20); got != retry.DefaultMaxDelay {
+		t.Errorf("default maximum delay = %v, want %v", got, retry.DefaultMaxDelay)
+	}
+}
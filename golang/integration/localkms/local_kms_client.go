@@ -0,0 +1,161 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+// Package localkms provides a KMS client whose master keys are cleartext AEAD keysets
+// stored in local files, for development and hermetic tests.
+//
+// Key URIs have the form "local-kms://path/to/keyset.json", where the path is either
+// absolute, as in "local-kms:///etc/keys/master.json", or relative to the working
+// directory. Keysets in files ending with ".json" are read in JSON format, all others
+// in binary format. A keyset can be created with Tinkey:
+//
+//	tinkey create-keyset --key-template AES256_GCM --out-format json --out master.json
+//
+// Example:
+//
+//	package main
+//
+//	import (
+//		"github.com/tsingson/tink/golang/aead"
+//		"github.com/tsingson/tink/golang/core/registry"
+//		"github.com/tsingson/tink/golang/integration/localkms"
+//		"github.com/tsingson/tink/golang/keyset"
+//	)
+//
+//	const (
+//		keyURI = "local-kms://master.json"
+//	)
+//
+//	func main() {
+//		client, err := localkms.NewLocalClient(keyURI)
+//		if err != nil {
+//			// handle error
+//		}
+//		registry.RegisterKMSClient(client)
+//
+//		dek := aead.AES128GCMKeyTemplate()
+//		kh, err := keyset.NewHandle(aead.KMSEnvelopeAEADKeyTemplate(keyURI, dek))
+//		if err != nil {
+//			// handle error
+//		}
+//		a, err := aead.New(kh)
+//		if err != nil {
+//			// handle error
+//		}
+//
+//		ct, err = a.Encrypt([]byte("secret message"), []byte("associated data"))
+//		if err != nil {
+//			// handle error
+//		}
+//	}
+//
+// The master keys are not protected in any way, so this client must not be used in
+// production.
+package localkms
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/tsingson/tink/golang/aead"
+	"github.com/tsingson/tink/golang/core/registry"
+	"github.com/tsingson/tink/golang/insecurecleartextkeyset"
+	"github.com/tsingson/tink/golang/keyset"
+	"github.com/tsingson/tink/golang/tink"
+)
+
+const (
+	localPrefix = "local-kms://"
+)
+
+// LocalClient is a KMS client for master keys stored in local keyset files.
+type LocalClient struct {
+	keyURI string
+
+	mu    sync.Mutex
+	aeads map[string]tink.AEAD // path -> AEAD of the keyset in the file
+}
+
+var _ registry.KMSClient = (*LocalClient)(nil)
+
+// NewLocalClient returns a new client for local master keys. If uri is only the
+// "local-kms://" prefix, the client supports all local key URIs; otherwise it is bound to
+// the given key URI.
+func NewLocalClient(uri string) (*LocalClient, error) {
+	if !strings.HasPrefix(strings.ToLower(uri), localPrefix) {
+		return nil, fmt.Errorf("localkms: key URI must start with %s", localPrefix)
+	}
+	keyURI := uri
+	if len(uri) == len(localPrefix) {
+		keyURI = ""
+	}
+	return &LocalClient{
+		keyURI: keyURI,
+		aeads:  make(map[string]tink.AEAD),
+	}, nil
+}
+
+// Supported true if this client does support keyURI
+func (c *LocalClient) Supported(keyURI string) bool {
+	if len(c.keyURI) > 0 {
+		return c.keyURI == keyURI
+	}
+	return strings.HasPrefix(strings.ToLower(keyURI), localPrefix) && len(keyURI) > len(localPrefix)
+}
+
+// GetAEAD gets an AEAD backend by keyURI. The keyset file is read on the first call for
+// a key URI; later changes of the file are not picked up.
+func (c *LocalClient) GetAEAD(keyURI string) (tink.AEAD, error) {
+	if !c.Supported(keyURI) {
+		return nil, fmt.Errorf("localkms: unsupported key URI %s", keyURI)
+	}
+	path := keyURI[len(localPrefix):]
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if a, ok := c.aeads[path]; ok {
+		return a, nil
+	}
+	a, err := readAEAD(path)
+	if err != nil {
+		return nil, err
+	}
+	c.aeads[path] = a
+	return a, nil
+}
+
+// readAEAD returns the AEAD primitive of the keyset in the given file.
+func readAEAD(path string) (tink.AEAD, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("localkms: cannot open keyset: %s", err)
+	}
+	defer f.Close()
+	var r keyset.Reader
+	if strings.HasSuffix(strings.ToLower(path), ".json") {
+		r = keyset.NewJSONReader(f)
+	} else {
+		r = keyset.NewBinaryReader(f)
+	}
+	h, err := insecurecleartextkeyset.Read(r)
+	if err != nil {
+		return nil, fmt.Errorf("localkms: cannot read keyset %s: %s", path, err)
+	}
+	a, err := aead.New(h)
+	if err != nil {
+		return nil, fmt.Errorf("localkms: keyset %s is not an AEAD keyset: %s", path, err)
+	}
+	return a, nil
+}
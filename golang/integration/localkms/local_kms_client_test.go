@@ -0,0 +1,190 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package localkms_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+
+	"github.com/tsingson/tink/golang/aead"
+	"github.com/tsingson/tink/golang/core/registry"
+	"github.com/tsingson/tink/golang/insecurecleartextkeyset"
+	"github.com/tsingson/tink/golang/integration/localkms"
+	"github.com/tsingson/tink/golang/keyset"
+	"github.com/tsingson/tink/golang/mac"
+)
+
+// writeKeyset writes a new keyset generated with the AES256-GCM template to path.
+func writeKeyset(t *testing.T, path string, json bool) {
+	t.Helper()
+	h, err := keyset.NewHandle(aead.AES256GCMKeyTemplate())
+	if err != nil {
+		t.Fatalf("keyset.NewHandle() err = %v, want nil", err)
+	}
+	var b bytes.Buffer
+	var w keyset.Writer = keyset.NewBinaryWriter(&b)
+	if json {
+		w = keyset.NewJSONWriter(&b)
+	}
+	if err := insecurecleartextkeyset.Write(h, w); err != nil {
+		t.Fatalf("insecurecleartextkeyset.Write() err = %v, want nil", err)
+	}
+	if err := ioutil.WriteFile(path, b.Bytes(), 0600); err != nil {
+		t.Fatalf("ioutil.WriteFile() err = %v, want nil", err)
+	}
+}
+
+func tempDir(t *testing.T) string {
+	t.Helper()
+	dir, err := ioutil.TempDir("", "localkms")
+	if err != nil {
+		t.Fatalf("ioutil.TempDir() err = %v, want nil", err)
+	}
+	return dir
+}
+
+func TestEnvelopeEncryption(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	for _, name := range []string{"master.json", "master.bin"} {
+		path := filepath.Join(dir, name)
+		writeKeyset(t, path, filepath.Ext(name) == ".json")
+		keyURI := "local-kms://" + path
+
+		client, err := localkms.NewLocalClient("local-kms://")
+		if err != nil {
+			t.Fatalf("localkms.NewLocalClient() err = %v, want nil", err)
+		}
+		r := registry.New()
+		r.RegisterKMSClient(client)
+
+		kh, err := keyset.NewHandle(aead.KMSEnvelopeAEADKeyTemplate(keyURI, aead.AES128GCMKeyTemplate()))
+		if err != nil {
+			t.Fatalf("keyset.NewHandle() err = %v, want nil", err)
+		}
+		a, err := aead.NewWithRegistry(kh, r)
+		if err != nil {
+			t.Fatalf("%s: aead.NewWithRegistry() err = %v, want nil", name, err)
+		}
+		pt := []byte("plaintext")
+		ct, err := a.Encrypt(pt, []byte("ad"))
+		if err != nil {
+			t.Fatalf("%s: a.Encrypt() err = %v, want nil", name, err)
+		}
+
+		// A second client reading the same file can decrypt.
+		other, err := localkms.NewLocalClient(keyURI)
+		if err != nil {
+			t.Fatalf("localkms.NewLocalClient() err = %v, want nil", err)
+		}
+		r2 := registry.New()
+		r2.RegisterKMSClient(other)
+		a2, err := aead.NewWithRegistry(kh, r2)
+		if err != nil {
+			t.Fatalf("%s: aead.NewWithRegistry() err = %v, want nil", name, err)
+		}
+		got, err := a2.Decrypt(ct, []byte("ad"))
+		if err != nil || !bytes.Equal(got, pt) {
+			t.Errorf("%s: a2.Decrypt() = %q, %v, want %q, nil", name, got, err, pt)
+		}
+	}
+}
+
+func TestKeysetWithMasterKey(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "master.json")
+	writeKeyset(t, path, true)
+	keyURI := "local-kms://" + path
+
+	client, err := localkms.NewLocalClient(keyURI)
+	if err != nil {
+		t.Fatalf("localkms.NewLocalClient() err = %v, want nil", err)
+	}
+	masterKey, err := client.GetAEAD(keyURI)
+	if err != nil {
+		t.Fatalf("client.GetAEAD() err = %v, want nil", err)
+	}
+	h, err := keyset.NewHandle(mac.HMACSHA256Tag256KeyTemplate())
+	if err != nil {
+		t.Fatalf("keyset.NewHandle() err = %v, want nil", err)
+	}
+	mem := &keyset.MemReaderWriter{}
+	if err := h.Write(mem, masterKey); err != nil {
+		t.Fatalf("h.Write() err = %v, want nil", err)
+	}
+	h2, err := keyset.Read(mem, masterKey)
+	if err != nil {
+		t.Fatalf("keyset.Read() err = %v, want nil", err)
+	}
+	if !proto.Equal(insecurecleartextkeyset.KeysetMaterial(h), insecurecleartextkeyset.KeysetMaterial(h2)) {
+		t.Errorf("keyset.Read() = %v, want %v", h2, h)
+	}
+}
+
+func TestLocalClientWithInvalidInput(t *testing.T) {
+	if _, err := localkms.NewLocalClient("aws-kms://key"); err == nil {
+		t.Errorf("localkms.NewLocalClient() err = nil, want error for foreign URI")
+	}
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	client, err := localkms.NewLocalClient("local-kms://")
+	if err != nil {
+		t.Fatalf("localkms.NewLocalClient() err = %v, want nil", err)
+	}
+	if client.Supported("local-kms://") || client.Supported("gcp-kms://key") {
+		t.Errorf("client.Supported() = true, want false for an empty path and a foreign URI")
+	}
+	if _, err := client.GetAEAD("local-kms://" + filepath.Join(dir, "missing.json")); err == nil {
+		t.Errorf("client.GetAEAD() err = nil, want error for missing file")
+	}
+	garbage := filepath.Join(dir, "garbage.json")
+	if err := ioutil.WriteFile(garbage, []byte("{not a keyset"), 0600); err != nil {
+		t.Fatalf("ioutil.WriteFile() err = %v, want nil", err)
+	}
+	if _, err := client.GetAEAD("local-kms://" + garbage); err == nil {
+		t.Errorf("client.GetAEAD() err = nil, want error for invalid keyset")
+	}
+
+	macKeyset := filepath.Join(dir, "mac.bin")
+	h, err := keyset.NewHandle(mac.HMACSHA256Tag256KeyTemplate())
+	if err != nil {
+		t.Fatalf("keyset.NewHandle() err = %v, want nil", err)
+	}
+	var b bytes.Buffer
+	if err := insecurecleartextkeyset.Write(h, keyset.NewBinaryWriter(&b)); err != nil {
+		t.Fatalf("insecurecleartextkeyset.Write() err = %v, want nil", err)
+	}
+	if err := ioutil.WriteFile(macKeyset, b.Bytes(), 0600); err != nil {
+		t.Fatalf("ioutil.WriteFile() err = %v, want nil", err)
+	}
+	if _, err := client.GetAEAD("local-kms://" + macKeyset); err == nil {
+		t.Errorf("client.GetAEAD() err = nil, want error for MAC keyset")
+	}
+
+	bound, err := localkms.NewLocalClient("local-kms://" + macKeyset)
+	if err != nil {
+		t.Fatalf("localkms.NewLocalClient() err = %v, want nil", err)
+	}
+	if _, err := bound.GetAEAD("local-kms://" + garbage); err == nil {
+		t.Errorf("bound.GetAEAD() err = nil, want error for a different key URI")
+	}
+}
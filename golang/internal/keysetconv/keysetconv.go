@@ -0,0 +1,58 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+// Package keysetconv holds the helpers shared by the packages that convert keysets from and
+// to other key formats.
+package keysetconv
+
+import (
+	"github.com/golang/protobuf/proto"
+
+	"github.com/tsingson/tink/golang/core/registry"
+	tinkpb "github.com/tsingson/tink/proto/tink_go_proto"
+)
+
+// NewKeyData serializes key and checks that the registered key manager for typeURL accepts it.
+func NewKeyData(typeURL string, key proto.Message, materialType tinkpb.KeyData_KeyMaterialType) (*tinkpb.KeyData, error) {
+	serialized, err := proto.Marshal(key)
+	if err != nil {
+		return nil, err
+	}
+	km, err := registry.GetKeyManager(typeURL)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := km.Primitive(serialized); err != nil {
+		return nil, err
+	}
+	return &tinkpb.KeyData{
+		TypeUrl:         typeURL,
+		Value:           serialized,
+		KeyMaterialType: materialType,
+	}, nil
+}
+
+// EnabledKeys returns the enabled keys of ks, the primary key first and the other keys in
+// keyset order.
+func EnabledKeys(ks *tinkpb.Keyset) []*tinkpb.Keyset_Key {
+	var keys []*tinkpb.Keyset_Key
+	for _, primary := range []bool{true, false} {
+		for _, key := range ks.Key {
+			if key.Status == tinkpb.KeyStatusType_ENABLED && (key.KeyId == ks.PrimaryKeyId) == primary {
+				keys = append(keys, key)
+			}
+		}
+	}
+	return keys
+}
@@ -0,0 +1,67 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package keysetconv_test
+
+import (
+	"testing"
+
+	"github.com/tsingson/tink/golang/internal/keysetconv"
+	_ "github.com/tsingson/tink/golang/mac" // register the HMAC key manager
+	"github.com/tsingson/tink/golang/testutil"
+	commonpb "github.com/tsingson/tink/proto/common_go_proto"
+	hmacpb "github.com/tsingson/tink/proto/hmac_go_proto"
+	tinkpb "github.com/tsingson/tink/proto/tink_go_proto"
+)
+
+func TestNewKeyData(t *testing.T) {
+	key := testutil.NewHMACKey(commonpb.HashType_SHA256, 32)
+	kd, err := keysetconv.NewKeyData(testutil.HMACTypeURL, key, tinkpb.KeyData_SYMMETRIC)
+	if err != nil {
+		t.Fatalf("keysetconv.NewKeyData() err = %v", err)
+	}
+	if kd.TypeUrl != testutil.HMACTypeURL || kd.KeyMaterialType != tinkpb.KeyData_SYMMETRIC {
+		t.Errorf("got type URL %s and material type %s, want %s and SYMMETRIC", kd.TypeUrl, kd.KeyMaterialType, testutil.HMACTypeURL)
+	}
+
+	invalid := &hmacpb.HmacKey{Params: key.Params, KeyValue: []byte{1}}
+	if _, err := keysetconv.NewKeyData(testutil.HMACTypeURL, invalid, tinkpb.KeyData_SYMMETRIC); err == nil {
+		t.Errorf("keysetconv.NewKeyData() with an invalid key: err = nil, want error")
+	}
+	if _, err := keysetconv.NewKeyData("some.unknown.type", key, tinkpb.KeyData_SYMMETRIC); err == nil {
+		t.Errorf("keysetconv.NewKeyData() with an unknown type URL: err = nil, want error")
+	}
+}
+
+func TestEnabledKeys(t *testing.T) {
+	ks := &tinkpb.Keyset{
+		PrimaryKeyId: 3,
+		Key: []*tinkpb.Keyset_Key{
+			testutil.NewDummyKey(1, tinkpb.KeyStatusType_ENABLED, tinkpb.OutputPrefixType_RAW),
+			testutil.NewDummyKey(2, tinkpb.KeyStatusType_DISABLED, tinkpb.OutputPrefixType_RAW),
+			testutil.NewDummyKey(3, tinkpb.KeyStatusType_ENABLED, tinkpb.OutputPrefixType_RAW),
+			testutil.NewDummyKey(4, tinkpb.KeyStatusType_ENABLED, tinkpb.OutputPrefixType_TINK),
+		},
+	}
+	keys := keysetconv.EnabledKeys(ks)
+	want := []uint32{3, 1, 4}
+	if len(keys) != len(want) {
+		t.Fatalf("got %d keys, want %d", len(keys), len(want))
+	}
+	for i, key := range keys {
+		if key.KeyId != want[i] {
+			t.Errorf("keys[%d].KeyId = %d, want %d", i, key.KeyId, want[i])
+		}
+	}
+}
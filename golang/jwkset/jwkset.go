@@ -0,0 +1,373 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+// Package jwkset converts public signature keysets from and to JSON Web Key Sets (RFC 7517).
+//
+// ECDSA keys map to the ES256, ES384 and ES512 algorithms, Ed25519 keys to EdDSA, RSA-SSA-PKCS1
+// keys to RS256, RS384 and RS512 and RSA-SSA-PSS keys to PS256, PS384 and PS512. The ID of a
+// Tink key is its "kid", written as a decimal number.
+//
+// Only keys with the RAW output prefix produce signatures that JWS implementations accept, so
+// only RAW keys are exported and all imported keys are RAW.
+package jwkset
+
+import (
+	"bytes"
+	"crypto/elliptic"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+
+	"github.com/golang/protobuf/proto"
+
+	"github.com/tsingson/tink/golang/internal/keysetconv"
+	"github.com/tsingson/tink/golang/keyset"
+	_ "github.com/tsingson/tink/golang/signature" // register the verifier key managers
+	"github.com/tsingson/tink/golang/subtle/random"
+	commonpb "github.com/tsingson/tink/proto/common_go_proto"
+	ecdsapb "github.com/tsingson/tink/proto/ecdsa_go_proto"
+	ed25519pb "github.com/tsingson/tink/proto/ed25519_go_proto"
+	rsassapkcs1pb "github.com/tsingson/tink/proto/rsa_ssa_pkcs1_go_proto"
+	rsassapsspb "github.com/tsingson/tink/proto/rsa_ssa_pss_go_proto"
+	tinkpb "github.com/tsingson/tink/proto/tink_go_proto"
+)
+
+const (
+	ecdsaPublicKeyTypeURL       = "type.googleapis.com/google.crypto.tink.EcdsaPublicKey"
+	ed25519PublicKeyTypeURL     = "type.googleapis.com/google.crypto.tink.Ed25519PublicKey"
+	rsaSSAPKCS1PublicKeyTypeURL = "type.googleapis.com/google.crypto.tink.RsaSsaPkcs1PublicKey"
+	rsaSSAPSSPublicKeyTypeURL   = "type.googleapis.com/google.crypto.tink.RsaSsaPssPublicKey"
+)
+
+// ecAlgorithm describes a JWS ECDSA algorithm.
+type ecAlgorithm struct {
+	name      string
+	crv       string
+	curve     commonpb.EllipticCurveType
+	ec        elliptic.Curve
+	hash      commonpb.HashType
+	coordSize int
+}
+
+var ecAlgorithms = []ecAlgorithm{
+	{"ES256", "P-256", commonpb.EllipticCurveType_NIST_P256, elliptic.P256(), commonpb.HashType_SHA256, 32},
+	{"ES384", "P-384", commonpb.EllipticCurveType_NIST_P384, elliptic.P384(), commonpb.HashType_SHA384, 48},
+	{"ES512", "P-521", commonpb.EllipticCurveType_NIST_P521, elliptic.P521(), commonpb.HashType_SHA512, 66},
+}
+
+// rsaAlgorithm describes a JWS RSA algorithm. PSS algorithms use the hash for MGF1 as well and a
+// salt as long as the hash.
+type rsaAlgorithm struct {
+	name       string
+	pss        bool
+	hash       commonpb.HashType
+	saltLength int32
+}
+
+var rsaAlgorithms = []rsaAlgorithm{
+	{"RS256", false, commonpb.HashType_SHA256, 0},
+	{"RS384", false, commonpb.HashType_SHA384, 0},
+	{"RS512", false, commonpb.HashType_SHA512, 0},
+	{"PS256", true, commonpb.HashType_SHA256, 32},
+	{"PS384", true, commonpb.HashType_SHA384, 48},
+	{"PS512", true, commonpb.HashType_SHA512, 64},
+}
+
+// jwk is a JSON Web Key holding a public signature key.
+type jwk struct {
+	Kty string `json:"kty"`
+	Use string `json:"use,omitempty"`
+	Alg string `json:"alg,omitempty"`
+	Kid string `json:"kid,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	// D is only decoded to reject private keys.
+	D string `json:"d,omitempty"`
+}
+
+type jwkSet struct {
+	Keys []*jwk `json:"keys"`
+}
+
+// ExportPublicKeys encodes the enabled keys of h as a JSON Web Key Set, the primary key first.
+// h must not contain secret key material; use keyset.Handle.Public() to obtain the public
+// keyset of a private keyset. Keys whose output prefix is not RAW, and keys whose parameters
+// match no JWS algorithm, such as DER encoded ECDSA signatures, are rejected.
+func ExportPublicKeys(h *keyset.Handle) ([]byte, error) {
+	if h == nil {
+		return nil, errors.New("jwkset: nil keyset handle")
+	}
+	mem := &keyset.MemReaderWriter{}
+	if err := h.WriteWithNoSecrets(mem); err != nil {
+		return nil, fmt.Errorf("jwkset: %s", err)
+	}
+	ks := mem.Keyset
+	set := &jwkSet{Keys: []*jwk{}}
+	for _, key := range keysetconv.EnabledKeys(ks) {
+		if key.OutputPrefixType != tinkpb.OutputPrefixType_RAW {
+			return nil, fmt.Errorf("jwkset: key %d has output prefix %s, only RAW keys produce JWS signatures", key.KeyId, key.OutputPrefixType)
+		}
+		k, err := marshalKey(key.KeyData)
+		if err != nil {
+			return nil, fmt.Errorf("jwkset: key %d: %s", key.KeyId, err)
+		}
+		k.Use = "sig"
+		k.Kid = strconv.FormatUint(uint64(key.KeyId), 10)
+		set.Keys = append(set.Keys, k)
+	}
+	if len(set.Keys) == 0 {
+		return nil, errors.New("jwkset: no keys to export")
+	}
+	return json.Marshal(set)
+}
+
+// ImportPublicKeys creates a keyset.Handle holding the signature keys of the given JSON Web Key
+// Set. The first key becomes the primary key and all keys have the RAW output prefix. A decimal
+// "kid" becomes the key ID, other keys get random IDs. Keys meant for encryption are skipped.
+func ImportPublicKeys(jwks []byte) (*keyset.Handle, error) {
+	set := new(jwkSet)
+	if err := json.Unmarshal(jwks, set); err != nil {
+		return nil, fmt.Errorf("jwkset: invalid JSON Web Key Set: %s", err)
+	}
+	ks := &tinkpb.Keyset{}
+	used := make(map[uint32]bool)
+	for i, k := range set.Keys {
+		if k == nil || k.Use == "enc" {
+			continue
+		}
+		kd, err := unmarshalKey(k)
+		if err != nil {
+			return nil, fmt.Errorf("jwkset: key %d: %s", i, err)
+		}
+		id, err := keyID(k.Kid, used)
+		if err != nil {
+			return nil, fmt.Errorf("jwkset: key %d: %s", i, err)
+		}
+		used[id] = true
+		ks.Key = append(ks.Key, &tinkpb.Keyset_Key{
+			KeyData:          kd,
+			Status:           tinkpb.KeyStatusType_ENABLED,
+			KeyId:            id,
+			OutputPrefixType: tinkpb.OutputPrefixType_RAW,
+		})
+	}
+	if len(ks.Key) == 0 {
+		return nil, errors.New("jwkset: no signature keys found")
+	}
+	ks.PrimaryKeyId = ks.Key[0].KeyId
+	return keyset.NewHandleWithNoSecrets(ks)
+}
+
+// keyID returns the key ID given by kid, or a random unused ID if kid is not a decimal number.
+func keyID(kid string, used map[uint32]bool) (uint32, error) {
+	if id, err := strconv.ParseUint(kid, 10, 32); err == nil {
+		if used[uint32(id)] {
+			return 0, fmt.Errorf("duplicate kid %q", kid)
+		}
+		return uint32(id), nil
+	}
+	id := random.GetRandomUint32()
+	for used[id] {
+		id = random.GetRandomUint32()
+	}
+	return id, nil
+}
+
+func marshalKey(kd *tinkpb.KeyData) (*jwk, error) {
+	switch kd.TypeUrl {
+	case ecdsaPublicKeyTypeURL:
+		key := new(ecdsapb.EcdsaPublicKey)
+		if err := proto.Unmarshal(kd.Value, key); err != nil {
+			return nil, err
+		}
+		if key.Params == nil {
+			return nil, errors.New("invalid ecdsa public key")
+		}
+		if key.Params.Encoding != ecdsapb.EcdsaSignatureEncoding_IEEE_P1363 {
+			return nil, fmt.Errorf("ecdsa signature encoding %s is not supported by JWS, use IEEE_P1363", key.Params.Encoding)
+		}
+		for _, alg := range ecAlgorithms {
+			if alg.curve != key.Params.Curve || alg.hash != key.Params.HashType {
+				continue
+			}
+			x, err := padCoordinate(key.X, alg.coordSize)
+			if err != nil {
+				return nil, err
+			}
+			y, err := padCoordinate(key.Y, alg.coordSize)
+			if err != nil {
+				return nil, err
+			}
+			return &jwk{Kty: "EC", Alg: alg.name, Crv: alg.crv, X: encode(x), Y: encode(y)}, nil
+		}
+		return nil, fmt.Errorf("no JWS algorithm for ecdsa with curve %s and hash %s", key.Params.Curve, key.Params.HashType)
+	case ed25519PublicKeyTypeURL:
+		key := new(ed25519pb.Ed25519PublicKey)
+		if err := proto.Unmarshal(kd.Value, key); err != nil {
+			return nil, err
+		}
+		return &jwk{Kty: "OKP", Alg: "EdDSA", Crv: "Ed25519", X: encode(key.KeyValue)}, nil
+	case rsaSSAPKCS1PublicKeyTypeURL:
+		key := new(rsassapkcs1pb.RsaSsaPkcs1PublicKey)
+		if err := proto.Unmarshal(kd.Value, key); err != nil {
+			return nil, err
+		}
+		if key.Params == nil {
+			return nil, errors.New("invalid rsa-ssa-pkcs1 public key")
+		}
+		for _, alg := range rsaAlgorithms {
+			if !alg.pss && alg.hash == key.Params.HashType {
+				return rsaJWK(alg.name, key.N, key.E), nil
+			}
+		}
+		return nil, fmt.Errorf("no JWS algorithm for rsa-ssa-pkcs1 with hash %s", key.Params.HashType)
+	case rsaSSAPSSPublicKeyTypeURL:
+		key := new(rsassapsspb.RsaSsaPssPublicKey)
+		if err := proto.Unmarshal(kd.Value, key); err != nil {
+			return nil, err
+		}
+		if key.Params == nil {
+			return nil, errors.New("invalid rsa-ssa-pss public key")
+		}
+		p := key.Params
+		for _, alg := range rsaAlgorithms {
+			if alg.pss && alg.hash == p.SigHash && alg.hash == p.Mgf1Hash && alg.saltLength == p.SaltLength {
+				return rsaJWK(alg.name, key.N, key.E), nil
+			}
+		}
+		return nil, fmt.Errorf("no JWS algorithm for rsa-ssa-pss with hash %s, mgf1 hash %s and salt length %d", p.SigHash, p.Mgf1Hash, p.SaltLength)
+	default:
+		return nil, fmt.Errorf("unsupported key type %s", kd.TypeUrl)
+	}
+}
+
+func rsaJWK(alg string, n, e []byte) *jwk {
+	return &jwk{Kty: "RSA", Alg: alg, N: encode(trimZeros(n)), E: encode(trimZeros(e))}
+}
+
+func unmarshalKey(k *jwk) (*tinkpb.KeyData, error) {
+	if k.D != "" {
+		return nil, errors.New("private keys are not supported")
+	}
+	switch k.Kty {
+	case "EC":
+		for _, alg := range ecAlgorithms {
+			if alg.crv != k.Crv || (k.Alg != "" && k.Alg != alg.name) {
+				continue
+			}
+			x, err := decode(k.X)
+			if err != nil {
+				return nil, err
+			}
+			y, err := decode(k.Y)
+			if err != nil {
+				return nil, err
+			}
+			if len(x) != alg.coordSize || len(y) != alg.coordSize {
+				return nil, fmt.Errorf("invalid %s coordinate size", k.Crv)
+			}
+			if !alg.ec.IsOnCurve(new(big.Int).SetBytes(x), new(big.Int).SetBytes(y)) {
+				return nil, fmt.Errorf("point is not on curve %s", k.Crv)
+			}
+			key := &ecdsapb.EcdsaPublicKey{
+				Params: &ecdsapb.EcdsaParams{
+					HashType: alg.hash,
+					Curve:    alg.curve,
+					Encoding: ecdsapb.EcdsaSignatureEncoding_IEEE_P1363,
+				},
+				// Tink stores the coordinates without leading zeros.
+				X: trimZeros(x),
+				Y: trimZeros(y),
+			}
+			return keysetconv.NewKeyData(ecdsaPublicKeyTypeURL, key, tinkpb.KeyData_ASYMMETRIC_PUBLIC)
+		}
+		return nil, fmt.Errorf("unsupported EC curve %q with algorithm %q", k.Crv, k.Alg)
+	case "OKP":
+		if k.Crv != "Ed25519" || (k.Alg != "" && k.Alg != "EdDSA") {
+			return nil, fmt.Errorf("unsupported OKP curve %q with algorithm %q", k.Crv, k.Alg)
+		}
+		x, err := decode(k.X)
+		if err != nil {
+			return nil, err
+		}
+		return keysetconv.NewKeyData(ed25519PublicKeyTypeURL, &ed25519pb.Ed25519PublicKey{KeyValue: x}, tinkpb.KeyData_ASYMMETRIC_PUBLIC)
+	case "RSA":
+		n, err := decode(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decode(k.E)
+		if err != nil {
+			return nil, err
+		}
+		for _, alg := range rsaAlgorithms {
+			if alg.name != k.Alg {
+				continue
+			}
+			if alg.pss {
+				key := &rsassapsspb.RsaSsaPssPublicKey{
+					Params: &rsassapsspb.RsaSsaPssParams{
+						SigHash:    alg.hash,
+						Mgf1Hash:   alg.hash,
+						SaltLength: alg.saltLength,
+					},
+					N: n,
+					E: e,
+				}
+				return keysetconv.NewKeyData(rsaSSAPSSPublicKeyTypeURL, key, tinkpb.KeyData_ASYMMETRIC_PUBLIC)
+			}
+			key := &rsassapkcs1pb.RsaSsaPkcs1PublicKey{
+				Params: &rsassapkcs1pb.RsaSsaPkcs1Params{HashType: alg.hash},
+				N:      n,
+				E:      e,
+			}
+			return keysetconv.NewKeyData(rsaSSAPKCS1PublicKeyTypeURL, key, tinkpb.KeyData_ASYMMETRIC_PUBLIC)
+		}
+		return nil, fmt.Errorf("unsupported RSA algorithm %q", k.Alg)
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+	}
+}
+
+// padCoordinate left-pads the big-endian coordinate b with zeros to size bytes, as RFC 7518
+// requires.
+func padCoordinate(b []byte, size int) ([]byte, error) {
+	b = trimZeros(b)
+	if len(b) > size {
+		return nil, errors.New("invalid ecdsa public key coordinate")
+	}
+	return append(make([]byte, size-len(b)), b...), nil
+}
+
+func trimZeros(b []byte) []byte {
+	return bytes.TrimLeft(b, "\x00")
+}
+
+func encode(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decode(s string) ([]byte, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base64url value: %s", err)
+	}
+	return b, nil
+}
@@ -0,0 +1,271 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package jwkset_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+
+	"github.com/tsingson/tink/golang/jwkset"
+	"github.com/tsingson/tink/golang/keyset"
+	"github.com/tsingson/tink/golang/signature"
+	"github.com/tsingson/tink/golang/testkeyset"
+	commonpb "github.com/tsingson/tink/proto/common_go_proto"
+	ecdsapb "github.com/tsingson/tink/proto/ecdsa_go_proto"
+	tinkpb "github.com/tsingson/tink/proto/tink_go_proto"
+)
+
+func ecdsaTemplate(hash commonpb.HashType, curve commonpb.EllipticCurveType, encoding ecdsapb.EcdsaSignatureEncoding) *tinkpb.KeyTemplate {
+	format := &ecdsapb.EcdsaKeyFormat{
+		Params: &ecdsapb.EcdsaParams{HashType: hash, Curve: curve, Encoding: encoding},
+	}
+	serialized, _ := proto.Marshal(format)
+	return &tinkpb.KeyTemplate{
+		TypeUrl: "type.googleapis.com/google.crypto.tink.EcdsaPrivateKey",
+		Value:   serialized,
+	}
+}
+
+func raw(kt *tinkpb.KeyTemplate) *tinkpb.KeyTemplate {
+	kt.OutputPrefixType = tinkpb.OutputPrefixType_RAW
+	return kt
+}
+
+func newPublicHandle(t *testing.T, kt *tinkpb.KeyTemplate) (*keyset.Handle, *keyset.Handle) {
+	priv, err := keyset.NewHandle(kt)
+	if err != nil {
+		t.Fatalf("keyset.NewHandle() err = %v", err)
+	}
+	pub, err := priv.Public()
+	if err != nil {
+		t.Fatalf("priv.Public() err = %v", err)
+	}
+	return priv, pub
+}
+
+func TestExportImportSignAndVerify(t *testing.T) {
+	testCases := []struct {
+		alg string
+		kt  *tinkpb.KeyTemplate
+	}{
+		{"ES256", ecdsaTemplate(commonpb.HashType_SHA256, commonpb.EllipticCurveType_NIST_P256, ecdsapb.EcdsaSignatureEncoding_IEEE_P1363)},
+		{"ES384", ecdsaTemplate(commonpb.HashType_SHA384, commonpb.EllipticCurveType_NIST_P384, ecdsapb.EcdsaSignatureEncoding_IEEE_P1363)},
+		{"ES512", ecdsaTemplate(commonpb.HashType_SHA512, commonpb.EllipticCurveType_NIST_P521, ecdsapb.EcdsaSignatureEncoding_IEEE_P1363)},
+		{"EdDSA", signature.ED25519KeyTemplate()},
+		{"RS256", signature.RSASSAPKCS12048SHA256F4KeyTemplate()},
+		{"PS256", signature.RSASSAPSS2048SHA256F4KeyTemplate()},
+	}
+	for _, tc := range testCases {
+		priv, pub := newPublicHandle(t, raw(tc.kt))
+		exported, err := jwkset.ExportPublicKeys(pub)
+		if err != nil {
+			t.Fatalf("%s: jwkset.ExportPublicKeys() err = %v", tc.alg, err)
+		}
+		var set struct {
+			Keys []map[string]string `json:"keys"`
+		}
+		if err := json.Unmarshal(exported, &set); err != nil {
+			t.Fatalf("%s: json.Unmarshal() err = %v", tc.alg, err)
+		}
+		if len(set.Keys) != 1 {
+			t.Fatalf("%s: got %d keys, want 1", tc.alg, len(set.Keys))
+		}
+		ks := testkeyset.KeysetMaterial(pub)
+		k := set.Keys[0]
+		if k["alg"] != tc.alg || k["use"] != "sig" || k["kid"] != strconv.FormatUint(uint64(ks.PrimaryKeyId), 10) {
+			t.Errorf("%s: got alg %q, use %q and kid %q, want %s, sig and %d", tc.alg, k["alg"], k["use"], k["kid"], tc.alg, ks.PrimaryKeyId)
+		}
+
+		imported, err := jwkset.ImportPublicKeys(exported)
+		if err != nil {
+			t.Fatalf("%s: jwkset.ImportPublicKeys() err = %v", tc.alg, err)
+		}
+		if !proto.Equal(testkeyset.KeysetMaterial(imported), ks) {
+			t.Errorf("%s: imported keyset differs from the exported keyset", tc.alg)
+		}
+		signer, err := signature.NewSigner(priv)
+		if err != nil {
+			t.Fatalf("%s: signature.NewSigner() err = %v", tc.alg, err)
+		}
+		data := []byte("data")
+		sig, err := signer.Sign(data)
+		if err != nil {
+			t.Fatalf("%s: signer.Sign() err = %v", tc.alg, err)
+		}
+		verifier, err := signature.NewVerifier(imported)
+		if err != nil {
+			t.Fatalf("%s: signature.NewVerifier() err = %v", tc.alg, err)
+		}
+		if err := verifier.Verify(sig, data); err != nil {
+			t.Errorf("%s: verifier.Verify() err = %v", tc.alg, err)
+		}
+	}
+}
+
+func TestExportPublicKeysOrderAndStatus(t *testing.T) {
+	manager := keyset.NewManager()
+	for _, kt := range []*tinkpb.KeyTemplate{
+		raw(signature.ED25519KeyTemplate()),
+		raw(signature.ED25519KeyTemplate()),
+		raw(signature.ED25519KeyTemplate()),
+	} {
+		if err := manager.Rotate(kt); err != nil {
+			t.Fatalf("manager.Rotate() err = %v", err)
+		}
+	}
+	priv, err := manager.Handle()
+	if err != nil {
+		t.Fatalf("manager.Handle() err = %v", err)
+	}
+	ks := testkeyset.KeysetMaterial(priv)
+	if err := manager.Disable(ks.Key[0].KeyId); err != nil {
+		t.Fatalf("manager.Disable() err = %v", err)
+	}
+	priv, err = manager.Handle()
+	if err != nil {
+		t.Fatalf("manager.Handle() err = %v", err)
+	}
+	pub, err := priv.Public()
+	if err != nil {
+		t.Fatalf("priv.Public() err = %v", err)
+	}
+	exported, err := jwkset.ExportPublicKeys(pub)
+	if err != nil {
+		t.Fatalf("jwkset.ExportPublicKeys() err = %v", err)
+	}
+	imported, err := jwkset.ImportPublicKeys(exported)
+	if err != nil {
+		t.Fatalf("jwkset.ImportPublicKeys() err = %v", err)
+	}
+	got := testkeyset.KeysetMaterial(imported)
+	if len(got.Key) != 2 {
+		t.Fatalf("got %d keys, want the 2 enabled keys", len(got.Key))
+	}
+	if got.PrimaryKeyId != ks.PrimaryKeyId || got.Key[0].KeyId != ks.PrimaryKeyId || got.Key[1].KeyId != ks.Key[1].KeyId {
+		t.Errorf("got key IDs %d and %d with primary %d, want %d and %d with primary %d",
+			got.Key[0].KeyId, got.Key[1].KeyId, got.PrimaryKeyId, ks.PrimaryKeyId, ks.Key[1].KeyId, ks.PrimaryKeyId)
+	}
+}
+
+func TestExportPublicKeysWithInvalidInput(t *testing.T) {
+	testCases := []struct {
+		name string
+		kt   *tinkpb.KeyTemplate
+	}{
+		{"TINK output prefix", signature.ED25519KeyTemplate()},
+		{"DER encoding", raw(signature.ECDSAP256KeyTemplate())},
+		{"P-384 with SHA512", raw(ecdsaTemplate(commonpb.HashType_SHA512, commonpb.EllipticCurveType_NIST_P384, ecdsapb.EcdsaSignatureEncoding_IEEE_P1363))},
+	}
+	for _, tc := range testCases {
+		_, pub := newPublicHandle(t, tc.kt)
+		if _, err := jwkset.ExportPublicKeys(pub); err == nil {
+			t.Errorf("%s: jwkset.ExportPublicKeys() err = nil, want error", tc.name)
+		}
+	}
+	priv, _ := newPublicHandle(t, raw(signature.ED25519KeyTemplate()))
+	if _, err := jwkset.ExportPublicKeys(priv); err == nil {
+		t.Errorf("jwkset.ExportPublicKeys() of private keys err = nil, want error")
+	}
+	if _, err := jwkset.ExportPublicKeys(nil); err == nil {
+		t.Errorf("jwkset.ExportPublicKeys(nil) err = nil, want error")
+	}
+}
+
+func TestImportPublicKeysInterop(t *testing.T) {
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("ecdsa.GenerateKey() err = %v", err)
+	}
+	enc := base64.RawURLEncoding
+	coord := func(b []byte) string {
+		return enc.EncodeToString(append(make([]byte, 32-len(b)), b...))
+	}
+	jwks := fmt.Sprintf(`{"keys":[
+		{"kty":"EC","use":"enc","crv":"P-256","x":"AA","y":"AA","kid":"encryption"},
+		{"kty":"EC","crv":"P-256","x":%q,"y":%q,"kid":"issuer-key-1"}]}`,
+		coord(priv.X.Bytes()), coord(priv.Y.Bytes()))
+	h, err := jwkset.ImportPublicKeys([]byte(jwks))
+	if err != nil {
+		t.Fatalf("jwkset.ImportPublicKeys() err = %v", err)
+	}
+	if ks := testkeyset.KeysetMaterial(h); len(ks.Key) != 1 || ks.Key[0].OutputPrefixType != tinkpb.OutputPrefixType_RAW {
+		t.Errorf("imported keyset = %v, want a single RAW key", ks)
+	}
+
+	// An ES256 JWS signature is r || s, each 32 bytes.
+	data := []byte("header.payload")
+	digest := sha256.Sum256(data)
+	r, s, err := ecdsa.Sign(rand.Reader, priv, digest[:])
+	if err != nil {
+		t.Fatalf("ecdsa.Sign() err = %v", err)
+	}
+	sig := append(append(make([]byte, 32-len(r.Bytes())), r.Bytes()...), append(make([]byte, 32-len(s.Bytes())), s.Bytes()...)...)
+	verifier, err := signature.NewVerifier(h)
+	if err != nil {
+		t.Fatalf("signature.NewVerifier() err = %v", err)
+	}
+	if err := verifier.Verify(sig, data); err != nil {
+		t.Errorf("verifier.Verify() err = %v", err)
+	}
+}
+
+func TestImportPublicKeysWithInvalidInput(t *testing.T) {
+	_, pub := newPublicHandle(t, raw(signature.ED25519KeyTemplate()))
+	exported, err := jwkset.ExportPublicKeys(pub)
+	if err != nil {
+		t.Fatalf("jwkset.ExportPublicKeys() err = %v", err)
+	}
+	var set struct {
+		Keys []map[string]string `json:"keys"`
+	}
+	if err := json.Unmarshal(exported, &set); err != nil {
+		t.Fatalf("json.Unmarshal() err = %v", err)
+	}
+	x := set.Keys[0]["x"]
+
+	testCases := []struct {
+		name string
+		jwks string
+	}{
+		{"not JSON", "keys"},
+		{"no keys", `{"keys":[]}`},
+		{"only encryption keys", `{"keys":[{"kty":"OKP","use":"enc","crv":"Ed25519","x":"` + x + `"}]}`},
+		{"private key", `{"keys":[{"kty":"OKP","crv":"Ed25519","x":"` + x + `","d":"` + x + `"}]}`},
+		{"unknown key type", `{"keys":[{"kty":"oct","k":"` + x + `"}]}`},
+		{"unknown curve", `{"keys":[{"kty":"OKP","crv":"X25519","x":"` + x + `"}]}`},
+		{"wrong algorithm", `{"keys":[{"kty":"OKP","crv":"Ed25519","alg":"ES256","x":"` + x + `"}]}`},
+		{"invalid base64", `{"keys":[{"kty":"OKP","crv":"Ed25519","x":"` + x + `="}]}`},
+		{"short key", `{"keys":[{"kty":"OKP","crv":"Ed25519","x":"` + x[:20] + `"}]}`},
+		{"short EC coordinates", `{"keys":[{"kty":"EC","crv":"P-256","x":"` + x[:20] + `","y":"` + x[:20] + `"}]}`},
+		{"point not on curve", `{"keys":[{"kty":"EC","crv":"P-256","x":"` + x + `","y":"` + x + `"}]}`},
+		{"RSA without algorithm", `{"keys":[{"kty":"RSA","n":"` + strings.Repeat("A", 342) + `","e":"AQAB"}]}`},
+		{"duplicate kid", `{"keys":[{"kty":"OKP","crv":"Ed25519","x":"` + x + `","kid":"7"},{"kty":"OKP","crv":"Ed25519","x":"` + x + `","kid":"7"}]}`},
+	}
+	for _, tc := range testCases {
+		if _, err := jwkset.ImportPublicKeys([]byte(tc.jwks)); err == nil {
+			t.Errorf("%s: jwkset.ImportPublicKeys() err = nil, want error", tc.name)
+		}
+	}
+}
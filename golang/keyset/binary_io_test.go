@@ -21,9 +21,9 @@ import (
 
 	"github.com/golang/protobuf/proto"
 
-	"github.com/tsingson/tink/go/keyset"
-	"github.com/tsingson/tink/go/testkeyset"
-	"github.com/tsingson/tink/go/testutil"
+	"github.com/tsingson/tink/golang/keyset"
+	"github.com/tsingson/tink/golang/testkeyset"
+	"github.com/tsingson/tink/golang/testutil"
 
 	tinkpb "github.com/tsingson/tink/proto/tink_go_proto"
 )
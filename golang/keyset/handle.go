@@ -15,14 +15,16 @@
 package keyset
 
 import (
+	"context"
 	"errors"
 	"fmt"
 
 	"github.com/golang/protobuf/proto"
 
-	"github.com/tsingson/tink/go/core/primitiveset"
-	"github.com/tsingson/tink/go/core/registry"
-	"github.com/tsingson/tink/go/tink"
+	"github.com/tsingson/tink/golang/core/primitiveset"
+	"github.com/tsingson/tink/golang/core/registry"
+	"github.com/tsingson/tink/golang/monitoring"
+	"github.com/tsingson/tink/golang/tink"
 	tinkpb "github.com/tsingson/tink/proto/tink_go_proto"
 )
 
@@ -31,7 +33,8 @@ var errInvalidKeyset = fmt.Errorf("keyset.Handle: invalid keyset")
 // Handle provides access to a Keyset protobuf, to limit the exposure of actual protocol
 // buffers that hold sensitive key material.
 type Handle struct {
-	ks *tinkpb.Keyset
+	ks      *tinkpb.Keyset
+	monitor monitoring.Monitor
 }
 
 // NewHandle creates a keyset handle that contains a single fresh key generated according
@@ -55,7 +58,7 @@ func NewHandleWithNoSecrets(ks *tinkpb.Keyset) (*Handle, error) {
 	if ks == nil {
 		return nil, errors.New("keyset.Handle: nil keyset")
 	}
-	h := &Handle{ks}
+	h := &Handle{ks: ks}
 	if h.hasSecrets() {
 		// If you need to do this, you have to use func insecurecleartextkeyset.Read() instead.
 		return nil, errors.New("importing unencrypted secret key material is forbidden")
@@ -73,7 +76,21 @@ func Read(reader Reader, masterKey tink.AEAD) (*Handle, error) {
 	if err != nil {
 		return nil, err
 	}
-	return &Handle{ks}, nil
+	return &Handle{ks: ks}, nil
+}
+
+// ReadWithContext is like Read, but decrypts the keyset with a master key whose operations
+// take a context, e.g. a key in a remote KMS.
+func ReadWithContext(ctx context.Context, reader Reader, masterKey tink.AEADWithContext) (*Handle, error) {
+	encryptedKeyset, err := reader.ReadEncrypted()
+	if err != nil {
+		return nil, err
+	}
+	ks, err := decryptWithContext(ctx, encryptedKeyset, masterKey)
+	if err != nil {
+		return nil, err
+	}
+	return &Handle{ks: ks}, nil
 }
 
 // ReadWithNoSecrets tries to create a keyset.Handle from a keyset obtained via reader.
@@ -91,6 +108,14 @@ func (h *Handle) Public() (*Handle, error) {
 	pubKeys := make([]*tinkpb.Keyset_Key, len(privKeys))
 
 	for i := 0; i < len(privKeys); i++ {
+		if privKeys[i] != nil && privKeys[i].Status == tinkpb.KeyStatusType_DESTROYED {
+			pubKeys[i] = &tinkpb.Keyset_Key{
+				Status:           privKeys[i].Status,
+				KeyId:            privKeys[i].KeyId,
+				OutputPrefixType: privKeys[i].OutputPrefixType,
+			}
+			continue
+		}
 		if privKeys[i] == nil || privKeys[i].KeyData == nil {
 			return nil, errInvalidKeyset
 		}
@@ -110,7 +135,7 @@ func (h *Handle) Public() (*Handle, error) {
 		PrimaryKeyId: h.ks.PrimaryKeyId,
 		Key:          pubKeys,
 	}
-	return &Handle{ks}, nil
+	return &Handle{ks: ks, monitor: h.monitor}, nil
 }
 
 // String returns a string representation of the managed keyset.
@@ -132,6 +157,16 @@ func (h *Handle) Write(writer Writer, masterKey tink.AEAD) error {
 	return writer.WriteEncrypted(encrypted)
 }
 
+// WriteWithContext is like Write, but encrypts the keyset with a master key whose operations
+// take a context, e.g. a key in a remote KMS.
+func (h *Handle) WriteWithContext(ctx context.Context, writer Writer, masterKey tink.AEADWithContext) error {
+	encrypted, err := encryptWithContext(ctx, h.ks, masterKey)
+	if err != nil {
+		return err
+	}
+	return writer.WriteEncrypted(encrypted)
+}
+
 // WriteWithNoSecrets exports the keyset in h to the given Writer w returning an error if the keyset
 // contains secret key material.
 func (h *Handle) WriteWithNoSecrets(w Writer) error {
@@ -142,6 +177,12 @@ func (h *Handle) WriteWithNoSecrets(w Writer) error {
 	return w.Write(h.ks)
 }
 
+// WithMonitor returns a Handle of the same keyset whose primitives report their
+// operations to the given Monitor instead of the global one.
+func (h *Handle) WithMonitor(m monitoring.Monitor) *Handle {
+	return &Handle{ks: h.ks, monitor: m}
+}
+
 // Primitives creates a set of primitives corresponding to the keys with
 // status=ENABLED in the keyset of the given keyset handle, assuming all the
 // corresponding key managers are present (keys with status!=ENABLED are skipped).
@@ -165,10 +206,28 @@ func (h *Handle) Primitives() (*primitiveset.PrimitiveSet, error) {
 // The returned set is usually later "wrapped" into a class that implements
 // the corresponding Primitive-interface.
 func (h *Handle) PrimitivesWithKeyManager(km registry.KeyManager) (*primitiveset.PrimitiveSet, error) {
+	return h.primitives(registry.Default(), km)
+}
+
+// PrimitivesWithRegistry creates a set of primitives corresponding to the keys with
+// status=ENABLED in the keyset of the given keyset handle, using the key managers of
+// the given registry instead of the default one.
+//
+// The returned set is usually later "wrapped" into a class that implements
+// the corresponding Primitive-interface.
+func (h *Handle) PrimitivesWithRegistry(r *registry.Registry) (*primitiveset.PrimitiveSet, error) {
+	if r == nil {
+		return nil, fmt.Errorf("registry.PrimitivesWithRegistry: invalid registry")
+	}
+	return h.primitives(r, nil)
+}
+
+func (h *Handle) primitives(r *registry.Registry, km registry.KeyManager) (*primitiveset.PrimitiveSet, error) {
 	if err := Validate(h.ks); err != nil {
 		return nil, fmt.Errorf("registry.PrimitivesWithKeyManager: invalid keyset: %s", err)
 	}
 	primitiveSet := primitiveset.New()
+	primitiveSet.Monitor = h.monitor
 	for _, key := range h.ks.Key {
 		if key.Status != tinkpb.KeyStatusType_ENABLED {
 			continue
@@ -178,7 +237,7 @@ func (h *Handle) PrimitivesWithKeyManager(km registry.KeyManager) (*primitiveset
 		if km != nil && km.DoesSupport(key.KeyData.TypeUrl) {
 			primitive, err = km.Primitive(key.KeyData.Value)
 		} else {
-			primitive, err = registry.PrimitiveFromKeyData(key.KeyData)
+			primitive, err = r.PrimitiveFromKeyData(key.KeyData)
 		}
 		if err != nil {
 			return nil, fmt.Errorf("registry.PrimitivesWithKeyManager: cannot get primitive from key: %s", err)
@@ -231,10 +290,27 @@ func publicKeyData(privKeyData *tinkpb.KeyData) (*tinkpb.KeyData, error) {
 }
 
 func decrypt(encryptedKeyset *tinkpb.EncryptedKeyset, masterKey tink.AEAD) (*tinkpb.Keyset, error) {
-	if encryptedKeyset == nil || masterKey == nil {
+	if masterKey == nil {
 		return nil, fmt.Errorf("keyset.Handle: invalid encrypted keyset")
 	}
-	decrypted, err := masterKey.Decrypt(encryptedKeyset.EncryptedKeyset, []byte{})
+	return decryptWith(encryptedKeyset, masterKey.Decrypt)
+}
+
+func decryptWithContext(ctx context.Context, encryptedKeyset *tinkpb.EncryptedKeyset, masterKey tink.AEADWithContext) (*tinkpb.Keyset, error) {
+	if masterKey == nil {
+		return nil, fmt.Errorf("keyset.Handle: invalid encrypted keyset")
+	}
+	return decryptWith(encryptedKeyset, func(ct, ad []byte) ([]byte, error) {
+		return masterKey.DecryptWithContext(ctx, ct, ad)
+	})
+}
+
+// decryptWith decrypts the given keyset with the given decryption function of a master key.
+func decryptWith(encryptedKeyset *tinkpb.EncryptedKeyset, decryptFunc func(ct, ad []byte) ([]byte, error)) (*tinkpb.Keyset, error) {
+	if encryptedKeyset == nil {
+		return nil, fmt.Errorf("keyset.Handle: invalid encrypted keyset")
+	}
+	decrypted, err := decryptFunc(encryptedKeyset.EncryptedKeyset, []byte{})
 	if err != nil {
 		return nil, fmt.Errorf("keyset.Handle: decryption failed: %s", err)
 	}
@@ -246,11 +322,28 @@ func decrypt(encryptedKeyset *tinkpb.EncryptedKeyset, masterKey tink.AEAD) (*tin
 }
 
 func encrypt(keyset *tinkpb.Keyset, masterKey tink.AEAD) (*tinkpb.EncryptedKeyset, error) {
+	if masterKey == nil {
+		return nil, fmt.Errorf("keyset.Handle: invalid master key")
+	}
+	return encryptWith(keyset, masterKey.Encrypt)
+}
+
+func encryptWithContext(ctx context.Context, keyset *tinkpb.Keyset, masterKey tink.AEADWithContext) (*tinkpb.EncryptedKeyset, error) {
+	if masterKey == nil {
+		return nil, fmt.Errorf("keyset.Handle: invalid master key")
+	}
+	return encryptWith(keyset, func(pt, ad []byte) ([]byte, error) {
+		return masterKey.EncryptWithContext(ctx, pt, ad)
+	})
+}
+
+// encryptWith encrypts the given keyset with the given encryption function of a master key.
+func encryptWith(keyset *tinkpb.Keyset, encryptFunc func(pt, ad []byte) ([]byte, error)) (*tinkpb.EncryptedKeyset, error) {
 	serializedKeyset, err := proto.Marshal(keyset)
 	if err != nil {
 		return nil, errInvalidKeyset
 	}
-	encrypted, err := masterKey.Encrypt(serializedKeyset, []byte{})
+	encrypted, err := encryptFunc(serializedKeyset, []byte{})
 	if err != nil {
 		return nil, fmt.Errorf("keyset.Handle: encrypted failed: %s", err)
 	}
@@ -291,8 +384,12 @@ func getKeyInfo(key *tinkpb.Keyset_Key) (*tinkpb.KeysetInfo_KeyInfo, error) {
 	if key == nil {
 		return nil, errors.New("keyset.Handle: keyset must be non nil")
 	}
+	var typeURL string
+	if key.KeyData != nil {
+		typeURL = key.KeyData.TypeUrl
+	}
 	return &tinkpb.KeysetInfo_KeyInfo{
-		TypeUrl:          key.KeyData.TypeUrl,
+		TypeUrl:          typeURL,
 		Status:           key.Status,
 		KeyId:            key.KeyId,
 		OutputPrefixType: key.OutputPrefixType,
@@ -15,16 +15,18 @@
 package keyset_test
 
 import (
+	"context"
 	"strings"
 	"testing"
 
 	"github.com/golang/protobuf/proto"
 
-	"github.com/tsingson/tink/go/keyset"
-	"github.com/tsingson/tink/go/mac"
-	"github.com/tsingson/tink/go/subtle/aead"
-	"github.com/tsingson/tink/go/testkeyset"
-	"github.com/tsingson/tink/go/testutil"
+	"github.com/tsingson/tink/golang/keyset"
+	"github.com/tsingson/tink/golang/mac"
+	"github.com/tsingson/tink/golang/subtle/aead"
+	"github.com/tsingson/tink/golang/testkeyset"
+	"github.com/tsingson/tink/golang/testutil"
+	"github.com/tsingson/tink/golang/tink"
 
 	tinkpb "github.com/tsingson/tink/proto/tink_go_proto"
 )
@@ -89,6 +91,71 @@ func TestRead(t *testing.T) {
 	}
 }
 
+// contextAEAD is a tink.AEADWithContext that fails once its context is done.
+type contextAEAD struct {
+	a tink.AEAD
+}
+
+func (c *contextAEAD) EncryptWithContext(ctx context.Context, pt, ad []byte) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return c.a.Encrypt(pt, ad)
+}
+
+func (c *contextAEAD) DecryptWithContext(ctx context.Context, ct, ad []byte) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return c.a.Decrypt(ct, ad)
+}
+
+func TestReadWithContext(t *testing.T) {
+	a, err := aead.NewAESGCM([]byte(strings.Repeat("A", 32)))
+	if err != nil {
+		t.Fatalf("aead.NewAESGCM(): %v", err)
+	}
+	masterKey := &contextAEAD{a: a}
+
+	keyData := testutil.NewKeyData("some type url", []byte{0}, tinkpb.KeyData_SYMMETRIC)
+	key := testutil.NewKey(keyData, tinkpb.KeyStatusType_ENABLED, 1, tinkpb.OutputPrefixType_TINK)
+	ks := testutil.NewKeyset(1, []*tinkpb.Keyset_Key{key})
+	h, _ := testkeyset.NewHandle(ks)
+
+	ctx := context.Background()
+	memKeyset := &keyset.MemReaderWriter{}
+	if err := h.WriteWithContext(ctx, memKeyset, masterKey); err != nil {
+		t.Fatalf("handle.WriteWithContext(): %v", err)
+	}
+	// The keyset can also be read with the non-context master key.
+	h2, err := keyset.Read(memKeyset, a)
+	if err != nil {
+		t.Fatalf("keyset.Read(): %v", err)
+	}
+	if !proto.Equal(testkeyset.KeysetMaterial(h), testkeyset.KeysetMaterial(h2)) {
+		t.Fatalf("Decrypt failed: got %v, want %v", h2, h)
+	}
+	h3, err := keyset.ReadWithContext(ctx, memKeyset, masterKey)
+	if err != nil {
+		t.Fatalf("keyset.ReadWithContext(): %v", err)
+	}
+	if !proto.Equal(testkeyset.KeysetMaterial(h), testkeyset.KeysetMaterial(h3)) {
+		t.Fatalf("Decrypt failed: got %v, want %v", h3, h)
+	}
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, err := keyset.ReadWithContext(cancelled, memKeyset, masterKey); err == nil {
+		t.Errorf("keyset.ReadWithContext() with cancelled context: err = nil, want error")
+	}
+	if err := h.WriteWithContext(cancelled, memKeyset, masterKey); err == nil {
+		t.Errorf("handle.WriteWithContext() with cancelled context: err = nil, want error")
+	}
+	if _, err := keyset.ReadWithContext(ctx, memKeyset, nil); err == nil {
+		t.Errorf("keyset.ReadWithContext() with nil master key: err = nil, want error")
+	}
+}
+
 func TestReadWithNoSecrets(t *testing.T) {
 	// Create a keyset containing public key material
 	keyData := testutil.NewKeyData("some type url", []byte{0}, tinkpb.KeyData_ASYMMETRIC_PUBLIC)
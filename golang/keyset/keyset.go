@@ -16,14 +16,14 @@
 package keyset
 
 import (
-	"github.com/tsingson/tink/go/internal"
+	"github.com/tsingson/tink/golang/internal"
 	tinkpb "github.com/tsingson/tink/proto/tink_go_proto"
 )
 
 // keysetHandle is used by package insecurecleartextkeyset and package testkeyset (via package internal)
 // to create a keyset.Handle from cleartext key material.
 func keysetHandle(ks *tinkpb.Keyset) *Handle {
-	return &Handle{ks}
+	return &Handle{ks: ks}
 }
 
 // keysetMaterial is used by package insecurecleartextkeyset and package testkeyset (via package internal)
@@ -15,10 +15,11 @@
 package keyset
 
 import (
+	"errors"
 	"fmt"
 
-	"github.com/tsingson/tink/go/core/registry"
-	"github.com/tsingson/tink/go/subtle/random"
+	"github.com/tsingson/tink/golang/core/registry"
+	"github.com/tsingson/tink/golang/subtle/random"
 	tinkpb "github.com/tsingson/tink/proto/tink_go_proto"
 )
 
@@ -42,36 +43,182 @@ func NewManagerFromHandle(kh *Handle) *Manager {
 	return ret
 }
 
+// Errors reported through KeyError when a key lifecycle operation is not allowed.
+var (
+	// ErrKeyNotFound indicates that the keyset does not contain a key with the given ID.
+	ErrKeyNotFound = errors.New("key not found")
+	// ErrKeyIsPrimary indicates that the operation cannot be applied to the primary key.
+	ErrKeyIsPrimary = errors.New("key is the primary key")
+	// ErrKeyNotEnabled indicates that the operation requires an ENABLED key.
+	ErrKeyNotEnabled = errors.New("key is not enabled")
+	// ErrKeyDestroyed indicates that the key material has already been destroyed.
+	ErrKeyDestroyed = errors.New("key has been destroyed")
+	// ErrKeyStatusUnknown indicates that the key has neither of the ENABLED, DISABLED and
+	// DESTROYED statuses.
+	ErrKeyStatusUnknown = errors.New("key has an unknown status")
+)
+
+// KeyError records a key lifecycle operation that was rejected by the Manager.
+// Err is one of ErrKeyNotFound, ErrKeyIsPrimary, ErrKeyNotEnabled, ErrKeyDestroyed or
+// ErrKeyStatusUnknown.
+type KeyError struct {
+	Op    string
+	KeyID uint32
+	Err   error
+}
+
+func (e *KeyError) Error() string {
+	return fmt.Sprintf("keyset_manager: cannot %s key %d: %s", e.Op, e.KeyID, e.Err)
+}
+
 // Rotate generates a fresh key using the given key template and
 // sets the new key as the primary key.
 func (km *Manager) Rotate(kt *tinkpb.KeyTemplate) error {
 	if kt == nil {
 		return fmt.Errorf("keyset_manager: cannot rotate, need key template")
 	}
+	key, err := km.newKey(kt)
+	if err != nil {
+		return err
+	}
+	// Set the new key as the primary key
+	km.ks.Key = append(km.ks.Key, key)
+	km.ks.PrimaryKeyId = key.KeyId
+	return nil
+}
+
+// Add generates a fresh key using the given key template and adds it to the keyset
+// as an ENABLED, non-primary key. It returns the ID of the new key.
+func (km *Manager) Add(kt *tinkpb.KeyTemplate) (uint32, error) {
+	if kt == nil {
+		return 0, fmt.Errorf("keyset_manager: cannot add key, need key template")
+	}
+	key, err := km.newKey(kt)
+	if err != nil {
+		return 0, err
+	}
+	km.ks.Key = append(km.ks.Key, key)
+	return key.KeyId, nil
+}
+
+// SetPrimary sets the key with the given ID as the primary key. The key must be ENABLED.
+func (km *Manager) SetPrimary(keyID uint32) error {
+	key, err := km.findKey("set primary", keyID)
+	if err != nil {
+		return err
+	}
+	if key.Status != tinkpb.KeyStatusType_ENABLED {
+		return &KeyError{Op: "set primary", KeyID: keyID, Err: ErrKeyNotEnabled}
+	}
+	km.ks.PrimaryKeyId = keyID
+	return nil
+}
+
+// Enable sets the status of the key with the given ID to ENABLED.
+// Keys whose material has been destroyed cannot be enabled.
+func (km *Manager) Enable(keyID uint32) error {
+	key, err := km.findKey("enable", keyID)
+	if err != nil {
+		return err
+	}
+	if err := statusChangeError(key); err != nil {
+		return &KeyError{Op: "enable", KeyID: keyID, Err: err}
+	}
+	key.Status = tinkpb.KeyStatusType_ENABLED
+	return nil
+}
+
+// Disable sets the status of the key with the given ID to DISABLED.
+// The primary key cannot be disabled.
+func (km *Manager) Disable(keyID uint32) error {
+	if keyID == km.ks.PrimaryKeyId {
+		return &KeyError{Op: "disable", KeyID: keyID, Err: ErrKeyIsPrimary}
+	}
+	key, err := km.findKey("disable", keyID)
+	if err != nil {
+		return err
+	}
+	if err := statusChangeError(key); err != nil {
+		return &KeyError{Op: "disable", KeyID: keyID, Err: err}
+	}
+	key.Status = tinkpb.KeyStatusType_DISABLED
+	return nil
+}
+
+// Destroy wipes the key material of the key with the given ID and sets its status
+// to DESTROYED. The key ID remains in the keyset. The primary key cannot be destroyed.
+func (km *Manager) Destroy(keyID uint32) error {
+	if keyID == km.ks.PrimaryKeyId {
+		return &KeyError{Op: "destroy", KeyID: keyID, Err: ErrKeyIsPrimary}
+	}
+	key, err := km.findKey("destroy", keyID)
+	if err != nil {
+		return err
+	}
+	key.KeyData = nil
+	key.Status = tinkpb.KeyStatusType_DESTROYED
+	return nil
+}
+
+// Delete removes the key with the given ID from the keyset.
+// The primary key cannot be deleted.
+func (km *Manager) Delete(keyID uint32) error {
+	if keyID == km.ks.PrimaryKeyId {
+		return &KeyError{Op: "delete", KeyID: keyID, Err: ErrKeyIsPrimary}
+	}
+	for i, key := range km.ks.Key {
+		if key.KeyId == keyID {
+			km.ks.Key = append(km.ks.Key[:i], km.ks.Key[i+1:]...)
+			return nil
+		}
+	}
+	return &KeyError{Op: "delete", KeyID: keyID, Err: ErrKeyNotFound}
+}
+
+// Handle creates a new Handle for the managed keyset.
+func (km *Manager) Handle() (*Handle, error) {
+	return &Handle{ks: km.ks}, nil
+}
+
+// newKey generates a fresh ENABLED key using the given key template.
+func (km *Manager) newKey(kt *tinkpb.KeyTemplate) (*tinkpb.Keyset_Key, error) {
 	keyData, err := registry.NewKeyData(kt)
 	if err != nil {
-		return fmt.Errorf("keyset_manager: cannot create KeyData: %s", err)
+		return nil, fmt.Errorf("keyset_manager: cannot create KeyData: %s", err)
 	}
-	keyID := km.newKeyID()
 	outputPrefixType := kt.OutputPrefixType
 	if outputPrefixType == tinkpb.OutputPrefixType_UNKNOWN_PREFIX {
 		outputPrefixType = tinkpb.OutputPrefixType_TINK
 	}
-	key := &tinkpb.Keyset_Key{
+	return &tinkpb.Keyset_Key{
 		KeyData:          keyData,
 		Status:           tinkpb.KeyStatusType_ENABLED,
-		KeyId:            keyID,
+		KeyId:            km.newKeyID(),
 		OutputPrefixType: outputPrefixType,
+	}, nil
+}
+
+// statusChangeError returns the reason why the given key cannot be enabled or disabled,
+// or nil if it can.
+func statusChangeError(key *tinkpb.Keyset_Key) error {
+	switch key.Status {
+	case tinkpb.KeyStatusType_ENABLED, tinkpb.KeyStatusType_DISABLED:
+		return nil
+	case tinkpb.KeyStatusType_DESTROYED:
+		return ErrKeyDestroyed
+	default:
+		return ErrKeyStatusUnknown
 	}
-	// Set the new key as the primary key
-	km.ks.Key = append(km.ks.Key, key)
-	km.ks.PrimaryKeyId = keyID
-	return nil
 }
 
-// Handle creates a new Handle for the managed keyset.
-func (km *Manager) Handle() (*Handle, error) {
-	return &Handle{km.ks}, nil
+// findKey returns the key with the given ID, or a KeyError for op if there is none.
+func (km *Manager) findKey(op string, keyID uint32) (*tinkpb.Keyset_Key, error) {
+	for _, key := range km.ks.Key {
+		if key.KeyId == keyID {
+			return key, nil
+		}
+	}
+	return nil, &KeyError{Op: op, KeyID: keyID, Err: ErrKeyNotFound}
 }
 
 // newKeyID generates a key id that has not been used by any key in the keyset.
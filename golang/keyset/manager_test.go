@@ -17,11 +17,12 @@ package keyset_test
 import (
 	"testing"
 
-	"github.com/tsingson/tink/go/keyset"
-	"github.com/tsingson/tink/go/testkeyset"
+	"github.com/tsingson/tink/golang/keyset"
+	"github.com/tsingson/tink/golang/testkeyset"
 
-	"github.com/tsingson/tink/go/mac"
-	"github.com/tsingson/tink/go/testutil"
+	"github.com/tsingson/tink/golang/mac"
+	"github.com/tsingson/tink/golang/testutil"
+	commonpb "github.com/tsingson/tink/proto/common_go_proto"
 	tinkpb "github.com/tsingson/tink/proto/tink_go_proto"
 )
 
@@ -82,3 +83,182 @@ func TestExistingKeyset(t *testing.T) {
 		t.Errorf("expect the second key to be primary")
 	}
 }
+
+func TestKeysetManagerAdd(t *testing.T) {
+	ksm := keyset.NewManager()
+	kt := mac.HMACSHA256Tag128KeyTemplate()
+	if err := ksm.Rotate(kt); err != nil {
+		t.Fatalf("cannot rotate when key template is available: %s", err)
+	}
+	keyID, err := ksm.Add(kt)
+	if err != nil {
+		t.Fatalf("cannot add key: %s", err)
+	}
+	h, err := ksm.Handle()
+	if err != nil {
+		t.Fatalf("cannot get keyset handle: %s", err)
+	}
+	ks := testkeyset.KeysetMaterial(h)
+	if len(ks.Key) != 2 {
+		t.Errorf("expect the number of keys to be 2, got %d", len(ks.Key))
+	}
+	if ks.Key[1].KeyId != keyID ||
+		ks.Key[1].Status != tinkpb.KeyStatusType_ENABLED ||
+		ks.PrimaryKeyId == keyID {
+		t.Errorf("incorrect key information: %s", ks.Key[1])
+	}
+	if _, err := ksm.Add(nil); err == nil {
+		t.Errorf("expect an error when key template is nil")
+	}
+}
+
+func TestKeysetManagerLifecycle(t *testing.T) {
+	ksm := keyset.NewManager()
+	kt := mac.HMACSHA256Tag128KeyTemplate()
+	if err := ksm.Rotate(kt); err != nil {
+		t.Fatalf("cannot rotate when key template is available: %s", err)
+	}
+	h, err := ksm.Handle()
+	if err != nil {
+		t.Fatalf("cannot get keyset handle: %s", err)
+	}
+	ks := testkeyset.KeysetMaterial(h)
+	oldID := ks.PrimaryKeyId
+	newID, err := ksm.Add(kt)
+	if err != nil {
+		t.Fatalf("cannot add key: %s", err)
+	}
+	if err := ksm.SetPrimary(newID); err != nil {
+		t.Fatalf("cannot set primary: %s", err)
+	}
+	if ks.PrimaryKeyId != newID {
+		t.Errorf("expect key %d to be primary, got %d", newID, ks.PrimaryKeyId)
+	}
+	if err := ksm.Disable(oldID); err != nil {
+		t.Fatalf("cannot disable key: %s", err)
+	}
+	if ks.Key[0].Status != tinkpb.KeyStatusType_DISABLED {
+		t.Errorf("expect key %d to be disabled, got %s", oldID, ks.Key[0].Status)
+	}
+	if err := ksm.Enable(oldID); err != nil {
+		t.Fatalf("cannot enable key: %s", err)
+	}
+	if ks.Key[0].Status != tinkpb.KeyStatusType_ENABLED {
+		t.Errorf("expect key %d to be enabled, got %s", oldID, ks.Key[0].Status)
+	}
+	if err := ksm.Destroy(oldID); err != nil {
+		t.Fatalf("cannot destroy key: %s", err)
+	}
+	if ks.Key[0].Status != tinkpb.KeyStatusType_DESTROYED || ks.Key[0].KeyData != nil {
+		t.Errorf("expect key %d to be destroyed: %s", oldID, ks.Key[0])
+	}
+	if _, err := mac.New(h); err != nil {
+		t.Errorf("cannot get primitive from a keyset with a destroyed key: %s", err)
+	}
+	if h.String() == "" {
+		t.Errorf("expect keyset info of a keyset with a destroyed key")
+	}
+	if err := ksm.Delete(oldID); err != nil {
+		t.Fatalf("cannot delete key: %s", err)
+	}
+	ks = testkeyset.KeysetMaterial(h)
+	if len(ks.Key) != 1 || ks.Key[0].KeyId != newID {
+		t.Errorf("expect only key %d to remain in the keyset", newID)
+	}
+}
+
+func TestKeysetManagerInvalidTransitions(t *testing.T) {
+	ksm := keyset.NewManager()
+	kt := mac.HMACSHA256Tag128KeyTemplate()
+	if err := ksm.Rotate(kt); err != nil {
+		t.Fatalf("cannot rotate when key template is available: %s", err)
+	}
+	h, err := ksm.Handle()
+	if err != nil {
+		t.Fatalf("cannot get keyset handle: %s", err)
+	}
+	primaryID := testkeyset.KeysetMaterial(h).PrimaryKeyId
+	disabledID, err := ksm.Add(kt)
+	if err != nil {
+		t.Fatalf("cannot add key: %s", err)
+	}
+	if err := ksm.Disable(disabledID); err != nil {
+		t.Fatalf("cannot disable key: %s", err)
+	}
+	destroyedID, err := ksm.Add(kt)
+	if err != nil {
+		t.Fatalf("cannot add key: %s", err)
+	}
+	if err := ksm.Destroy(destroyedID); err != nil {
+		t.Fatalf("cannot destroy key: %s", err)
+	}
+	unknownID := primaryID + disabledID + destroyedID
+
+	var tests = []struct {
+		name string
+		op   func() error
+		want error
+	}{
+		{"disable primary", func() error { return ksm.Disable(primaryID) }, keyset.ErrKeyIsPrimary},
+		{"destroy primary", func() error { return ksm.Destroy(primaryID) }, keyset.ErrKeyIsPrimary},
+		{"delete primary", func() error { return ksm.Delete(primaryID) }, keyset.ErrKeyIsPrimary},
+		{"promote disabled", func() error { return ksm.SetPrimary(disabledID) }, keyset.ErrKeyNotEnabled},
+		{"promote destroyed", func() error { return ksm.SetPrimary(destroyedID) }, keyset.ErrKeyNotEnabled},
+		{"enable destroyed", func() error { return ksm.Enable(destroyedID) }, keyset.ErrKeyDestroyed},
+		{"disable destroyed", func() error { return ksm.Disable(destroyedID) }, keyset.ErrKeyDestroyed},
+		{"promote unknown", func() error { return ksm.SetPrimary(unknownID) }, keyset.ErrKeyNotFound},
+		{"enable unknown", func() error { return ksm.Enable(unknownID) }, keyset.ErrKeyNotFound},
+		{"disable unknown", func() error { return ksm.Disable(unknownID) }, keyset.ErrKeyNotFound},
+		{"destroy unknown", func() error { return ksm.Destroy(unknownID) }, keyset.ErrKeyNotFound},
+		{"delete unknown", func() error { return ksm.Delete(unknownID) }, keyset.ErrKeyNotFound},
+	}
+	for _, tt := range tests {
+		err := tt.op()
+		kerr, ok := err.(*keyset.KeyError)
+		if !ok {
+			t.Errorf("%s: expect a *keyset.KeyError, got %v", tt.name, err)
+			continue
+		}
+		if kerr.Err != tt.want {
+			t.Errorf("%s: expect error %q, got %q", tt.name, tt.want, kerr.Err)
+		}
+	}
+	if testkeyset.KeysetMaterial(h).PrimaryKeyId != primaryID {
+		t.Errorf("expect the primary key to be unchanged")
+	}
+}
+
+func TestKeysetManagerUnknownStatus(t *testing.T) {
+	keyData := testutil.NewHMACKeyData(commonpb.HashType_SHA256, 16)
+	ks := testutil.NewKeyset(1, []*tinkpb.Keyset_Key{
+		testutil.NewKey(keyData, tinkpb.KeyStatusType_ENABLED, 1, tinkpb.OutputPrefixType_TINK),
+		testutil.NewKey(keyData, tinkpb.KeyStatusType_UNKNOWN_STATUS, 2, tinkpb.OutputPrefixType_TINK),
+	})
+	h, err := testkeyset.NewHandle(ks)
+	if err != nil {
+		t.Fatalf("cannot create keyset handle: %s", err)
+	}
+	ksm := keyset.NewManagerFromHandle(h)
+
+	var tests = []struct {
+		name string
+		op   func() error
+	}{
+		{"enable", func() error { return ksm.Enable(2) }},
+		{"disable", func() error { return ksm.Disable(2) }},
+	}
+	for _, tt := range tests {
+		err := tt.op()
+		kerr, ok := err.(*keyset.KeyError)
+		if !ok {
+			t.Errorf("%s: expect a *keyset.KeyError, got %v", tt.name, err)
+			continue
+		}
+		if kerr.Err != keyset.ErrKeyStatusUnknown {
+			t.Errorf("%s: expect error %q, got %q", tt.name, keyset.ErrKeyStatusUnknown, kerr.Err)
+		}
+	}
+	if ks.Key[1].Status != tinkpb.KeyStatusType_UNKNOWN_STATUS {
+		t.Errorf("expect the status of key 2 to be unchanged, got %s", ks.Key[1].Status)
+	}
+}
@@ -0,0 +1,267 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package keyset
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/scrypt"
+
+	"github.com/tsingson/tink/golang/subtle/aead"
+	"github.com/tsingson/tink/golang/subtle/random"
+	"github.com/tsingson/tink/golang/tink"
+)
+
+// PasswordKDF is a memory-hard function that derives the master key of a keyset from a
+// password.
+type PasswordKDF byte
+
+const (
+	// Scrypt is the scrypt function of RFC 7914.
+	Scrypt PasswordKDF = 1
+	// Argon2id is the Argon2id function of RFC 9106.
+	Argon2id PasswordKDF = 2
+)
+
+// Bounds of the KDF parameters, enforced when a keyset is written and when it is read.
+// The lower bounds reject weak parameters. The upper bounds are small multiples of the
+// recommended parameters, so that a crafted keyset costs at most 256 MiB with scrypt
+// and 1 GiB with Argon2id to read.
+const (
+	minScryptN = 1 << 15
+	maxScryptN = 1 << 18
+	scryptR    = 8
+	minScryptP = 1
+	maxScryptP = 4
+
+	minArgon2Time    = 2
+	maxArgon2Time    = 10
+	minArgon2Memory  = 19 * 1024 // KiB
+	maxArgon2Memory  = 1024 * 1024
+	minArgon2Threads = 1
+	maxArgon2Threads = 16
+
+	passwordHeaderVersion = 1
+	passwordSaltSize      = 16
+	passwordKeySize       = 32
+)
+
+// PasswordParams are the parameters of the key derivation of WriteWithPassword. They are
+// stored in the encrypted keyset together with a random salt, so that ReadWithPassword
+// only needs the password.
+type PasswordParams struct {
+	KDF PasswordKDF
+
+	// ScryptN, ScryptR and ScryptP are the cost parameters of scrypt. ScryptN must be a
+	// power of two between 2^15 and 2^18, ScryptR must be 8 and ScryptP at most 4.
+	ScryptN, ScryptR, ScryptP int
+
+	// Argon2Time, Argon2Memory and Argon2Threads are the number of passes, the memory in
+	// KiB and the parallelism of Argon2id. They are limited to 10 passes, 1 GiB of memory
+	// and 16 threads.
+	Argon2Time, Argon2Memory uint32
+	Argon2Threads            uint8
+}
+
+// ScryptPasswordParams returns the recommended parameters for scrypt: N = 2^17, r = 8
+// and p = 1.
+func ScryptPasswordParams() *PasswordParams {
+	return &PasswordParams{KDF: Scrypt, ScryptN: 1 << 17, ScryptR: 8, ScryptP: 1}
+}
+
+// Argon2idPasswordParams returns the recommended parameters for Argon2id: 3 passes over
+// 64 MiB of memory with 4 threads.
+func Argon2idPasswordParams() *PasswordParams {
+	return &PasswordParams{KDF: Argon2id, Argon2Time: 3, Argon2Memory: 64 * 1024, Argon2Threads: 4}
+}
+
+// validate returns an error if the parameters are outside of the accepted bounds.
+func (p *PasswordParams) validate() error {
+	switch p.KDF {
+	case Scrypt:
+		if p.ScryptN < minScryptN || p.ScryptN > maxScryptN || p.ScryptN&(p.ScryptN-1) != 0 {
+			return fmt.Errorf("scrypt N must be a power of two between %d and %d", minScryptN, maxScryptN)
+		}
+		if p.ScryptR != scryptR {
+			return fmt.Errorf("scrypt r must be %d", scryptR)
+		}
+		if p.ScryptP < minScryptP || p.ScryptP > maxScryptP {
+			return fmt.Errorf("scrypt p must be between %d and %d", minScryptP, maxScryptP)
+		}
+	case Argon2id:
+		if p.Argon2Time < minArgon2Time || p.Argon2Time > maxArgon2Time {
+			return fmt.Errorf("argon2id time must be between %d and %d", minArgon2Time, maxArgon2Time)
+		}
+		if p.Argon2Memory < minArgon2Memory || p.Argon2Memory > maxArgon2Memory {
+			return fmt.Errorf("argon2id memory must be between %d and %d KiB", minArgon2Memory, maxArgon2Memory)
+		}
+		if p.Argon2Threads < minArgon2Threads || p.Argon2Threads > maxArgon2Threads {
+			return fmt.Errorf("argon2id threads must be between %d and %d", minArgon2Threads, maxArgon2Threads)
+		}
+	default:
+		return fmt.Errorf("unknown KDF %d", p.KDF)
+	}
+	return nil
+}
+
+// ReadWithPassword creates a Handle from a keyset obtained via reader that was encrypted
+// by WriteWithPassword. It fails if the KDF parameters stored in the keyset are weaker
+// than the accepted minimum or costlier than the accepted maximum.
+func ReadWithPassword(reader Reader, password []byte) (*Handle, error) {
+	encryptedKeyset, err := reader.ReadEncrypted()
+	if err != nil {
+		return nil, err
+	}
+	if encryptedKeyset == nil {
+		return nil, fmt.Errorf("keyset.Handle: invalid encrypted keyset")
+	}
+	masterKey, err := parsePasswordKey(encryptedKeyset.EncryptedKeyset, password)
+	if err != nil {
+		return nil, err
+	}
+	ks, err := decrypt(encryptedKeyset, masterKey)
+	if err != nil {
+		return nil, err
+	}
+	return &Handle{ks: ks}, nil
+}
+
+// WriteWithPassword encrypts the keyset with a master key derived from password with the
+// given parameters and writes it. If params is nil, ScryptPasswordParams is used.
+func (h *Handle) WriteWithPassword(writer Writer, password []byte, params *PasswordParams) error {
+	if params == nil {
+		params = ScryptPasswordParams()
+	}
+	masterKey, err := newPasswordKey(password, params, random.GetRandomBytes(passwordSaltSize))
+	if err != nil {
+		return err
+	}
+	return h.Write(writer, masterKey)
+}
+
+// passwordKey is a master key derived from a password. Its ciphertexts are prefixed with a
+// header holding the KDF parameters and the salt, which is authenticated as associated data.
+type passwordKey struct {
+	header []byte
+	aead   tink.AEAD
+}
+
+var _ tink.AEAD = (*passwordKey)(nil)
+
+// newPasswordKey derives the master key from password with the given parameters and salt.
+func newPasswordKey(password []byte, params *PasswordParams, salt []byte) (*passwordKey, error) {
+	if len(password) == 0 {
+		return nil, errors.New("keyset.Handle: password must not be empty")
+	}
+	if err := params.validate(); err != nil {
+		return nil, fmt.Errorf("keyset.Handle: invalid password parameters: %s", err)
+	}
+	var key []byte
+	header := []byte{passwordHeaderVersion, byte(params.KDF)}
+	switch params.KDF {
+	case Scrypt:
+		var err error
+		key, err = scrypt.Key(password, salt, params.ScryptN, params.ScryptR, params.ScryptP, passwordKeySize)
+		if err != nil {
+			return nil, fmt.Errorf("keyset.Handle: scrypt failed: %s", err)
+		}
+		logN := byte(0)
+		for n := params.ScryptN; n > 1; n >>= 1 {
+			logN++
+		}
+		header = append(header, logN)
+		header = appendUint32(header, uint32(params.ScryptR))
+		header = appendUint32(header, uint32(params.ScryptP))
+	case Argon2id:
+		key = argon2.IDKey(password, salt, params.Argon2Time, params.Argon2Memory, params.Argon2Threads, passwordKeySize)
+		header = appendUint32(header, params.Argon2Time)
+		header = appendUint32(header, params.Argon2Memory)
+		header = append(header, params.Argon2Threads)
+	}
+	a, err := aead.NewAESGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	return &passwordKey{
+		header: append(header, salt...),
+		aead:   a,
+	}, nil
+}
+
+// parsePasswordKey derives the master key of an encrypted keyset from password, using the
+// KDF parameters and salt in the header of the encrypted keyset.
+func parsePasswordKey(encrypted, password []byte) (*passwordKey, error) {
+	errHeader := errors.New("keyset.Handle: invalid password-encrypted keyset")
+	if len(encrypted) < 2 || encrypted[0] != passwordHeaderVersion {
+		return nil, errHeader
+	}
+	params := &PasswordParams{KDF: PasswordKDF(encrypted[1])}
+	b := encrypted[2:]
+	switch params.KDF {
+	case Scrypt:
+		if len(b) < 9 || b[0] >= 31 {
+			return nil, errHeader
+		}
+		params.ScryptN = 1 << b[0]
+		params.ScryptR = int(binary.BigEndian.Uint32(b[1:]))
+		params.ScryptP = int(binary.BigEndian.Uint32(b[5:]))
+		b = b[9:]
+	case Argon2id:
+		if len(b) < 9 {
+			return nil, errHeader
+		}
+		params.Argon2Time = binary.BigEndian.Uint32(b)
+		params.Argon2Memory = binary.BigEndian.Uint32(b[4:])
+		params.Argon2Threads = b[8]
+		b = b[9:]
+	default:
+		return nil, errHeader
+	}
+	if len(b) < passwordSaltSize {
+		return nil, errHeader
+	}
+	return newPasswordKey(password, params, b[:passwordSaltSize])
+}
+
+// Encrypt encrypts plaintext and prefixes the ciphertext with the header.
+func (k *passwordKey) Encrypt(plaintext, additionalData []byte) ([]byte, error) {
+	ct, err := k.aead.Encrypt(plaintext, k.associatedData(additionalData))
+	if err != nil {
+		return nil, err
+	}
+	return append(append([]byte{}, k.header...), ct...), nil
+}
+
+// Decrypt decrypts a ciphertext created by Encrypt with the same header.
+func (k *passwordKey) Decrypt(ciphertext, additionalData []byte) ([]byte, error) {
+	if !bytes.HasPrefix(ciphertext, k.header) {
+		return nil, errors.New("ciphertext header mismatch")
+	}
+	return k.aead.Decrypt(ciphertext[len(k.header):], k.associatedData(additionalData))
+}
+
+func (k *passwordKey) associatedData(additionalData []byte) []byte {
+	return append(append([]byte{}, k.header...), additionalData...)
+}
+
+func appendUint32(b []byte, v uint32) []byte {
+	var buf [4]byte
+	binary.BigEndian.PutUint32(buf[:], v)
+	return append(b, buf[:]...)
+}
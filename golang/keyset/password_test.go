@@ -0,0 +1,138 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package keyset_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+
+	"github.com/tsingson/tink/golang/keyset"
+	"github.com/tsingson/tink/golang/mac"
+	"github.com/tsingson/tink/golang/testkeyset"
+)
+
+// Minimum accepted parameters, to keep the tests fast.
+var (
+	testScryptParams   = &keyset.PasswordParams{KDF: keyset.Scrypt, ScryptN: 1 << 15, ScryptR: 8, ScryptP: 1}
+	testArgon2idParams = &keyset.PasswordParams{KDF: keyset.Argon2id, Argon2Time: 2, Argon2Memory: 19 * 1024, Argon2Threads: 1}
+)
+
+func TestReadWithPassword(t *testing.T) {
+	h, err := keyset.NewHandle(mac.HMACSHA256Tag256KeyTemplate())
+	if err != nil {
+		t.Fatalf("keyset.NewHandle(): %v", err)
+	}
+	password := []byte("correct horse battery staple")
+	for _, params := range []*keyset.PasswordParams{testScryptParams, testArgon2idParams} {
+		var buf bytes.Buffer
+		if err := h.WriteWithPassword(keyset.NewJSONWriter(&buf), password, params); err != nil {
+			t.Fatalf("handle.WriteWithPassword(): %v", err)
+		}
+		if strings.Contains(buf.String(), "keyValue") {
+			t.Errorf("handle.WriteWithPassword() wrote cleartext key material")
+		}
+		h2, err := keyset.ReadWithPassword(keyset.NewJSONReader(bytes.NewReader(buf.Bytes())), password)
+		if err != nil {
+			t.Fatalf("keyset.ReadWithPassword(): %v", err)
+		}
+		if !proto.Equal(testkeyset.KeysetMaterial(h), testkeyset.KeysetMaterial(h2)) {
+			t.Errorf("keyset.ReadWithPassword() = %v, want %v", h2, h)
+		}
+		if _, err := keyset.ReadWithPassword(keyset.NewJSONReader(bytes.NewReader(buf.Bytes())), []byte("wrong password")); err == nil {
+			t.Errorf("keyset.ReadWithPassword() with wrong password: err = nil, want error")
+		}
+	}
+}
+
+func TestReadWithPasswordEnforcesParameterFloors(t *testing.T) {
+	h, err := keyset.NewHandle(mac.HMACSHA256Tag256KeyTemplate())
+	if err != nil {
+		t.Fatalf("keyset.NewHandle(): %v", err)
+	}
+	password := []byte("password")
+	testCases := []struct {
+		name   string
+		params *keyset.PasswordParams
+		// tamper modifies the header at the start of the encrypted keyset.
+		tamper func(header []byte)
+	}{
+		{"scrypt N below minimum", testScryptParams, func(b []byte) { b[2]-- }},
+		{"scrypt N above maximum", testScryptParams, func(b []byte) { b[2] = 19 }},
+		{"scrypt r below minimum", testScryptParams, func(b []byte) { b[6] = 1 }},
+		{"scrypt r above maximum", testScryptParams, func(b []byte) { b[6] = 16 }},
+		{"scrypt p zero", testScryptParams, func(b []byte) { b[10] = 0 }},
+		{"scrypt p above maximum", testScryptParams, func(b []byte) { b[10] = 5 }},
+		{"argon2id time below minimum", testArgon2idParams, func(b []byte) { b[5] = 1 }},
+		{"argon2id time above maximum", testArgon2idParams, func(b []byte) { b[5] = 11 }},
+		{"argon2id memory below minimum", testArgon2idParams, func(b []byte) { b[8] = 0 }},
+		{"argon2id memory above maximum", testArgon2idParams, func(b []byte) { b[7] = 0x10 }},
+		{"argon2id no threads", testArgon2idParams, func(b []byte) { b[10] = 0 }},
+		{"argon2id threads above maximum", testArgon2idParams, func(b []byte) { b[10] = 17 }},
+		{"unknown version", testScryptParams, func(b []byte) { b[0] = 2 }},
+		{"unknown KDF", testScryptParams, func(b []byte) { b[1] = 3 }},
+		{"modified salt", testScryptParams, func(b []byte) { b[len(b)-1] ^= 1 }},
+	}
+	for _, tc := range testCases {
+		mem := &keyset.MemReaderWriter{}
+		if err := h.WriteWithPassword(mem, password, tc.params); err != nil {
+			t.Fatalf("%s: handle.WriteWithPassword(): %v", tc.name, err)
+		}
+		headerSize := 2 + 9 + 16
+		tc.tamper(mem.EncryptedKeyset.EncryptedKeyset[:headerSize])
+		if _, err := keyset.ReadWithPassword(mem, password); err == nil {
+			t.Errorf("%s: keyset.ReadWithPassword() err = nil, want error", tc.name)
+		}
+	}
+
+	// A keyset encrypted with a password cannot be read with the header cut off.
+	mem := &keyset.MemReaderWriter{}
+	if err := h.WriteWithPassword(mem, password, testScryptParams); err != nil {
+		t.Fatalf("handle.WriteWithPassword(): %v", err)
+	}
+	mem.EncryptedKeyset.EncryptedKeyset = mem.EncryptedKeyset.EncryptedKeyset[:10]
+	if _, err := keyset.ReadWithPassword(mem, password); err == nil {
+		t.Errorf("keyset.ReadWithPassword() of truncated keyset: err = nil, want error")
+	}
+}
+
+func TestWriteWithPasswordWithInvalidInput(t *testing.T) {
+	h, err := keyset.NewHandle(mac.HMACSHA256Tag256KeyTemplate())
+	if err != nil {
+		t.Fatalf("keyset.NewHandle(): %v", err)
+	}
+	testCases := []struct {
+		name     string
+		password string
+		params   *keyset.PasswordParams
+	}{
+		{"empty password", "", testScryptParams},
+		{"unknown KDF", "password", &keyset.PasswordParams{KDF: 3}},
+		{"scrypt N not a power of two", "password", &keyset.PasswordParams{KDF: keyset.Scrypt, ScryptN: 3 << 14, ScryptR: 8, ScryptP: 1}},
+		{"scrypt N too small", "password", &keyset.PasswordParams{KDF: keyset.Scrypt, ScryptN: 1 << 14, ScryptR: 8, ScryptP: 1}},
+		{"scrypt N too big", "password", &keyset.PasswordParams{KDF: keyset.Scrypt, ScryptN: 1 << 19, ScryptR: 8, ScryptP: 1}},
+		{"scrypt r not 8", "password", &keyset.PasswordParams{KDF: keyset.Scrypt, ScryptN: 1 << 15, ScryptR: 16, ScryptP: 1}},
+		{"argon2id memory too small", "password", &keyset.PasswordParams{KDF: keyset.Argon2id, Argon2Time: 3, Argon2Memory: 1024, Argon2Threads: 1}},
+		{"argon2id memory too big", "password", &keyset.PasswordParams{KDF: keyset.Argon2id, Argon2Time: 3, Argon2Memory: 2 * 1024 * 1024, Argon2Threads: 1}},
+		{"argon2id too many threads", "password", &keyset.PasswordParams{KDF: keyset.Argon2id, Argon2Time: 3, Argon2Memory: 64 * 1024, Argon2Threads: 255}},
+	}
+	for _, tc := range testCases {
+		if err := h.WriteWithPassword(&keyset.MemReaderWriter{}, []byte(tc.password), tc.params); err == nil {
+			t.Errorf("%s: handle.WriteWithPassword() err = nil, want error", tc.name)
+		}
+	}
+}
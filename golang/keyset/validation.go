@@ -82,7 +82,8 @@ func validateKey(key *tinkpb.Keyset_Key) error {
 	if key.KeyId == 0 {
 		return fmt.Errorf("key has zero key id: %d", key.KeyId)
 	}
-	if key.KeyData == nil {
+	// The key material of DESTROYED keys has been wiped.
+	if key.KeyData == nil && key.Status != tinkpb.KeyStatusType_DESTROYED {
 		return fmt.Errorf("key %d has no key data", key.KeyId)
 	}
 	if key.OutputPrefixType != tinkpb.OutputPrefixType_TINK &&
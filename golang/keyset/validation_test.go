@@ -17,9 +17,9 @@ package keyset_test
 import (
 	"testing"
 
-	"github.com/tsingson/tink/go/keyset"
-	"github.com/tsingson/tink/go/subtle/random"
-	"github.com/tsingson/tink/go/testutil"
+	"github.com/tsingson/tink/golang/keyset"
+	"github.com/tsingson/tink/golang/subtle/random"
+	"github.com/tsingson/tink/golang/testutil"
 	tinkpb "github.com/tsingson/tink/proto/tink_go_proto"
 )
 
@@ -81,6 +81,14 @@ func TestValidate(t *testing.T) {
 	if err = keyset.Validate(testutil.NewKeyset(1, keys)); err == nil {
 		t.Errorf("expect an error when there are no primary keys")
 	}
+	// destroyed keys have no key data
+	keys = []*tinkpb.Keyset_Key{
+		testutil.NewDummyKey(1, tinkpb.KeyStatusType_ENABLED, tinkpb.OutputPrefixType_TINK),
+		testutil.NewKey(nil, tinkpb.KeyStatusType_DESTROYED, 2, tinkpb.OutputPrefixType_TINK),
+	}
+	if err = keyset.Validate(testutil.NewKeyset(1, keys)); err != nil {
+		t.Errorf("valid test failed when a key is destroyed: %v", err)
+	}
 	// public key only
 	keys = []*tinkpb.Keyset_Key{
 		testutil.NewKey(testutil.NewKeyData(testutil.EciesAeadHkdfPublicKeyTypeURL, random.GetRandomBytes(10), tinkpb.KeyData_ASYMMETRIC_PUBLIC), tinkpb.KeyStatusType_ENABLED, 1, tinkpb.OutputPrefixType_TINK),
@@ -0,0 +1,136 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package keywrap
+
+import (
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+
+	"github.com/tsingson/tink/golang/core/registry"
+	"github.com/tsingson/tink/golang/keyset"
+	"github.com/tsingson/tink/golang/subtle/kwp"
+	"github.com/tsingson/tink/golang/subtle/random"
+	kwppb "github.com/tsingson/tink/proto/aes_kwp_go_proto"
+	tinkpb "github.com/tsingson/tink/proto/tink_go_proto"
+)
+
+const (
+	aesKWPKeyVersion = 0
+	// AES-KWP keys are local to this fork, so their type URL is outside the google.crypto.tink
+	// namespace of upstream Tink.
+	aesKWPTypeURL = "type.googleapis.com/tsingson.tink.AesKwpKey"
+)
+
+// common errors
+var errInvalidAESKWPKey = fmt.Errorf("aes_kwp_key_manager: invalid key")
+var errInvalidAESKWPKeyFormat = fmt.Errorf("aes_kwp_key_manager: invalid key format")
+
+// aesKWPKeyManager is an implementation of KeyManager interface.
+// It generates new AesKwpKey keys and produces new instances of KWP subtle.
+type aesKWPKeyManager struct{}
+
+// Assert that aesKWPKeyManager implements the KeyManager interface.
+var _ registry.KeyManager = (*aesKWPKeyManager)(nil)
+
+// newAESKWPKeyManager creates a new aesKWPKeyManager.
+func newAESKWPKeyManager() *aesKWPKeyManager {
+	return new(aesKWPKeyManager)
+}
+
+// Primitive creates a KWP subtle for the given serialized AesKwpKey proto.
+func (km *aesKWPKeyManager) Primitive(serializedKey []byte) (interface{}, error) {
+	if len(serializedKey) == 0 {
+		return nil, errInvalidAESKWPKey
+	}
+	key := new(kwppb.AesKwpKey)
+	if err := proto.Unmarshal(serializedKey, key); err != nil {
+		return nil, errInvalidAESKWPKey
+	}
+	if err := km.validateKey(key); err != nil {
+		return nil, err
+	}
+	ret, err := kwp.NewKWP(key.KeyValue)
+	if err != nil {
+		return nil, fmt.Errorf("aes_kwp_key_manager: cannot create new primitive: %s", err)
+	}
+	return ret, nil
+}
+
+// NewKey creates a new key according to specification the given serialized AesKwpKeyFormat.
+func (km *aesKWPKeyManager) NewKey(serializedKeyFormat []byte) (proto.Message, error) {
+	if len(serializedKeyFormat) == 0 {
+		return nil, errInvalidAESKWPKeyFormat
+	}
+	keyFormat := new(kwppb.AesKwpKeyFormat)
+	if err := proto.Unmarshal(serializedKeyFormat, keyFormat); err != nil {
+		return nil, errInvalidAESKWPKeyFormat
+	}
+	if err := validateKeySize(keyFormat.KeySize); err != nil {
+		return nil, fmt.Errorf("aes_kwp_key_manager: invalid key format: %s", err)
+	}
+	return &kwppb.AesKwpKey{
+		Version:  aesKWPKeyVersion,
+		KeyValue: random.GetRandomBytes(keyFormat.KeySize),
+	}, nil
+}
+
+// NewKeyData creates a new KeyData according to specification in the given serialized
+// AesKwpKeyFormat.
+// It should be used solely by the key management API.
+func (km *aesKWPKeyManager) NewKeyData(serializedKeyFormat []byte) (*tinkpb.KeyData, error) {
+	key, err := km.NewKey(serializedKeyFormat)
+	if err != nil {
+		return nil, err
+	}
+	serializedKey, err := proto.Marshal(key)
+	if err != nil {
+		return nil, err
+	}
+	return &tinkpb.KeyData{
+		TypeUrl:         aesKWPTypeURL,
+		Value:           serializedKey,
+		KeyMaterialType: tinkpb.KeyData_SYMMETRIC,
+	}, nil
+}
+
+// DoesSupport indicates if this key manager supports the given key type.
+func (km *aesKWPKeyManager) DoesSupport(typeURL string) bool {
+	return typeURL == aesKWPTypeURL
+}
+
+// TypeURL returns the key type of keys managed by this key manager.
+func (km *aesKWPKeyManager) TypeURL() string {
+	return aesKWPTypeURL
+}
+
+// validateKey validates the given AesKwpKey.
+func (km *aesKWPKeyManager) validateKey(key *kwppb.AesKwpKey) error {
+	if err := keyset.ValidateKeyVersion(key.Version, aesKWPKeyVersion); err != nil {
+		return fmt.Errorf("aes_kwp_key_manager: %s", err)
+	}
+	if err := validateKeySize(uint32(len(key.KeyValue))); err != nil {
+		return fmt.Errorf("aes_kwp_key_manager: %s", err)
+	}
+	return nil
+}
+
+// validateKeySize checks that the given wrapping key size is supported by KWP.
+func validateKeySize(keySize uint32) error {
+	if keySize != 16 && keySize != 32 {
+		return fmt.Errorf("invalid key size; want 16 or 32, got %d", keySize)
+	}
+	return nil
+}
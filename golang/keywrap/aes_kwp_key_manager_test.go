@@ -0,0 +1,160 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package keywrap_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+
+	"github.com/tsingson/tink/golang/core/registry"
+	"github.com/tsingson/tink/golang/subtle/kwp"
+	"github.com/tsingson/tink/golang/subtle/random"
+	"github.com/tsingson/tink/golang/testutil"
+	kwppb "github.com/tsingson/tink/proto/aes_kwp_go_proto"
+	tinkpb "github.com/tsingson/tink/proto/tink_go_proto"
+)
+
+var keySizes = []uint32{16, 32}
+
+func TestAESKWPGetPrimitiveBasic(t *testing.T) {
+	km, err := registry.GetKeyManager(testutil.AESKWPTypeURL)
+	if err != nil {
+		t.Fatalf("cannot obtain AES-KWP key manager: %s", err)
+	}
+	for _, keySize := range keySizes {
+		key := newAESKWPKey(testutil.AESKWPKeyVersion, keySize)
+		serializedKey, _ := proto.Marshal(key)
+		p, err := km.Primitive(serializedKey)
+		if err != nil {
+			t.Errorf("unexpected error: %s", err)
+			continue
+		}
+		// the primitive must be compatible with a KWP built from the same key
+		expected, err := kwp.NewKWP(key.KeyValue)
+		if err != nil {
+			t.Fatalf("kwp.NewKWP() failed: %s", err)
+		}
+		data := random.GetRandomBytes(32)
+		wrapped, err := p.(*kwp.KWP).Wrap(data)
+		if err != nil {
+			t.Errorf("wrap failed: %s", err)
+		}
+		unwrapped, err := expected.Unwrap(wrapped)
+		if err != nil || !bytes.Equal(unwrapped, data) {
+			t.Errorf("unwrap failed: %s", err)
+		}
+	}
+}
+
+func TestAESKWPGetPrimitiveWithInvalidInput(t *testing.T) {
+	km, err := registry.GetKeyManager(testutil.AESKWPTypeURL)
+	if err != nil {
+		t.Fatalf("cannot obtain AES-KWP key manager: %s", err)
+	}
+	testKeys := []proto.Message{
+		// not an AesKwpKey
+		&kwppb.AesKwpKeyFormat{KeySize: 32},
+		// bad key size
+		newAESKWPKey(testutil.AESKWPKeyVersion, 24),
+		newAESKWPKey(testutil.AESKWPKeyVersion, 33),
+		// bad version
+		newAESKWPKey(testutil.AESKWPKeyVersion+1, 16),
+	}
+	for i, key := range testKeys {
+		serializedKey, _ := proto.Marshal(key)
+		if _, err := km.Primitive(serializedKey); err == nil {
+			t.Errorf("expect an error in test case %d", i)
+		}
+	}
+	if _, err := km.Primitive(nil); err == nil {
+		t.Errorf("expect an error when input is nil")
+	}
+	if _, err := km.Primitive([]byte{}); err == nil {
+		t.Errorf("expect an error when input is empty")
+	}
+}
+
+func TestAESKWPNewKeyData(t *testing.T) {
+	km, err := registry.GetKeyManager(testutil.AESKWPTypeURL)
+	if err != nil {
+		t.Fatalf("cannot obtain AES-KWP key manager: %s", err)
+	}
+	for _, keySize := range keySizes {
+		serializedFormat, _ := proto.Marshal(&kwppb.AesKwpKeyFormat{KeySize: keySize})
+		keyData, err := km.NewKeyData(serializedFormat)
+		if err != nil {
+			t.Errorf("unexpected error: %s", err)
+			continue
+		}
+		if keyData.TypeUrl != testutil.AESKWPTypeURL {
+			t.Errorf("incorrect type url")
+		}
+		if keyData.KeyMaterialType != tinkpb.KeyData_SYMMETRIC {
+			t.Errorf("incorrect key material type")
+		}
+		key := new(kwppb.AesKwpKey)
+		if err := proto.Unmarshal(keyData.Value, key); err != nil {
+			t.Errorf("incorrect key value")
+		}
+		if key.Version != testutil.AESKWPKeyVersion || uint32(len(key.KeyValue)) != keySize {
+			t.Errorf("key does not match the format")
+		}
+	}
+	// keys must be random
+	serializedFormat, _ := proto.Marshal(&kwppb.AesKwpKeyFormat{KeySize: 16})
+	keys := make(map[string]bool)
+	nTest := 26
+	for i := 0; i < nTest; i++ {
+		keyData, _ := km.NewKeyData(serializedFormat)
+		keys[string(keyData.Value)] = true
+	}
+	if len(keys) != nTest {
+		t.Errorf("key is repeated")
+	}
+}
+
+func TestAESKWPNewKeyWithInvalidInput(t *testing.T) {
+	km, err := registry.GetKeyManager(testutil.AESKWPTypeURL)
+	if err != nil {
+		t.Fatalf("cannot obtain AES-KWP key manager: %s", err)
+	}
+	for _, keySize := range []uint32{0, 15, 24, 64} {
+		serializedFormat, _ := proto.Marshal(&kwppb.AesKwpKeyFormat{KeySize: keySize})
+		if _, err := km.NewKey(serializedFormat); err == nil {
+			t.Errorf("expect an error with key size %d", keySize)
+		}
+	}
+	if _, err := km.NewKey(nil); err == nil {
+		t.Errorf("expect an error when input is nil")
+	}
+	if _, err := km.NewKey([]byte{}); err == nil {
+		t.Errorf("expect an error when input is empty")
+	}
+	if !km.DoesSupport(testutil.AESKWPTypeURL) || km.DoesSupport("some bad type") {
+		t.Errorf("AESKWPKeyManager must support only %s", testutil.AESKWPTypeURL)
+	}
+	if km.TypeURL() != testutil.AESKWPTypeURL {
+		t.Errorf("incorrect key type")
+	}
+}
+
+func newAESKWPKey(keyVersion, keySize uint32) *kwppb.AesKwpKey {
+	return &kwppb.AesKwpKey{
+		Version:  keyVersion,
+		KeyValue: random.GetRandomBytes(keySize),
+	}
+}
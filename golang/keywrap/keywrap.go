@@ -0,0 +1,72 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+// Package keywrap provides implementations of the KeyWrap primitive.
+// Key wrapping protects key material with a key-encryption key, e.g. before
+// importing third-party data keys into an HSM.
+// Example:
+//
+// package main
+//
+// import (
+//
+//	"fmt"
+//
+//	"github.com/tsingson/tink/golang/keyset"
+//	"github.com/tsingson/tink/golang/keywrap"
+//
+// )
+//
+// func main() {
+//
+//	    kh, err := keyset.NewHandle(keywrap.AES256KWPKeyTemplate())
+//	    if err != nil {
+//	        // handle the error
+//	    }
+//
+//	    w, err := keywrap.New(kh)
+//	    if err != nil {
+//	        // handle the error
+//	    }
+//
+//	    wrapped, err := w.Wrap(dataKey)
+//	    if err != nil {
+//	        // handle error
+//	    }
+//
+//	    unwrapped, err := w.Unwrap(wrapped)
+//	    if err != nil {
+//	        // handle error
+//	    }
+//	}
+package keywrap
+
+import (
+	"fmt"
+
+	"github.com/tsingson/tink/golang/core/registry"
+)
+
+// keyWrapPrimitiveName is the name of the primitive in the Tink catalogue.
+const keyWrapPrimitiveName = "KeyWrap"
+
+func init() {
+	if err := registry.TinkCatalogue().Add(keyWrapPrimitiveName, aesKWPTypeURL, 0, func(*registry.Registry) registry.KeyManager { return newAESKWPKeyManager() }); err != nil {
+		panic(fmt.Sprintf("keywrap.init() failed: %v", err))
+	}
+
+	if err := registry.RegisterPrimitiveWrapper(new(wrapper)); err != nil {
+		panic(fmt.Sprintf("keywrap.init() failed: %v", err))
+	}
+}
@@ -0,0 +1,123 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package keywrap
+
+import (
+	"fmt"
+
+	"github.com/tsingson/tink/golang/core/primitiveset"
+	"github.com/tsingson/tink/golang/core/registry"
+	"github.com/tsingson/tink/golang/keyset"
+	"github.com/tsingson/tink/golang/tink"
+)
+
+// New returns a KeyWrap primitive from the given keyset handle.
+func New(h *keyset.Handle) (tink.KeyWrap, error) {
+	return NewWithKeyManager(h, nil /*keyManager*/)
+}
+
+// NewWithKeyManager returns a KeyWrap primitive from the given keyset handle and custom key manager.
+func NewWithKeyManager(h *keyset.Handle, km registry.KeyManager) (tink.KeyWrap, error) {
+	ps, err := h.PrimitivesWithKeyManager(km)
+	if err != nil {
+		return nil, fmt.Errorf("keywrap_factory: cannot obtain primitive set: %s", err)
+	}
+	return wrap(registry.Default(), ps)
+}
+
+// NewWithRegistry returns a KeyWrap primitive from the given keyset handle, using the key
+// managers of the given registry.
+func NewWithRegistry(h *keyset.Handle, r *registry.Registry) (tink.KeyWrap, error) {
+	ps, err := h.PrimitivesWithRegistry(r)
+	if err != nil {
+		return nil, fmt.Errorf("keywrap_factory: cannot obtain primitive set: %s", err)
+	}
+	return wrap(r, ps)
+}
+
+// wrap combines the primitives in ps into a KeyWrap using the primitive wrapper
+// registered in r.
+func wrap(r *registry.Registry, ps *primitiveset.PrimitiveSet) (tink.KeyWrap, error) {
+	p, err := r.Wrap(keyWrapPrimitiveName, ps)
+	if err != nil {
+		return nil, fmt.Errorf("keywrap_factory: cannot wrap primitive set: %s", err)
+	}
+	ret, ok := p.(tink.KeyWrap)
+	if !ok {
+		return nil, fmt.Errorf("keywrap_factory: not a KeyWrap primitive")
+	}
+	return ret, nil
+}
+
+// wrapper is the registry.PrimitiveWrapper for the KeyWrap primitive.
+type wrapper struct{}
+
+// Asserts that wrapper implements the PrimitiveWrapper interface.
+var _ registry.PrimitiveWrapper = (*wrapper)(nil)
+
+// PrimitiveName returns the name of the KeyWrap primitive.
+func (w *wrapper) PrimitiveName() string {
+	return keyWrapPrimitiveName
+}
+
+// Wrap returns a KeyWrap that uses the primitives in the given primitive set, which must
+// all be KeyWrap primitives.
+func (w *wrapper) Wrap(ps *primitiveset.PrimitiveSet) (interface{}, error) {
+	if !ps.All(func(p interface{}) bool { _, ok := p.(tink.KeyWrap); return ok }) {
+		return nil, fmt.Errorf("keywrap_factory: not a KeyWrap primitive")
+	}
+	return newPrimitiveSet(ps), nil
+}
+
+// primitiveSet is a KeyWrap implementation that uses the underlying primitive set
+// for wrapping and unwrapping.
+type primitiveSet struct {
+	ps *primitiveset.PrimitiveSet
+}
+
+// Asserts that primitiveSet implements the KeyWrap interface.
+var _ tink.KeyWrap = (*primitiveSet)(nil)
+
+func newPrimitiveSet(ps *primitiveset.PrimitiveSet) *primitiveSet {
+	ret := new(primitiveSet)
+	ret.ps = ps
+	return ret
+}
+
+// Wrap wraps the given data with the primary key.
+// It returns the concatenation of the primary's identifier and the wrapped data.
+func (w *primitiveSet) Wrap(data []byte) ([]byte, error) {
+	primary := w.ps.Primary
+	p := (primary.Primitive).(tink.KeyWrap)
+	wrapped, err := p.Wrap(data)
+	if err != nil {
+		return nil, err
+	}
+	return primary.Prefixed(wrapped), nil
+}
+
+// Unwrap unwraps the given data with the key identified by its prefix, falling
+// back to the raw keys in the keyset.
+func (w *primitiveSet) Unwrap(data []byte) ([]byte, error) {
+	for _, c := range w.ps.Candidates(data) {
+		p := (c.Entry.Primitive).(tink.KeyWrap)
+		unwrapped, err := p.Unwrap(c.Output)
+		if err == nil {
+			return unwrapped, nil
+		}
+	}
+	// nothing worked
+	return nil, fmt.Errorf("keywrap_factory: unwrap failed")
+}
@@ -0,0 +1,109 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package keywrap_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/tsingson/tink/golang/core/cryptofmt"
+	"github.com/tsingson/tink/golang/keyset"
+	"github.com/tsingson/tink/golang/keywrap"
+	"github.com/tsingson/tink/golang/subtle/random"
+	"github.com/tsingson/tink/golang/testkeyset"
+	tinkpb "github.com/tsingson/tink/proto/tink_go_proto"
+)
+
+func TestFactoryWithRotation(t *testing.T) {
+	kh, err := keyset.NewHandle(keywrap.AES128KWPKeyTemplate())
+	if err != nil {
+		t.Fatalf("keyset.NewHandle() failed: %s", err)
+	}
+	w1, err := keywrap.New(kh)
+	if err != nil {
+		t.Fatalf("keywrap.New() failed: %s", err)
+	}
+	data := random.GetRandomBytes(32)
+	wrapped1, err := w1.Wrap(data)
+	if err != nil {
+		t.Fatalf("wrap failed: %s", err)
+	}
+	primary := testkeyset.KeysetMaterial(kh).Key[0]
+	expectedPrefix, _ := cryptofmt.OutputPrefix(primary)
+	if string(wrapped1[:len(expectedPrefix)]) != expectedPrefix {
+		t.Errorf("incorrect prefix")
+	}
+
+	// rotate to a new key; data wrapped with the old key can still be unwrapped
+	manager := keyset.NewManagerFromHandle(kh)
+	if err := manager.Rotate(keywrap.AES256KWPKeyTemplate()); err != nil {
+		t.Fatalf("manager.Rotate() failed: %s", err)
+	}
+	kh2, err := manager.Handle()
+	if err != nil {
+		t.Fatalf("manager.Handle() failed: %s", err)
+	}
+	w2, err := keywrap.New(kh2)
+	if err != nil {
+		t.Fatalf("keywrap.New() failed: %s", err)
+	}
+	wrapped2, err := w2.Wrap(data)
+	if err != nil {
+		t.Fatalf("wrap failed: %s", err)
+	}
+	if bytes.Equal(wrapped1, wrapped2) {
+		t.Errorf("the new primary key must be used for wrapping")
+	}
+	for _, wrapped := range [][]byte{wrapped1, wrapped2} {
+		unwrapped, err := w2.Unwrap(wrapped)
+		if err != nil || !bytes.Equal(unwrapped, data) {
+			t.Errorf("unwrap failed: %s", err)
+		}
+	}
+	// a keyset without the new key cannot unwrap data wrapped with it
+	if _, err := w1.Unwrap(wrapped2); err == nil {
+		t.Errorf("expect an error when unwrapping with the wrong keyset")
+	}
+}
+
+func TestFactoryRawKeyAsPrimary(t *testing.T) {
+	template := keywrap.AES256KWPKeyTemplate()
+	template.OutputPrefixType = tinkpb.OutputPrefixType_RAW
+	kh, err := keyset.NewHandle(template)
+	if err != nil {
+		t.Fatalf("keyset.NewHandle() failed: %s", err)
+	}
+	w, err := keywrap.New(kh)
+	if err != nil {
+		t.Fatalf("keywrap.New() failed: %s", err)
+	}
+	data := random.GetRandomBytes(16)
+	wrapped, err := w.Wrap(data)
+	if err != nil {
+		t.Fatalf("wrap failed: %s", err)
+	}
+	// KWP output of a 16 byte key is 24 bytes, without any prefix
+	if len(wrapped) != 24 {
+		t.Errorf("unexpected wrapped length %d", len(wrapped))
+	}
+	unwrapped, err := w.Unwrap(wrapped)
+	if err != nil || !bytes.Equal(unwrapped, data) {
+		t.Errorf("unwrap failed: %s", err)
+	}
+	wrapped[len(wrapped)-1] ^= 1
+	if _, err := w.Unwrap(wrapped); err == nil {
+		t.Errorf("expect an error when unwrapping modified data")
+	}
+}
@@ -0,0 +1,71 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package keywrap
+
+import (
+	"github.com/golang/protobuf/proto"
+
+	kwppb "github.com/tsingson/tink/proto/aes_kwp_go_proto"
+	tinkpb "github.com/tsingson/tink/proto/tink_go_proto"
+)
+
+// This file contains pre-generated KeyTemplates for KeyWrap keys. One can use these templates
+// to generate new Keysets.
+//
+// The TINK templates prefix wrapped keys with the ID of the wrapping key, so that keysets
+// with several keys can be rotated. Keys wrapped with the RAW templates are plain RFC 5649
+// outputs that other KWP implementations can unwrap.
+
+// AES128KWPKeyTemplate is a KeyTemplate that generates an AES-KWP key with the following parameters:
+//   - Key size: 16 bytes
+//   - Output prefix type: TINK
+func AES128KWPKeyTemplate() *tinkpb.KeyTemplate {
+	return createAESKWPKeyTemplate(16, tinkpb.OutputPrefixType_TINK)
+}
+
+// AES256KWPKeyTemplate is a KeyTemplate that generates an AES-KWP key with the following parameters:
+//   - Key size: 32 bytes
+//   - Output prefix type: TINK
+func AES256KWPKeyTemplate() *tinkpb.KeyTemplate {
+	return createAESKWPKeyTemplate(32, tinkpb.OutputPrefixType_TINK)
+}
+
+// AES128KWPRawKeyTemplate is a KeyTemplate that generates an AES-KWP key with the following parameters:
+//   - Key size: 16 bytes
+//   - Output prefix type: RAW
+func AES128KWPRawKeyTemplate() *tinkpb.KeyTemplate {
+	return createAESKWPKeyTemplate(16, tinkpb.OutputPrefixType_RAW)
+}
+
+// AES256KWPRawKeyTemplate is a KeyTemplate that generates an AES-KWP key with the following parameters:
+//   - Key size: 32 bytes
+//   - Output prefix type: RAW
+func AES256KWPRawKeyTemplate() *tinkpb.KeyTemplate {
+	return createAESKWPKeyTemplate(32, tinkpb.OutputPrefixType_RAW)
+}
+
+// createAESKWPKeyTemplate creates a new AES-KWP key template with the given key
+// size in bytes and output prefix type.
+func createAESKWPKeyTemplate(keySize uint32, outputPrefixType tinkpb.OutputPrefixType) *tinkpb.KeyTemplate {
+	format := &kwppb.AesKwpKeyFormat{
+		KeySize: keySize,
+	}
+	serializedFormat, _ := proto.Marshal(format)
+	return &tinkpb.KeyTemplate{
+		TypeUrl:          aesKWPTypeURL,
+		Value:            serializedFormat,
+		OutputPrefixType: outputPrefixType,
+	}
+}
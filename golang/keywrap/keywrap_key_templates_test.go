@@ -0,0 +1,82 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package keywrap_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+
+	"github.com/tsingson/tink/golang/core/registry"
+	"github.com/tsingson/tink/golang/keywrap"
+	"github.com/tsingson/tink/golang/subtle/random"
+	"github.com/tsingson/tink/golang/testutil"
+	"github.com/tsingson/tink/golang/tink"
+	kwppb "github.com/tsingson/tink/proto/aes_kwp_go_proto"
+	tinkpb "github.com/tsingson/tink/proto/tink_go_proto"
+)
+
+func TestAESKWPKeyTemplates(t *testing.T) {
+	var testCases = []struct {
+		name       string
+		template   *tinkpb.KeyTemplate
+		keySize    uint32
+		prefixType tinkpb.OutputPrefixType
+	}{
+		{"AES128KWP", keywrap.AES128KWPKeyTemplate(), 16, tinkpb.OutputPrefixType_TINK},
+		{"AES256KWP", keywrap.AES256KWPKeyTemplate(), 32, tinkpb.OutputPrefixType_TINK},
+		{"AES128KWPRaw", keywrap.AES128KWPRawKeyTemplate(), 16, tinkpb.OutputPrefixType_RAW},
+		{"AES256KWPRaw", keywrap.AES256KWPRawKeyTemplate(), 32, tinkpb.OutputPrefixType_RAW},
+	}
+	for _, tc := range testCases {
+		if tc.template.TypeUrl != testutil.AESKWPTypeURL {
+			t.Errorf("%s: incorrect type url: %v, expected %v", tc.name, tc.template.TypeUrl, testutil.AESKWPTypeURL)
+		}
+		if tc.template.OutputPrefixType != tc.prefixType {
+			t.Errorf("%s: incorrect output prefix type", tc.name)
+		}
+		format := new(kwppb.AesKwpKeyFormat)
+		if err := proto.Unmarshal(tc.template.Value, format); err != nil {
+			t.Errorf("%s: cannot deserialize key format: %s", tc.name, err)
+			continue
+		}
+		if format.KeySize != tc.keySize {
+			t.Errorf("%s: incorrect key size, expect %d, got %d", tc.name, tc.keySize, format.KeySize)
+		}
+		key, err := registry.NewKey(tc.template)
+		if err != nil {
+			t.Errorf("%s: failed to get key from template: %s", tc.name, err)
+			continue
+		}
+		sk, _ := proto.Marshal(key)
+		p, err := registry.Primitive(tc.template.TypeUrl, sk)
+		if err != nil {
+			t.Errorf("%s: failed to get primitive from serialized key: %s", tc.name, err)
+			continue
+		}
+		w := p.(tink.KeyWrap)
+		data := random.GetRandomBytes(32)
+		wrapped, err := w.Wrap(data)
+		if err != nil {
+			t.Errorf("%s: wrap failed: %s", tc.name, err)
+			continue
+		}
+		unwrapped, err := w.Unwrap(wrapped)
+		if err != nil || !bytes.Equal(unwrapped, data) {
+			t.Errorf("%s: unwrap failed: %s", tc.name, err)
+		}
+	}
+}
@@ -0,0 +1,146 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package mac
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+
+	"github.com/tsingson/tink/golang/core/registry"
+	"github.com/tsingson/tink/golang/keyset"
+	"github.com/tsingson/tink/golang/subtle/mac"
+	"github.com/tsingson/tink/golang/subtle/random"
+	cmacpb "github.com/tsingson/tink/proto/aes_cmac_go_proto"
+	tinkpb "github.com/tsingson/tink/proto/tink_go_proto"
+)
+
+const (
+	aesCMACKeyVersion = 0
+	aesCMACTypeURL    = "type.googleapis.com/google.crypto.tink.AesCmacKey"
+	// aesCMACKeySize is the only key size accepted by the key manager.
+	aesCMACKeySize = 32
+)
+
+var errInvalidAESCMACKey = errors.New("aes_cmac_key_manager: invalid key")
+var errInvalidAESCMACKeyFormat = errors.New("aes_cmac_key_manager: invalid key format")
+
+// aesCMACKeyManager generates new AES-CMAC keys and produces new instances of AESCMAC.
+type aesCMACKeyManager struct{}
+
+// Assert that aesCMACKeyManager implements the KeyManager interface.
+var _ registry.KeyManager = (*aesCMACKeyManager)(nil)
+
+// newAESCMACKeyManager returns a new aesCMACKeyManager.
+func newAESCMACKeyManager() *aesCMACKeyManager {
+	return new(aesCMACKeyManager)
+}
+
+// Primitive constructs an AESCMAC instance for the given serialized AesCmacKey.
+func (km *aesCMACKeyManager) Primitive(serializedKey []byte) (interface{}, error) {
+	if len(serializedKey) == 0 {
+		return nil, errInvalidAESCMACKey
+	}
+	key := new(cmacpb.AesCmacKey)
+	if err := proto.Unmarshal(serializedKey, key); err != nil {
+		return nil, errInvalidAESCMACKey
+	}
+	if err := km.validateKey(key); err != nil {
+		return nil, err
+	}
+	cmac, err := mac.NewAESCMAC(key.KeyValue, key.Params.TagSize)
+	if err != nil {
+		return nil, err
+	}
+	return cmac, nil
+}
+
+// NewKey generates a new AesCmacKey according to specification in the given AesCmacKeyFormat.
+func (km *aesCMACKeyManager) NewKey(serializedKeyFormat []byte) (proto.Message, error) {
+	if len(serializedKeyFormat) == 0 {
+		return nil, errInvalidAESCMACKeyFormat
+	}
+	keyFormat := new(cmacpb.AesCmacKeyFormat)
+	if err := proto.Unmarshal(serializedKeyFormat, keyFormat); err != nil {
+		return nil, errInvalidAESCMACKeyFormat
+	}
+	if err := km.validateKeyFormat(keyFormat); err != nil {
+		return nil, fmt.Errorf("aes_cmac_key_manager: invalid key format: %s", err)
+	}
+	keyValue := random.GetRandomBytes(keyFormat.KeySize)
+	return &cmacpb.AesCmacKey{
+		Version:  aesCMACKeyVersion,
+		Params:   keyFormat.Params,
+		KeyValue: keyValue,
+	}, nil
+}
+
+// NewKeyData generates a new KeyData according to specification in the given
+// serialized AesCmacKeyFormat. This should be used solely by the key management API.
+func (km *aesCMACKeyManager) NewKeyData(serializedKeyFormat []byte) (*tinkpb.KeyData, error) {
+	key, err := km.NewKey(serializedKeyFormat)
+	if err != nil {
+		return nil, err
+	}
+	serializedKey, err := proto.Marshal(key)
+	if err != nil {
+		return nil, errInvalidAESCMACKeyFormat
+	}
+
+	return &tinkpb.KeyData{
+		TypeUrl:         aesCMACTypeURL,
+		Value:           serializedKey,
+		KeyMaterialType: tinkpb.KeyData_SYMMETRIC,
+	}, nil
+}
+
+// DoesSupport checks whether this KeyManager supports the given key type.
+func (km *aesCMACKeyManager) DoesSupport(typeURL string) bool {
+	return typeURL == aesCMACTypeURL
+}
+
+// TypeURL returns the type URL of keys managed by this KeyManager.
+func (km *aesCMACKeyManager) TypeURL() string {
+	return aesCMACTypeURL
+}
+
+// validateKey validates the given AesCmacKey.
+func (km *aesCMACKeyManager) validateKey(key *cmacpb.AesCmacKey) error {
+	err := keyset.ValidateKeyVersion(key.Version, aesCMACKeyVersion)
+	if err != nil {
+		return fmt.Errorf("aes_cmac_key_manager: invalid version: %s", err)
+	}
+	if err := km.validateParams(uint32(len(key.KeyValue)), key.Params); err != nil {
+		return fmt.Errorf("aes_cmac_key_manager: %s", err)
+	}
+	return nil
+}
+
+// validateKeyFormat validates the given AesCmacKeyFormat.
+func (km *aesCMACKeyManager) validateKeyFormat(format *cmacpb.AesCmacKeyFormat) error {
+	return km.validateParams(format.KeySize, format.Params)
+}
+
+// validateParams checks the key size and the AES-CMAC params.
+func (km *aesCMACKeyManager) validateParams(keySize uint32, params *cmacpb.AesCmacParams) error {
+	if params == nil {
+		return fmt.Errorf("null AES-CMAC params")
+	}
+	if keySize != aesCMACKeySize {
+		return fmt.Errorf("invalid key size %d, want %d", keySize, aesCMACKeySize)
+	}
+	return mac.ValidateAESCMACParams(keySize, params.TagSize)
+}
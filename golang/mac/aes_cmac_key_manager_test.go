@@ -0,0 +1,209 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package mac_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+
+	"github.com/tsingson/tink/golang/core/registry"
+	"github.com/tsingson/tink/golang/keyset"
+	"github.com/tsingson/tink/golang/mac"
+	subtleMac "github.com/tsingson/tink/golang/subtle/mac"
+	"github.com/tsingson/tink/golang/subtle/random"
+	"github.com/tsingson/tink/golang/testutil"
+	cmacpb "github.com/tsingson/tink/proto/aes_cmac_go_proto"
+	tinkpb "github.com/tsingson/tink/proto/tink_go_proto"
+)
+
+func newAESCMACKey(keySize, tagSize uint32) *cmacpb.AesCmacKey {
+	return &cmacpb.AesCmacKey{
+		Version:  testutil.AESCMACKeyVersion,
+		KeyValue: random.GetRandomBytes(keySize),
+		Params:   &cmacpb.AesCmacParams{TagSize: tagSize},
+	}
+}
+
+func TestAESCMACGetPrimitiveBasic(t *testing.T) {
+	km, err := registry.GetKeyManager(testutil.AESCMACTypeURL)
+	if err != nil {
+		t.Fatalf("cannot obtain AES-CMAC key manager: %s", err)
+	}
+	for _, tagSize := range []uint32{10, 16} {
+		key := newAESCMACKey(32, tagSize)
+		serializedKey, _ := proto.Marshal(key)
+		p, err := km.Primitive(serializedKey)
+		if err != nil {
+			t.Errorf("unexpected error: %s", err)
+			continue
+		}
+		cmac, ok := p.(*subtleMac.AESCMAC)
+		if !ok {
+			t.Errorf("primitive is not AESCMAC")
+			continue
+		}
+		expected, _ := subtleMac.NewAESCMAC(key.KeyValue, tagSize)
+		data := random.GetRandomBytes(40)
+		tag, err := cmac.ComputeMAC(data)
+		if err != nil {
+			t.Errorf("mac computation failed: %s", err)
+		}
+		if err := expected.VerifyMAC(tag, data); err != nil {
+			t.Errorf("primitive does not match the key: %s", err)
+		}
+	}
+}
+
+func TestAESCMACGetPrimitiveWithInvalidInput(t *testing.T) {
+	km, err := registry.GetKeyManager(testutil.AESCMACTypeURL)
+	if err != nil {
+		t.Fatalf("cannot obtain AES-CMAC key manager: %s", err)
+	}
+	badVersion := newAESCMACKey(32, 16)
+	badVersion.Version++
+	noParams := newAESCMACKey(32, 16)
+	noParams.Params = nil
+	invalidKeys := []*cmacpb.AesCmacKey{
+		badVersion,
+		noParams,
+		newAESCMACKey(16, 16),
+		newAESCMACKey(24, 16),
+		newAESCMACKey(32, 9),
+		newAESCMACKey(32, 17),
+	}
+	for i, key := range invalidKeys {
+		serializedKey, _ := proto.Marshal(key)
+		if _, err := km.Primitive(serializedKey); err == nil {
+			t.Errorf("expect an error in test case %d", i)
+		}
+	}
+	if _, err := km.Primitive(nil); err == nil {
+		t.Errorf("expect an error when input is nil")
+	}
+	if _, err := km.Primitive([]byte{}); err == nil {
+		t.Errorf("expect an error when input is empty")
+	}
+}
+
+func TestAESCMACNewKeyMultipleTimes(t *testing.T) {
+	km, err := registry.GetKeyManager(testutil.AESCMACTypeURL)
+	if err != nil {
+		t.Fatalf("cannot obtain AES-CMAC key manager: %s", err)
+	}
+	serializedFormat := mac.AESCMACTag128KeyTemplate().Value
+	keys := make(map[string]bool)
+	nTest := 26
+	for i := 0; i < nTest; i++ {
+		key, _ := km.NewKey(serializedFormat)
+		serializedKey, _ := proto.Marshal(key)
+		keys[string(serializedKey)] = true
+
+		keyData, _ := km.NewKeyData(serializedFormat)
+		keys[string(keyData.Value)] = true
+	}
+	if len(keys) != nTest*2 {
+		t.Errorf("key is repeated")
+	}
+}
+
+func TestAESCMACNewKeyData(t *testing.T) {
+	km, err := registry.GetKeyManager(testutil.AESCMACTypeURL)
+	if err != nil {
+		t.Fatalf("cannot obtain AES-CMAC key manager: %s", err)
+	}
+	keyData, err := km.NewKeyData(mac.AESCMACTag128KeyTemplate().Value)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if keyData.TypeUrl != testutil.AESCMACTypeURL {
+		t.Errorf("incorrect type url")
+	}
+	if keyData.KeyMaterialType != tinkpb.KeyData_SYMMETRIC {
+		t.Errorf("incorrect key material type")
+	}
+	key := new(cmacpb.AesCmacKey)
+	if err := proto.Unmarshal(keyData.Value, key); err != nil {
+		t.Fatalf("incorrect key value")
+	}
+	if len(key.KeyValue) != 32 || key.Params.TagSize != 16 {
+		t.Errorf("key does not match the key format: %s", key)
+	}
+}
+
+func TestAESCMACNewKeyWithInvalidInput(t *testing.T) {
+	km, err := registry.GetKeyManager(testutil.AESCMACTypeURL)
+	if err != nil {
+		t.Fatalf("cannot obtain AES-CMAC key manager: %s", err)
+	}
+	invalidFormats := []*cmacpb.AesCmacKeyFormat{
+		{KeySize: 32},
+		{KeySize: 16, Params: &cmacpb.AesCmacParams{TagSize: 16}},
+		{KeySize: 32, Params: &cmacpb.AesCmacParams{TagSize: 9}},
+		{KeySize: 32, Params: &cmacpb.AesCmacParams{TagSize: 17}},
+	}
+	for i, format := range invalidFormats {
+		serializedFormat, _ := proto.Marshal(format)
+		if _, err := km.NewKey(serializedFormat); err == nil {
+			t.Errorf("expect an error in test case %d", i)
+		}
+	}
+	if _, err := km.NewKey(nil); err == nil {
+		t.Errorf("expect an error when input is nil")
+	}
+}
+
+func TestAESCMACDoesSupport(t *testing.T) {
+	km, err := registry.GetKeyManager(testutil.AESCMACTypeURL)
+	if err != nil {
+		t.Fatalf("cannot obtain AES-CMAC key manager: %s", err)
+	}
+	if !km.DoesSupport(testutil.AESCMACTypeURL) {
+		t.Errorf("AESCMACKeyManager must support %s", testutil.AESCMACTypeURL)
+	}
+	if km.DoesSupport("some bad type") {
+		t.Errorf("AESCMACKeyManager must support only %s", testutil.AESCMACTypeURL)
+	}
+	if km.TypeURL() != testutil.AESCMACTypeURL {
+		t.Errorf("incorrect type url")
+	}
+}
+
+func TestAESCMACFactory(t *testing.T) {
+	kh, err := keyset.NewHandle(mac.AESCMACTag128KeyTemplate())
+	if err != nil {
+		t.Fatalf("cannot create keyset handle: %s", err)
+	}
+	m, err := mac.New(kh)
+	if err != nil {
+		t.Fatalf("mac.New failed: %s", err)
+	}
+	data := random.GetRandomBytes(100)
+	tag, err := m.ComputeMAC(data)
+	if err != nil {
+		t.Fatalf("mac computation failed: %s", err)
+	}
+	// The tag is prefixed with the TINK output prefix.
+	if len(tag) != 5+16 {
+		t.Errorf("unexpected tag length %d", len(tag))
+	}
+	if err := m.VerifyMAC(tag, data); err != nil {
+		t.Errorf("mac verification failed: %s", err)
+	}
+	if err := m.VerifyMAC(tag, bytes.Repeat([]byte{1}, 10)); err == nil {
+		t.Errorf("expect an error with wrong data")
+	}
+}
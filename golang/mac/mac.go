@@ -20,29 +20,31 @@
 // package main
 //
 // import (
-//     "fmt"
 //
-//     "github.com/tsingson/tink/golang/mac"
-//     "github.com/tsingson/tink/golang/keyset"
+//	"fmt"
+//
+//	"github.com/tsingson/tink/golang/mac"
+//	"github.com/tsingson/tink/golang/keyset"
+//
 // )
 //
 // func main() {
 //
-//     kh, err := keyset.NewHandle(mac.HMACSHA256Tag256KeyTemplate())
-//     if err != nil {
-//         // handle the error
-//     }
+//	kh, err := keyset.NewHandle(mac.HMACSHA256Tag256KeyTemplate())
+//	if err != nil {
+//	    // handle the error
+//	}
 //
-//     m := mac.New(kh)
+//	m := mac.New(kh)
 //
-//     mac , err := m.ComputeMac([]byte("this data needs to be MACed"))
-//     if err != nil {
-//         // handle error
-//     }
+//	mac , err := m.ComputeMac([]byte("this data needs to be MACed"))
+//	if err != nil {
+//	    // handle error
+//	}
 //
-//     if m.VerifyMAC(mac, []byte("this data needs to be MACed")); err != nil {
-//         //handle error
-//     }
+//	if m.VerifyMAC(mac, []byte("this data needs to be MACed")); err != nil {
+//	    //handle error
+//	}
 //
 // }
 package mac
@@ -53,8 +55,18 @@ import (
 	"github.com/tsingson/tink/golang/core/registry"
 )
 
+// macPrimitiveName is the name of the primitive in the Tink catalogue.
+const macPrimitiveName = "Mac"
+
 func init() {
-	if err := registry.RegisterKeyManager(newHMACKeyManager()); err != nil {
+	if err := registry.TinkCatalogue().Add(macPrimitiveName, hmacTypeURL, 0, func(*registry.Registry) registry.KeyManager { return newHMACKeyManager() }); err != nil {
+		panic(fmt.Sprintf("mac.init() failed: %v", err))
+	}
+	if err := registry.TinkCatalogue().Add(macPrimitiveName, aesCMACTypeURL, 0, func(*registry.Registry) registry.KeyManager { return newAESCMACKeyManager() }); err != nil {
+		panic(fmt.Sprintf("mac.init() failed: %v", err))
+	}
+
+	if err := registry.RegisterPrimitiveWrapper(new(wrapper)); err != nil {
 		panic(fmt.Sprintf("mac.init() failed: %v", err))
 	}
 }
@@ -21,6 +21,7 @@ import (
 	"github.com/tsingson/tink/golang/core/primitiveset"
 	"github.com/tsingson/tink/golang/core/registry"
 	"github.com/tsingson/tink/golang/keyset"
+	"github.com/tsingson/tink/golang/monitoring"
 	"github.com/tsingson/tink/golang/tink"
 )
 
@@ -35,6 +36,50 @@ func NewWithKeyManager(h *keyset.Handle, km registry.KeyManager) (tink.MAC, erro
 	if err != nil {
 		return nil, fmt.Errorf("mac_factory: cannot obtain primitive set: %s", err)
 	}
+	return wrap(registry.Default(), ps)
+}
+
+// NewWithRegistry returns a MAC primitive from the given keyset handle, using the key
+// managers of the given registry.
+func NewWithRegistry(h *keyset.Handle, r *registry.Registry) (tink.MAC, error) {
+	ps, err := h.PrimitivesWithRegistry(r)
+	if err != nil {
+		return nil, fmt.Errorf("mac_factory: cannot obtain primitive set: %s", err)
+	}
+	return wrap(r, ps)
+}
+
+// wrap combines the primitives in ps into a MAC using the primitive wrapper
+// registered in r.
+func wrap(r *registry.Registry, ps *primitiveset.PrimitiveSet) (tink.MAC, error) {
+	p, err := r.Wrap(macPrimitiveName, ps)
+	if err != nil {
+		return nil, fmt.Errorf("mac_factory: cannot wrap primitive set: %s", err)
+	}
+	ret, ok := p.(tink.MAC)
+	if !ok {
+		return nil, fmt.Errorf("mac_factory: not a MAC primitive")
+	}
+	return ret, nil
+}
+
+// wrapper is the registry.PrimitiveWrapper for the MAC primitive.
+type wrapper struct{}
+
+// Asserts that wrapper implements the PrimitiveWrapper interface.
+var _ registry.PrimitiveWrapper = (*wrapper)(nil)
+
+// PrimitiveName returns the name of the MAC primitive.
+func (w *wrapper) PrimitiveName() string {
+	return macPrimitiveName
+}
+
+// Wrap returns a MAC that uses the primitives in the given primitive set, which must
+// all be MAC primitives.
+func (w *wrapper) Wrap(ps *primitiveset.PrimitiveSet) (interface{}, error) {
+	if !ps.All(func(p interface{}) bool { _, ok := p.(tink.MAC); return ok }) {
+		return nil, fmt.Errorf("mac_factory: not a MAC primitive")
+	}
 	return newPrimitiveSet(ps), nil
 }
 
@@ -59,13 +104,11 @@ func (m *primitiveSet) ComputeMAC(data []byte) ([]byte, error) {
 	primary := m.ps.Primary
 	var primitive = (primary.Primitive).(tink.MAC)
 	mac, err := primitive.ComputeMAC(data)
+	m.ps.Log(primary, macPrimitiveName, monitoring.ComputeMAC, len(data), err == nil)
 	if err != nil {
 		return nil, err
 	}
-	var ret []byte
-	ret = append(ret, primary.Prefix...)
-	ret = append(ret, mac...)
-	return ret, nil
+	return primary.Prefixed(mac), nil
 }
 
 var errInvalidMAC = fmt.Errorf("mac_factory: invalid mac")
@@ -77,30 +120,17 @@ func (m *primitiveSet) VerifyMAC(mac, data []byte) error {
 	// clearly insecure, thus should be discouraged.
 	prefixSize := cryptofmt.NonRawPrefixSize
 	if len(mac) <= prefixSize {
+		m.ps.LogFailure(mac, macPrimitiveName, monitoring.VerifyMAC, len(data))
 		return errInvalidMAC
 	}
-	// try non raw keys
-	prefix := mac[:prefixSize]
-	macNoPrefix := mac[prefixSize:]
-	entries, err := m.ps.EntriesForPrefix(string(prefix))
-	if err == nil {
-		for i := 0; i < len(entries); i++ {
-			var p = (entries[i].Primitive).(tink.MAC)
-			if err = p.VerifyMAC(macNoPrefix, data); err == nil {
-				return nil
-			}
-		}
-	}
-	// try raw keys
-	entries, err = m.ps.RawEntries()
-	if err == nil {
-		for i := 0; i < len(entries); i++ {
-			var p = (entries[i].Primitive).(tink.MAC)
-			if err = p.VerifyMAC(mac, data); err == nil {
-				return nil
-			}
+	for _, c := range m.ps.Candidates(mac) {
+		var p = (c.Entry.Primitive).(tink.MAC)
+		if err := p.VerifyMAC(c.Output, data); err == nil {
+			m.ps.Log(c.Entry, macPrimitiveName, monitoring.VerifyMAC, len(data), true)
+			return nil
 		}
 	}
 	// nothing worked
+	m.ps.LogFailure(mac, macPrimitiveName, monitoring.VerifyMAC, len(data))
 	return errInvalidMAC
 }
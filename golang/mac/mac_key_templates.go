@@ -17,6 +17,7 @@ package mac
 import (
 	"github.com/golang/protobuf/proto"
 
+	cmacpb "github.com/tsingson/tink/proto/aes_cmac_go_proto"
 	commonpb "github.com/tsingson/tink/proto/common_go_proto"
 	hmacpb "github.com/tsingson/tink/proto/hmac_go_proto"
 	tinkpb "github.com/tsingson/tink/proto/tink_go_proto"
@@ -56,6 +57,13 @@ func HMACSHA512Tag512KeyTemplate() *tinkpb.KeyTemplate {
 	return createHMACKeyTemplate(64, 64, commonpb.HashType_SHA512)
 }
 
+// AESCMACTag128KeyTemplate is a KeyTemplate that generates a AES-CMAC key with the following parameters:
+//   - Key size: 32 bytes
+//   - Tag size: 16 bytes
+func AESCMACTag128KeyTemplate() *tinkpb.KeyTemplate {
+	return createAESCMACKeyTemplate(32, 16)
+}
+
 // createHMACKeyTemplate creates a new KeyTemplate for HMAC using the given parameters.
 func createHMACKeyTemplate(keySize uint32,
 	tagSize uint32,
@@ -74,3 +82,16 @@ func createHMACKeyTemplate(keySize uint32,
 		Value:   serializedFormat,
 	}
 }
+
+// createAESCMACKeyTemplate creates a new KeyTemplate for AES-CMAC using the given parameters.
+func createAESCMACKeyTemplate(keySize uint32, tagSize uint32) *tinkpb.KeyTemplate {
+	format := cmacpb.AesCmacKeyFormat{
+		Params:  &cmacpb.AesCmacParams{TagSize: tagSize},
+		KeySize: keySize,
+	}
+	serializedFormat, _ := proto.Marshal(&format)
+	return &tinkpb.KeyTemplate{
+		TypeUrl: aesCMACTypeURL,
+		Value:   serializedFormat,
+	}
+}
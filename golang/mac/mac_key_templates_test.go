@@ -22,6 +22,7 @@ import (
 
 	"github.com/tsingson/tink/golang/mac"
 	"github.com/tsingson/tink/golang/testutil"
+	cmacpb "github.com/tsingson/tink/proto/aes_cmac_go_proto"
 	commonpb "github.com/tsingson/tink/proto/common_go_proto"
 	hmacpb "github.com/tsingson/tink/proto/hmac_go_proto"
 	tinkpb "github.com/tsingson/tink/proto/tink_go_proto"
@@ -44,6 +45,10 @@ func TestTemplates(t *testing.T) {
 	if err := checkTemplate(template, 64, 64, commonpb.HashType_SHA512); err != nil {
 		t.Errorf("incorrect HMACSHA512Tag512KeyTemplate: %s", err)
 	}
+	template = mac.AESCMACTag128KeyTemplate()
+	if err := checkAESCMACTemplate(template, 32, 16); err != nil {
+		t.Errorf("incorrect AESCMACTag128KeyTemplate: %s", err)
+	}
 }
 
 func checkTemplate(template *tinkpb.KeyTemplate,
@@ -64,3 +69,17 @@ func checkTemplate(template *tinkpb.KeyTemplate,
 	}
 	return nil
 }
+
+func checkAESCMACTemplate(template *tinkpb.KeyTemplate, keySize uint32, tagSize uint32) error {
+	if template.TypeUrl != testutil.AESCMACTypeURL {
+		return fmt.Errorf("TypeUrl is incorrect")
+	}
+	format := new(cmacpb.AesCmacKeyFormat)
+	if err := proto.Unmarshal(template.Value, format); err != nil {
+		return fmt.Errorf("unable to unmarshal serialized key format")
+	}
+	if format.KeySize != keySize || format.Params.TagSize != tagSize {
+		return fmt.Errorf("KeyFormat is incorrect")
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package monitoring
+
+import "sync"
+
+// Counter is a Monitor that counts operations and processed bytes in memory.
+// It is safe for concurrent use.
+type Counter struct {
+	mu     sync.Mutex
+	counts map[counterKey]*counterValue
+}
+
+type counterKey struct {
+	keyID     uint32
+	primitive string
+	op        string
+	success   bool
+}
+
+type counterValue struct {
+	count    int
+	numBytes int
+}
+
+// Asserts that Counter implements the Monitor interface.
+var _ Monitor = (*Counter)(nil)
+
+// NewCounter returns an empty Counter.
+func NewCounter() *Counter {
+	return &Counter{counts: make(map[counterKey]*counterValue)}
+}
+
+// Log records the given operation.
+func (c *Counter) Log(keyID uint32, primitive, op string, numBytes int, success bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	k := counterKey{keyID: keyID, primitive: primitive, op: op, success: success}
+	v, ok := c.counts[k]
+	if !ok {
+		v = new(counterValue)
+		c.counts[k] = v
+	}
+	v.count++
+	v.numBytes += numBytes
+}
+
+// Count returns how many operations op of the given primitive were performed with the
+// given key and had the given outcome.
+func (c *Counter) Count(keyID uint32, primitive, op string, success bool) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if v, ok := c.counts[counterKey{keyID: keyID, primitive: primitive, op: op, success: success}]; ok {
+		return v.count
+	}
+	return 0
+}
+
+// Bytes returns the number of bytes processed by the operations counted by Count.
+func (c *Counter) Bytes(keyID uint32, primitive, op string, success bool) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if v, ok := c.counts[counterKey{keyID: keyID, primitive: primitive, op: op, success: success}]; ok {
+		return v.numBytes
+	}
+	return 0
+}
+
+// KeyUsed returns whether any operation was performed with the given key.
+func (c *Counter) KeyUsed(keyID uint32) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for k := range c.counts {
+		if k.keyID == keyID {
+			return true
+		}
+	}
+	return false
+}
+
+// Reset discards all recorded operations.
+func (c *Counter) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.counts = make(map[counterKey]*counterValue)
+}
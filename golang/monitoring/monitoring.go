@@ -0,0 +1,71 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+// Package monitoring provides hooks to observe how the keys of a keyset are used.
+//
+// The keyset-level primitives of the aead, daead, mac, signature and hybrid packages
+// report every operation to a Monitor, together with the ID of the key that performed
+// it. This can for example be used to find out whether a rotated-out key is still
+// used before destroying it.
+//
+// A Monitor can be set for all keyset handles with SetGlobal, or for a single handle
+// with keyset.Handle.WithMonitor. Counter is an in-memory Monitor.
+package monitoring
+
+import "sync"
+
+// Operations reported by the keyset-level primitives.
+const (
+	Encrypt    = "encrypt"
+	Decrypt    = "decrypt"
+	ComputeMAC = "compute_mac"
+	VerifyMAC  = "verify_mac"
+	Sign       = "sign"
+	Verify     = "verify"
+)
+
+// Monitor is notified of the operations performed by keyset-level primitives.
+type Monitor interface {
+	// Log is called after each operation. primitive is the name of the primitive,
+	// e.g. "Aead", and op is one of the operation constants of this package.
+	//
+	// numBytes is the size of the plaintext for both encryption and decryption, so that
+	// the volumes encrypted and decrypted with a key can be compared, and the size of the
+	// data for MACs and signatures. A failed decryption reports 0 bytes, since it yields
+	// no plaintext.
+	//
+	// For a failed operation that consumes output, e.g. a decryption, keyID is the ID
+	// of the first key identified by the prefix of the output, or 0 if no key is.
+	Log(keyID uint32, primitive, op string, numBytes int, success bool)
+}
+
+var (
+	globalMu sync.RWMutex
+	global   Monitor
+)
+
+// SetGlobal sets the Monitor used by keyset-level primitives whose keyset handle has
+// no Monitor of its own. A nil Monitor disables global monitoring.
+func SetGlobal(m Monitor) {
+	globalMu.Lock()
+	defer globalMu.Unlock()
+	global = m
+}
+
+// Global returns the Monitor set with SetGlobal, or nil if there is none.
+func Global() Monitor {
+	globalMu.RLock()
+	defer globalMu.RUnlock()
+	return global
+}
@@ -0,0 +1,212 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package monitoring_test
+
+import (
+	"testing"
+
+	"github.com/tsingson/tink/golang/aead"
+	"github.com/tsingson/tink/golang/keyset"
+	"github.com/tsingson/tink/golang/mac"
+	"github.com/tsingson/tink/golang/monitoring"
+	"github.com/tsingson/tink/golang/signature"
+	"github.com/tsingson/tink/golang/testkeyset"
+)
+
+func primaryKeyID(t *testing.T, h *keyset.Handle) uint32 {
+	t.Helper()
+	mem := &keyset.MemReaderWriter{}
+	if err := testkeyset.Write(h, mem); err != nil {
+		t.Fatalf("testkeyset.Write() err = %v, want nil", err)
+	}
+	return mem.Keyset.PrimaryKeyId
+}
+
+func TestCounter(t *testing.T) {
+	c := monitoring.NewCounter()
+	c.Log(1, "Aead", monitoring.Encrypt, 10, true)
+	c.Log(1, "Aead", monitoring.Encrypt, 5, true)
+	c.Log(1, "Aead", monitoring.Decrypt, 7, false)
+	if got := c.Count(1, "Aead", monitoring.Encrypt, true); got != 2 {
+		t.Errorf("c.Count() = %d, want 2", got)
+	}
+	if got := c.Bytes(1, "Aead", monitoring.Encrypt, true); got != 15 {
+		t.Errorf("c.Bytes() = %d, want 15", got)
+	}
+	if got := c.Count(1, "Aead", monitoring.Decrypt, true); got != 0 {
+		t.Errorf("c.Count() = %d, want 0", got)
+	}
+	if got := c.Count(1, "Aead", monitoring.Decrypt, false); got != 1 {
+		t.Errorf("c.Count() = %d, want 1", got)
+	}
+	if !c.KeyUsed(1) || c.KeyUsed(2) {
+		t.Errorf("c.KeyUsed() = %t, %t, want true, false", c.KeyUsed(1), c.KeyUsed(2))
+	}
+	c.Reset()
+	if c.KeyUsed(1) {
+		t.Errorf("c.KeyUsed() = true after Reset, want false")
+	}
+}
+
+func TestHandleMonitor(t *testing.T) {
+	m := keyset.NewManager()
+	oldID, err := m.Add(aead.AES128GCMKeyTemplate())
+	if err != nil {
+		t.Fatalf("m.Add() err = %v, want nil", err)
+	}
+	if err := m.SetPrimary(oldID); err != nil {
+		t.Fatalf("m.SetPrimary() err = %v, want nil", err)
+	}
+	oldHandle, err := m.Handle()
+	if err != nil {
+		t.Fatalf("m.Handle() err = %v, want nil", err)
+	}
+	oldAEAD, err := aead.New(oldHandle)
+	if err != nil {
+		t.Fatalf("aead.New() err = %v, want nil", err)
+	}
+	oldCT, err := oldAEAD.Encrypt([]byte("old"), nil)
+	if err != nil {
+		t.Fatalf("oldAEAD.Encrypt() err = %v, want nil", err)
+	}
+
+	if err := m.Rotate(aead.AES128GCMKeyTemplate()); err != nil {
+		t.Fatalf("m.Rotate() err = %v, want nil", err)
+	}
+	h, err := m.Handle()
+	if err != nil {
+		t.Fatalf("m.Handle() err = %v, want nil", err)
+	}
+	newID := primaryKeyID(t, h)
+
+	c := monitoring.NewCounter()
+	a, err := aead.New(h.WithMonitor(c))
+	if err != nil {
+		t.Fatalf("aead.New() err = %v, want nil", err)
+	}
+	pt := []byte("plaintext")
+	ct, err := a.Encrypt(pt, nil)
+	if err != nil {
+		t.Fatalf("a.Encrypt() err = %v, want nil", err)
+	}
+	if _, err := a.Decrypt(ct, nil); err != nil {
+		t.Fatalf("a.Decrypt() err = %v, want nil", err)
+	}
+	if _, err := a.Decrypt(oldCT, nil); err != nil {
+		t.Fatalf("a.Decrypt() err = %v, want nil", err)
+	}
+	if _, err := a.Decrypt(oldCT, []byte("wrong associated data")); err == nil {
+		t.Fatalf("a.Decrypt() err = nil, want error")
+	}
+
+	if got := c.Count(newID, "Aead", monitoring.Encrypt, true); got != 1 {
+		t.Errorf("encryptions with the primary key = %d, want 1", got)
+	}
+	if got := c.Bytes(newID, "Aead", monitoring.Encrypt, true); got != len(pt) {
+		t.Errorf("encrypted bytes with the primary key = %d, want %d", got, len(pt))
+	}
+	if got := c.Count(newID, "Aead", monitoring.Decrypt, true); got != 1 {
+		t.Errorf("decryptions with the primary key = %d, want 1", got)
+	}
+	if got := c.Bytes(newID, "Aead", monitoring.Decrypt, true); got != len(pt) {
+		t.Errorf("decrypted bytes with the primary key = %d, want %d", got, len(pt))
+	}
+	if got := c.Count(oldID, "Aead", monitoring.Decrypt, true); got != 1 {
+		t.Errorf("decryptions with the old key = %d, want 1", got)
+	}
+	if got := c.Count(oldID, "Aead", monitoring.Decrypt, false); got != 1 {
+		t.Errorf("failed decryptions with the old key = %d, want 1", got)
+	}
+	if got := c.Bytes(oldID, "Aead", monitoring.Decrypt, false); got != 0 {
+		t.Errorf("bytes of failed decryptions with the old key = %d, want 0", got)
+	}
+	if got := c.Count(0, "Aead", monitoring.Decrypt, false); got != 0 {
+		t.Errorf("failed decryptions with no key = %d, want 0", got)
+	}
+	if _, err := a.Decrypt([]byte("garbage"), nil); err == nil {
+		t.Fatalf("a.Decrypt() err = nil, want error")
+	}
+	if got := c.Count(0, "Aead", monitoring.Decrypt, false); got != 1 {
+		t.Errorf("failed decryptions with no key = %d, want 1", got)
+	}
+}
+
+func TestGlobalMonitor(t *testing.T) {
+	c := monitoring.NewCounter()
+	monitoring.SetGlobal(c)
+	defer monitoring.SetGlobal(nil)
+
+	macHandle, err := keyset.NewHandle(mac.HMACSHA256Tag256KeyTemplate())
+	if err != nil {
+		t.Fatalf("keyset.NewHandle() err = %v, want nil", err)
+	}
+	macID := primaryKeyID(t, macHandle)
+	p, err := mac.New(macHandle)
+	if err != nil {
+		t.Fatalf("mac.New() err = %v, want nil", err)
+	}
+	data := []byte("data")
+	tag, err := p.ComputeMAC(data)
+	if err != nil {
+		t.Fatalf("p.ComputeMAC() err = %v, want nil", err)
+	}
+	if err := p.VerifyMAC(tag, data); err != nil {
+		t.Fatalf("p.VerifyMAC() err = %v, want nil", err)
+	}
+	if c.Count(macID, "Mac", monitoring.ComputeMAC, true) != 1 || c.Count(macID, "Mac", monitoring.VerifyMAC, true) != 1 {
+		t.Errorf("MAC operations were not counted")
+	}
+
+	privHandle, err := keyset.NewHandle(signature.ECDSAP256KeyTemplate())
+	if err != nil {
+		t.Fatalf("keyset.NewHandle() err = %v, want nil", err)
+	}
+	sigID := primaryKeyID(t, privHandle)
+	pubHandle, err := privHandle.Public()
+	if err != nil {
+		t.Fatalf("privHandle.Public() err = %v, want nil", err)
+	}
+	signer, err := signature.NewSigner(privHandle)
+	if err != nil {
+		t.Fatalf("signature.NewSigner() err = %v, want nil", err)
+	}
+	verifier, err := signature.NewVerifier(pubHandle)
+	if err != nil {
+		t.Fatalf("signature.NewVerifier() err = %v, want nil", err)
+	}
+	sig, err := signer.Sign(data)
+	if err != nil {
+		t.Fatalf("signer.Sign() err = %v, want nil", err)
+	}
+	if err := verifier.Verify(sig, []byte("other data")); err == nil {
+		t.Fatalf("verifier.Verify() err = nil, want error")
+	}
+	if c.Count(sigID, "PublicKeySign", monitoring.Sign, true) != 1 || c.Count(sigID, "PublicKeyVerify", monitoring.Verify, false) != 1 {
+		t.Errorf("signature operations were not counted")
+	}
+
+	// A handle with its own monitor does not report to the global one.
+	own := monitoring.NewCounter()
+	p, err = mac.New(macHandle.WithMonitor(own))
+	if err != nil {
+		t.Fatalf("mac.New() err = %v, want nil", err)
+	}
+	if _, err := p.ComputeMAC(data); err != nil {
+		t.Fatalf("p.ComputeMAC() err = %v, want nil", err)
+	}
+	if c.Count(macID, "Mac", monitoring.ComputeMAC, true) != 1 || own.Count(macID, "Mac", monitoring.ComputeMAC, true) != 1 {
+		t.Errorf("MAC operation was reported to the wrong monitor")
+	}
+}
@@ -0,0 +1,376 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+// Package pemkeyset converts asymmetric keysets from and to PEM encoded keys.
+//
+// Public keys are exchanged as PKIX SubjectPublicKeyInfo structures in "PUBLIC KEY" blocks,
+// private keys as PKCS#8 structures in "PRIVATE KEY" blocks. ECDSA, Ed25519 and ECIES-AEAD-HKDF
+// keys are supported. Since a PEM key carries no Tink parameters, imports take them from
+// ImportOptions.
+package pemkeyset
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/golang/protobuf/proto"
+	"golang.org/x/crypto/ed25519"
+
+	_ "github.com/tsingson/tink/golang/hybrid" // register the ECIES-AEAD-HKDF key managers
+	"github.com/tsingson/tink/golang/insecurecleartextkeyset"
+	"github.com/tsingson/tink/golang/internal/keysetconv"
+	"github.com/tsingson/tink/golang/keyset"
+	_ "github.com/tsingson/tink/golang/signature" // register the ECDSA and Ed25519 key managers
+	"github.com/tsingson/tink/golang/subtle/random"
+	commonpb "github.com/tsingson/tink/proto/common_go_proto"
+	ecdsapb "github.com/tsingson/tink/proto/ecdsa_go_proto"
+	eciespb "github.com/tsingson/tink/proto/ecies_aead_hkdf_go_proto"
+	ed25519pb "github.com/tsingson/tink/proto/ed25519_go_proto"
+	tinkpb "github.com/tsingson/tink/proto/tink_go_proto"
+)
+
+const (
+	ecdsaPublicKeyTypeURL    = "type.googleapis.com/google.crypto.tink.EcdsaPublicKey"
+	ecdsaPrivateKeyTypeURL   = "type.googleapis.com/google.crypto.tink.EcdsaPrivateKey"
+	ed25519PublicKeyTypeURL  = "type.googleapis.com/google.crypto.tink.Ed25519PublicKey"
+	ed25519PrivateKeyTypeURL = "type.googleapis.com/google.crypto.tink.Ed25519PrivateKey"
+	eciesPublicKeyTypeURL    = "type.googleapis.com/google.crypto.tink.EciesAeadHkdfPublicKey"
+	eciesPrivateKeyTypeURL   = "type.googleapis.com/google.crypto.tink.EciesAeadHkdfPrivateKey"
+
+	publicKeyBlockType  = "PUBLIC KEY"
+	privateKeyBlockType = "PRIVATE KEY"
+
+	ed25519KeySize = 32
+)
+
+var (
+	// ed25519SPKIPrefix is the DER encoding of a SubjectPublicKeyInfo holding an Ed25519 key
+	// (RFC 8410), up to the 32 byte key itself.
+	ed25519SPKIPrefix = []byte{0x30, 0x2a, 0x30, 0x05, 0x06, 0x03, 0x2b, 0x65, 0x70, 0x03, 0x21, 0x00}
+	// ed25519PKCS8Prefix is the DER encoding of a PKCS#8 PrivateKeyInfo holding an Ed25519 key
+	// (RFC 8410), up to the 32 byte seed itself.
+	ed25519PKCS8Prefix = []byte{0x30, 0x2e, 0x02, 0x01, 0x00, 0x30, 0x05, 0x06, 0x03, 0x2b, 0x65, 0x70, 0x04, 0x22, 0x04, 0x20}
+
+	errNoKeys = errors.New("pemkeyset: no keys found")
+)
+
+// ImportOptions holds the Tink parameters of the keys created by an import.
+type ImportOptions struct {
+	// ECDSAHashType is the hash function of imported ECDSA keys. If unset, SHA256 is used for
+	// NIST P-256 keys and SHA512 for NIST P-384 and P-521 keys.
+	ECDSAHashType commonpb.HashType
+	// ECDSAEncoding is the signature encoding of imported ECDSA keys. If unset, DER is used.
+	ECDSAEncoding ecdsapb.EcdsaSignatureEncoding
+	// ECIESParams, if set, makes EC keys import as ECIES-AEAD-HKDF keys with these parameters
+	// instead of ECDSA keys. An unset curve is taken from the key.
+	ECIESParams *eciespb.EciesAeadHkdfParams
+	// OutputPrefixType is the output prefix type of the imported keys. If unset, RAW is used,
+	// so that signatures and ciphertexts interoperate with other libraries.
+	OutputPrefixType tinkpb.OutputPrefixType
+}
+
+// ExportPublicKeys encodes the enabled keys of h as a sequence of "PUBLIC KEY" PEM blocks,
+// the primary key first. h must not contain secret key material; use keyset.Handle.Public()
+// to obtain the public keyset of a private keyset.
+func ExportPublicKeys(h *keyset.Handle) ([]byte, error) {
+	if h == nil {
+		return nil, errors.New("pemkeyset: nil keyset handle")
+	}
+	mem := &keyset.MemReaderWriter{}
+	if err := h.WriteWithNoSecrets(mem); err != nil {
+		return nil, fmt.Errorf("pemkeyset: %s", err)
+	}
+	ks := mem.Keyset
+	var buf bytes.Buffer
+	for _, key := range keysetconv.EnabledKeys(ks) {
+		der, err := marshalPublicKey(key.KeyData)
+		if err != nil {
+			return nil, fmt.Errorf("pemkeyset: key %d: %s", key.KeyId, err)
+		}
+		if err := pem.Encode(&buf, &pem.Block{Type: publicKeyBlockType, Bytes: der}); err != nil {
+			return nil, fmt.Errorf("pemkeyset: %s", err)
+		}
+	}
+	if buf.Len() == 0 {
+		return nil, errNoKeys
+	}
+	return buf.Bytes(), nil
+}
+
+// ImportPublicKeys creates a keyset.Handle holding the keys of the "PUBLIC KEY" PEM blocks in
+// pemBytes. The first key becomes the primary key. Blocks of other types are ignored.
+func ImportPublicKeys(pemBytes []byte, opts ImportOptions) (*keyset.Handle, error) {
+	var keyData []*tinkpb.KeyData
+	for _, der := range pemBlocks(pemBytes, publicKeyBlockType) {
+		kd, err := publicKeyData(der, &opts)
+		if err != nil {
+			return nil, fmt.Errorf("pemkeyset: key %d: %s", len(keyData), err)
+		}
+		keyData = append(keyData, kd)
+	}
+	ks, err := newKeyset(keyData, &opts)
+	if err != nil {
+		return nil, err
+	}
+	return keyset.NewHandleWithNoSecrets(ks)
+}
+
+// InsecureImportPKCS8PrivateKeys creates a keyset.Handle holding the keys of the unencrypted
+// "PRIVATE KEY" PEM blocks in pemBytes. The first key becomes the primary key. Blocks of other
+// types are ignored.
+//
+// Handling cleartext secret key material is dangerous; this is meant for migrating existing
+// keys into Tink, after which the keyset should be stored encrypted.
+func InsecureImportPKCS8PrivateKeys(pemBytes []byte, opts ImportOptions) (*keyset.Handle, error) {
+	var keyData []*tinkpb.KeyData
+	for _, der := range pemBlocks(pemBytes, privateKeyBlockType) {
+		kd, err := privateKeyData(der, &opts)
+		if err != nil {
+			return nil, fmt.Errorf("pemkeyset: key %d: %s", len(keyData), err)
+		}
+		keyData = append(keyData, kd)
+	}
+	ks, err := newKeyset(keyData, &opts)
+	if err != nil {
+		return nil, err
+	}
+	return insecurecleartextkeyset.KeysetHandle(ks), nil
+}
+
+// pemBlocks returns the contents of the PEM blocks of the given type in pemBytes.
+func pemBlocks(pemBytes []byte, blockType string) [][]byte {
+	var ders [][]byte
+	for {
+		var b *pem.Block
+		b, pemBytes = pem.Decode(pemBytes)
+		if b == nil {
+			return ders
+		}
+		if b.Type == blockType {
+			ders = append(ders, b.Bytes)
+		}
+	}
+}
+
+// newKeyset creates a keyset of ENABLED keys with random, distinct IDs from keyData.
+func newKeyset(keyData []*tinkpb.KeyData, opts *ImportOptions) (*tinkpb.Keyset, error) {
+	if len(keyData) == 0 {
+		return nil, errNoKeys
+	}
+	prefixType := opts.OutputPrefixType
+	if prefixType == tinkpb.OutputPrefixType_UNKNOWN_PREFIX {
+		prefixType = tinkpb.OutputPrefixType_RAW
+	}
+	ks := &tinkpb.Keyset{}
+	used := make(map[uint32]bool)
+	for _, kd := range keyData {
+		id := random.GetRandomUint32()
+		for used[id] {
+			id = random.GetRandomUint32()
+		}
+		used[id] = true
+		ks.Key = append(ks.Key, &tinkpb.Keyset_Key{
+			KeyData:          kd,
+			Status:           tinkpb.KeyStatusType_ENABLED,
+			KeyId:            id,
+			OutputPrefixType: prefixType,
+		})
+	}
+	ks.PrimaryKeyId = ks.Key[0].KeyId
+	return ks, nil
+}
+
+func publicKeyData(der []byte, opts *ImportOptions) (*tinkpb.KeyData, error) {
+	if len(der) == len(ed25519SPKIPrefix)+ed25519KeySize && bytes.HasPrefix(der, ed25519SPKIPrefix) {
+		key := &ed25519pb.Ed25519PublicKey{KeyValue: der[len(ed25519SPKIPrefix):]}
+		return keysetconv.NewKeyData(ed25519PublicKeyTypeURL, key, tinkpb.KeyData_ASYMMETRIC_PUBLIC)
+	}
+	pub, err := x509.ParsePKIXPublicKey(der)
+	if err != nil {
+		return nil, err
+	}
+	ecPub, ok := pub.(*ecdsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("unsupported public key type %T", pub)
+	}
+	if opts.ECIESParams != nil {
+		key, err := eciesPublicKey(ecPub, opts.ECIESParams)
+		if err != nil {
+			return nil, err
+		}
+		return keysetconv.NewKeyData(eciesPublicKeyTypeURL, key, tinkpb.KeyData_ASYMMETRIC_PUBLIC)
+	}
+	key, err := ecdsaPublicKey(ecPub, opts)
+	if err != nil {
+		return nil, err
+	}
+	return keysetconv.NewKeyData(ecdsaPublicKeyTypeURL, key, tinkpb.KeyData_ASYMMETRIC_PUBLIC)
+}
+
+func privateKeyData(der []byte, opts *ImportOptions) (*tinkpb.KeyData, error) {
+	if len(der) == len(ed25519PKCS8Prefix)+ed25519KeySize && bytes.HasPrefix(der, ed25519PKCS8Prefix) {
+		seed := der[len(ed25519PKCS8Prefix):]
+		pub := ed25519.NewKeyFromSeed(seed).Public().(ed25519.PublicKey)
+		key := &ed25519pb.Ed25519PrivateKey{
+			PublicKey: &ed25519pb.Ed25519PublicKey{KeyValue: pub},
+			KeyValue:  seed,
+		}
+		return keysetconv.NewKeyData(ed25519PrivateKeyTypeURL, key, tinkpb.KeyData_ASYMMETRIC_PRIVATE)
+	}
+	priv, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		return nil, err
+	}
+	ecPriv, ok := priv.(*ecdsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("unsupported private key type %T", priv)
+	}
+	if opts.ECIESParams != nil {
+		pub, err := eciesPublicKey(&ecPriv.PublicKey, opts.ECIESParams)
+		if err != nil {
+			return nil, err
+		}
+		key := &eciespb.EciesAeadHkdfPrivateKey{PublicKey: pub, KeyValue: ecPriv.D.Bytes()}
+		return keysetconv.NewKeyData(eciesPrivateKeyTypeURL, key, tinkpb.KeyData_ASYMMETRIC_PRIVATE)
+	}
+	pub, err := ecdsaPublicKey(&ecPriv.PublicKey, opts)
+	if err != nil {
+		return nil, err
+	}
+	key := &ecdsapb.EcdsaPrivateKey{PublicKey: pub, KeyValue: ecPriv.D.Bytes()}
+	return keysetconv.NewKeyData(ecdsaPrivateKeyTypeURL, key, tinkpb.KeyData_ASYMMETRIC_PRIVATE)
+}
+
+func ecdsaPublicKey(pub *ecdsa.PublicKey, opts *ImportOptions) (*ecdsapb.EcdsaPublicKey, error) {
+	curve, err := curveType(pub.Curve)
+	if err != nil {
+		return nil, err
+	}
+	hashType := opts.ECDSAHashType
+	if hashType == commonpb.HashType_UNKNOWN_HASH {
+		hashType = commonpb.HashType_SHA512
+		if curve == commonpb.EllipticCurveType_NIST_P256 {
+			hashType = commonpb.HashType_SHA256
+		}
+	}
+	encoding := opts.ECDSAEncoding
+	if encoding == ecdsapb.EcdsaSignatureEncoding_UNKNOWN_ENCODING {
+		encoding = ecdsapb.EcdsaSignatureEncoding_DER
+	}
+	return &ecdsapb.EcdsaPublicKey{
+		Params: &ecdsapb.EcdsaParams{
+			HashType: hashType,
+			Curve:    curve,
+			Encoding: encoding,
+		},
+		X: pub.X.Bytes(),
+		Y: pub.Y.Bytes(),
+	}, nil
+}
+
+func eciesPublicKey(pub *ecdsa.PublicKey, params *eciespb.EciesAeadHkdfParams) (*eciespb.EciesAeadHkdfPublicKey, error) {
+	curve, err := curveType(pub.Curve)
+	if err != nil {
+		return nil, err
+	}
+	params = proto.Clone(params).(*eciespb.EciesAeadHkdfParams)
+	if params.KemParams == nil {
+		params.KemParams = &eciespb.EciesHkdfKemParams{}
+	}
+	switch params.KemParams.CurveType {
+	case commonpb.EllipticCurveType_UNKNOWN_CURVE:
+		params.KemParams.CurveType = curve
+	case curve:
+	default:
+		return nil, fmt.Errorf("key curve %s does not match ECIES curve %s", curve, params.KemParams.CurveType)
+	}
+	return &eciespb.EciesAeadHkdfPublicKey{
+		Params: params,
+		X:      pub.X.Bytes(),
+		Y:      pub.Y.Bytes(),
+	}, nil
+}
+
+func marshalPublicKey(kd *tinkpb.KeyData) ([]byte, error) {
+	switch kd.TypeUrl {
+	case ed25519PublicKeyTypeURL:
+		key := new(ed25519pb.Ed25519PublicKey)
+		if err := proto.Unmarshal(kd.Value, key); err != nil {
+			return nil, err
+		}
+		if len(key.KeyValue) != ed25519KeySize {
+			return nil, errors.New("invalid ed25519 public key")
+		}
+		return append(append([]byte{}, ed25519SPKIPrefix...), key.KeyValue...), nil
+	case ecdsaPublicKeyTypeURL:
+		key := new(ecdsapb.EcdsaPublicKey)
+		if err := proto.Unmarshal(kd.Value, key); err != nil {
+			return nil, err
+		}
+		if key.Params == nil {
+			return nil, errors.New("invalid ecdsa public key")
+		}
+		return marshalECPublicKey(key.Params.Curve, key.X, key.Y)
+	case eciesPublicKeyTypeURL:
+		key := new(eciespb.EciesAeadHkdfPublicKey)
+		if err := proto.Unmarshal(kd.Value, key); err != nil {
+			return nil, err
+		}
+		if key.Params == nil || key.Params.KemParams == nil {
+			return nil, errors.New("invalid ecies public key")
+		}
+		return marshalECPublicKey(key.Params.KemParams.CurveType, key.X, key.Y)
+	default:
+		return nil, fmt.Errorf("unsupported key type %s", kd.TypeUrl)
+	}
+}
+
+func marshalECPublicKey(curve commonpb.EllipticCurveType, x, y []byte) ([]byte, error) {
+	var c elliptic.Curve
+	switch curve {
+	case commonpb.EllipticCurveType_NIST_P256:
+		c = elliptic.P256()
+	case commonpb.EllipticCurveType_NIST_P384:
+		c = elliptic.P384()
+	case commonpb.EllipticCurveType_NIST_P521:
+		c = elliptic.P521()
+	default:
+		return nil, fmt.Errorf("unsupported curve %s", curve)
+	}
+	pub := &ecdsa.PublicKey{
+		Curve: c,
+		X:     new(big.Int).SetBytes(x),
+		Y:     new(big.Int).SetBytes(y),
+	}
+	return x509.MarshalPKIXPublicKey(pub)
+}
+
+func curveType(c elliptic.Curve) (commonpb.EllipticCurveType, error) {
+	switch c {
+	case elliptic.P256():
+		return commonpb.EllipticCurveType_NIST_P256, nil
+	case elliptic.P384():
+		return commonpb.EllipticCurveType_NIST_P384, nil
+	case elliptic.P521():
+		return commonpb.EllipticCurveType_NIST_P521, nil
+	default:
+		return commonpb.EllipticCurveType_UNKNOWN_CURVE, fmt.Errorf("unsupported curve %s", c.Params().Name)
+	}
+}
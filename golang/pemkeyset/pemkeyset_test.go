@@ -0,0 +1,312 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package pemkeyset_test
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"golang.org/x/crypto/ed25519"
+
+	"github.com/tsingson/tink/golang/hybrid"
+	"github.com/tsingson/tink/golang/keyset"
+	"github.com/tsingson/tink/golang/pemkeyset"
+	"github.com/tsingson/tink/golang/signature"
+	"github.com/tsingson/tink/golang/testkeyset"
+	commonpb "github.com/tsingson/tink/proto/common_go_proto"
+	ecdsapb "github.com/tsingson/tink/proto/ecdsa_go_proto"
+	eciespb "github.com/tsingson/tink/proto/ecies_aead_hkdf_go_proto"
+	tinkpb "github.com/tsingson/tink/proto/tink_go_proto"
+)
+
+// ed25519PKCS8 returns the PKCS#8 encoding of the Ed25519 key with the given seed.
+func ed25519PKCS8(seed []byte) []byte {
+	prefix := []byte{0x30, 0x2e, 0x02, 0x01, 0x00, 0x30, 0x05, 0x06, 0x03, 0x2b, 0x65, 0x70, 0x04, 0x22, 0x04, 0x20}
+	return append(prefix, seed...)
+}
+
+// ed25519SPKI returns the SubjectPublicKeyInfo encoding of the given Ed25519 public key.
+func ed25519SPKI(pub []byte) []byte {
+	prefix := []byte{0x30, 0x2a, 0x30, 0x05, 0x06, 0x03, 0x2b, 0x65, 0x70, 0x03, 0x21, 0x00}
+	return append(prefix, pub...)
+}
+
+func pemEncode(blockType string, der []byte) []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der})
+}
+
+func eciesParams(t *testing.T) *eciespb.EciesAeadHkdfParams {
+	format := new(eciespb.EciesAeadHkdfKeyFormat)
+	if err := proto.Unmarshal(hybrid.ECIESHKDFAES128GCMKeyTemplate().Value, format); err != nil {
+		t.Fatalf("proto.Unmarshal() err = %v", err)
+	}
+	return format.Params
+}
+
+func TestPKCS8ImportSignAndVerify(t *testing.T) {
+	type testCase struct {
+		name      string
+		pkcs8     []byte
+		publicPEM []byte
+	}
+	var cases []testCase
+	for _, c := range []elliptic.Curve{elliptic.P256(), elliptic.P384(), elliptic.P521()} {
+		priv, err := ecdsa.GenerateKey(c, rand.Reader)
+		if err != nil {
+			t.Fatalf("ecdsa.GenerateKey() err = %v", err)
+		}
+		der, err := x509.MarshalPKCS8PrivateKey(priv)
+		if err != nil {
+			t.Fatalf("x509.MarshalPKCS8PrivateKey() err = %v", err)
+		}
+		pubDER, err := x509.MarshalPKIXPublicKey(&priv.PublicKey)
+		if err != nil {
+			t.Fatalf("x509.MarshalPKIXPublicKey() err = %v", err)
+		}
+		cases = append(cases, testCase{c.Params().Name, der, pemEncode("PUBLIC KEY", pubDER)})
+	}
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("ed25519.GenerateKey() err = %v", err)
+	}
+	cases = append(cases, testCase{"Ed25519", ed25519PKCS8(priv.Seed()), pemEncode("PUBLIC KEY", ed25519SPKI(pub))})
+
+	for _, tc := range cases {
+		privHandle, err := pemkeyset.InsecureImportPKCS8PrivateKeys(pemEncode("PRIVATE KEY", tc.pkcs8), pemkeyset.ImportOptions{})
+		if err != nil {
+			t.Fatalf("%s: pemkeyset.InsecureImportPKCS8PrivateKeys() err = %v", tc.name, err)
+		}
+		signer, err := signature.NewSigner(privHandle)
+		if err != nil {
+			t.Fatalf("%s: signature.NewSigner() err = %v", tc.name, err)
+		}
+		data := []byte("data")
+		sig, err := signer.Sign(data)
+		if err != nil {
+			t.Fatalf("%s: signer.Sign() err = %v", tc.name, err)
+		}
+
+		pubHandle, err := privHandle.Public()
+		if err != nil {
+			t.Fatalf("%s: privHandle.Public() err = %v", tc.name, err)
+		}
+		exported, err := pemkeyset.ExportPublicKeys(pubHandle)
+		if err != nil {
+			t.Fatalf("%s: pemkeyset.ExportPublicKeys() err = %v", tc.name, err)
+		}
+		if !bytes.Equal(exported, tc.publicPEM) {
+			t.Errorf("%s: pemkeyset.ExportPublicKeys() = %q, want %q", tc.name, exported, tc.publicPEM)
+		}
+
+		imported, err := pemkeyset.ImportPublicKeys(exported, pemkeyset.ImportOptions{})
+		if err != nil {
+			t.Fatalf("%s: pemkeyset.ImportPublicKeys() err = %v", tc.name, err)
+		}
+		verifier, err := signature.NewVerifier(imported)
+		if err != nil {
+			t.Fatalf("%s: signature.NewVerifier() err = %v", tc.name, err)
+		}
+		if err := verifier.Verify(sig, data); err != nil {
+			t.Errorf("%s: verifier.Verify() err = %v", tc.name, err)
+		}
+		if err := verifier.Verify(sig, []byte("other data")); err == nil {
+			t.Errorf("%s: verifier.Verify() with other data err = nil, want error", tc.name)
+		}
+	}
+}
+
+func TestExportPublicKeysOfGeneratedKeyset(t *testing.T) {
+	manager := keyset.NewManager()
+	templates := []*tinkpb.KeyTemplate{
+		signature.ECDSAP256KeyTemplate(),
+		signature.ECDSAP384KeyTemplate(),
+		signature.ECDSAP521KeyTemplate(),
+		signature.ED25519KeyTemplate(),
+	}
+	for _, kt := range templates {
+		if err := manager.Rotate(kt); err != nil {
+			t.Fatalf("manager.Rotate() err = %v", err)
+		}
+	}
+	h, err := manager.Handle()
+	if err != nil {
+		t.Fatalf("manager.Handle() err = %v", err)
+	}
+	if _, err := pemkeyset.ExportPublicKeys(h); err == nil {
+		t.Errorf("pemkeyset.ExportPublicKeys() with private keys err = nil, want error")
+	}
+	pubHandle, err := h.Public()
+	if err != nil {
+		t.Fatalf("h.Public() err = %v", err)
+	}
+	exported, err := pemkeyset.ExportPublicKeys(pubHandle)
+	if err != nil {
+		t.Fatalf("pemkeyset.ExportPublicKeys() err = %v", err)
+	}
+
+	// The last rotated key is the primary and comes first.
+	var blocks []*pem.Block
+	for rest := exported; ; {
+		var b *pem.Block
+		if b, rest = pem.Decode(rest); b == nil {
+			break
+		}
+		blocks = append(blocks, b)
+	}
+	if len(blocks) != len(templates) {
+		t.Fatalf("got %d PEM blocks, want %d", len(blocks), len(templates))
+	}
+	if !bytes.HasPrefix(blocks[0].Bytes, ed25519SPKI(nil)) {
+		t.Errorf("first PEM block is not the primary Ed25519 key")
+	}
+	for i, b := range blocks[1:] {
+		if b.Type != "PUBLIC KEY" {
+			t.Errorf("block %d type = %q, want PUBLIC KEY", i, b.Type)
+		}
+		if _, err := x509.ParsePKIXPublicKey(b.Bytes); err != nil {
+			t.Errorf("x509.ParsePKIXPublicKey() of block %d err = %v", i, err)
+		}
+	}
+
+	// Importing and exporting again is lossless.
+	imported, err := pemkeyset.ImportPublicKeys(exported, pemkeyset.ImportOptions{
+		OutputPrefixType: tinkpb.OutputPrefixType_TINK,
+	})
+	if err != nil {
+		t.Fatalf("pemkeyset.ImportPublicKeys() err = %v", err)
+	}
+	ks := testkeyset.KeysetMaterial(imported)
+	if len(ks.Key) != len(templates) || ks.PrimaryKeyId != ks.Key[0].KeyId {
+		t.Errorf("imported keyset has %d keys and primary %d, want %d keys and the first primary", len(ks.Key), ks.PrimaryKeyId, len(templates))
+	}
+	for _, key := range ks.Key {
+		if key.OutputPrefixType != tinkpb.OutputPrefixType_TINK || key.KeyData.KeyMaterialType != tinkpb.KeyData_ASYMMETRIC_PUBLIC {
+			t.Errorf("imported key %d has prefix %s and material %s", key.KeyId, key.OutputPrefixType, key.KeyData.KeyMaterialType)
+		}
+	}
+	reexported, err := pemkeyset.ExportPublicKeys(imported)
+	if err != nil {
+		t.Fatalf("pemkeyset.ExportPublicKeys() err = %v", err)
+	}
+	if !bytes.Equal(reexported, exported) {
+		t.Errorf("re-exported keys differ from the exported keys")
+	}
+}
+
+func TestImportECIESKeys(t *testing.T) {
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("ecdsa.GenerateKey() err = %v", err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(priv)
+	if err != nil {
+		t.Fatalf("x509.MarshalPKCS8PrivateKey() err = %v", err)
+	}
+	opts := pemkeyset.ImportOptions{ECIESParams: eciesParams(t)}
+	privHandle, err := pemkeyset.InsecureImportPKCS8PrivateKeys(pemEncode("PRIVATE KEY", der), opts)
+	if err != nil {
+		t.Fatalf("pemkeyset.InsecureImportPKCS8PrivateKeys() err = %v", err)
+	}
+	pubHandle, err := privHandle.Public()
+	if err != nil {
+		t.Fatalf("privHandle.Public() err = %v", err)
+	}
+	exported, err := pemkeyset.ExportPublicKeys(pubHandle)
+	if err != nil {
+		t.Fatalf("pemkeyset.ExportPublicKeys() err = %v", err)
+	}
+	imported, err := pemkeyset.ImportPublicKeys(exported, opts)
+	if err != nil {
+		t.Fatalf("pemkeyset.ImportPublicKeys() err = %v", err)
+	}
+
+	enc, err := hybrid.NewHybridEncrypt(imported)
+	if err != nil {
+		t.Fatalf("hybrid.NewHybridEncrypt() err = %v", err)
+	}
+	dec, err := hybrid.NewHybridDecrypt(privHandle)
+	if err != nil {
+		t.Fatalf("hybrid.NewHybridDecrypt() err = %v", err)
+	}
+	pt := []byte("plaintext")
+	ci := []byte("context info")
+	ct, err := enc.Encrypt(pt, ci)
+	if err != nil {
+		t.Fatalf("enc.Encrypt() err = %v", err)
+	}
+	got, err := dec.Decrypt(ct, ci)
+	if err != nil || !bytes.Equal(got, pt) {
+		t.Errorf("dec.Decrypt() = %q, %v, want %q, nil", got, err, pt)
+	}
+}
+
+func TestImportWithInvalidInput(t *testing.T) {
+	p256, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("ecdsa.GenerateKey() err = %v", err)
+	}
+	p256DER, _ := x509.MarshalPKIXPublicKey(&p256.PublicKey)
+	p224, err := ecdsa.GenerateKey(elliptic.P224(), rand.Reader)
+	if err != nil {
+		t.Fatalf("ecdsa.GenerateKey() err = %v", err)
+	}
+	p224DER, _ := x509.MarshalPKIXPublicKey(&p224.PublicKey)
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("rsa.GenerateKey() err = %v", err)
+	}
+	rsaDER, _ := x509.MarshalPKIXPublicKey(&rsaKey.PublicKey)
+	p384Params := eciesParams(t)
+	p384Params.KemParams.CurveType = commonpb.EllipticCurveType_NIST_P384
+
+	testCases := []struct {
+		name string
+		pem  []byte
+		opts pemkeyset.ImportOptions
+	}{
+		{"empty input", nil, pemkeyset.ImportOptions{}},
+		{"no public key blocks", pemEncode("CERTIFICATE", p256DER), pemkeyset.ImportOptions{}},
+		{"malformed key", pemEncode("PUBLIC KEY", p256DER[:20]), pemkeyset.ImportOptions{}},
+		{"unsupported curve", pemEncode("PUBLIC KEY", p224DER), pemkeyset.ImportOptions{}},
+		{"unsupported key type", pemEncode("PUBLIC KEY", rsaDER), pemkeyset.ImportOptions{}},
+		{"invalid hash for curve", pemEncode("PUBLIC KEY", p256DER), pemkeyset.ImportOptions{ECDSAHashType: commonpb.HashType_SHA512}},
+		{"invalid encoding", pemEncode("PUBLIC KEY", p256DER), pemkeyset.ImportOptions{ECDSAEncoding: ecdsapb.EcdsaSignatureEncoding(42)}},
+		{"ECIES curve mismatch", pemEncode("PUBLIC KEY", p256DER), pemkeyset.ImportOptions{ECIESParams: p384Params}},
+	}
+	for _, tc := range testCases {
+		if _, err := pemkeyset.ImportPublicKeys(tc.pem, tc.opts); err == nil {
+			t.Errorf("%s: pemkeyset.ImportPublicKeys() err = nil, want error", tc.name)
+		}
+	}
+
+	// Private keys are only imported from PRIVATE KEY blocks.
+	privDER, _ := x509.MarshalPKCS8PrivateKey(p256)
+	if _, err := pemkeyset.InsecureImportPKCS8PrivateKeys(pemEncode("PUBLIC KEY", p256DER), pemkeyset.ImportOptions{}); err == nil {
+		t.Errorf("pemkeyset.InsecureImportPKCS8PrivateKeys() of a public key err = nil, want error")
+	}
+	if _, err := pemkeyset.ImportPublicKeys(pemEncode("PRIVATE KEY", privDER), pemkeyset.ImportOptions{}); err == nil {
+		t.Errorf("pemkeyset.ImportPublicKeys() of a private key err = nil, want error")
+	}
+	if _, err := pemkeyset.ExportPublicKeys(nil); err == nil {
+		t.Errorf("pemkeyset.ExportPublicKeys(nil) err = nil, want error")
+	}
+}
@@ -0,0 +1,107 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package signature
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"math/big"
+
+	subtleSignature "github.com/tsingson/tink/golang/subtle/signature"
+)
+
+// rsaF4 is the public exponent used by the RSA key templates.
+const rsaF4 = subtleSignature.RSAMinPublicExponent
+
+var errRSAInvalidPublicExponent = errors.New("invalid public exponent")
+
+// rsaPublicExponent converts the given big-endian public exponent to an int.
+func rsaPublicExponent(e []byte) (int, error) {
+	exponent := new(big.Int).SetBytes(e)
+	if !exponent.IsInt64() || exponent.Int64() > subtleSignature.RSAMaxPublicExponent {
+		return 0, errRSAInvalidPublicExponent
+	}
+	return int(exponent.Int64()), nil
+}
+
+// newRSAPublicKey creates an rsa.PublicKey from the given big-endian modulus and
+// public exponent, and checks the minimum modulus size and public exponent.
+func newRSAPublicKey(n, e []byte) (*rsa.PublicKey, error) {
+	exponent, err := rsaPublicExponent(e)
+	if err != nil {
+		return nil, err
+	}
+	pub := &rsa.PublicKey{
+		N: new(big.Int).SetBytes(n),
+		E: exponent,
+	}
+	if err := subtleSignature.ValidateRSAPublicKey(pub); err != nil {
+		return nil, err
+	}
+	return pub, nil
+}
+
+// newRSAPrivateKey creates an rsa.PrivateKey from the given public key, the
+// private exponent, the two prime factors and the stored CRT values, if any.
+// The key is validated by the subtle signer it is passed to.
+func newRSAPrivateKey(pub *rsa.PublicKey, d, p, q, dp, dq, crt []byte) *rsa.PrivateKey {
+	priv := &rsa.PrivateKey{
+		PublicKey: *pub,
+		D:         new(big.Int).SetBytes(d),
+		Primes: []*big.Int{
+			new(big.Int).SetBytes(p),
+			new(big.Int).SetBytes(q),
+		},
+	}
+	if len(dp) > 0 && len(dq) > 0 && len(crt) > 0 {
+		priv.Precomputed = rsa.PrecomputedValues{
+			Dp:   new(big.Int).SetBytes(dp),
+			Dq:   new(big.Int).SetBytes(dq),
+			Qinv: new(big.Int).SetBytes(crt),
+		}
+	}
+	return priv
+}
+
+// validateRSAKeyFormat validates the modulus size and public exponent of an RSA key format.
+func validateRSAKeyFormat(modulusSizeInBits uint32, publicExponent []byte) error {
+	if err := subtleSignature.ValidateRSAModulusSize(int(modulusSizeInBits)); err != nil {
+		return err
+	}
+	exponent, err := rsaPublicExponent(publicExponent)
+	if err != nil {
+		return err
+	}
+	if err := subtleSignature.ValidateRSAPublicExponent(exponent); err != nil {
+		return err
+	}
+	// crypto/rsa always generates keys with F4 as the public exponent.
+	if exponent != subtleSignature.RSAMinPublicExponent {
+		return fmt.Errorf("only public exponent %d is supported for key generation", subtleSignature.RSAMinPublicExponent)
+	}
+	return nil
+}
+
+// generateRSAKey generates a new RSA private key with the given modulus size.
+func generateRSAKey(modulusSizeInBits uint32) (*rsa.PrivateKey, error) {
+	priv, err := rsa.GenerateKey(rand.Reader, int(modulusSizeInBits))
+	if err != nil {
+		return nil, err
+	}
+	priv.Precompute()
+	return priv, nil
+}
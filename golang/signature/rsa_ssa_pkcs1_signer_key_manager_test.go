@@ -0,0 +1,212 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package signature_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+
+	"github.com/tsingson/tink/golang/core/registry"
+	"github.com/tsingson/tink/golang/subtle/random"
+	subtleSignature "github.com/tsingson/tink/golang/subtle/signature"
+	"github.com/tsingson/tink/golang/testutil"
+	"github.com/tsingson/tink/golang/tink"
+	commonpb "github.com/tsingson/tink/proto/common_go_proto"
+	rsassapkcs1pb "github.com/tsingson/tink/proto/rsa_ssa_pkcs1_go_proto"
+	tinkpb "github.com/tsingson/tink/proto/tink_go_proto"
+)
+
+func TestRSASSAPKCS1SignerNewKeyBasic(t *testing.T) {
+	km, err := registry.GetKeyManager(testutil.RSASSAPKCS1SignerTypeURL)
+	if err != nil {
+		t.Fatalf("cannot obtain RSASSAPKCS1Signer key manager: %s", err)
+	}
+	key := newRSASSAPKCS1PrivateKey(t, commonpb.HashType_SHA256)
+	if key.Version != testutil.RSASSAPKCS1SignerKeyVersion ||
+		key.PublicKey.Version != testutil.RSASSAPKCS1VerifierKeyVersion {
+		t.Errorf("incorrect key version")
+	}
+	if new(big.Int).SetBytes(key.PublicKey.N).BitLen() != 2048 {
+		t.Errorf("incorrect modulus size")
+	}
+	if new(big.Int).SetBytes(key.PublicKey.E).Int64() != 65537 {
+		t.Errorf("incorrect public exponent")
+	}
+	if key.PublicKey.Params.HashType != commonpb.HashType_SHA256 {
+		t.Errorf("incorrect params")
+	}
+	serializedKey, _ := proto.Marshal(key)
+	p, err := km.Primitive(serializedKey)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	var _ *subtleSignature.RSASSAPKCS1Signer = p.(*subtleSignature.RSASSAPKCS1Signer)
+
+	// signatures must verify with the public key
+	pkm, ok := km.(registry.PrivateKeyManager)
+	if !ok {
+		t.Fatalf("RSASSAPKCS1Signer key manager is not a PrivateKeyManager")
+	}
+	pubKeyData, err := pkm.PublicKeyData(serializedKey)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if pubKeyData.TypeUrl != testutil.RSASSAPKCS1VerifierTypeURL ||
+		pubKeyData.KeyMaterialType != tinkpb.KeyData_ASYMMETRIC_PUBLIC {
+		t.Errorf("incorrect public key data")
+	}
+	v, err := registry.Primitive(pubKeyData.TypeUrl, pubKeyData.Value)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	data := random.GetRandomBytes(100)
+	sig, err := p.(tink.Signer).Sign(data)
+	if err != nil {
+		t.Fatalf("signing failed: %s", err)
+	}
+	if err := v.(tink.Verifier).Verify(sig, data); err != nil {
+		t.Errorf("verification failed: %s", err)
+	}
+}
+
+func TestRSASSAPKCS1SignerNewKeyWithInvalidInput(t *testing.T) {
+	km, err := registry.GetKeyManager(testutil.RSASSAPKCS1SignerTypeURL)
+	if err != nil {
+		t.Fatalf("cannot obtain RSASSAPKCS1Signer key manager: %s", err)
+	}
+	badFormats := []*rsassapkcs1pb.RsaSsaPkcs1KeyFormat{
+		// small modulus
+		newRSASSAPKCS1KeyFormat(commonpb.HashType_SHA256, 1024, 65537),
+		// small public exponent
+		newRSASSAPKCS1KeyFormat(commonpb.HashType_SHA256, 2048, 3),
+		// even public exponent
+		newRSASSAPKCS1KeyFormat(commonpb.HashType_SHA256, 2048, 65538),
+		// public exponent other than F4
+		newRSASSAPKCS1KeyFormat(commonpb.HashType_SHA256, 2048, 65539),
+		// weak hash
+		newRSASSAPKCS1KeyFormat(commonpb.HashType_SHA1, 2048, 65537),
+		newRSASSAPKCS1KeyFormat(commonpb.HashType_UNKNOWN_HASH, 2048, 65537),
+		// missing params
+		&rsassapkcs1pb.RsaSsaPkcs1KeyFormat{ModulusSizeInBits: 2048, PublicExponent: big.NewInt(65537).Bytes()},
+	}
+	for i, format := range badFormats {
+		serializedFormat, _ := proto.Marshal(format)
+		if _, err := km.NewKey(serializedFormat); err == nil {
+			t.Errorf("expect an error in test case %d", i)
+		}
+		if _, err := km.NewKeyData(serializedFormat); err == nil {
+			t.Errorf("expect an error in test case %d", i)
+		}
+	}
+	if _, err := km.NewKey(nil); err == nil {
+		t.Errorf("expect an error when input is nil")
+	}
+	if _, err := km.NewKey([]byte{}); err == nil {
+		t.Errorf("expect an error when input is empty slice")
+	}
+}
+
+func TestRSASSAPKCS1SignerGetPrimitiveWithInvalidInput(t *testing.T) {
+	km, err := registry.GetKeyManager(testutil.RSASSAPKCS1SignerTypeURL)
+	if err != nil {
+		t.Fatalf("cannot obtain RSASSAPKCS1Signer key manager: %s", err)
+	}
+	key := newRSASSAPKCS1PrivateKey(t, commonpb.HashType_SHA256)
+
+	// invalid version
+	badKey := proto.Clone(key).(*rsassapkcs1pb.RsaSsaPkcs1PrivateKey)
+	badKey.Version = testutil.RSASSAPKCS1SignerKeyVersion + 1
+	serializedKey, _ := proto.Marshal(badKey)
+	if _, err := km.Primitive(serializedKey); err == nil {
+		t.Errorf("expect an error when version is invalid")
+	}
+	// weak hash
+	badKey = proto.Clone(key).(*rsassapkcs1pb.RsaSsaPkcs1PrivateKey)
+	badKey.PublicKey.Params.HashType = commonpb.HashType_SHA1
+	serializedKey, _ = proto.Marshal(badKey)
+	if _, err := km.Primitive(serializedKey); err == nil {
+		t.Errorf("expect an error when hash is SHA1")
+	}
+	// inconsistent private key
+	badKey = proto.Clone(key).(*rsassapkcs1pb.RsaSsaPkcs1PrivateKey)
+	badKey.D = random.GetRandomBytes(uint32(len(key.D)))
+	serializedKey, _ = proto.Marshal(badKey)
+	if _, err := km.Primitive(serializedKey); err == nil {
+		t.Errorf("expect an error when the private exponent is invalid")
+	}
+	// missing public key
+	badKey = proto.Clone(key).(*rsassapkcs1pb.RsaSsaPkcs1PrivateKey)
+	badKey.PublicKey = nil
+	serializedKey, _ = proto.Marshal(badKey)
+	if _, err := km.Primitive(serializedKey); err == nil {
+		t.Errorf("expect an error when the public key is missing")
+	}
+	if _, err := km.Primitive(nil); err == nil {
+		t.Errorf("expect an error when input is nil")
+	}
+	if _, err := km.Primitive([]byte{}); err == nil {
+		t.Errorf("expect an error when input is empty slice")
+	}
+}
+
+func TestRSASSAPKCS1SignerNewKeyData(t *testing.T) {
+	km, err := registry.GetKeyManager(testutil.RSASSAPKCS1SignerTypeURL)
+	if err != nil {
+		t.Fatalf("cannot obtain RSASSAPKCS1Signer key manager: %s", err)
+	}
+	serializedFormat, _ := proto.Marshal(newRSASSAPKCS1KeyFormat(commonpb.HashType_SHA512, 2048, 65537))
+	keyData, err := km.NewKeyData(serializedFormat)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if keyData.TypeUrl != testutil.RSASSAPKCS1SignerTypeURL {
+		t.Errorf("incorrect type url")
+	}
+	if keyData.KeyMaterialType != tinkpb.KeyData_ASYMMETRIC_PRIVATE {
+		t.Errorf("incorrect key material type")
+	}
+	if _, err := km.Primitive(keyData.Value); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+	if !km.DoesSupport(testutil.RSASSAPKCS1SignerTypeURL) || km.DoesSupport(testutil.RSASSAPKCS1VerifierTypeURL) {
+		t.Errorf("RSASSAPKCS1Signer key manager must support only %s", testutil.RSASSAPKCS1SignerTypeURL)
+	}
+	if km.TypeURL() != testutil.RSASSAPKCS1SignerTypeURL {
+		t.Errorf("incorrect type url")
+	}
+}
+
+func newRSASSAPKCS1KeyFormat(hashType commonpb.HashType, modulusSizeInBits uint32, e int64) *rsassapkcs1pb.RsaSsaPkcs1KeyFormat {
+	return &rsassapkcs1pb.RsaSsaPkcs1KeyFormat{
+		Params:            &rsassapkcs1pb.RsaSsaPkcs1Params{HashType: hashType},
+		ModulusSizeInBits: modulusSizeInBits,
+		PublicExponent:    big.NewInt(e).Bytes(),
+	}
+}
+
+func newRSASSAPKCS1PrivateKey(t *testing.T, hashType commonpb.HashType) *rsassapkcs1pb.RsaSsaPkcs1PrivateKey {
+	km, err := registry.GetKeyManager(testutil.RSASSAPKCS1SignerTypeURL)
+	if err != nil {
+		t.Fatalf("cannot obtain RSASSAPKCS1Signer key manager: %s", err)
+	}
+	serializedFormat, _ := proto.Marshal(newRSASSAPKCS1KeyFormat(hashType, 2048, 65537))
+	m, err := km.NewKey(serializedFormat)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	return m.(*rsassapkcs1pb.RsaSsaPkcs1PrivateKey)
+}
@@ -0,0 +1,104 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package signature
+
+import (
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+
+	"github.com/tsingson/tink/golang/core/registry"
+	"github.com/tsingson/tink/golang/keyset"
+	subtleSignature "github.com/tsingson/tink/golang/subtle/signature"
+	commonpb "github.com/tsingson/tink/proto/common_go_proto"
+	rsassapkcs1pb "github.com/tsingson/tink/proto/rsa_ssa_pkcs1_go_proto"
+	tinkpb "github.com/tsingson/tink/proto/tink_go_proto"
+)
+
+const (
+	rsaSSAPKCS1VerifierKeyVersion = 0
+	rsaSSAPKCS1VerifierTypeURL    = "type.googleapis.com/google.crypto.tink.RsaSsaPkcs1PublicKey"
+)
+
+// common errors
+var errInvalidRSASSAPKCS1VerifierKey = fmt.Errorf("rsa_ssa_pkcs1_verifier_key_manager: invalid key")
+var errRSASSAPKCS1VerifierNotImplemented = fmt.Errorf("rsa_ssa_pkcs1_verifier_key_manager: not implemented")
+
+// rsaSSAPKCS1VerifierKeyManager is an implementation of KeyManager interface.
+// It doesn't support key generation.
+type rsaSSAPKCS1VerifierKeyManager struct{}
+
+// Assert that rsaSSAPKCS1VerifierKeyManager implements the KeyManager interface.
+var _ registry.KeyManager = (*rsaSSAPKCS1VerifierKeyManager)(nil)
+
+// newRSASSAPKCS1VerifierKeyManager creates a new rsaSSAPKCS1VerifierKeyManager.
+func newRSASSAPKCS1VerifierKeyManager() *rsaSSAPKCS1VerifierKeyManager {
+	return new(rsaSSAPKCS1VerifierKeyManager)
+}
+
+// Primitive creates an RSASSAPKCS1Verifier subtle for the given serialized RSASSAPKCS1PublicKey proto.
+func (km *rsaSSAPKCS1VerifierKeyManager) Primitive(serializedKey []byte) (interface{}, error) {
+	if len(serializedKey) == 0 {
+		return nil, errInvalidRSASSAPKCS1VerifierKey
+	}
+	key := new(rsassapkcs1pb.RsaSsaPkcs1PublicKey)
+	if err := proto.Unmarshal(serializedKey, key); err != nil {
+		return nil, errInvalidRSASSAPKCS1VerifierKey
+	}
+	if err := km.validateKey(key); err != nil {
+		return nil, fmt.Errorf("rsa_ssa_pkcs1_verifier_key_manager: %s", err)
+	}
+	pub, err := newRSAPublicKey(key.N, key.E)
+	if err != nil {
+		return nil, fmt.Errorf("rsa_ssa_pkcs1_verifier_key_manager: invalid key: %s", err)
+	}
+	hash := commonpb.HashType_name[int32(key.Params.HashType)]
+	ret, err := subtleSignature.NewRSASSAPKCS1Verifier(hash, pub)
+	if err != nil {
+		return nil, fmt.Errorf("rsa_ssa_pkcs1_verifier_key_manager: invalid key: %s", err)
+	}
+	return ret, nil
+}
+
+// NewKey is not implemented.
+func (km *rsaSSAPKCS1VerifierKeyManager) NewKey(serializedKeyFormat []byte) (proto.Message, error) {
+	return nil, errRSASSAPKCS1VerifierNotImplemented
+}
+
+// NewKeyData is not implemented.
+func (km *rsaSSAPKCS1VerifierKeyManager) NewKeyData(serializedKeyFormat []byte) (*tinkpb.KeyData, error) {
+	return nil, errRSASSAPKCS1VerifierNotImplemented
+}
+
+// DoesSupport indicates if this key manager supports the given key type.
+func (km *rsaSSAPKCS1VerifierKeyManager) DoesSupport(typeURL string) bool {
+	return typeURL == rsaSSAPKCS1VerifierTypeURL
+}
+
+// TypeURL returns the key type of keys managed by this key manager.
+func (km *rsaSSAPKCS1VerifierKeyManager) TypeURL() string {
+	return rsaSSAPKCS1VerifierTypeURL
+}
+
+// validateKey validates the given RSASSAPKCS1PublicKey.
+func (km *rsaSSAPKCS1VerifierKeyManager) validateKey(key *rsassapkcs1pb.RsaSsaPkcs1PublicKey) error {
+	if err := keyset.ValidateKeyVersion(key.Version, rsaSSAPKCS1VerifierKeyVersion); err != nil {
+		return err
+	}
+	if key.Params == nil {
+		return fmt.Errorf("missing params")
+	}
+	return validateRSASSAPKCS1Params(key.Params)
+}
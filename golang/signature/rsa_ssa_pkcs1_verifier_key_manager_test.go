@@ -0,0 +1,79 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package signature_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+
+	"github.com/tsingson/tink/golang/core/registry"
+	subtleSignature "github.com/tsingson/tink/golang/subtle/signature"
+	"github.com/tsingson/tink/golang/testutil"
+	commonpb "github.com/tsingson/tink/proto/common_go_proto"
+	rsassapkcs1pb "github.com/tsingson/tink/proto/rsa_ssa_pkcs1_go_proto"
+)
+
+func TestRSASSAPKCS1VerifierGetPrimitiveBasic(t *testing.T) {
+	km, err := registry.GetKeyManager(testutil.RSASSAPKCS1VerifierTypeURL)
+	if err != nil {
+		t.Fatalf("cannot obtain RSASSAPKCS1Verifier key manager: %s", err)
+	}
+	for _, hashType := range []commonpb.HashType{commonpb.HashType_SHA256, commonpb.HashType_SHA512} {
+		key := newRSASSAPKCS1PrivateKey(t, hashType)
+		serializedKey, _ := proto.Marshal(key.PublicKey)
+		p, err := km.Primitive(serializedKey)
+		if err != nil {
+			t.Errorf("unexpected error: %s", err)
+		}
+		var _ *subtleSignature.RSASSAPKCS1Verifier = p.(*subtleSignature.RSASSAPKCS1Verifier)
+	}
+}
+
+func TestRSASSAPKCS1VerifierGetPrimitiveWithInvalidInput(t *testing.T) {
+	km, err := registry.GetKeyManager(testutil.RSASSAPKCS1VerifierTypeURL)
+	if err != nil {
+		t.Fatalf("cannot obtain RSASSAPKCS1Verifier key manager: %s", err)
+	}
+	key := newRSASSAPKCS1PrivateKey(t, commonpb.HashType_SHA256).PublicKey
+	var testCases = []func(*rsassapkcs1pb.RsaSsaPkcs1PublicKey){
+		func(k *rsassapkcs1pb.RsaSsaPkcs1PublicKey) { k.Version = testutil.RSASSAPKCS1VerifierKeyVersion + 1 },
+		func(k *rsassapkcs1pb.RsaSsaPkcs1PublicKey) { k.Params.HashType = commonpb.HashType_SHA1 },
+		func(k *rsassapkcs1pb.RsaSsaPkcs1PublicKey) { k.Params = nil },
+		func(k *rsassapkcs1pb.RsaSsaPkcs1PublicKey) { k.E = big.NewInt(3).Bytes() },
+		func(k *rsassapkcs1pb.RsaSsaPkcs1PublicKey) { k.E = big.NewInt(65538).Bytes() },
+		func(k *rsassapkcs1pb.RsaSsaPkcs1PublicKey) { k.N = k.N[:128] },
+	}
+	for i, modify := range testCases {
+		badKey := proto.Clone(key).(*rsassapkcs1pb.RsaSsaPkcs1PublicKey)
+		modify(badKey)
+		serializedKey, _ := proto.Marshal(badKey)
+		if _, err := km.Primitive(serializedKey); err == nil {
+			t.Errorf("expect an error in test case %d", i)
+		}
+	}
+	if _, err := km.Primitive(nil); err == nil {
+		t.Errorf("expect an error when input is nil")
+	}
+	if _, err := km.Primitive([]byte{}); err == nil {
+		t.Errorf("expect an error when input is empty slice")
+	}
+	// key generation is not supported
+	serializedFormat, _ := proto.Marshal(newRSASSAPKCS1KeyFormat(commonpb.HashType_SHA256, 2048, 65537))
+	if _, err := km.NewKey(serializedFormat); err == nil {
+		t.Errorf("expect an error from NewKey")
+	}
+}
@@ -0,0 +1,194 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package signature
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/golang/protobuf/proto"
+
+	"github.com/tsingson/tink/golang/core/registry"
+	"github.com/tsingson/tink/golang/keyset"
+	subtleSignature "github.com/tsingson/tink/golang/subtle/signature"
+	commonpb "github.com/tsingson/tink/proto/common_go_proto"
+	rsassapsspb "github.com/tsingson/tink/proto/rsa_ssa_pss_go_proto"
+	tinkpb "github.com/tsingson/tink/proto/tink_go_proto"
+)
+
+const (
+	rsaSSAPSSSignerKeyVersion = 0
+	rsaSSAPSSSignerTypeURL    = "type.googleapis.com/google.crypto.tink.RsaSsaPssPrivateKey"
+)
+
+// common errors
+var errInvalidRSASSAPSSSignKey = errors.New("rsa_ssa_pss_signer_key_manager: invalid key")
+var errInvalidRSASSAPSSSignKeyFormat = errors.New("rsa_ssa_pss_signer_key_manager: invalid key format")
+
+// rsaSSAPSSSignerKeyManager is an implementation of KeyManager interface.
+// It generates new RSASSAPSSPrivateKeys and produces new instances of RSASSAPSSSigner subtle.
+type rsaSSAPSSSignerKeyManager struct{}
+
+// Assert that rsaSSAPSSSignerKeyManager implements the PrivateKeyManager interface.
+var _ registry.PrivateKeyManager = (*rsaSSAPSSSignerKeyManager)(nil)
+
+// newRSASSAPSSSignerKeyManager creates a new rsaSSAPSSSignerKeyManager.
+func newRSASSAPSSSignerKeyManager() *rsaSSAPSSSignerKeyManager {
+	return new(rsaSSAPSSSignerKeyManager)
+}
+
+// Primitive creates an RSASSAPSSSigner subtle for the given serialized RSASSAPSSPrivateKey proto.
+func (km *rsaSSAPSSSignerKeyManager) Primitive(serializedKey []byte) (interface{}, error) {
+	if len(serializedKey) == 0 {
+		return nil, errInvalidRSASSAPSSSignKey
+	}
+	key := new(rsassapsspb.RsaSsaPssPrivateKey)
+	if err := proto.Unmarshal(serializedKey, key); err != nil {
+		return nil, errInvalidRSASSAPSSSignKey
+	}
+	if err := km.validateKey(key); err != nil {
+		return nil, err
+	}
+	pub, err := newRSAPublicKey(key.PublicKey.N, key.PublicKey.E)
+	if err != nil {
+		return nil, fmt.Errorf("rsa_ssa_pss_signer_key_manager: %s", err)
+	}
+	priv := newRSAPrivateKey(pub, key.D, key.P, key.Q, key.Dp, key.Dq, key.Crt)
+	sigHash, mgf1Hash, saltLength := getRSASSAPSSParamNames(key.PublicKey.Params)
+	ret, err := subtleSignature.NewRSASSAPSSSigner(sigHash, mgf1Hash, saltLength, priv)
+	if err != nil {
+		return nil, fmt.Errorf("rsa_ssa_pss_signer_key_manager: %s", err)
+	}
+	return ret, nil
+}
+
+// NewKey creates a new RSASSAPSSPrivateKey according to specification the given serialized RSASSAPSSKeyFormat.
+func (km *rsaSSAPSSSignerKeyManager) NewKey(serializedKeyFormat []byte) (proto.Message, error) {
+	if len(serializedKeyFormat) == 0 {
+		return nil, errInvalidRSASSAPSSSignKeyFormat
+	}
+	keyFormat := new(rsassapsspb.RsaSsaPssKeyFormat)
+	if err := proto.Unmarshal(serializedKeyFormat, keyFormat); err != nil {
+		return nil, fmt.Errorf("rsa_ssa_pss_signer_key_manager: invalid proto: %s", err)
+	}
+	if err := km.validateKeyFormat(keyFormat); err != nil {
+		return nil, fmt.Errorf("rsa_ssa_pss_signer_key_manager: invalid key format: %s", err)
+	}
+	priv, err := generateRSAKey(keyFormat.ModulusSizeInBits)
+	if err != nil {
+		return nil, fmt.Errorf("rsa_ssa_pss_signer_key_manager: cannot generate RSA key: %s", err)
+	}
+	return &rsassapsspb.RsaSsaPssPrivateKey{
+		Version: rsaSSAPSSSignerKeyVersion,
+		PublicKey: &rsassapsspb.RsaSsaPssPublicKey{
+			Version: rsaSSAPSSSignerKeyVersion,
+			Params:  keyFormat.Params,
+			N:       priv.N.Bytes(),
+			E:       big.NewInt(int64(priv.E)).Bytes(),
+		},
+		D:   priv.D.Bytes(),
+		P:   priv.Primes[0].Bytes(),
+		Q:   priv.Primes[1].Bytes(),
+		Dp:  priv.Precomputed.Dp.Bytes(),
+		Dq:  priv.Precomputed.Dq.Bytes(),
+		Crt: priv.Precomputed.Qinv.Bytes(),
+	}, nil
+}
+
+// NewKeyData creates a new KeyData according to specification in  the given
+// serialized RSASSAPSSKeyFormat. It should be used solely by the key management API.
+func (km *rsaSSAPSSSignerKeyManager) NewKeyData(serializedKeyFormat []byte) (*tinkpb.KeyData, error) {
+	key, err := km.NewKey(serializedKeyFormat)
+	if err != nil {
+		return nil, err
+	}
+	serializedKey, err := proto.Marshal(key)
+	if err != nil {
+		return nil, errInvalidRSASSAPSSSignKeyFormat
+	}
+	return &tinkpb.KeyData{
+		TypeUrl:         rsaSSAPSSSignerTypeURL,
+		Value:           serializedKey,
+		KeyMaterialType: tinkpb.KeyData_ASYMMETRIC_PRIVATE,
+	}, nil
+}
+
+// PublicKeyData extracts the public key data from the private key.
+func (km *rsaSSAPSSSignerKeyManager) PublicKeyData(serializedPrivKey []byte) (*tinkpb.KeyData, error) {
+	privKey := new(rsassapsspb.RsaSsaPssPrivateKey)
+	if err := proto.Unmarshal(serializedPrivKey, privKey); err != nil {
+		return nil, errInvalidRSASSAPSSSignKey
+	}
+	if privKey.PublicKey == nil {
+		return nil, errInvalidRSASSAPSSSignKey
+	}
+	serializedPubKey, err := proto.Marshal(privKey.PublicKey)
+	if err != nil {
+		return nil, errInvalidRSASSAPSSSignKey
+	}
+	return &tinkpb.KeyData{
+		TypeUrl:         rsaSSAPSSVerifierTypeURL,
+		Value:           serializedPubKey,
+		KeyMaterialType: tinkpb.KeyData_ASYMMETRIC_PUBLIC,
+	}, nil
+}
+
+// DoesSupport indicates if this key manager supports the given key type.
+func (km *rsaSSAPSSSignerKeyManager) DoesSupport(typeURL string) bool {
+	return typeURL == rsaSSAPSSSignerTypeURL
+}
+
+// TypeURL returns the key type of keys managed by this key manager.
+func (km *rsaSSAPSSSignerKeyManager) TypeURL() string {
+	return rsaSSAPSSSignerTypeURL
+}
+
+// validateKey validates the given RSASSAPSSPrivateKey.
+func (km *rsaSSAPSSSignerKeyManager) validateKey(key *rsassapsspb.RsaSsaPssPrivateKey) error {
+	if err := keyset.ValidateKeyVersion(key.Version, rsaSSAPSSSignerKeyVersion); err != nil {
+		return fmt.Errorf("rsa_ssa_pss_signer_key_manager: invalid key: %s", err)
+	}
+	if key.PublicKey == nil || key.PublicKey.Params == nil {
+		return errInvalidRSASSAPSSSignKey
+	}
+	if err := validateRSASSAPSSParams(key.PublicKey.Params); err != nil {
+		return fmt.Errorf("rsa_ssa_pss_signer_key_manager: invalid key: %s", err)
+	}
+	return nil
+}
+
+// validateKeyFormat validates the given RSASSAPSSKeyFormat.
+func (km *rsaSSAPSSSignerKeyManager) validateKeyFormat(format *rsassapsspb.RsaSsaPssKeyFormat) error {
+	if format.Params == nil {
+		return errors.New("missing params")
+	}
+	if err := validateRSASSAPSSParams(format.Params); err != nil {
+		return err
+	}
+	return validateRSAKeyFormat(format.ModulusSizeInBits, format.PublicExponent)
+}
+
+// validateRSASSAPSSParams validates the given RSASSAPSSParams.
+func validateRSASSAPSSParams(params *rsassapsspb.RsaSsaPssParams) error {
+	return subtleSignature.ValidateRSASSAPSSParams(getRSASSAPSSParamNames(params))
+}
+
+// getRSASSAPSSParamNames returns the hash names and the salt length in the given RSASSAPSSParams.
+func getRSASSAPSSParamNames(params *rsassapsspb.RsaSsaPssParams) (string, string, int) {
+	sigHash := commonpb.HashType_name[int32(params.SigHash)]
+	mgf1Hash := commonpb.HashType_name[int32(params.Mgf1Hash)]
+	return sigHash, mgf1Hash, int(params.SaltLength)
+}
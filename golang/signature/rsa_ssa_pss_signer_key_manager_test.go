@@ -0,0 +1,228 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package signature_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+
+	"github.com/tsingson/tink/golang/core/registry"
+	"github.com/tsingson/tink/golang/subtle/random"
+	subtleSignature "github.com/tsingson/tink/golang/subtle/signature"
+	"github.com/tsingson/tink/golang/testutil"
+	"github.com/tsingson/tink/golang/tink"
+	commonpb "github.com/tsingson/tink/proto/common_go_proto"
+	rsassapsspb "github.com/tsingson/tink/proto/rsa_ssa_pss_go_proto"
+	tinkpb "github.com/tsingson/tink/proto/tink_go_proto"
+)
+
+func TestRSASSAPSSSignerNewKeyBasic(t *testing.T) {
+	km, err := registry.GetKeyManager(testutil.RSASSAPSSSignerTypeURL)
+	if err != nil {
+		t.Fatalf("cannot obtain RSASSAPSSSigner key manager: %s", err)
+	}
+	key := newRSASSAPSSPrivateKey(t, commonpb.HashType_SHA256)
+	if key.Version != testutil.RSASSAPSSSignerKeyVersion ||
+		key.PublicKey.Version != testutil.RSASSAPSSVerifierKeyVersion {
+		t.Errorf("incorrect key version")
+	}
+	if new(big.Int).SetBytes(key.PublicKey.N).BitLen() != 2048 {
+		t.Errorf("incorrect modulus size")
+	}
+	if new(big.Int).SetBytes(key.PublicKey.E).Int64() != 65537 {
+		t.Errorf("incorrect public exponent")
+	}
+	if key.PublicKey.Params.SigHash != commonpb.HashType_SHA256 || key.PublicKey.Params.SaltLength != 32 {
+		t.Errorf("incorrect params")
+	}
+	serializedKey, _ := proto.Marshal(key)
+	p, err := km.Primitive(serializedKey)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	var _ *subtleSignature.RSASSAPSSSigner = p.(*subtleSignature.RSASSAPSSSigner)
+
+	// signatures must verify with the public key
+	pkm, ok := km.(registry.PrivateKeyManager)
+	if !ok {
+		t.Fatalf("RSASSAPSSSigner key manager is not a PrivateKeyManager")
+	}
+	pubKeyData, err := pkm.PublicKeyData(serializedKey)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if pubKeyData.TypeUrl != testutil.RSASSAPSSVerifierTypeURL ||
+		pubKeyData.KeyMaterialType != tinkpb.KeyData_ASYMMETRIC_PUBLIC {
+		t.Errorf("incorrect public key data")
+	}
+	v, err := registry.Primitive(pubKeyData.TypeUrl, pubKeyData.Value)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	data := random.GetRandomBytes(100)
+	sig, err := p.(tink.Signer).Sign(data)
+	if err != nil {
+		t.Fatalf("signing failed: %s", err)
+	}
+	if err := v.(tink.Verifier).Verify(sig, data); err != nil {
+		t.Errorf("verification failed: %s", err)
+	}
+}
+
+func TestRSASSAPSSSignerNewKeyWithInvalidInput(t *testing.T) {
+	km, err := registry.GetKeyManager(testutil.RSASSAPSSSignerTypeURL)
+	if err != nil {
+		t.Fatalf("cannot obtain RSASSAPSSSigner key manager: %s", err)
+	}
+	badFormats := []*rsassapsspb.RsaSsaPssKeyFormat{
+		// small modulus
+		newRSASSAPSSKeyFormat(commonpb.HashType_SHA256, 1024, 65537),
+		// small public exponent
+		newRSASSAPSSKeyFormat(commonpb.HashType_SHA256, 2048, 3),
+		// even public exponent
+		newRSASSAPSSKeyFormat(commonpb.HashType_SHA256, 2048, 65538),
+		// public exponent other than F4
+		newRSASSAPSSKeyFormat(commonpb.HashType_SHA256, 2048, 65539),
+		// weak hash
+		newRSASSAPSSKeyFormat(commonpb.HashType_SHA1, 2048, 65537),
+		newRSASSAPSSKeyFormat(commonpb.HashType_UNKNOWN_HASH, 2048, 65537),
+		// MGF1 hash differs from signature hash
+		&rsassapsspb.RsaSsaPssKeyFormat{
+			Params:            &rsassapsspb.RsaSsaPssParams{SigHash: commonpb.HashType_SHA256, Mgf1Hash: commonpb.HashType_SHA512, SaltLength: 32},
+			ModulusSizeInBits: 2048,
+			PublicExponent:    big.NewInt(65537).Bytes(),
+		},
+		// zero salt length
+		&rsassapsspb.RsaSsaPssKeyFormat{
+			Params:            &rsassapsspb.RsaSsaPssParams{SigHash: commonpb.HashType_SHA256, Mgf1Hash: commonpb.HashType_SHA256},
+			ModulusSizeInBits: 2048,
+			PublicExponent:    big.NewInt(65537).Bytes(),
+		},
+		// missing params
+		&rsassapsspb.RsaSsaPssKeyFormat{ModulusSizeInBits: 2048, PublicExponent: big.NewInt(65537).Bytes()},
+	}
+	for i, format := range badFormats {
+		serializedFormat, _ := proto.Marshal(format)
+		if _, err := km.NewKey(serializedFormat); err == nil {
+			t.Errorf("expect an error in test case %d", i)
+		}
+		if _, err := km.NewKeyData(serializedFormat); err == nil {
+			t.Errorf("expect an error in test case %d", i)
+		}
+	}
+	if _, err := km.NewKey(nil); err == nil {
+		t.Errorf("expect an error when input is nil")
+	}
+	if _, err := km.NewKey([]byte{}); err == nil {
+		t.Errorf("expect an error when input is empty slice")
+	}
+}
+
+func TestRSASSAPSSSignerGetPrimitiveWithInvalidInput(t *testing.T) {
+	km, err := registry.GetKeyManager(testutil.RSASSAPSSSignerTypeURL)
+	if err != nil {
+		t.Fatalf("cannot obtain RSASSAPSSSigner key manager: %s", err)
+	}
+	key := newRSASSAPSSPrivateKey(t, commonpb.HashType_SHA256)
+
+	// invalid version
+	badKey := proto.Clone(key).(*rsassapsspb.RsaSsaPssPrivateKey)
+	badKey.Version = testutil.RSASSAPSSSignerKeyVersion + 1
+	serializedKey, _ := proto.Marshal(badKey)
+	if _, err := km.Primitive(serializedKey); err == nil {
+		t.Errorf("expect an error when version is invalid")
+	}
+	// weak hash
+	badKey = proto.Clone(key).(*rsassapsspb.RsaSsaPssPrivateKey)
+	badKey.PublicKey.Params.Mgf1Hash = commonpb.HashType_SHA512
+	serializedKey, _ = proto.Marshal(badKey)
+	if _, err := km.Primitive(serializedKey); err == nil {
+		t.Errorf("expect an error when MGF1 hash differs from signature hash")
+	}
+	// inconsistent private key
+	badKey = proto.Clone(key).(*rsassapsspb.RsaSsaPssPrivateKey)
+	badKey.D = random.GetRandomBytes(uint32(len(key.D)))
+	serializedKey, _ = proto.Marshal(badKey)
+	if _, err := km.Primitive(serializedKey); err == nil {
+		t.Errorf("expect an error when the private exponent is invalid")
+	}
+	// missing public key
+	badKey = proto.Clone(key).(*rsassapsspb.RsaSsaPssPrivateKey)
+	badKey.PublicKey = nil
+	serializedKey, _ = proto.Marshal(badKey)
+	if _, err := km.Primitive(serializedKey); err == nil {
+		t.Errorf("expect an error when the public key is missing")
+	}
+	if _, err := km.Primitive(nil); err == nil {
+		t.Errorf("expect an error when input is nil")
+	}
+	if _, err := km.Primitive([]byte{}); err == nil {
+		t.Errorf("expect an error when input is empty slice")
+	}
+}
+
+func TestRSASSAPSSSignerNewKeyData(t *testing.T) {
+	km, err := registry.GetKeyManager(testutil.RSASSAPSSSignerTypeURL)
+	if err != nil {
+		t.Fatalf("cannot obtain RSASSAPSSSigner key manager: %s", err)
+	}
+	serializedFormat, _ := proto.Marshal(newRSASSAPSSKeyFormat(commonpb.HashType_SHA512, 2048, 65537))
+	keyData, err := km.NewKeyData(serializedFormat)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if keyData.TypeUrl != testutil.RSASSAPSSSignerTypeURL {
+		t.Errorf("incorrect type url")
+	}
+	if keyData.KeyMaterialType != tinkpb.KeyData_ASYMMETRIC_PRIVATE {
+		t.Errorf("incorrect key material type")
+	}
+	if _, err := km.Primitive(keyData.Value); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+	if !km.DoesSupport(testutil.RSASSAPSSSignerTypeURL) || km.DoesSupport(testutil.RSASSAPSSVerifierTypeURL) {
+		t.Errorf("RSASSAPSSSigner key manager must support only %s", testutil.RSASSAPSSSignerTypeURL)
+	}
+	if km.TypeURL() != testutil.RSASSAPSSSignerTypeURL {
+		t.Errorf("incorrect type url")
+	}
+}
+
+func newRSASSAPSSKeyFormat(hashType commonpb.HashType, modulusSizeInBits uint32, e int64) *rsassapsspb.RsaSsaPssKeyFormat {
+	return &rsassapsspb.RsaSsaPssKeyFormat{
+		Params: &rsassapsspb.RsaSsaPssParams{
+			SigHash:    hashType,
+			Mgf1Hash:   hashType,
+			SaltLength: 32,
+		},
+		ModulusSizeInBits: modulusSizeInBits,
+		PublicExponent:    big.NewInt(e).Bytes(),
+	}
+}
+
+func newRSASSAPSSPrivateKey(t *testing.T, hashType commonpb.HashType) *rsassapsspb.RsaSsaPssPrivateKey {
+	km, err := registry.GetKeyManager(testutil.RSASSAPSSSignerTypeURL)
+	if err != nil {
+		t.Fatalf("cannot obtain RSASSAPSSSigner key manager: %s", err)
+	}
+	serializedFormat, _ := proto.Marshal(newRSASSAPSSKeyFormat(hashType, 2048, 65537))
+	m, err := km.NewKey(serializedFormat)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	return m.(*rsassapsspb.RsaSsaPssPrivateKey)
+}
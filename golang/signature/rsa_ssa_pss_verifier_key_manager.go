@@ -0,0 +1,103 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package signature
+
+import (
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+
+	"github.com/tsingson/tink/golang/core/registry"
+	"github.com/tsingson/tink/golang/keyset"
+	subtleSignature "github.com/tsingson/tink/golang/subtle/signature"
+	rsassapsspb "github.com/tsingson/tink/proto/rsa_ssa_pss_go_proto"
+	tinkpb "github.com/tsingson/tink/proto/tink_go_proto"
+)
+
+const (
+	rsaSSAPSSVerifierKeyVersion = 0
+	rsaSSAPSSVerifierTypeURL    = "type.googleapis.com/google.crypto.tink.RsaSsaPssPublicKey"
+)
+
+// common errors
+var errInvalidRSASSAPSSVerifierKey = fmt.Errorf("rsa_ssa_pss_verifier_key_manager: invalid key")
+var errRSASSAPSSVerifierNotImplemented = fmt.Errorf("rsa_ssa_pss_verifier_key_manager: not implemented")
+
+// rsaSSAPSSVerifierKeyManager is an implementation of KeyManager interface.
+// It doesn't support key generation.
+type rsaSSAPSSVerifierKeyManager struct{}
+
+// Assert that rsaSSAPSSVerifierKeyManager implements the KeyManager interface.
+var _ registry.KeyManager = (*rsaSSAPSSVerifierKeyManager)(nil)
+
+// newRSASSAPSSVerifierKeyManager creates a new rsaSSAPSSVerifierKeyManager.
+func newRSASSAPSSVerifierKeyManager() *rsaSSAPSSVerifierKeyManager {
+	return new(rsaSSAPSSVerifierKeyManager)
+}
+
+// Primitive creates an RSASSAPSSVerifier subtle for the given serialized RSASSAPSSPublicKey proto.
+func (km *rsaSSAPSSVerifierKeyManager) Primitive(serializedKey []byte) (interface{}, error) {
+	if len(serializedKey) == 0 {
+		return nil, errInvalidRSASSAPSSVerifierKey
+	}
+	key := new(rsassapsspb.RsaSsaPssPublicKey)
+	if err := proto.Unmarshal(serializedKey, key); err != nil {
+		return nil, errInvalidRSASSAPSSVerifierKey
+	}
+	if err := km.validateKey(key); err != nil {
+		return nil, fmt.Errorf("rsa_ssa_pss_verifier_key_manager: %s", err)
+	}
+	pub, err := newRSAPublicKey(key.N, key.E)
+	if err != nil {
+		return nil, fmt.Errorf("rsa_ssa_pss_verifier_key_manager: invalid key: %s", err)
+	}
+	sigHash, mgf1Hash, saltLength := getRSASSAPSSParamNames(key.Params)
+	ret, err := subtleSignature.NewRSASSAPSSVerifier(sigHash, mgf1Hash, saltLength, pub)
+	if err != nil {
+		return nil, fmt.Errorf("rsa_ssa_pss_verifier_key_manager: invalid key: %s", err)
+	}
+	return ret, nil
+}
+
+// NewKey is not implemented.
+func (km *rsaSSAPSSVerifierKeyManager) NewKey(serializedKeyFormat []byte) (proto.Message, error) {
+	return nil, errRSASSAPSSVerifierNotImplemented
+}
+
+// NewKeyData is not implemented.
+func (km *rsaSSAPSSVerifierKeyManager) NewKeyData(serializedKeyFormat []byte) (*tinkpb.KeyData, error) {
+	return nil, errRSASSAPSSVerifierNotImplemented
+}
+
+// DoesSupport indicates if this key manager supports the given key type.
+func (km *rsaSSAPSSVerifierKeyManager) DoesSupport(typeURL string) bool {
+	return typeURL == rsaSSAPSSVerifierTypeURL
+}
+
+// TypeURL returns the key type of keys managed by this key manager.
+func (km *rsaSSAPSSVerifierKeyManager) TypeURL() string {
+	return rsaSSAPSSVerifierTypeURL
+}
+
+// validateKey validates the given RSASSAPSSPublicKey.
+func (km *rsaSSAPSSVerifierKeyManager) validateKey(key *rsassapsspb.RsaSsaPssPublicKey) error {
+	if err := keyset.ValidateKeyVersion(key.Version, rsaSSAPSSVerifierKeyVersion); err != nil {
+		return err
+	}
+	if key.Params == nil {
+		return fmt.Errorf("missing params")
+	}
+	return validateRSASSAPSSParams(key.Params)
+}
@@ -0,0 +1,81 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package signature_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+
+	"github.com/tsingson/tink/golang/core/registry"
+	subtleSignature "github.com/tsingson/tink/golang/subtle/signature"
+	"github.com/tsingson/tink/golang/testutil"
+	commonpb "github.com/tsingson/tink/proto/common_go_proto"
+	rsassapsspb "github.com/tsingson/tink/proto/rsa_ssa_pss_go_proto"
+)
+
+func TestRSASSAPSSVerifierGetPrimitiveBasic(t *testing.T) {
+	km, err := registry.GetKeyManager(testutil.RSASSAPSSVerifierTypeURL)
+	if err != nil {
+		t.Fatalf("cannot obtain RSASSAPSSVerifier key manager: %s", err)
+	}
+	for _, hashType := range []commonpb.HashType{commonpb.HashType_SHA256, commonpb.HashType_SHA512} {
+		key := newRSASSAPSSPrivateKey(t, hashType)
+		serializedKey, _ := proto.Marshal(key.PublicKey)
+		p, err := km.Primitive(serializedKey)
+		if err != nil {
+			t.Errorf("unexpected error: %s", err)
+		}
+		var _ *subtleSignature.RSASSAPSSVerifier = p.(*subtleSignature.RSASSAPSSVerifier)
+	}
+}
+
+func TestRSASSAPSSVerifierGetPrimitiveWithInvalidInput(t *testing.T) {
+	km, err := registry.GetKeyManager(testutil.RSASSAPSSVerifierTypeURL)
+	if err != nil {
+		t.Fatalf("cannot obtain RSASSAPSSVerifier key manager: %s", err)
+	}
+	key := newRSASSAPSSPrivateKey(t, commonpb.HashType_SHA256).PublicKey
+	var testCases = []func(*rsassapsspb.RsaSsaPssPublicKey){
+		func(k *rsassapsspb.RsaSsaPssPublicKey) { k.Version = testutil.RSASSAPSSVerifierKeyVersion + 1 },
+		func(k *rsassapsspb.RsaSsaPssPublicKey) { k.Params.SigHash = commonpb.HashType_SHA1 },
+		func(k *rsassapsspb.RsaSsaPssPublicKey) { k.Params.Mgf1Hash = commonpb.HashType_SHA512 },
+		func(k *rsassapsspb.RsaSsaPssPublicKey) { k.Params.SaltLength = 0 },
+		func(k *rsassapsspb.RsaSsaPssPublicKey) { k.Params = nil },
+		func(k *rsassapsspb.RsaSsaPssPublicKey) { k.E = big.NewInt(3).Bytes() },
+		func(k *rsassapsspb.RsaSsaPssPublicKey) { k.E = big.NewInt(65538).Bytes() },
+		func(k *rsassapsspb.RsaSsaPssPublicKey) { k.N = k.N[:128] },
+	}
+	for i, modify := range testCases {
+		badKey := proto.Clone(key).(*rsassapsspb.RsaSsaPssPublicKey)
+		modify(badKey)
+		serializedKey, _ := proto.Marshal(badKey)
+		if _, err := km.Primitive(serializedKey); err == nil {
+			t.Errorf("expect an error in test case %d", i)
+		}
+	}
+	if _, err := km.Primitive(nil); err == nil {
+		t.Errorf("expect an error when input is nil")
+	}
+	if _, err := km.Primitive([]byte{}); err == nil {
+		t.Errorf("expect an error when input is empty slice")
+	}
+	// key generation is not supported
+	serializedFormat, _ := proto.Marshal(newRSASSAPSSKeyFormat(commonpb.HashType_SHA256, 2048, 65537))
+	if _, err := km.NewKey(serializedFormat); err == nil {
+		t.Errorf("expect an error from NewKey")
+	}
+}
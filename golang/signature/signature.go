@@ -13,38 +13,40 @@
 ////////////////////////////////////////////////////////////////////////////////
 
 // Package signature provides implementations of the Signer and Verifier primitives.
-// To sign data using Tink you can use ECDSA or ED25519 key templates.
+// To sign data using Tink you can use ECDSA, ED25519, RSA-SSA-PKCS1 or RSA-SSA-PSS key templates.
 // Example:
 //
 // package main
 //
 // import (
-//     "fmt"
 //
-//     "github.com/tsingson/tink/golang/signature"
-//     "github.com/tsingson/tink/golang/keyset"
+//	"fmt"
+//
+//	"github.com/tsingson/tink/golang/signature"
+//	"github.com/tsingson/tink/golang/keyset"
+//
 // )
 //
 // func main() {
 //
-//     kh, err := keyset.NewHandle(signature.ECDSAP256KeyTemplate()) // other key templates can also be used
-//     if err != nil {
-//         // handle the error
-//     }
+//	    kh, err := keyset.NewHandle(signature.ECDSAP256KeyTemplate()) // other key templates can also be used
+//	    if err != nil {
+//	        // handle the error
+//	    }
 //
-//     s := signature.NewSigner(kh)
+//	    s := signature.NewSigner(kh)
 //
-//     a , err := s.Sign([]byte("this data needs to be signed"))
-//     if err != nil {
-//         // handle error
-//     }
+//	    a , err := s.Sign([]byte("this data needs to be signed"))
+//	    if err != nil {
+//	        // handle error
+//	    }
 //
-//     v := signature.NewVerifier(kh)
+//	    v := signature.NewVerifier(kh)
 //
-//     if err := v.Verify(a, []byte("this data needs to be signed")); err != nil {
-//         // handle error
-//     }
-// }
+//	    if err := v.Verify(a, []byte("this data needs to be signed")); err != nil {
+//	        // handle error
+//	    }
+//	}
 package signature
 
 import (
@@ -53,20 +55,49 @@ import (
 	"github.com/tsingson/tink/golang/core/registry"
 )
 
+// Names of the primitives whose key managers this package adds to the Tink catalogue.
+const (
+	signerPrimitiveName   = "PublicKeySign"
+	verifierPrimitiveName = "PublicKeyVerify"
+)
+
 func init() {
 	// ECDSA
-	if err := registry.RegisterKeyManager(newECDSASignerKeyManager()); err != nil {
+	if err := registry.TinkCatalogue().Add(signerPrimitiveName, ecdsaSignerTypeURL, 0, func(*registry.Registry) registry.KeyManager { return newECDSASignerKeyManager() }); err != nil {
 		panic(fmt.Sprintf("signature.init() failed: %v", err))
 	}
-	if err := registry.RegisterKeyManager(newECDSAVerifierKeyManager()); err != nil {
+	if err := registry.TinkCatalogue().Add(verifierPrimitiveName, ecdsaVerifierTypeURL, 0, func(*registry.Registry) registry.KeyManager { return newECDSAVerifierKeyManager() }); err != nil {
 		panic(fmt.Sprintf("signature.init() failed: %v", err))
 	}
 
 	// ED25519
-	if err := registry.RegisterKeyManager(newED25519SignerKeyManager()); err != nil {
+	if err := registry.TinkCatalogue().Add(signerPrimitiveName, ed25519SignerTypeURL, 0, func(*registry.Registry) registry.KeyManager { return newED25519SignerKeyManager() }); err != nil {
+		panic(fmt.Sprintf("signature.init() failed: %v", err))
+	}
+	if err := registry.TinkCatalogue().Add(verifierPrimitiveName, ed25519VerifierTypeURL, 0, func(*registry.Registry) registry.KeyManager { return newED25519VerifierKeyManager() }); err != nil {
+		panic(fmt.Sprintf("signature.init() failed: %v", err))
+	}
+
+	// RSA-SSA-PKCS1
+	if err := registry.TinkCatalogue().Add(signerPrimitiveName, rsaSSAPKCS1SignerTypeURL, 0, func(*registry.Registry) registry.KeyManager { return newRSASSAPKCS1SignerKeyManager() }); err != nil {
+		panic(fmt.Sprintf("signature.init() failed: %v", err))
+	}
+	if err := registry.TinkCatalogue().Add(verifierPrimitiveName, rsaSSAPKCS1VerifierTypeURL, 0, func(*registry.Registry) registry.KeyManager { return newRSASSAPKCS1VerifierKeyManager() }); err != nil {
+		panic(fmt.Sprintf("signature.init() failed: %v", err))
+	}
+
+	// RSA-SSA-PSS
+	if err := registry.TinkCatalogue().Add(signerPrimitiveName, rsaSSAPSSSignerTypeURL, 0, func(*registry.Registry) registry.KeyManager { return newRSASSAPSSSignerKeyManager() }); err != nil {
+		panic(fmt.Sprintf("signature.init() failed: %v", err))
+	}
+	if err := registry.TinkCatalogue().Add(verifierPrimitiveName, rsaSSAPSSVerifierTypeURL, 0, func(*registry.Registry) registry.KeyManager { return newRSASSAPSSVerifierKeyManager() }); err != nil {
+		panic(fmt.Sprintf("signature.init() failed: %v", err))
+	}
+
+	if err := registry.RegisterPrimitiveWrapper(new(signerWrapper)); err != nil {
 		panic(fmt.Sprintf("signature.init() failed: %v", err))
 	}
-	if err := registry.RegisterKeyManager(newED25519VerifierKeyManager()); err != nil {
+	if err := registry.RegisterPrimitiveWrapper(new(verifierWrapper)); err != nil {
 		panic(fmt.Sprintf("signature.init() failed: %v", err))
 	}
 }
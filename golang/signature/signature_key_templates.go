@@ -15,10 +15,14 @@
 package signature
 
 import (
+	"math/big"
+
 	"github.com/golang/protobuf/proto"
 
 	commonpb "github.com/tsingson/tink/proto/common_go_proto"
 	ecdsapb "github.com/tsingson/tink/proto/ecdsa_go_proto"
+	rsassapkcs1pb "github.com/tsingson/tink/proto/rsa_ssa_pkcs1_go_proto"
+	rsassapsspb "github.com/tsingson/tink/proto/rsa_ssa_pss_go_proto"
 	tinkpb "github.com/tsingson/tink/proto/tink_go_proto"
 )
 
@@ -77,3 +81,88 @@ func ED25519KeyTemplate() *tinkpb.KeyTemplate {
 		TypeUrl: ed25519SignerTypeURL,
 	}
 }
+
+// RSASSAPKCS12048SHA256F4KeyTemplate is a KeyTemplate that generates a new RSA-SSA-PKCS1 private key with the following parameters:
+//   - Modulus size in bits: 2048
+//   - Hash function: SHA256
+//   - Public exponent: 65537 (aka F4)
+func RSASSAPKCS12048SHA256F4KeyTemplate() *tinkpb.KeyTemplate {
+	return createRSASSAPKCS1KeyTemplate(commonpb.HashType_SHA256, 2048)
+}
+
+// RSASSAPKCS13072SHA256F4KeyTemplate is a KeyTemplate that generates a new RSA-SSA-PKCS1 private key with the following parameters:
+//   - Modulus size in bits: 3072
+//   - Hash function: SHA256
+//   - Public exponent: 65537 (aka F4)
+func RSASSAPKCS13072SHA256F4KeyTemplate() *tinkpb.KeyTemplate {
+	return createRSASSAPKCS1KeyTemplate(commonpb.HashType_SHA256, 3072)
+}
+
+// RSASSAPKCS14096SHA512F4KeyTemplate is a KeyTemplate that generates a new RSA-SSA-PKCS1 private key with the following parameters:
+//   - Modulus size in bits: 4096
+//   - Hash function: SHA512
+//   - Public exponent: 65537 (aka F4)
+func RSASSAPKCS14096SHA512F4KeyTemplate() *tinkpb.KeyTemplate {
+	return createRSASSAPKCS1KeyTemplate(commonpb.HashType_SHA512, 4096)
+}
+
+// createRSASSAPKCS1KeyTemplate creates a KeyTemplate containing a RsaSsaPkcs1KeyFormat
+// with the given parameters and F4 as the public exponent.
+func createRSASSAPKCS1KeyTemplate(hashType commonpb.HashType, modulusSizeInBits uint32) *tinkpb.KeyTemplate {
+	format := &rsassapkcs1pb.RsaSsaPkcs1KeyFormat{
+		Params:            &rsassapkcs1pb.RsaSsaPkcs1Params{HashType: hashType},
+		ModulusSizeInBits: modulusSizeInBits,
+		PublicExponent:    big.NewInt(rsaF4).Bytes(),
+	}
+	serializedFormat, _ := proto.Marshal(format)
+	return &tinkpb.KeyTemplate{
+		TypeUrl: rsaSSAPKCS1SignerTypeURL,
+		Value:   serializedFormat,
+	}
+}
+
+// RSASSAPSS2048SHA256F4KeyTemplate is a KeyTemplate that generates a new RSA-SSA-PSS private key with the following parameters:
+//   - Modulus size in bits: 2048
+//   - Signature hash and MGF1 hash: SHA256
+//   - Salt length: 32 bytes
+//   - Public exponent: 65537 (aka F4)
+func RSASSAPSS2048SHA256F4KeyTemplate() *tinkpb.KeyTemplate {
+	return createRSASSAPSSKeyTemplate(commonpb.HashType_SHA256, 32, 2048)
+}
+
+// RSASSAPSS3072SHA256F4KeyTemplate is a KeyTemplate that generates a new RSA-SSA-PSS private key with the following parameters:
+//   - Modulus size in bits: 3072
+//   - Signature hash and MGF1 hash: SHA256
+//   - Salt length: 32 bytes
+//   - Public exponent: 65537 (aka F4)
+func RSASSAPSS3072SHA256F4KeyTemplate() *tinkpb.KeyTemplate {
+	return createRSASSAPSSKeyTemplate(commonpb.HashType_SHA256, 32, 3072)
+}
+
+// RSASSAPSS4096SHA512F4KeyTemplate is a KeyTemplate that generates a new RSA-SSA-PSS private key with the following parameters:
+//   - Modulus size in bits: 4096
+//   - Signature hash and MGF1 hash: SHA512
+//   - Salt length: 64 bytes
+//   - Public exponent: 65537 (aka F4)
+func RSASSAPSS4096SHA512F4KeyTemplate() *tinkpb.KeyTemplate {
+	return createRSASSAPSSKeyTemplate(commonpb.HashType_SHA512, 64, 4096)
+}
+
+// createRSASSAPSSKeyTemplate creates a KeyTemplate containing a RsaSsaPssKeyFormat
+// with the given parameters and F4 as the public exponent.
+func createRSASSAPSSKeyTemplate(hashType commonpb.HashType, saltLength int32, modulusSizeInBits uint32) *tinkpb.KeyTemplate {
+	format := &rsassapsspb.RsaSsaPssKeyFormat{
+		Params: &rsassapsspb.RsaSsaPssParams{
+			SigHash:    hashType,
+			Mgf1Hash:   hashType,
+			SaltLength: saltLength,
+		},
+		ModulusSizeInBits: modulusSizeInBits,
+		PublicExponent:    big.NewInt(rsaF4).Bytes(),
+	}
+	serializedFormat, _ := proto.Marshal(format)
+	return &tinkpb.KeyTemplate{
+		TypeUrl: rsaSSAPSSSignerTypeURL,
+		Value:   serializedFormat,
+	}
+}
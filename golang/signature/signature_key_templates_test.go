@@ -16,14 +16,18 @@ package signature_test
 
 import (
 	"fmt"
+	"math/big"
 	"testing"
 
 	"github.com/golang/protobuf/proto"
 
+	"github.com/tsingson/tink/golang/keyset"
 	"github.com/tsingson/tink/golang/signature"
 	"github.com/tsingson/tink/golang/testutil"
 	commonpb "github.com/tsingson/tink/proto/common_go_proto"
 	ecdsapb "github.com/tsingson/tink/proto/ecdsa_go_proto"
+	rsassapkcs1pb "github.com/tsingson/tink/proto/rsa_ssa_pkcs1_go_proto"
+	rsassapsspb "github.com/tsingson/tink/proto/rsa_ssa_pss_go_proto"
 	tinkpb "github.com/tsingson/tink/proto/tink_go_proto"
 )
 
@@ -82,3 +86,112 @@ func checkECDSAKeyTemplate(template *tinkpb.KeyTemplate,
 	}
 	return nil
 }
+
+func TestRSASSAPKCS1KeyTemplates(t *testing.T) {
+	var testCases = []struct {
+		name              string
+		template          *tinkpb.KeyTemplate
+		hashType          commonpb.HashType
+		modulusSizeInBits uint32
+	}{
+		{"RSA-SSA-PKCS1 2048 SHA256", signature.RSASSAPKCS12048SHA256F4KeyTemplate(), commonpb.HashType_SHA256, 2048},
+		{"RSA-SSA-PKCS1 3072 SHA256", signature.RSASSAPKCS13072SHA256F4KeyTemplate(), commonpb.HashType_SHA256, 3072},
+		{"RSA-SSA-PKCS1 4096 SHA512", signature.RSASSAPKCS14096SHA512F4KeyTemplate(), commonpb.HashType_SHA512, 4096},
+	}
+	for _, tc := range testCases {
+		if tc.template.TypeUrl != testutil.RSASSAPKCS1SignerTypeURL {
+			t.Errorf("%s: incorrect typeurl: expect %s, got %s", tc.name, testutil.RSASSAPKCS1SignerTypeURL, tc.template.TypeUrl)
+		}
+		format := new(rsassapkcs1pb.RsaSsaPkcs1KeyFormat)
+		if err := proto.Unmarshal(tc.template.Value, format); err != nil {
+			t.Errorf("%s: cannot unmarshal key format: %s", tc.name, err)
+			continue
+		}
+		if format.Params.HashType != tc.hashType {
+			t.Errorf("%s: incorrect hash type: expect %d, got %d", tc.name, tc.hashType, format.Params.HashType)
+		}
+		if err := checkRSAKeyFormat(format.ModulusSizeInBits, format.PublicExponent, tc.modulusSizeInBits); err != nil {
+			t.Errorf("%s: %s", tc.name, err)
+		}
+	}
+	if err := testSignVerifyWithTemplate(signature.RSASSAPKCS12048SHA256F4KeyTemplate()); err != nil {
+		t.Errorf("%s", err)
+	}
+}
+
+func TestRSASSAPSSKeyTemplates(t *testing.T) {
+	var testCases = []struct {
+		name              string
+		template          *tinkpb.KeyTemplate
+		hashType          commonpb.HashType
+		saltLength        int32
+		modulusSizeInBits uint32
+	}{
+		{"RSA-SSA-PSS 2048 SHA256", signature.RSASSAPSS2048SHA256F4KeyTemplate(), commonpb.HashType_SHA256, 32, 2048},
+		{"RSA-SSA-PSS 3072 SHA256", signature.RSASSAPSS3072SHA256F4KeyTemplate(), commonpb.HashType_SHA256, 32, 3072},
+		{"RSA-SSA-PSS 4096 SHA512", signature.RSASSAPSS4096SHA512F4KeyTemplate(), commonpb.HashType_SHA512, 64, 4096},
+	}
+	for _, tc := range testCases {
+		if tc.template.TypeUrl != testutil.RSASSAPSSSignerTypeURL {
+			t.Errorf("%s: incorrect typeurl: expect %s, got %s", tc.name, testutil.RSASSAPSSSignerTypeURL, tc.template.TypeUrl)
+		}
+		format := new(rsassapsspb.RsaSsaPssKeyFormat)
+		if err := proto.Unmarshal(tc.template.Value, format); err != nil {
+			t.Errorf("%s: cannot unmarshal key format: %s", tc.name, err)
+			continue
+		}
+		params := format.Params
+		if params.SigHash != tc.hashType || params.Mgf1Hash != tc.hashType {
+			t.Errorf("%s: incorrect hash type: expect %d, got %d and %d", tc.name, tc.hashType, params.SigHash, params.Mgf1Hash)
+		}
+		if params.SaltLength != tc.saltLength {
+			t.Errorf("%s: incorrect salt length: expect %d, got %d", tc.name, tc.saltLength, params.SaltLength)
+		}
+		if err := checkRSAKeyFormat(format.ModulusSizeInBits, format.PublicExponent, tc.modulusSizeInBits); err != nil {
+			t.Errorf("%s: %s", tc.name, err)
+		}
+	}
+	if err := testSignVerifyWithTemplate(signature.RSASSAPSS2048SHA256F4KeyTemplate()); err != nil {
+		t.Errorf("%s", err)
+	}
+}
+
+func checkRSAKeyFormat(modulusSizeInBits uint32, publicExponent []byte, expectedModulusSizeInBits uint32) error {
+	if modulusSizeInBits != expectedModulusSizeInBits {
+		return fmt.Errorf("incorrect modulus size: expect %d, got %d", expectedModulusSizeInBits, modulusSizeInBits)
+	}
+	if e := new(big.Int).SetBytes(publicExponent); e.Int64() != 65537 {
+		return fmt.Errorf("incorrect public exponent: expect 65537, got %s", e)
+	}
+	return nil
+}
+
+// testSignVerifyWithTemplate generates a keyset from the given template, signs
+// some data with it, and verifies the signature with the public keyset.
+func testSignVerifyWithTemplate(template *tinkpb.KeyTemplate) error {
+	privHandle, err := keyset.NewHandle(template)
+	if err != nil {
+		return fmt.Errorf("cannot create keyset handle: %s", err)
+	}
+	pubHandle, err := privHandle.Public()
+	if err != nil {
+		return fmt.Errorf("cannot get public keyset handle: %s", err)
+	}
+	signer, err := signature.NewSigner(privHandle)
+	if err != nil {
+		return fmt.Errorf("cannot create signer: %s", err)
+	}
+	verifier, err := signature.NewVerifier(pubHandle)
+	if err != nil {
+		return fmt.Errorf("cannot create verifier: %s", err)
+	}
+	data := []byte("this data needs to be signed")
+	sig, err := signer.Sign(data)
+	if err != nil {
+		return fmt.Errorf("signing failed: %s", err)
+	}
+	if err := verifier.Verify(sig, data); err != nil {
+		return fmt.Errorf("verification failed: %s", err)
+	}
+	return nil
+}
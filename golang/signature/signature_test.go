@@ -33,4 +33,17 @@ func TestSignatureInit(t *testing.T) {
 	if err != nil {
 		t.Errorf("unexpected error: %s", err)
 	}
+
+	// check for RSA-SSA-PKCS1 and RSA-SSA-PSS key managers
+	typeURLs := []string{
+		testutil.RSASSAPKCS1SignerTypeURL,
+		testutil.RSASSAPKCS1VerifierTypeURL,
+		testutil.RSASSAPSSSignerTypeURL,
+		testutil.RSASSAPSSVerifierTypeURL,
+	}
+	for _, typeURL := range typeURLs {
+		if _, err := registry.GetKeyManager(typeURL); err != nil {
+			t.Errorf("unexpected error: %s", err)
+		}
+	}
 }
@@ -21,6 +21,7 @@ import (
 	"github.com/tsingson/tink/golang/core/primitiveset"
 	"github.com/tsingson/tink/golang/core/registry"
 	"github.com/tsingson/tink/golang/keyset"
+	"github.com/tsingson/tink/golang/monitoring"
 	"github.com/tsingson/tink/golang/tink"
 	tinkpb "github.com/tsingson/tink/proto/tink_go_proto"
 )
@@ -36,6 +37,50 @@ func NewSignerWithKeyManager(h *keyset.Handle, km registry.KeyManager) (tink.Sig
 	if err != nil {
 		return nil, fmt.Errorf("public_key_sign_factory: cannot obtain primitive set: %s", err)
 	}
+	return wrapSigner(registry.Default(), ps)
+}
+
+// NewSignerWithRegistry returns a Signer primitive from the given keyset handle, using the key
+// managers of the given registry.
+func NewSignerWithRegistry(h *keyset.Handle, r *registry.Registry) (tink.Signer, error) {
+	ps, err := h.PrimitivesWithRegistry(r)
+	if err != nil {
+		return nil, fmt.Errorf("public_key_sign_factory: cannot obtain primitive set: %s", err)
+	}
+	return wrapSigner(r, ps)
+}
+
+// wrapSigner combines the primitives in ps into a Signer using the primitive wrapper
+// registered in r.
+func wrapSigner(r *registry.Registry, ps *primitiveset.PrimitiveSet) (tink.Signer, error) {
+	p, err := r.Wrap(signerPrimitiveName, ps)
+	if err != nil {
+		return nil, fmt.Errorf("public_key_sign_factory: cannot wrap primitive set: %s", err)
+	}
+	ret, ok := p.(tink.Signer)
+	if !ok {
+		return nil, fmt.Errorf("public_key_sign_factory: not a Signer primitive")
+	}
+	return ret, nil
+}
+
+// signerWrapper is the registry.PrimitiveWrapper for the Signer primitive.
+type signerWrapper struct{}
+
+// Asserts that signerWrapper implements the PrimitiveWrapper interface.
+var _ registry.PrimitiveWrapper = (*signerWrapper)(nil)
+
+// PrimitiveName returns the name of the Signer primitive.
+func (w *signerWrapper) PrimitiveName() string {
+	return signerPrimitiveName
+}
+
+// Wrap returns a Signer that uses the primitives in the given primitive set, which must
+// all be Signer primitives.
+func (w *signerWrapper) Wrap(ps *primitiveset.PrimitiveSet) (interface{}, error) {
+	if !ps.All(func(p interface{}) bool { _, ok := p.(tink.Signer); return ok }) {
+		return nil, fmt.Errorf("public_key_sign_factory: not a Signer primitive")
+	}
 	return newSignerSet(ps), nil
 }
 
@@ -66,11 +111,9 @@ func (s *signerSet) Sign(data []byte) ([]byte, error) {
 		signedData = data
 	}
 	signature, err := signer.Sign(signedData)
+	s.ps.Log(primary, signerPrimitiveName, monitoring.Sign, len(data), err == nil)
 	if err != nil {
 		return nil, err
 	}
-	var ret []byte
-	ret = append(ret, primary.Prefix...)
-	ret = append(ret, signature...)
-	return ret, nil
+	return primary.Prefixed(signature), nil
 }
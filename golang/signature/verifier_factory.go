@@ -22,6 +22,7 @@ import (
 	"github.com/tsingson/tink/golang/core/primitiveset"
 	"github.com/tsingson/tink/golang/core/registry"
 	"github.com/tsingson/tink/golang/keyset"
+	"github.com/tsingson/tink/golang/monitoring"
 	"github.com/tsingson/tink/golang/tink"
 	tinkpb "github.com/tsingson/tink/proto/tink_go_proto"
 )
@@ -37,10 +38,53 @@ func NewVerifierWithKeyManager(h *keyset.Handle, km registry.KeyManager) (tink.V
 	if err != nil {
 		return nil, fmt.Errorf("verifier_factory: cannot obtain primitive set: %s", err)
 	}
-	var ret = newVerifierSet(ps)
+	return wrapVerifier(registry.Default(), ps)
+}
+
+// NewVerifierWithRegistry returns a Verifier primitive from the given keyset handle, using the key
+// managers of the given registry.
+func NewVerifierWithRegistry(h *keyset.Handle, r *registry.Registry) (tink.Verifier, error) {
+	ps, err := h.PrimitivesWithRegistry(r)
+	if err != nil {
+		return nil, fmt.Errorf("verifier_factory: cannot obtain primitive set: %s", err)
+	}
+	return wrapVerifier(r, ps)
+}
+
+// wrapVerifier combines the primitives in ps into a Verifier using the primitive wrapper
+// registered in r.
+func wrapVerifier(r *registry.Registry, ps *primitiveset.PrimitiveSet) (tink.Verifier, error) {
+	p, err := r.Wrap(verifierPrimitiveName, ps)
+	if err != nil {
+		return nil, fmt.Errorf("verifier_factory: cannot wrap primitive set: %s", err)
+	}
+	ret, ok := p.(tink.Verifier)
+	if !ok {
+		return nil, fmt.Errorf("verifier_factory: not a Verifier primitive")
+	}
 	return ret, nil
 }
 
+// verifierWrapper is the registry.PrimitiveWrapper for the Verifier primitive.
+type verifierWrapper struct{}
+
+// Asserts that verifierWrapper implements the PrimitiveWrapper interface.
+var _ registry.PrimitiveWrapper = (*verifierWrapper)(nil)
+
+// PrimitiveName returns the name of the Verifier primitive.
+func (w *verifierWrapper) PrimitiveName() string {
+	return verifierPrimitiveName
+}
+
+// Wrap returns a Verifier that uses the primitives in the given primitive set, which must
+// all be Verifier primitives.
+func (w *verifierWrapper) Wrap(ps *primitiveset.PrimitiveSet) (interface{}, error) {
+	if !ps.All(func(p interface{}) bool { _, ok := p.(tink.Verifier); return ok }) {
+		return nil, fmt.Errorf("verifier_factory: not a Verifier primitive")
+	}
+	return newVerifierSet(ps), nil
+}
+
 // verifierSet is an Signer implementation that uses the
 // underlying primitive set for signing.
 type verifierSet struct {
@@ -62,35 +106,20 @@ var errInvalidSignature = errors.New("verifier_factory: invalid signature")
 func (v *verifierSet) Verify(signature, data []byte) error {
 	prefixSize := cryptofmt.NonRawPrefixSize
 	if len(signature) < prefixSize {
+		v.ps.LogFailure(signature, verifierPrimitiveName, monitoring.Verify, len(data))
 		return errInvalidSignature
 	}
-	// try non-raw keys
-	prefix := signature[:prefixSize]
-	signatureNoPrefix := signature[prefixSize:]
-	entries, err := v.ps.EntriesForPrefix(string(prefix))
-	if err == nil {
-		for i := 0; i < len(entries); i++ {
-			var signedData []byte
-			if entries[i].PrefixType == tinkpb.OutputPrefixType_LEGACY {
-				signedData = append(data, cryptofmt.LegacyStartByte)
-			} else {
-				signedData = data
-			}
-			var verifier = (entries[i].Primitive).(tink.Verifier)
-			if err = verifier.Verify(signatureNoPrefix, signedData); err == nil {
-				return nil
-			}
+	for _, c := range v.ps.Candidates(signature) {
+		signedData := data
+		if c.Entry.PrefixType == tinkpb.OutputPrefixType_LEGACY {
+			signedData = append(append([]byte{}, data...), cryptofmt.LegacyStartByte)
 		}
-	}
-	// try raw keys
-	entries, err = v.ps.RawEntries()
-	if err == nil {
-		for i := 0; i < len(entries); i++ {
-			var verifier = (entries[i].Primitive).(tink.Verifier)
-			if err = verifier.Verify(signature, data); err == nil {
-				return nil
-			}
+		var verifier = (c.Entry.Primitive).(tink.Verifier)
+		if err := verifier.Verify(c.Output, signedData); err == nil {
+			v.ps.Log(c.Entry, verifierPrimitiveName, monitoring.Verify, len(data), true)
+			return nil
 		}
 	}
+	v.ps.LogFailure(signature, verifierPrimitiveName, monitoring.Verify, len(data))
 	return errInvalidSignature
 }
@@ -0,0 +1,174 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package streamingaead
+
+import (
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+
+	"github.com/tsingson/tink/golang/core/registry"
+	"github.com/tsingson/tink/golang/keyset"
+	"github.com/tsingson/tink/golang/subtle/aead"
+	"github.com/tsingson/tink/golang/subtle/mac"
+	"github.com/tsingson/tink/golang/subtle/random"
+	subtle "github.com/tsingson/tink/golang/subtle/streamingaead"
+	ctrhmacpb "github.com/tsingson/tink/proto/aes_ctr_hmac_streaming_go_proto"
+	commonpb "github.com/tsingson/tink/proto/common_go_proto"
+	tinkpb "github.com/tsingson/tink/proto/tink_go_proto"
+)
+
+const (
+	aesCTRHMACKeyVersion = 0
+	aesCTRHMACTypeURL    = "type.googleapis.com/google.crypto.tink.AesCtrHmacStreamingKey"
+)
+
+// common errors
+var errInvalidAESCTRHMACKey = fmt.Errorf("aes_ctr_hmac_key_manager: invalid key")
+var errInvalidAESCTRHMACKeyFormat = fmt.Errorf("aes_ctr_hmac_key_manager: invalid key format")
+
+// aesCTRHMACKeyManager is an implementation of KeyManager interface.
+// It generates new AesCtrHmacStreamingKey keys and produces new instances of AESCTRHMAC subtle.
+type aesCTRHMACKeyManager struct{}
+
+// Assert that aesCTRHMACKeyManager implements the KeyManager interface.
+var _ registry.KeyManager = (*aesCTRHMACKeyManager)(nil)
+
+// newAESCTRHMACKeyManager creates a new aesCTRHMACKeyManager.
+func newAESCTRHMACKeyManager() *aesCTRHMACKeyManager {
+	return new(aesCTRHMACKeyManager)
+}
+
+// Primitive creates an AESCTRHMAC subtle for the given serialized AesCtrHmacStreamingKey proto.
+func (km *aesCTRHMACKeyManager) Primitive(serializedKey []byte) (interface{}, error) {
+	if len(serializedKey) == 0 {
+		return nil, errInvalidAESCTRHMACKey
+	}
+	key := new(ctrhmacpb.AesCtrHmacStreamingKey)
+	if err := proto.Unmarshal(serializedKey, key); err != nil {
+		return nil, errInvalidAESCTRHMACKey
+	}
+	if err := km.validateKey(key); err != nil {
+		return nil, err
+	}
+	ret, err := subtle.NewAESCTRHMAC(
+		key.KeyValue,
+		commonpb.HashType_name[int32(key.Params.HkdfHashType)],
+		int(key.Params.DerivedKeySize),
+		commonpb.HashType_name[int32(key.Params.HmacParams.Hash)],
+		int(key.Params.HmacParams.TagSize),
+		int(key.Params.CiphertextSegmentSize),
+		// no first segment offset
+		0)
+	if err != nil {
+		return nil, fmt.Errorf("aes_ctr_hmac_key_manager: cannot create new primitive: %s", err)
+	}
+	return ret, nil
+}
+
+// NewKey creates a new key according to specification the given serialized AesCtrHmacStreamingKeyFormat.
+func (km *aesCTRHMACKeyManager) NewKey(serializedKeyFormat []byte) (proto.Message, error) {
+	if len(serializedKeyFormat) == 0 {
+		return nil, errInvalidAESCTRHMACKeyFormat
+	}
+	keyFormat := new(ctrhmacpb.AesCtrHmacStreamingKeyFormat)
+	if err := proto.Unmarshal(serializedKeyFormat, keyFormat); err != nil {
+		return nil, errInvalidAESCTRHMACKeyFormat
+	}
+	if err := km.validateKeyFormat(keyFormat); err != nil {
+		return nil, fmt.Errorf("aes_ctr_hmac_key_manager: invalid key format: %s", err)
+	}
+	return &ctrhmacpb.AesCtrHmacStreamingKey{
+		Version:  aesCTRHMACKeyVersion,
+		KeyValue: random.GetRandomBytes(keyFormat.KeySize),
+		Params:   keyFormat.Params,
+	}, nil
+}
+
+// NewKeyData creates a new KeyData according to specification in the given serialized
+// AesCtrHmacStreamingKeyFormat.
+// It should be used solely by the key management API.
+func (km *aesCTRHMACKeyManager) NewKeyData(serializedKeyFormat []byte) (*tinkpb.KeyData, error) {
+	key, err := km.NewKey(serializedKeyFormat)
+	if err != nil {
+		return nil, err
+	}
+	serializedKey, err := proto.Marshal(key)
+	if err != nil {
+		return nil, err
+	}
+	return &tinkpb.KeyData{
+		TypeUrl:         aesCTRHMACTypeURL,
+		Value:           serializedKey,
+		KeyMaterialType: tinkpb.KeyData_SYMMETRIC,
+	}, nil
+}
+
+// DoesSupport indicates if this key manager supports the given key type.
+func (km *aesCTRHMACKeyManager) DoesSupport(typeURL string) bool {
+	return typeURL == aesCTRHMACTypeURL
+}
+
+// TypeURL returns the key type of keys managed by this key manager.
+func (km *aesCTRHMACKeyManager) TypeURL() string {
+	return aesCTRHMACTypeURL
+}
+
+// validateKey validates the given AesCtrHmacStreamingKey.
+func (km *aesCTRHMACKeyManager) validateKey(key *ctrhmacpb.AesCtrHmacStreamingKey) error {
+	if err := keyset.ValidateKeyVersion(key.Version, aesCTRHMACKeyVersion); err != nil {
+		return fmt.Errorf("aes_ctr_hmac_key_manager: %s", err)
+	}
+	if err := km.validateParams(key.Params); err != nil {
+		return fmt.Errorf("aes_ctr_hmac_key_manager: %s", err)
+	}
+	if err := validateMainKeySize(uint32(len(key.KeyValue)), key.Params.DerivedKeySize); err != nil {
+		return fmt.Errorf("aes_ctr_hmac_key_manager: %s", err)
+	}
+	return nil
+}
+
+// validateKeyFormat validates the given AesCtrHmacStreamingKeyFormat.
+func (km *aesCTRHMACKeyManager) validateKeyFormat(format *ctrhmacpb.AesCtrHmacStreamingKeyFormat) error {
+	if err := km.validateParams(format.Params); err != nil {
+		return err
+	}
+	return validateMainKeySize(format.KeySize, format.Params.DerivedKeySize)
+}
+
+// validateParams validates the given AesCtrHmacStreamingParams.
+func (km *aesCTRHMACKeyManager) validateParams(params *ctrhmacpb.AesCtrHmacStreamingParams) error {
+	if params == nil {
+		return fmt.Errorf("missing params")
+	}
+	if err := aead.ValidateAESKeySize(params.DerivedKeySize); err != nil {
+		return err
+	}
+	if params.HkdfHashType == commonpb.HashType_UNKNOWN_HASH {
+		return fmt.Errorf("unknown HKDF hash type")
+	}
+	if params.HmacParams == nil {
+		return fmt.Errorf("missing HMAC params")
+	}
+	hashType := commonpb.HashType_name[int32(params.HmacParams.Hash)]
+	if err := mac.ValidateHMACParams(hashType, subtle.AESCTRHMACHMACKeySize, params.HmacParams.TagSize); err != nil {
+		return err
+	}
+	// header (1 + derived key size + 7 bytes nonce prefix) + tag + at least one byte
+	if params.CiphertextSegmentSize < params.DerivedKeySize+params.HmacParams.TagSize+9 {
+		return fmt.Errorf("ciphertext segment size must be at least derived key size + tag size + 9")
+	}
+	return nil
+}
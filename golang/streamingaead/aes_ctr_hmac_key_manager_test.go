@@ -0,0 +1,221 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package streamingaead_test
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+
+	"github.com/tsingson/tink/golang/core/registry"
+	"github.com/tsingson/tink/golang/subtle/random"
+	subtle "github.com/tsingson/tink/golang/subtle/streamingaead"
+	"github.com/tsingson/tink/golang/testutil"
+	ctrhmacpb "github.com/tsingson/tink/proto/aes_ctr_hmac_streaming_go_proto"
+	commonpb "github.com/tsingson/tink/proto/common_go_proto"
+	hmacpb "github.com/tsingson/tink/proto/hmac_go_proto"
+	tinkpb "github.com/tsingson/tink/proto/tink_go_proto"
+)
+
+func newAESCTRHMACParams(derivedKeySize, tagSize, segmentSize uint32) *ctrhmacpb.AesCtrHmacStreamingParams {
+	return &ctrhmacpb.AesCtrHmacStreamingParams{
+		CiphertextSegmentSize: segmentSize,
+		DerivedKeySize:        derivedKeySize,
+		HkdfHashType:          commonpb.HashType_SHA256,
+		HmacParams: &hmacpb.HmacParams{
+			Hash:    commonpb.HashType_SHA256,
+			TagSize: tagSize,
+		},
+	}
+}
+
+func newAESCTRHMACKey(keySize, derivedKeySize uint32) *ctrhmacpb.AesCtrHmacStreamingKey {
+	return &ctrhmacpb.AesCtrHmacStreamingKey{
+		Version:  testutil.AESCTRHMACKeyVersion,
+		KeyValue: random.GetRandomBytes(keySize),
+		Params:   newAESCTRHMACParams(derivedKeySize, 32, 4096),
+	}
+}
+
+func TestAESCTRHMACGetPrimitiveBasic(t *testing.T) {
+	keyManager, err := registry.GetKeyManager(testutil.AESCTRHMACTypeURL)
+	if err != nil {
+		t.Fatalf("cannot obtain AES-CTR-HMAC key manager: %s", err)
+	}
+	for _, keySize := range []uint32{16, 32} {
+		key := newAESCTRHMACKey(keySize, keySize)
+		serializedKey, _ := proto.Marshal(key)
+		p, err := keyManager.Primitive(serializedKey)
+		if err != nil {
+			t.Errorf("unexpected error: %s", err)
+			continue
+		}
+		a, ok := p.(*subtle.AESCTRHMAC)
+		if !ok {
+			t.Errorf("primitive is not AESCTRHMAC")
+			continue
+		}
+		if err := encryptDecrypt(a, a, 10000); err != nil {
+			t.Errorf("%s", err)
+		}
+	}
+}
+
+func TestAESCTRHMACGetPrimitiveWithInvalidInput(t *testing.T) {
+	keyManager, err := registry.GetKeyManager(testutil.AESCTRHMACTypeURL)
+	if err != nil {
+		t.Fatalf("cannot obtain AES-CTR-HMAC key manager: %s", err)
+	}
+	badVersion := newAESCTRHMACKey(16, 16)
+	badVersion.Version++
+	noParams := newAESCTRHMACKey(16, 16)
+	noParams.Params = nil
+	noHMACParams := newAESCTRHMACKey(16, 16)
+	noHMACParams.Params.HmacParams = nil
+	unknownHash := newAESCTRHMACKey(16, 16)
+	unknownHash.Params.HkdfHashType = commonpb.HashType_UNKNOWN_HASH
+	unknownTagHash := newAESCTRHMACKey(16, 16)
+	unknownTagHash.Params.HmacParams.Hash = commonpb.HashType_UNKNOWN_HASH
+	shortTag := newAESCTRHMACKey(16, 16)
+	shortTag.Params.HmacParams.TagSize = 9
+	longTag := newAESCTRHMACKey(16, 16)
+	longTag.Params.HmacParams.TagSize = 33
+	smallSegment := newAESCTRHMACKey(16, 16)
+	smallSegment.Params.CiphertextSegmentSize = 56
+	testKeys := []*ctrhmacpb.AesCtrHmacStreamingKey{
+		badVersion,
+		noParams,
+		noHMACParams,
+		unknownHash,
+		unknownTagHash,
+		shortTag,
+		longTag,
+		smallSegment,
+		// key shorter than 16 bytes
+		newAESCTRHMACKey(15, 16),
+		// key shorter than the derived keys
+		newAESCTRHMACKey(16, 32),
+		// invalid derived key size
+		newAESCTRHMACKey(32, 24),
+	}
+	for i, key := range testKeys {
+		serializedKey, _ := proto.Marshal(key)
+		if _, err := keyManager.Primitive(serializedKey); err == nil {
+			t.Errorf("expect an error in test case %d", i)
+		}
+	}
+	if _, err := keyManager.Primitive(nil); err == nil {
+		t.Errorf("expect an error when input is nil")
+	}
+	if _, err := keyManager.Primitive([]byte{}); err == nil {
+		t.Errorf("expect an error when input is empty")
+	}
+}
+
+func TestAESCTRHMACNewKeyMultipleTimes(t *testing.T) {
+	keyManager, err := registry.GetKeyManager(testutil.AESCTRHMACTypeURL)
+	if err != nil {
+		t.Fatalf("cannot obtain AES-CTR-HMAC key manager: %s", err)
+	}
+	format := &ctrhmacpb.AesCtrHmacStreamingKeyFormat{
+		KeySize: 32,
+		Params:  newAESCTRHMACParams(32, 32, 4096),
+	}
+	serializedFormat, _ := proto.Marshal(format)
+	keys := make(map[string]bool)
+	nTest := 26
+	for i := 0; i < nTest; i++ {
+		key, _ := keyManager.NewKey(serializedFormat)
+		serializedKey, _ := proto.Marshal(key)
+		keys[string(serializedKey)] = true
+
+		keyData, _ := keyManager.NewKeyData(serializedFormat)
+		keys[string(keyData.Value)] = true
+	}
+	if len(keys) != nTest*2 {
+		t.Errorf("key is repeated")
+	}
+}
+
+func TestAESCTRHMACNewKeyData(t *testing.T) {
+	keyManager, err := registry.GetKeyManager(testutil.AESCTRHMACTypeURL)
+	if err != nil {
+		t.Fatalf("cannot obtain AES-CTR-HMAC key manager: %s", err)
+	}
+	format := &ctrhmacpb.AesCtrHmacStreamingKeyFormat{
+		KeySize: 16,
+		Params:  newAESCTRHMACParams(16, 16, 4096),
+	}
+	serializedFormat, _ := proto.Marshal(format)
+	keyData, err := keyManager.NewKeyData(serializedFormat)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if keyData.TypeUrl != testutil.AESCTRHMACTypeURL {
+		t.Errorf("incorrect type url")
+	}
+	if keyData.KeyMaterialType != tinkpb.KeyData_SYMMETRIC {
+		t.Errorf("incorrect key material type")
+	}
+	key := new(ctrhmacpb.AesCtrHmacStreamingKey)
+	if err := proto.Unmarshal(keyData.Value, key); err != nil {
+		t.Fatalf("incorrect key value")
+	}
+	if len(key.KeyValue) != 16 || !proto.Equal(key.Params, format.Params) {
+		t.Errorf("key does not match the key format: %s", key)
+	}
+	if _, err := keyManager.Primitive(keyData.Value); err != nil {
+		t.Errorf("cannot get primitive from new key: %s", err)
+	}
+}
+
+func TestAESCTRHMACNewKeyWithInvalidInput(t *testing.T) {
+	keyManager, err := registry.GetKeyManager(testutil.AESCTRHMACTypeURL)
+	if err != nil {
+		t.Fatalf("cannot obtain AES-CTR-HMAC key manager: %s", err)
+	}
+	formats := []*ctrhmacpb.AesCtrHmacStreamingKeyFormat{
+		{KeySize: 16},
+		{KeySize: 15, Params: newAESCTRHMACParams(16, 32, 4096)},
+		{KeySize: 16, Params: newAESCTRHMACParams(32, 32, 4096)},
+		{KeySize: 16, Params: newAESCTRHMACParams(16, 8, 4096)},
+		{KeySize: 16, Params: newAESCTRHMACParams(16, 32, 40)},
+	}
+	for i, format := range formats {
+		serializedFormat, _ := proto.Marshal(format)
+		if _, err := keyManager.NewKey(serializedFormat); err == nil {
+			t.Errorf("expect an error in test case %d", i)
+		}
+	}
+	if _, err := keyManager.NewKey(nil); err == nil {
+		t.Errorf("expect an error when input is nil")
+	}
+}
+
+func TestAESCTRHMACDoesSupport(t *testing.T) {
+	keyManager, err := registry.GetKeyManager(testutil.AESCTRHMACTypeURL)
+	if err != nil {
+		t.Fatalf("cannot obtain AES-CTR-HMAC key manager: %s", err)
+	}
+	if !keyManager.DoesSupport(testutil.AESCTRHMACTypeURL) {
+		t.Errorf("AESCTRHMACKeyManager must support %s", testutil.AESCTRHMACTypeURL)
+	}
+	if keyManager.DoesSupport("some bad type") {
+		t.Errorf("AESCTRHMACKeyManager must support only %s", testutil.AESCTRHMACTypeURL)
+	}
+	if keyManager.TypeURL() != testutil.AESCTRHMACTypeURL {
+		t.Errorf("incorrect key type")
+	}
+}
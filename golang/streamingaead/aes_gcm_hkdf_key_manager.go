@@ -0,0 +1,173 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package streamingaead
+
+import (
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+
+	"github.com/tsingson/tink/golang/core/registry"
+	"github.com/tsingson/tink/golang/keyset"
+	"github.com/tsingson/tink/golang/subtle/aead"
+	"github.com/tsingson/tink/golang/subtle/random"
+	subtle "github.com/tsingson/tink/golang/subtle/streamingaead"
+	gcmhkdfpb "github.com/tsingson/tink/proto/aes_gcm_hkdf_streaming_go_proto"
+	commonpb "github.com/tsingson/tink/proto/common_go_proto"
+	tinkpb "github.com/tsingson/tink/proto/tink_go_proto"
+)
+
+const (
+	aesGCMHKDFKeyVersion = 0
+	aesGCMHKDFTypeURL    = "type.googleapis.com/google.crypto.tink.AesGcmHkdfStreamingKey"
+)
+
+// common errors
+var errInvalidAESGCMHKDFKey = fmt.Errorf("aes_gcm_hkdf_key_manager: invalid key")
+var errInvalidAESGCMHKDFKeyFormat = fmt.Errorf("aes_gcm_hkdf_key_manager: invalid key format")
+
+// aesGCMHKDFKeyManager is an implementation of KeyManager interface.
+// It generates new AesGcmHkdfStreamingKey keys and produces new instances of AESGCMHKDF subtle.
+type aesGCMHKDFKeyManager struct{}
+
+// Assert that aesGCMHKDFKeyManager implements the KeyManager interface.
+var _ registry.KeyManager = (*aesGCMHKDFKeyManager)(nil)
+
+// newAESGCMHKDFKeyManager creates a new aesGCMHKDFKeyManager.
+func newAESGCMHKDFKeyManager() *aesGCMHKDFKeyManager {
+	return new(aesGCMHKDFKeyManager)
+}
+
+// Primitive creates an AESGCMHKDF subtle for the given serialized AesGcmHkdfStreamingKey proto.
+func (km *aesGCMHKDFKeyManager) Primitive(serializedKey []byte) (interface{}, error) {
+	if len(serializedKey) == 0 {
+		return nil, errInvalidAESGCMHKDFKey
+	}
+	key := new(gcmhkdfpb.AesGcmHkdfStreamingKey)
+	if err := proto.Unmarshal(serializedKey, key); err != nil {
+		return nil, errInvalidAESGCMHKDFKey
+	}
+	if err := km.validateKey(key); err != nil {
+		return nil, err
+	}
+	ret, err := subtle.NewAESGCMHKDF(
+		key.KeyValue,
+		commonpb.HashType_name[int32(key.Params.HkdfHashType)],
+		int(key.Params.DerivedKeySize),
+		int(key.Params.CiphertextSegmentSize),
+		// no first segment offset
+		0)
+	if err != nil {
+		return nil, fmt.Errorf("aes_gcm_hkdf_key_manager: cannot create new primitive: %s", err)
+	}
+	return ret, nil
+}
+
+// NewKey creates a new key according to specification the given serialized AesGcmHkdfStreamingKeyFormat.
+func (km *aesGCMHKDFKeyManager) NewKey(serializedKeyFormat []byte) (proto.Message, error) {
+	if len(serializedKeyFormat) == 0 {
+		return nil, errInvalidAESGCMHKDFKeyFormat
+	}
+	keyFormat := new(gcmhkdfpb.AesGcmHkdfStreamingKeyFormat)
+	if err := proto.Unmarshal(serializedKeyFormat, keyFormat); err != nil {
+		return nil, errInvalidAESGCMHKDFKeyFormat
+	}
+	if err := km.validateKeyFormat(keyFormat); err != nil {
+		return nil, fmt.Errorf("aes_gcm_hkdf_key_manager: invalid key format: %s", err)
+	}
+	return &gcmhkdfpb.AesGcmHkdfStreamingKey{
+		Version:  aesGCMHKDFKeyVersion,
+		KeyValue: random.GetRandomBytes(keyFormat.KeySize),
+		Params:   keyFormat.Params,
+	}, nil
+}
+
+// NewKeyData creates a new KeyData according to specification in the given serialized
+// AesGcmHkdfStreamingKeyFormat.
+// It should be used solely by the key management API.
+func (km *aesGCMHKDFKeyManager) NewKeyData(serializedKeyFormat []byte) (*tinkpb.KeyData, error) {
+	key, err := km.NewKey(serializedKeyFormat)
+	if err != nil {
+		return nil, err
+	}
+	serializedKey, err := proto.Marshal(key)
+	if err != nil {
+		return nil, err
+	}
+	return &tinkpb.KeyData{
+		TypeUrl:         aesGCMHKDFTypeURL,
+		Value:           serializedKey,
+		KeyMaterialType: tinkpb.KeyData_SYMMETRIC,
+	}, nil
+}
+
+// DoesSupport indicates if this key manager supports the given key type.
+func (km *aesGCMHKDFKeyManager) DoesSupport(typeURL string) bool {
+	return typeURL == aesGCMHKDFTypeURL
+}
+
+// TypeURL returns the key type of keys managed by this key manager.
+func (km *aesGCMHKDFKeyManager) TypeURL() string {
+	return aesGCMHKDFTypeURL
+}
+
+// validateKey validates the given AesGcmHkdfStreamingKey.
+func (km *aesGCMHKDFKeyManager) validateKey(key *gcmhkdfpb.AesGcmHkdfStreamingKey) error {
+	if err := keyset.ValidateKeyVersion(key.Version, aesGCMHKDFKeyVersion); err != nil {
+		return fmt.Errorf("aes_gcm_hkdf_key_manager: %s", err)
+	}
+	if err := km.validateParams(key.Params); err != nil {
+		return fmt.Errorf("aes_gcm_hkdf_key_manager: %s", err)
+	}
+	if err := validateMainKeySize(uint32(len(key.KeyValue)), key.Params.DerivedKeySize); err != nil {
+		return fmt.Errorf("aes_gcm_hkdf_key_manager: %s", err)
+	}
+	return nil
+}
+
+// validateKeyFormat validates the given AesGcmHkdfStreamingKeyFormat.
+func (km *aesGCMHKDFKeyManager) validateKeyFormat(format *gcmhkdfpb.AesGcmHkdfStreamingKeyFormat) error {
+	if err := km.validateParams(format.Params); err != nil {
+		return err
+	}
+	return validateMainKeySize(format.KeySize, format.Params.DerivedKeySize)
+}
+
+// validateParams validates the given AesGcmHkdfStreamingParams.
+func (km *aesGCMHKDFKeyManager) validateParams(params *gcmhkdfpb.AesGcmHkdfStreamingParams) error {
+	if params == nil {
+		return fmt.Errorf("missing params")
+	}
+	if err := aead.ValidateAESKeySize(params.DerivedKeySize); err != nil {
+		return err
+	}
+	if params.HkdfHashType == commonpb.HashType_UNKNOWN_HASH {
+		return fmt.Errorf("unknown HKDF hash type")
+	}
+	// header (1 + derived key size + 7 bytes nonce prefix) + tag (16 bytes) + at least one byte
+	if params.CiphertextSegmentSize < params.DerivedKeySize+subtle.AESGCMHKDFTagSize+9 {
+		return fmt.Errorf("ciphertext segment size must be at least derived key size + %d", subtle.AESGCMHKDFTagSize+9)
+	}
+	return nil
+}
+
+// validateMainKeySize checks that the key derivation key is at least 16 bytes long
+// and at least as long as the derived keys.
+func validateMainKeySize(keySize, derivedKeySize uint32) error {
+	if keySize < 16 || keySize < derivedKeySize {
+		return fmt.Errorf("key size must be at least 16 bytes and at least the derived key size, got %d", keySize)
+	}
+	return nil
+}
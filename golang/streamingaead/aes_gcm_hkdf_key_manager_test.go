@@ -0,0 +1,203 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package streamingaead_test
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+
+	"github.com/tsingson/tink/golang/core/registry"
+	"github.com/tsingson/tink/golang/subtle/random"
+	subtle "github.com/tsingson/tink/golang/subtle/streamingaead"
+	"github.com/tsingson/tink/golang/testutil"
+	gcmhkdfpb "github.com/tsingson/tink/proto/aes_gcm_hkdf_streaming_go_proto"
+	commonpb "github.com/tsingson/tink/proto/common_go_proto"
+	tinkpb "github.com/tsingson/tink/proto/tink_go_proto"
+)
+
+func newAESGCMHKDFParams(derivedKeySize, segmentSize uint32) *gcmhkdfpb.AesGcmHkdfStreamingParams {
+	return &gcmhkdfpb.AesGcmHkdfStreamingParams{
+		CiphertextSegmentSize: segmentSize,
+		DerivedKeySize:        derivedKeySize,
+		HkdfHashType:          commonpb.HashType_SHA256,
+	}
+}
+
+func newAESGCMHKDFKey(keySize, derivedKeySize uint32) *gcmhkdfpb.AesGcmHkdfStreamingKey {
+	return &gcmhkdfpb.AesGcmHkdfStreamingKey{
+		Version:  testutil.AESGCMHKDFKeyVersion,
+		KeyValue: random.GetRandomBytes(keySize),
+		Params:   newAESGCMHKDFParams(derivedKeySize, 4096),
+	}
+}
+
+func TestAESGCMHKDFGetPrimitiveBasic(t *testing.T) {
+	keyManager, err := registry.GetKeyManager(testutil.AESGCMHKDFTypeURL)
+	if err != nil {
+		t.Fatalf("cannot obtain AES-GCM-HKDF key manager: %s", err)
+	}
+	for _, keySize := range []uint32{16, 32} {
+		key := newAESGCMHKDFKey(keySize, keySize)
+		serializedKey, _ := proto.Marshal(key)
+		p, err := keyManager.Primitive(serializedKey)
+		if err != nil {
+			t.Errorf("unexpected error: %s", err)
+			continue
+		}
+		a, ok := p.(*subtle.AESGCMHKDF)
+		if !ok {
+			t.Errorf("primitive is not AESGCMHKDF")
+			continue
+		}
+		if err := encryptDecrypt(a, a, 10000); err != nil {
+			t.Errorf("%s", err)
+		}
+	}
+}
+
+func TestAESGCMHKDFGetPrimitiveWithInvalidInput(t *testing.T) {
+	keyManager, err := registry.GetKeyManager(testutil.AESGCMHKDFTypeURL)
+	if err != nil {
+		t.Fatalf("cannot obtain AES-GCM-HKDF key manager: %s", err)
+	}
+	badVersion := newAESGCMHKDFKey(16, 16)
+	badVersion.Version++
+	noParams := newAESGCMHKDFKey(16, 16)
+	noParams.Params = nil
+	unknownHash := newAESGCMHKDFKey(16, 16)
+	unknownHash.Params.HkdfHashType = commonpb.HashType_UNKNOWN_HASH
+	smallSegment := newAESGCMHKDFKey(16, 16)
+	smallSegment.Params.CiphertextSegmentSize = 40
+	testKeys := []*gcmhkdfpb.AesGcmHkdfStreamingKey{
+		badVersion,
+		noParams,
+		unknownHash,
+		smallSegment,
+		// key shorter than 16 bytes
+		newAESGCMHKDFKey(15, 16),
+		// key shorter than the derived keys
+		newAESGCMHKDFKey(16, 32),
+		// invalid derived key size
+		newAESGCMHKDFKey(32, 24),
+	}
+	for i, key := range testKeys {
+		serializedKey, _ := proto.Marshal(key)
+		if _, err := keyManager.Primitive(serializedKey); err == nil {
+			t.Errorf("expect an error in test case %d", i)
+		}
+	}
+	if _, err := keyManager.Primitive(nil); err == nil {
+		t.Errorf("expect an error when input is nil")
+	}
+	if _, err := keyManager.Primitive([]byte{}); err == nil {
+		t.Errorf("expect an error when input is empty")
+	}
+}
+
+func TestAESGCMHKDFNewKeyMultipleTimes(t *testing.T) {
+	keyManager, err := registry.GetKeyManager(testutil.AESGCMHKDFTypeURL)
+	if err != nil {
+		t.Fatalf("cannot obtain AES-GCM-HKDF key manager: %s", err)
+	}
+	format := &gcmhkdfpb.AesGcmHkdfStreamingKeyFormat{
+		KeySize: 32,
+		Params:  newAESGCMHKDFParams(32, 4096),
+	}
+	serializedFormat, _ := proto.Marshal(format)
+	keys := make(map[string]bool)
+	nTest := 26
+	for i := 0; i < nTest; i++ {
+		key, _ := keyManager.NewKey(serializedFormat)
+		serializedKey, _ := proto.Marshal(key)
+		keys[string(serializedKey)] = true
+
+		keyData, _ := keyManager.NewKeyData(serializedFormat)
+		keys[string(keyData.Value)] = true
+	}
+	if len(keys) != nTest*2 {
+		t.Errorf("key is repeated")
+	}
+}
+
+func TestAESGCMHKDFNewKeyData(t *testing.T) {
+	keyManager, err := registry.GetKeyManager(testutil.AESGCMHKDFTypeURL)
+	if err != nil {
+		t.Fatalf("cannot obtain AES-GCM-HKDF key manager: %s", err)
+	}
+	format := &gcmhkdfpb.AesGcmHkdfStreamingKeyFormat{
+		KeySize: 16,
+		Params:  newAESGCMHKDFParams(16, 4096),
+	}
+	serializedFormat, _ := proto.Marshal(format)
+	keyData, err := keyManager.NewKeyData(serializedFormat)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if keyData.TypeUrl != testutil.AESGCMHKDFTypeURL {
+		t.Errorf("incorrect type url")
+	}
+	if keyData.KeyMaterialType != tinkpb.KeyData_SYMMETRIC {
+		t.Errorf("incorrect key material type")
+	}
+	key := new(gcmhkdfpb.AesGcmHkdfStreamingKey)
+	if err := proto.Unmarshal(keyData.Value, key); err != nil {
+		t.Fatalf("incorrect key value")
+	}
+	if len(key.KeyValue) != 16 || !proto.Equal(key.Params, format.Params) {
+		t.Errorf("key does not match the key format: %s", key)
+	}
+	if _, err := keyManager.Primitive(keyData.Value); err != nil {
+		t.Errorf("cannot get primitive from new key: %s", err)
+	}
+}
+
+func TestAESGCMHKDFNewKeyWithInvalidInput(t *testing.T) {
+	keyManager, err := registry.GetKeyManager(testutil.AESGCMHKDFTypeURL)
+	if err != nil {
+		t.Fatalf("cannot obtain AES-GCM-HKDF key manager: %s", err)
+	}
+	formats := []*gcmhkdfpb.AesGcmHkdfStreamingKeyFormat{
+		{KeySize: 16},
+		{KeySize: 15, Params: newAESGCMHKDFParams(16, 4096)},
+		{KeySize: 16, Params: newAESGCMHKDFParams(32, 4096)},
+		{KeySize: 16, Params: newAESGCMHKDFParams(16, 16)},
+	}
+	for i, format := range formats {
+		serializedFormat, _ := proto.Marshal(format)
+		if _, err := keyManager.NewKey(serializedFormat); err == nil {
+			t.Errorf("expect an error in test case %d", i)
+		}
+	}
+	if _, err := keyManager.NewKey(nil); err == nil {
+		t.Errorf("expect an error when input is nil")
+	}
+}
+
+func TestAESGCMHKDFDoesSupport(t *testing.T) {
+	keyManager, err := registry.GetKeyManager(testutil.AESGCMHKDFTypeURL)
+	if err != nil {
+		t.Fatalf("cannot obtain AES-GCM-HKDF key manager: %s", err)
+	}
+	if !keyManager.DoesSupport(testutil.AESGCMHKDFTypeURL) {
+		t.Errorf("AESGCMHKDFKeyManager must support %s", testutil.AESGCMHKDFTypeURL)
+	}
+	if keyManager.DoesSupport("some bad type") {
+		t.Errorf("AESGCMHKDFKeyManager must support only %s", testutil.AESGCMHKDFTypeURL)
+	}
+	if keyManager.TypeURL() != testutil.AESGCMHKDFTypeURL {
+		t.Errorf("incorrect key type")
+	}
+}
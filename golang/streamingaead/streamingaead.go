@@ -0,0 +1,88 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+// Package streamingaead provides implementations of the streaming AEAD primitive.
+// Streaming AEAD encrypts large plaintexts, such as files, as a sequence of authenticated
+// segments, so that neither the plaintext nor the ciphertext has to be held in memory.
+// Ciphertexts stored in seekable media can also be decrypted at arbitrary offsets with
+// NewDecryptingReaderAt, which authenticates only the segments that are read.
+// Example:
+//
+// package main
+//
+// import (
+//
+//	"io"
+//	"os"
+//
+//	"github.com/tsingson/tink/golang/keyset"
+//	"github.com/tsingson/tink/golang/streamingaead"
+//
+// )
+//
+// func main() {
+//
+//	kh, err := keyset.NewHandle(streamingaead.AES256GCMHKDF4KBKeyTemplate())
+//	if err != nil {
+//	    // handle the error
+//	}
+//
+//	a, err := streamingaead.New(kh)
+//	if err != nil {
+//	    // handle the error
+//	}
+//
+//	w, err := a.NewEncryptingWriter(ciphertextFile, []byte("associated data"))
+//	if err != nil {
+//	    // handle error
+//	}
+//	if _, err := io.Copy(w, plaintextFile); err != nil {
+//	    // handle error
+//	}
+//	if err := w.Close(); err != nil {
+//	    // handle error
+//	}
+//
+//	r, err := a.NewDecryptingReader(ciphertextFile, []byte("associated data"))
+//	if err != nil {
+//	    // handle error
+//	}
+//	if _, err := io.Copy(os.Stdout, r); err != nil {
+//	    // handle error
+//	}
+//
+// }
+package streamingaead
+
+import (
+	"fmt"
+
+	"github.com/tsingson/tink/golang/core/registry"
+)
+
+// streamingAEADPrimitiveName is the name of the primitive in the Tink catalogue.
+const streamingAEADPrimitiveName = "StreamingAead"
+
+func init() {
+	if err := registry.TinkCatalogue().Add(streamingAEADPrimitiveName, aesGCMHKDFTypeURL, 0, func(*registry.Registry) registry.KeyManager { return newAESGCMHKDFKeyManager() }); err != nil {
+		panic(fmt.Sprintf("streamingaead.init() failed: %v", err))
+	}
+	if err := registry.TinkCatalogue().Add(streamingAEADPrimitiveName, aesCTRHMACTypeURL, 0, func(*registry.Registry) registry.KeyManager { return newAESCTRHMACKeyManager() }); err != nil {
+		panic(fmt.Sprintf("streamingaead.init() failed: %v", err))
+	}
+
+	if err := registry.RegisterPrimitiveWrapper(new(wrapper)); err != nil {
+		panic(fmt.Sprintf("streamingaead.init() failed: %v", err))
+	}
+}
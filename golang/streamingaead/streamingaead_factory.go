@@ -0,0 +1,235 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package streamingaead
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/tsingson/tink/golang/core/primitiveset"
+	"github.com/tsingson/tink/golang/core/registry"
+	"github.com/tsingson/tink/golang/keyset"
+	subtle "github.com/tsingson/tink/golang/subtle/streamingaead"
+	"github.com/tsingson/tink/golang/tink"
+)
+
+var errNoMatchingKey = errors.New("streamingaead_factory: no matching key found for the ciphertext in the stream")
+
+// ErrTruncated is returned on decryption when the final segments of a ciphertext
+// have been removed.
+var ErrTruncated = subtle.ErrTruncated
+
+// New returns a StreamingAEAD primitive from the given keyset handle.
+func New(h *keyset.Handle) (tink.StreamingAEAD, error) {
+	return NewWithKeyManager(h, nil /*keyManager*/)
+}
+
+// NewWithKeyManager returns a StreamingAEAD primitive from the given keyset handle and custom key manager.
+func NewWithKeyManager(h *keyset.Handle, km registry.KeyManager) (tink.StreamingAEAD, error) {
+	ps, err := h.PrimitivesWithKeyManager(km)
+	if err != nil {
+		return nil, fmt.Errorf("streamingaead_factory: cannot obtain primitive set: %s", err)
+	}
+	return wrap(registry.Default(), ps)
+}
+
+// NewWithRegistry returns a StreamingAEAD primitive from the given keyset handle, using the key
+// managers of the given registry.
+func NewWithRegistry(h *keyset.Handle, r *registry.Registry) (tink.StreamingAEAD, error) {
+	ps, err := h.PrimitivesWithRegistry(r)
+	if err != nil {
+		return nil, fmt.Errorf("streamingaead_factory: cannot obtain primitive set: %s", err)
+	}
+	return wrap(r, ps)
+}
+
+// wrap combines the primitives in ps into a StreamingAEAD using the primitive wrapper
+// registered in r.
+func wrap(r *registry.Registry, ps *primitiveset.PrimitiveSet) (tink.StreamingAEAD, error) {
+	p, err := r.Wrap(streamingAEADPrimitiveName, ps)
+	if err != nil {
+		return nil, fmt.Errorf("streamingaead_factory: cannot wrap primitive set: %s", err)
+	}
+	ret, ok := p.(tink.StreamingAEAD)
+	if !ok {
+		return nil, fmt.Errorf("streamingaead_factory: not a StreamingAEAD primitive")
+	}
+	return ret, nil
+}
+
+// wrapper is the registry.PrimitiveWrapper for the StreamingAEAD primitive.
+type wrapper struct{}
+
+// Asserts that wrapper implements the PrimitiveWrapper interface.
+var _ registry.PrimitiveWrapper = (*wrapper)(nil)
+
+// PrimitiveName returns the name of the StreamingAEAD primitive.
+func (w *wrapper) PrimitiveName() string {
+	return streamingAEADPrimitiveName
+}
+
+// Wrap returns a StreamingAEAD that uses the primitives in the given primitive set, which must
+// all be StreamingAEAD primitives.
+func (w *wrapper) Wrap(ps *primitiveset.PrimitiveSet) (interface{}, error) {
+	if !ps.All(func(p interface{}) bool { _, ok := p.(tink.StreamingAEAD); return ok }) {
+		return nil, fmt.Errorf("streamingaead_factory: not a StreamingAEAD primitive")
+	}
+	return newPrimitiveSet(ps), nil
+}
+
+// primitiveSet is a StreamingAEAD implementation that uses the underlying primitive set
+// for streaming encryption and decryption.
+//
+// Streaming ciphertexts carry no key identifier, so encryption always uses the primary
+// key and decryption tries every key in the set until one of them authenticates the
+// beginning of the ciphertext.
+type primitiveSet struct {
+	ps *primitiveset.PrimitiveSet
+}
+
+// Asserts that primitiveSet implements the StreamingAEAD interface.
+var _ tink.StreamingAEAD = (*primitiveSet)(nil)
+
+func newPrimitiveSet(ps *primitiveset.PrimitiveSet) *primitiveSet {
+	ret := new(primitiveSet)
+	ret.ps = ps
+	return ret
+}
+
+// NewEncryptingWriter returns a wrapper around w that encrypts the data written to it
+// with the primary key.
+func (s *primitiveSet) NewEncryptingWriter(w io.Writer, ad []byte) (io.WriteCloser, error) {
+	primary := s.ps.Primary
+	var p = (primary.Primitive).(tink.StreamingAEAD)
+	return p.NewEncryptingWriter(w, ad)
+}
+
+// NewDecryptingReader returns a wrapper around r that decrypts the ciphertext read from r.
+// The key is selected on the first read: the beginning of the ciphertext is buffered and
+// decrypted with each key in turn until one of them authenticates it. Errors other than
+// authentication failures, such as read errors and ErrTruncated, end the selection and
+// are returned unchanged.
+func (s *primitiveSet) NewDecryptingReader(r io.Reader, ad []byte) (io.Reader, error) {
+	return &decryptReader{wrapped: s, cr: &rewindReader{r: r}, ad: ad}, nil
+}
+
+// NewDecryptingReaderAt returns a reader over the decryption of the ciphertext of the given
+// size in r. The key is selected by decrypting the first segment with each key in turn;
+// errors other than authentication failures are returned unchanged.
+func (s *primitiveSet) NewDecryptingReaderAt(r io.ReaderAt, size int64, ad []byte) (*io.SectionReader, error) {
+	for _, e := range s.entries() {
+		var p = (e.Primitive).(tink.StreamingAEAD)
+		ra, err := p.NewDecryptingReaderAt(r, size, ad)
+		if err == subtle.ErrAuthentication {
+			continue
+		}
+		return ra, err
+	}
+	return nil, errNoMatchingKey
+}
+
+// entries returns all primitives in the set, starting with the primary.
+func (s *primitiveSet) entries() []*primitiveset.Entry {
+	var ret []*primitiveset.Entry
+	if s.ps.Primary != nil {
+		ret = append(ret, s.ps.Primary)
+	}
+	for _, entries := range s.ps.Entries {
+		for _, e := range entries {
+			if e != s.ps.Primary {
+				ret = append(ret, e)
+			}
+		}
+	}
+	return ret
+}
+
+// decryptReader decrypts a ciphertext with the first key in the primitive set that
+// successfully decrypts its first segment.
+type decryptReader struct {
+	wrapped *primitiveSet
+	cr      *rewindReader
+	ad      []byte
+	matched io.Reader
+}
+
+func (dr *decryptReader) Read(p []byte) (int, error) {
+	if dr.matched != nil {
+		return dr.matched.Read(p)
+	}
+	if len(p) == 0 {
+		return 0, nil
+	}
+	for _, e := range dr.wrapped.entries() {
+		var sa = (e.Primitive).(tink.StreamingAEAD)
+		dr.cr.rewind()
+		r, err := sa.NewDecryptingReader(dr.cr, dr.ad)
+		if err == subtle.ErrAuthentication {
+			continue
+		}
+		if err != nil {
+			return 0, err
+		}
+		n, err := r.Read(p)
+		if err == subtle.ErrAuthentication {
+			continue
+		}
+		if err != nil && err != io.EOF {
+			return n, err
+		}
+		dr.cr.stopRecording()
+		dr.matched = r
+		return n, err
+	}
+	return 0, errNoMatchingKey
+}
+
+// rewindReader records the data read from the underlying reader so that it can be
+// read again after rewind, until stopRecording is called.
+type rewindReader struct {
+	r         io.Reader
+	buf       []byte
+	pos       int
+	recording bool
+}
+
+func (rr *rewindReader) Read(p []byte) (int, error) {
+	if rr.pos < len(rr.buf) {
+		n := copy(p, rr.buf[rr.pos:])
+		rr.pos += n
+		return n, nil
+	}
+	n, err := rr.r.Read(p)
+	if rr.recording {
+		rr.buf = append(rr.buf, p[:n]...)
+		rr.pos += n
+	}
+	return n, err
+}
+
+// rewind restarts reading at the beginning of the recorded data.
+func (rr *rewindReader) rewind() {
+	rr.recording = true
+	rr.pos = 0
+}
+
+// stopRecording stops recording; data that has been recorded but not read again
+// is still returned by subsequent reads.
+func (rr *rewindReader) stopRecording() {
+	rr.recording = false
+	rr.buf = rr.buf[rr.pos:]
+	rr.pos = 0
+}
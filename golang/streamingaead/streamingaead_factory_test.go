@@ -0,0 +1,312 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package streamingaead_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/ioutil"
+	"testing"
+
+	"github.com/tsingson/tink/golang/keyset"
+	"github.com/tsingson/tink/golang/streamingaead"
+	"github.com/tsingson/tink/golang/subtle/random"
+	"github.com/tsingson/tink/golang/testkeyset"
+	"github.com/tsingson/tink/golang/tink"
+)
+
+func TestFactoryMultipleKeys(t *testing.T) {
+	ksm := keyset.NewManager()
+	if err := ksm.Rotate(streamingaead.AES128GCMHKDF4KBKeyTemplate()); err != nil {
+		t.Fatalf("cannot rotate: %s", err)
+	}
+	oldHandle, err := ksm.Handle()
+	if err != nil {
+		t.Fatalf("cannot get keyset handle: %s", err)
+	}
+	oldPrimary := testkeyset.KeysetMaterial(oldHandle).PrimaryKeyId
+	oldCipher, err := streamingaead.New(oldHandle)
+	if err != nil {
+		t.Fatalf("streamingaead.New failed: %s", err)
+	}
+	// Ciphertexts of the old primary key must be decryptable after rotation.
+	pt := random.GetRandomBytes(5000)
+	ad := random.GetRandomBytes(20)
+	oldCT, err := encrypt(oldCipher, pt, ad)
+	if err != nil {
+		t.Fatalf("encryption failed: %s", err)
+	}
+	for i := 0; i < 3; i++ {
+		if err := ksm.Rotate(streamingaead.AES256GCMHKDF4KBKeyTemplate()); err != nil {
+			t.Fatalf("cannot rotate: %s", err)
+		}
+	}
+	h, err := ksm.Handle()
+	if err != nil {
+		t.Fatalf("cannot get keyset handle: %s", err)
+	}
+	if testkeyset.KeysetMaterial(h).PrimaryKeyId == oldPrimary {
+		t.Fatalf("expect the primary key to change after rotation")
+	}
+	a, err := streamingaead.New(h)
+	if err != nil {
+		t.Fatalf("streamingaead.New failed: %s", err)
+	}
+	r, err := a.NewDecryptingReader(bytes.NewReader(oldCT), ad)
+	if err != nil {
+		t.Fatalf("cannot create decrypting reader: %s", err)
+	}
+	decrypted, err := ioutil.ReadAll(r)
+	if err != nil {
+		t.Fatalf("decryption with a non-primary key failed: %s", err)
+	}
+	if !bytes.Equal(pt, decrypted) {
+		t.Errorf("decryption is not inverse of encryption")
+	}
+	if err := encryptDecrypt(a, a, 5000); err != nil {
+		t.Errorf("%s", err)
+	}
+	// The new primary key must not be usable by the old keyset.
+	if err := encryptDecrypt(a, oldCipher, 5000); err == nil {
+		t.Errorf("expect decryption to fail with a keyset that does not contain the key")
+	}
+}
+
+func TestFactoryMixedKeyTypes(t *testing.T) {
+	ksm := keyset.NewManager()
+	if err := ksm.Rotate(streamingaead.AES128GCMHKDF4KBKeyTemplate()); err != nil {
+		t.Fatalf("cannot rotate: %s", err)
+	}
+	gcmHandle, err := ksm.Handle()
+	if err != nil {
+		t.Fatalf("cannot get keyset handle: %s", err)
+	}
+	if err := ksm.Rotate(streamingaead.AES128CTRHMACSHA256Segment4KBKeyTemplate()); err != nil {
+		t.Fatalf("cannot rotate: %s", err)
+	}
+	h, err := ksm.Handle()
+	if err != nil {
+		t.Fatalf("cannot get keyset handle: %s", err)
+	}
+	gcmCipher, err := streamingaead.New(gcmHandle)
+	if err != nil {
+		t.Fatalf("streamingaead.New failed: %s", err)
+	}
+	a, err := streamingaead.New(h)
+	if err != nil {
+		t.Fatalf("streamingaead.New failed: %s", err)
+	}
+	if err := encryptDecrypt(gcmCipher, a, 10000); err != nil {
+		t.Errorf("cannot decrypt AES-GCM-HKDF ciphertext: %s", err)
+	}
+	if err := encryptDecrypt(a, a, 10000); err != nil {
+		t.Errorf("cannot decrypt AES-CTR-HMAC ciphertext: %s", err)
+	}
+}
+
+func TestFactoryEmptyPlaintext(t *testing.T) {
+	kh, err := keyset.NewHandle(streamingaead.AES128GCMHKDF4KBKeyTemplate())
+	if err != nil {
+		t.Fatalf("cannot create keyset handle: %s", err)
+	}
+	a, err := streamingaead.New(kh)
+	if err != nil {
+		t.Fatalf("streamingaead.New failed: %s", err)
+	}
+	if err := encryptDecrypt(a, a, 0); err != nil {
+		t.Errorf("%s", err)
+	}
+}
+
+func TestFactoryModifiedCiphertext(t *testing.T) {
+	kh, err := keyset.NewHandle(streamingaead.AES128GCMHKDF4KBKeyTemplate())
+	if err != nil {
+		t.Fatalf("cannot create keyset handle: %s", err)
+	}
+	a, err := streamingaead.New(kh)
+	if err != nil {
+		t.Fatalf("streamingaead.New failed: %s", err)
+	}
+	pt := random.GetRandomBytes(10000)
+	ad := random.GetRandomBytes(20)
+	ct, err := encrypt(a, pt, ad)
+	if err != nil {
+		t.Fatalf("encryption failed: %s", err)
+	}
+	for _, modified := range [][]byte{
+		ct[:len(ct)-1],
+		ct[:4096],
+		append(append([]byte{}, ct[:5000]...), ct[5001:]...),
+	} {
+		r, err := a.NewDecryptingReader(bytes.NewReader(modified), ad)
+		if err != nil {
+			continue
+		}
+		if _, err := ioutil.ReadAll(r); err == nil {
+			t.Errorf("expect an error when the ciphertext is modified")
+		}
+	}
+}
+
+func TestFactoryDecryptingReaderAt(t *testing.T) {
+	ksm := keyset.NewManager()
+	if err := ksm.Rotate(streamingaead.AES128CTRHMACSHA256Segment4KBKeyTemplate()); err != nil {
+		t.Fatalf("cannot rotate: %s", err)
+	}
+	oldHandle, err := ksm.Handle()
+	if err != nil {
+		t.Fatalf("cannot get keyset handle: %s", err)
+	}
+	oldCipher, err := streamingaead.New(oldHandle)
+	if err != nil {
+		t.Fatalf("streamingaead.New failed: %s", err)
+	}
+	if err := ksm.Rotate(streamingaead.AES128GCMHKDF4KBKeyTemplate()); err != nil {
+		t.Fatalf("cannot rotate: %s", err)
+	}
+	h, err := ksm.Handle()
+	if err != nil {
+		t.Fatalf("cannot get keyset handle: %s", err)
+	}
+	a, err := streamingaead.New(h)
+	if err != nil {
+		t.Fatalf("streamingaead.New failed: %s", err)
+	}
+	pt := random.GetRandomBytes(20000)
+	ad := random.GetRandomBytes(20)
+	for _, encryptCipher := range []tink.StreamingAEAD{oldCipher, a} {
+		ct, err := encrypt(encryptCipher, pt, ad)
+		if err != nil {
+			t.Fatalf("encryption failed: %s", err)
+		}
+		sr, err := a.NewDecryptingReaderAt(bytes.NewReader(ct), int64(len(ct)), ad)
+		if err != nil {
+			t.Fatalf("cannot create decrypting reader: %s", err)
+		}
+		if sr.Size() != int64(len(pt)) {
+			t.Errorf("Size() = %d, want %d", sr.Size(), len(pt))
+		}
+		buf := make([]byte, 5000)
+		if _, err := sr.ReadAt(buf, 12345); err != nil || !bytes.Equal(buf, pt[12345:17345]) {
+			t.Errorf("ReadAt returned wrong plaintext: %v", err)
+		}
+		if _, err := a.NewDecryptingReaderAt(bytes.NewReader(ct), int64(len(ct)), []byte("wrong")); err == nil {
+			t.Errorf("expect an error with wrong associated data")
+		}
+	}
+}
+
+func TestFactoryTruncatedCiphertext(t *testing.T) {
+	kh, err := keyset.NewHandle(streamingaead.AES128GCMHKDF4KBKeyTemplate())
+	if err != nil {
+		t.Fatalf("cannot create keyset handle: %s", err)
+	}
+	a, err := streamingaead.New(kh)
+	if err != nil {
+		t.Fatalf("streamingaead.New failed: %s", err)
+	}
+	pt := random.GetRandomBytes(10000)
+	ad := random.GetRandomBytes(20)
+	ct, err := encrypt(a, pt, ad)
+	if err != nil {
+		t.Fatalf("encryption failed: %s", err)
+	}
+	// Drop the last segment.
+	truncated := ct[:8192]
+	r, err := a.NewDecryptingReader(bytes.NewReader(truncated), ad)
+	if err != nil {
+		t.Fatalf("cannot create decrypting reader: %s", err)
+	}
+	if _, err := ioutil.ReadAll(r); err != streamingaead.ErrTruncated {
+		t.Errorf("sequential decryption: got %v, want ErrTruncated", err)
+	}
+	sr, err := a.NewDecryptingReaderAt(bytes.NewReader(truncated), int64(len(truncated)), ad)
+	if err != nil {
+		t.Fatalf("cannot create decrypting reader: %s", err)
+	}
+	if _, err := sr.ReadAt(make([]byte, 10), 0); err != nil {
+		t.Errorf("reading an intact segment failed: %s", err)
+	}
+	if _, err := sr.ReadAt(make([]byte, 1), sr.Size()-1); err != streamingaead.ErrTruncated {
+		t.Errorf("random access decryption: got %v, want ErrTruncated", err)
+	}
+	// Only the first segment is left.
+	if _, err := a.NewDecryptingReaderAt(bytes.NewReader(ct[:4096]), 4096, ad); err != streamingaead.ErrTruncated {
+		t.Errorf("got %v, want ErrTruncated", err)
+	}
+}
+
+var errRead = errors.New("read failed")
+
+// failingReader returns errRead once the data of r has been read.
+type failingReader struct {
+	r io.Reader
+}
+
+func (f *failingReader) Read(p []byte) (int, error) {
+	n, err := f.r.Read(p)
+	if err == io.EOF {
+		err = errRead
+	}
+	return n, err
+}
+
+// failingReaderAt returns errRead for reads beyond the first limit bytes of r.
+type failingReaderAt struct {
+	r     io.ReaderAt
+	limit int64
+}
+
+func (f *failingReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	if off+int64(len(p)) > f.limit {
+		return 0, errRead
+	}
+	return f.r.ReadAt(p, off)
+}
+
+func TestFactoryReadError(t *testing.T) {
+	ksm := keyset.NewManager()
+	for i := 0; i < 2; i++ {
+		if err := ksm.Rotate(streamingaead.AES128GCMHKDF4KBKeyTemplate()); err != nil {
+			t.Fatalf("cannot rotate: %s", err)
+		}
+	}
+	kh, err := ksm.Handle()
+	if err != nil {
+		t.Fatalf("cannot get keyset handle: %s", err)
+	}
+	a, err := streamingaead.New(kh)
+	if err != nil {
+		t.Fatalf("streamingaead.New failed: %s", err)
+	}
+	ad := random.GetRandomBytes(20)
+	ct, err := encrypt(a, random.GetRandomBytes(10000), ad)
+	if err != nil {
+		t.Fatalf("encryption failed: %s", err)
+	}
+	// Read errors are returned as they are instead of making the next key be tried.
+	r, err := a.NewDecryptingReader(&failingReader{r: bytes.NewReader(ct[:100])}, ad)
+	if err != nil {
+		t.Fatalf("cannot create decrypting reader: %s", err)
+	}
+	if _, err := ioutil.ReadAll(r); err != errRead {
+		t.Errorf("sequential decryption: got %v, want %v", err, errRead)
+	}
+	ra := &failingReaderAt{r: bytes.NewReader(ct), limit: 100}
+	if _, err := a.NewDecryptingReaderAt(ra, int64(len(ct)), ad); err != errRead {
+		t.Errorf("random access decryption: got %v, want %v", err, errRead)
+	}
+}
@@ -0,0 +1,55 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package streamingaead_test
+
+import (
+	"testing"
+
+	"github.com/tsingson/tink/golang/keyset"
+	"github.com/tsingson/tink/golang/streamingaead"
+	tinkpb "github.com/tsingson/tink/proto/tink_go_proto"
+)
+
+func TestKeyTemplates(t *testing.T) {
+	var testCases = []struct {
+		name     string
+		template *tinkpb.KeyTemplate
+	}{
+		{"AES128GCMHKDF4KB", streamingaead.AES128GCMHKDF4KBKeyTemplate()},
+		{"AES256GCMHKDF4KB", streamingaead.AES256GCMHKDF4KBKeyTemplate()},
+		{"AES256GCMHKDF1MB", streamingaead.AES256GCMHKDF1MBKeyTemplate()},
+		{"AES128CTRHMACSHA256Segment4KB", streamingaead.AES128CTRHMACSHA256Segment4KBKeyTemplate()},
+		{"AES256CTRHMACSHA256Segment4KB", streamingaead.AES256CTRHMACSHA256Segment4KBKeyTemplate()},
+		{"AES256CTRHMACSHA256Segment1MB", streamingaead.AES256CTRHMACSHA256Segment1MBKeyTemplate()},
+	}
+	for _, tc := range testCases {
+		if tc.template.OutputPrefixType != tinkpb.OutputPrefixType_RAW {
+			t.Errorf("%s: expect RAW output prefix type", tc.name)
+		}
+		kh, err := keyset.NewHandle(tc.template)
+		if err != nil {
+			t.Errorf("%s: cannot create keyset handle: %s", tc.name, err)
+			continue
+		}
+		a, err := streamingaead.New(kh)
+		if err != nil {
+			t.Errorf("%s: cannot get primitive: %s", tc.name, err)
+			continue
+		}
+		if err := encryptDecrypt(a, a, 10000); err != nil {
+			t.Errorf("%s: %s", tc.name, err)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package streamingaead_test
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"testing"
+
+	"github.com/tsingson/tink/golang/core/registry"
+	"github.com/tsingson/tink/golang/subtle/random"
+	"github.com/tsingson/tink/golang/testutil"
+	"github.com/tsingson/tink/golang/tink"
+)
+
+var errDecryptionMismatch = errors.New("decryption is not inverse of encryption")
+
+func TestStreamingAEADInit(t *testing.T) {
+	// Check for AES-GCM-HKDF key manager.
+	_, err := registry.GetKeyManager(testutil.AESGCMHKDFTypeURL)
+	if err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+	// Check for AES-CTR-HMAC key manager.
+	_, err = registry.GetKeyManager(testutil.AESCTRHMACTypeURL)
+	if err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+}
+
+// encryptDecrypt encrypts a random plaintext of the given size with encryptCipher and
+// checks that decryptCipher recovers it.
+func encryptDecrypt(encryptCipher, decryptCipher tink.StreamingAEAD, ptSize uint32) error {
+	pt := random.GetRandomBytes(ptSize)
+	ad := random.GetRandomBytes(20)
+	ct, err := encrypt(encryptCipher, pt, ad)
+	if err != nil {
+		return err
+	}
+	r, err := decryptCipher.NewDecryptingReader(bytes.NewReader(ct), ad)
+	if err != nil {
+		return err
+	}
+	decrypted, err := ioutil.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(pt, decrypted) {
+		return errDecryptionMismatch
+	}
+	return nil
+}
+
+func encrypt(a tink.StreamingAEAD, pt, ad []byte) ([]byte, error) {
+	buf := new(bytes.Buffer)
+	w, err := a.NewEncryptingWriter(buf, ad)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(pt); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
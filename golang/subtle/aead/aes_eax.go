@@ -0,0 +1,133 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package aead
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+
+	"github.com/tsingson/tink/golang/subtle/internal/cmac"
+	"github.com/tsingson/tink/golang/subtle/random"
+	"github.com/tsingson/tink/golang/tink"
+)
+
+const (
+	// AESEAXTagSize is the only tag size that this implementation supports.
+	AESEAXTagSize = 16
+)
+
+// AESEAX is an implementation of AEAD interface using AES-EAX as described in
+// https://web.cs.ucdavis.edu/~rogaway/papers/eax.pdf.
+type AESEAX struct {
+	Key    []byte
+	IVSize int
+
+	block cipher.Block
+	// cmac is used to compute the OMAC values of EAX.
+	cmac *cmac.CMAC
+}
+
+// Assert that AESEAX implements the AEAD interface.
+var _ tink.AEAD = (*AESEAX)(nil)
+
+// NewAESEAX returns an AESEAX instance.
+// The key argument should be the AES key, either 16 or 32 bytes to select
+// AES-128 or AES-256. The ivSize argument is the size of the random IV in bytes,
+// either 12 or 16.
+func NewAESEAX(key []byte, ivSize int) (*AESEAX, error) {
+	if err := ValidateAESKeySize(uint32(len(key))); err != nil {
+		return nil, fmt.Errorf("aes_eax: %s", err)
+	}
+	if err := ValidateAESEAXIVSize(uint32(ivSize)); err != nil {
+		return nil, fmt.Errorf("aes_eax: %s", err)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("aes_eax: %s", err)
+	}
+	return &AESEAX{
+		Key:    key,
+		IVSize: ivSize,
+		block:  block,
+		cmac:   cmac.New(block),
+	}, nil
+}
+
+// ValidateAESEAXIVSize checks if the given IV size is supported by AESEAX.
+func ValidateAESEAXIVSize(sizeInBytes uint32) error {
+	switch sizeInBytes {
+	case 12, 16:
+		return nil
+	default:
+		return fmt.Errorf("invalid IV size; want 12 or 16, got %d", sizeInBytes)
+	}
+}
+
+// Encrypt encrypts pt with aad as additional authenticated data.
+// The resulting ciphertext consists of three parts:
+// (1) the IV used for encryption, (2) the actual ciphertext and (3) the tag.
+func (a *AESEAX) Encrypt(pt, aad []byte) ([]byte, error) {
+	iv := random.GetRandomBytes(uint32(a.IVSize))
+	ret := make([]byte, a.IVSize+len(pt)+AESEAXTagSize)
+	copy(ret, iv)
+	n := a.omac(0, iv)
+	ct := ret[a.IVSize : a.IVSize+len(pt)]
+	cipher.NewCTR(a.block, n).XORKeyStream(ct, pt)
+	tag := a.tag(n, aad, ct)
+	copy(ret[a.IVSize+len(pt):], tag)
+	return ret, nil
+}
+
+// Decrypt decrypts ct with aad as the additional authenticated data.
+func (a *AESEAX) Decrypt(ct, aad []byte) ([]byte, error) {
+	if len(ct) < a.IVSize+AESEAXTagSize {
+		return nil, errors.New("aes_eax: ciphertext too short")
+	}
+	iv := ct[:a.IVSize]
+	payload := ct[a.IVSize : len(ct)-AESEAXTagSize]
+	n := a.omac(0, iv)
+	tag := a.tag(n, aad, payload)
+	if subtle.ConstantTimeCompare(tag, ct[len(ct)-AESEAXTagSize:]) != 1 {
+		return nil, errors.New("aes_eax: message authentication failed")
+	}
+	pt := make([]byte, len(payload))
+	cipher.NewCTR(a.block, n).XORKeyStream(pt, payload)
+	return pt, nil
+}
+
+// tag computes OMAC^0(iv) xor OMAC^1(aad) xor OMAC^2(ct), given n = OMAC^0(iv).
+func (a *AESEAX) tag(n, aad, ct []byte) []byte {
+	tag := a.omac(1, aad)
+	xorBlock(tag, n)
+	xorBlock(tag, a.omac(2, ct))
+	return tag
+}
+
+// omac computes the CMAC of a block encoding t followed by data.
+func (a *AESEAX) omac(t byte, data []byte) []byte {
+	x := make([]byte, aes.BlockSize)
+	x[aes.BlockSize-1] = t
+	return a.cmac.SumAfterBlock(x, data)
+}
+
+// xorBlock sets dst[i] ^= src[i] for all bytes of src.
+func xorBlock(dst, src []byte) {
+	for i := range src {
+		dst[i] ^= src[i]
+	}
+}
@@ -0,0 +1,175 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package aead_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/tsingson/tink/golang/subtle/aead"
+	"github.com/tsingson/tink/golang/subtle/random"
+	"github.com/tsingson/tink/golang/tink"
+)
+
+func TestAESEAXEncryptDecrypt(t *testing.T) {
+	for _, keySize := range []uint32{16, 32} {
+		for _, ivSize := range []int{12, 16} {
+			key := random.GetRandomBytes(keySize)
+			a, err := aead.NewAESEAX(key, ivSize)
+			if err != nil {
+				t.Fatalf("cannot create new instance of AESEAX: %s", err)
+			}
+			for ptSize := 0; ptSize < 75; ptSize++ {
+				pt := random.GetRandomBytes(uint32(ptSize))
+				aad := random.GetRandomBytes(uint32(ptSize % 20))
+				ct, err := a.Encrypt(pt, aad)
+				if err != nil {
+					t.Fatalf("encryption failed: %s", err)
+				}
+				if len(ct) != ivSize+ptSize+aead.AESEAXTagSize {
+					t.Errorf("unexpected ciphertext length %d", len(ct))
+				}
+				decrypted, err := a.Decrypt(ct, aad)
+				if err != nil {
+					t.Fatalf("decryption failed: %s", err)
+				}
+				if !bytes.Equal(decrypted, pt) {
+					t.Errorf("decryption is not inverse of encryption")
+				}
+			}
+		}
+	}
+}
+
+func TestAESEAXModifyCiphertext(t *testing.T) {
+	a, err := aead.NewAESEAX(random.GetRandomBytes(16), 16)
+	if err != nil {
+		t.Fatalf("cannot create new instance of AESEAX: %s", err)
+	}
+	testModifyCiphertext(t, a)
+}
+
+func TestAESEAXInvalidParams(t *testing.T) {
+	if _, err := aead.NewAESEAX(random.GetRandomBytes(24), 16); err == nil {
+		t.Errorf("expect an error when key size is 24 bytes")
+	}
+	for _, ivSize := range []int{0, 8, 11, 13, 32} {
+		if _, err := aead.NewAESEAX(random.GetRandomBytes(16), ivSize); err == nil {
+			t.Errorf("expect an error when IV size is %d", ivSize)
+		}
+	}
+}
+
+func TestAESEAXVectors(t *testing.T) {
+	runWycheproofDecryptionTests(t, "aes_eax_test.json", func(key []byte, ivSize int) (tink.AEAD, error) {
+		if ivSize != 12 && ivSize != 16 {
+			return nil, nil
+		}
+		return aead.NewAESEAX(key, ivSize)
+	})
+}
+
+// testModifyCiphertext checks that a flipped bit in the ciphertext or the
+// associated data, or a truncated ciphertext, is detected.
+func testModifyCiphertext(t *testing.T, a tink.AEAD) {
+	t.Helper()
+	pt := random.GetRandomBytes(40)
+	aad := random.GetRandomBytes(20)
+	ct, err := a.Encrypt(pt, aad)
+	if err != nil {
+		t.Fatalf("encryption failed: %s", err)
+	}
+	for i := 0; i < len(ct)*8; i++ {
+		modified := append([]byte{}, ct...)
+		modified[i/8] ^= 1 << uint(i%8)
+		if _, err := a.Decrypt(modified, aad); err == nil {
+			t.Errorf("expect an error when bit %d of the ciphertext is flipped", i)
+		}
+	}
+	for i := 0; i < len(aad)*8; i++ {
+		modified := append([]byte{}, aad...)
+		modified[i/8] ^= 1 << uint(i%8)
+		if _, err := a.Decrypt(ct, modified); err == nil {
+			t.Errorf("expect an error when bit %d of the associated data is flipped", i)
+		}
+	}
+	for i := 0; i < len(ct); i++ {
+		if _, err := a.Decrypt(ct[:i], aad); err == nil {
+			t.Errorf("expect an error when the ciphertext is truncated to %d bytes", i)
+		}
+	}
+}
+
+// runWycheproofDecryptionTests decrypts the Wycheproof AEAD test vectors in the given file
+// with the ciphers returned by newCipher. Groups for which newCipher returns nil are skipped.
+func runWycheproofDecryptionTests(t *testing.T, filename string, newCipher func(key []byte, ivSize int) (tink.AEAD, error)) {
+	t.Helper()
+	f, err := os.Open("../../../third_party/wycheproof/testvectors/" + filename)
+	if err != nil {
+		t.Fatalf("cannot open file: %s, make sure that github.com/google/wycheproof is in your gopath", err)
+	}
+	defer f.Close()
+	parser := json.NewDecoder(f)
+	data := new(testdata)
+	if err := parser.Decode(data); err != nil {
+		t.Fatalf("cannot decode test data: %s", err)
+	}
+	numTests := 0
+	for _, g := range data.TestGroups {
+		if err := aead.ValidateAESKeySize(g.KeySize / 8); err != nil {
+			continue
+		}
+		for _, tc := range g.Tests {
+			key, _ := hex.DecodeString(tc.Key)
+			aad, _ := hex.DecodeString(tc.Aad)
+			msg, _ := hex.DecodeString(tc.Msg)
+			ct, _ := hex.DecodeString(tc.Ct)
+			iv, _ := hex.DecodeString(tc.Iv)
+			tag, _ := hex.DecodeString(tc.Tag)
+			cipher, err := newCipher(key, int(g.IvSize/8))
+			if err != nil {
+				t.Errorf("cannot create cipher in test case %d: %s", tc.TcID, err)
+				continue
+			}
+			if cipher == nil {
+				continue
+			}
+			numTests++
+			var combinedCt []byte
+			combinedCt = append(combinedCt, iv...)
+			combinedCt = append(combinedCt, ct...)
+			combinedCt = append(combinedCt, tag...)
+			decrypted, err := cipher.Decrypt(combinedCt, aad)
+			if err != nil {
+				if tc.Result == "valid" {
+					t.Errorf("unexpected error in test case %d: %s", tc.TcID, err)
+				}
+			} else {
+				if tc.Result == "invalid" {
+					t.Errorf("decrypted invalid test case %d", tc.TcID)
+				}
+				if !bytes.Equal(decrypted, msg) {
+					t.Errorf("incorrect decryption in test case %d", tc.TcID)
+				}
+			}
+		}
+	}
+	if numTests == 0 {
+		t.Errorf("no test vectors were run")
+	}
+}
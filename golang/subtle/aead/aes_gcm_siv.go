@@ -0,0 +1,280 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package aead
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/subtle"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/tsingson/tink/golang/subtle/random"
+	"github.com/tsingson/tink/golang/tink"
+)
+
+const (
+	// AESGCMSIVNonceSize is the only nonce size that this implementation supports.
+	AESGCMSIVNonceSize = 12
+	// AESGCMSIVTagSize is the only tag size that this implementation supports.
+	AESGCMSIVTagSize = 16
+	// aesGCMSIVMaxInputSize is the maximal size of the plaintext and of the
+	// additional authenticated data (2^36 bytes).
+	aesGCMSIVMaxInputSize = uint64(1) << 36
+)
+
+// AESGCMSIV is an implementation of AEAD interface using AES-GCM-SIV as defined in
+// RFC 8452. AES-GCM-SIV is nonce misuse resistant: repeating a nonce only reveals
+// whether the same plaintext was encrypted with the same additional data.
+type AESGCMSIV struct {
+	Key []byte
+}
+
+// Assert that AESGCMSIV implements the AEAD interface.
+var _ tink.AEAD = (*AESGCMSIV)(nil)
+
+// NewAESGCMSIV returns an AESGCMSIV instance.
+// The key argument should be the AES key, either 16 or 32 bytes to select
+// AES-128 or AES-256.
+func NewAESGCMSIV(key []byte) (*AESGCMSIV, error) {
+	if err := ValidateAESKeySize(uint32(len(key))); err != nil {
+		return nil, fmt.Errorf("aes_gcm_siv: %s", err)
+	}
+	return &AESGCMSIV{Key: key}, nil
+}
+
+// Encrypt encrypts pt with aad as additional authenticated data.
+// The resulting ciphertext consists of three parts:
+// (1) the nonce used for encryption, (2) the actual ciphertext and (3) the tag.
+func (a *AESGCMSIV) Encrypt(pt, aad []byte) ([]byte, error) {
+	if uint64(len(pt)) > aesGCMSIVMaxInputSize || uint64(len(aad)) > aesGCMSIVMaxInputSize {
+		return nil, errors.New("aes_gcm_siv: input too long")
+	}
+	nonce := random.GetRandomBytes(AESGCMSIVNonceSize)
+	authKey, encBlock, err := a.deriveKeys(nonce)
+	if err != nil {
+		return nil, err
+	}
+	tag := computeGCMSIVTag(authKey, encBlock, nonce, pt, aad)
+	ret := make([]byte, AESGCMSIVNonceSize+len(pt)+AESGCMSIVTagSize)
+	copy(ret, nonce)
+	gcmSIVCTR(encBlock, tag, ret[AESGCMSIVNonceSize:AESGCMSIVNonceSize+len(pt)], pt)
+	copy(ret[AESGCMSIVNonceSize+len(pt):], tag)
+	return ret, nil
+}
+
+// Decrypt decrypts ct with aad as the additional authenticated data.
+func (a *AESGCMSIV) Decrypt(ct, aad []byte) ([]byte, error) {
+	if len(ct) < AESGCMSIVNonceSize+AESGCMSIVTagSize {
+		return nil, errors.New("aes_gcm_siv: ciphertext too short")
+	}
+	if uint64(len(ct)) > aesGCMSIVMaxInputSize+AESGCMSIVNonceSize+AESGCMSIVTagSize || uint64(len(aad)) > aesGCMSIVMaxInputSize {
+		return nil, errors.New("aes_gcm_siv: input too long")
+	}
+	nonce := ct[:AESGCMSIVNonceSize]
+	tag := ct[len(ct)-AESGCMSIVTagSize:]
+	payload := ct[AESGCMSIVNonceSize : len(ct)-AESGCMSIVTagSize]
+	authKey, encBlock, err := a.deriveKeys(nonce)
+	if err != nil {
+		return nil, err
+	}
+	pt := make([]byte, len(payload))
+	gcmSIVCTR(encBlock, tag, pt, payload)
+	expectedTag := computeGCMSIVTag(authKey, encBlock, nonce, pt, aad)
+	if subtle.ConstantTimeCompare(expectedTag, tag) != 1 {
+		return nil, errors.New("aes_gcm_siv: message authentication failed")
+	}
+	return pt, nil
+}
+
+// deriveKeys derives the per-nonce POLYVAL key and AES encryption key as described
+// in section 4 of RFC 8452.
+func (a *AESGCMSIV) deriveKeys(nonce []byte) ([]byte, cipher.Block, error) {
+	block, err := aes.NewCipher(a.Key)
+	if err != nil {
+		return nil, nil, fmt.Errorf("aes_gcm_siv: %s", err)
+	}
+	numBlocks := 2 + len(a.Key)/8
+	derived := make([]byte, 0, 8*numBlocks)
+	in := make([]byte, aes.BlockSize)
+	out := make([]byte, aes.BlockSize)
+	copy(in[4:], nonce)
+	for i := 0; i < numBlocks; i++ {
+		binary.LittleEndian.PutUint32(in, uint32(i))
+		block.Encrypt(out, in)
+		derived = append(derived, out[:8]...)
+	}
+	encBlock, err := aes.NewCipher(derived[16:])
+	if err != nil {
+		return nil, nil, fmt.Errorf("aes_gcm_siv: %s", err)
+	}
+	return derived[:16], encBlock, nil
+}
+
+// computeGCMSIVTag computes the tag of pt and aad as described in section 4 of RFC 8452.
+func computeGCMSIVTag(authKey []byte, encBlock cipher.Block, nonce, pt, aad []byte) []byte {
+	p := newPolyval(authKey)
+	p.update(aad)
+	p.update(pt)
+	lengths := make([]byte, aes.BlockSize)
+	binary.LittleEndian.PutUint64(lengths, uint64(len(aad))*8)
+	binary.LittleEndian.PutUint64(lengths[8:], uint64(len(pt))*8)
+	p.update(lengths)
+	s := p.sum()
+	for i := range nonce {
+		s[i] ^= nonce[i]
+	}
+	s[aes.BlockSize-1] &= 0x7f
+	encBlock.Encrypt(s, s)
+	return s
+}
+
+// gcmSIVCTR encrypts or decrypts in to out in counter mode. The initial counter block is
+// the tag with the most significant bit of the last byte set; the counter is the first
+// 32 bits in little endian order and wraps around.
+func gcmSIVCTR(block cipher.Block, tag, out, in []byte) {
+	counter := make([]byte, aes.BlockSize)
+	copy(counter, tag)
+	counter[aes.BlockSize-1] |= 0x80
+	keyStream := make([]byte, aes.BlockSize)
+	for len(in) > 0 {
+		block.Encrypt(keyStream, counter)
+		binary.LittleEndian.PutUint32(counter, binary.LittleEndian.Uint32(counter)+1)
+		n := len(in)
+		if n > aes.BlockSize {
+			n = aes.BlockSize
+		}
+		for i := 0; i < n; i++ {
+			out[i] = in[i] ^ keyStream[i]
+		}
+		in = in[n:]
+		out = out[n:]
+	}
+}
+
+// polyval computes POLYVAL as defined in section 3 of RFC 8452. It is implemented
+// through the equivalent GHASH computation of appendix A, which allows to use
+// 4-bit multiplication tables:
+//
+//	POLYVAL(H, X_1, ..., X_n) =
+//	    ByteReverse(GHASH(mulX_GHASH(ByteReverse(H)), ByteReverse(X_1), ..., ByteReverse(X_n)))
+type polyval struct {
+	// productTable contains the multiples of the GHASH key by the 4-bit field
+	// elements, indexed by their bit-reversed value.
+	productTable [16]fieldElement
+	y            fieldElement
+}
+
+// fieldElement is an element of GF(2^128) in GHASH representation: low holds the
+// first 8 bytes and high the last 8 bytes of the block, in big endian order.
+type fieldElement struct {
+	low, high uint64
+}
+
+// ghashReductionTable is used to reduce the 4 bits that are shifted out in a
+// multiplication by x^4.
+var ghashReductionTable = []uint16{
+	0x0000, 0x1c20, 0x3840, 0x2460, 0x7080, 0x6ca0, 0x48c0, 0x54e0,
+	0xe100, 0xfd20, 0xd940, 0xc560, 0x9180, 0x8da0, 0xa9c0, 0xb5e0,
+}
+
+func newPolyval(key []byte) *polyval {
+	h := ghashDouble(reversedFieldElement(key))
+	p := new(polyval)
+	p.productTable[reverseBits(1)] = h
+	for i := 2; i < 16; i += 2 {
+		p.productTable[reverseBits(i)] = ghashDouble(p.productTable[reverseBits(i/2)])
+		p.productTable[reverseBits(i+1)] = ghashAdd(p.productTable[reverseBits(i)], h)
+	}
+	return p
+}
+
+// update absorbs data, which is zero padded to a multiple of the block size.
+func (p *polyval) update(data []byte) {
+	block := make([]byte, aes.BlockSize)
+	for len(data) > 0 {
+		n := copy(block, data)
+		for i := n; i < aes.BlockSize; i++ {
+			block[i] = 0
+		}
+		data = data[n:]
+		p.y = ghashAdd(p.y, reversedFieldElement(block))
+		p.mul()
+	}
+}
+
+// sum returns the POLYVAL of the data absorbed so far.
+func (p *polyval) sum() []byte {
+	out := make([]byte, aes.BlockSize)
+	binary.LittleEndian.PutUint64(out, p.y.high)
+	binary.LittleEndian.PutUint64(out[8:], p.y.low)
+	return out
+}
+
+// mul sets y to y*H.
+func (p *polyval) mul() {
+	var z fieldElement
+	for i := 0; i < 2; i++ {
+		word := p.y.high
+		if i == 1 {
+			word = p.y.low
+		}
+		for j := 0; j < 64; j += 4 {
+			msw := z.high & 0xf
+			z.high >>= 4
+			z.high |= z.low << 60
+			z.low >>= 4
+			z.low ^= uint64(ghashReductionTable[msw]) << 48
+			t := p.productTable[word&0xf]
+			z.low ^= t.low
+			z.high ^= t.high
+			word >>= 4
+		}
+	}
+	p.y = z
+}
+
+// reversedFieldElement returns the GHASH field element of the byte reversed block.
+func reversedFieldElement(block []byte) fieldElement {
+	return fieldElement{
+		low:  binary.LittleEndian.Uint64(block[8:]),
+		high: binary.LittleEndian.Uint64(block[:8]),
+	}
+}
+
+// ghashDouble multiplies x by the generator of the GHASH field.
+func ghashDouble(x fieldElement) fieldElement {
+	msbSet := x.high&1 == 1
+	var double fieldElement
+	double.high = x.high >> 1
+	double.high |= x.low << 63
+	double.low = x.low >> 1
+	if msbSet {
+		double.low ^= 0xe100000000000000
+	}
+	return double
+}
+
+func ghashAdd(x, y fieldElement) fieldElement {
+	return fieldElement{x.low ^ y.low, x.high ^ y.high}
+}
+
+// reverseBits reverses the order of the bits of a 4-bit number.
+func reverseBits(i int) int {
+	i = ((i << 2) & 0xc) | ((i >> 2) & 0x3)
+	i = ((i << 1) & 0xa) | ((i >> 1) & 0x5)
+	return i
+}
@@ -0,0 +1,95 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package aead_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/tsingson/tink/golang/subtle/aead"
+	"github.com/tsingson/tink/golang/subtle/random"
+	"github.com/tsingson/tink/golang/tink"
+)
+
+func TestAESGCMSIVEncryptDecrypt(t *testing.T) {
+	for _, keySize := range []uint32{16, 32} {
+		key := random.GetRandomBytes(keySize)
+		a, err := aead.NewAESGCMSIV(key)
+		if err != nil {
+			t.Fatalf("cannot create new instance of AESGCMSIV: %s", err)
+		}
+		for ptSize := 0; ptSize < 75; ptSize++ {
+			pt := random.GetRandomBytes(uint32(ptSize))
+			aad := random.GetRandomBytes(uint32(ptSize % 20))
+			ct, err := a.Encrypt(pt, aad)
+			if err != nil {
+				t.Fatalf("encryption failed: %s", err)
+			}
+			if len(ct) != aead.AESGCMSIVNonceSize+ptSize+aead.AESGCMSIVTagSize {
+				t.Errorf("unexpected ciphertext length %d", len(ct))
+			}
+			decrypted, err := a.Decrypt(ct, aad)
+			if err != nil {
+				t.Fatalf("decryption failed: %s", err)
+			}
+			if !bytes.Equal(decrypted, pt) {
+				t.Errorf("decryption is not inverse of encryption")
+			}
+		}
+	}
+}
+
+func TestAESGCMSIVLongMessages(t *testing.T) {
+	a, err := aead.NewAESGCMSIV(random.GetRandomBytes(32))
+	if err != nil {
+		t.Fatalf("cannot create new instance of AESGCMSIV: %s", err)
+	}
+	for ptSize := 16; ptSize <= 1<<20; ptSize *= 4 {
+		pt := random.GetRandomBytes(uint32(ptSize))
+		ct, err := a.Encrypt(pt, nil)
+		if err != nil {
+			t.Fatalf("encryption failed: %s", err)
+		}
+		decrypted, err := a.Decrypt(ct, nil)
+		if err != nil || !bytes.Equal(decrypted, pt) {
+			t.Errorf("decryption failed for plaintext of %d bytes: %v", ptSize, err)
+		}
+	}
+}
+
+func TestAESGCMSIVModifyCiphertext(t *testing.T) {
+	a, err := aead.NewAESGCMSIV(random.GetRandomBytes(16))
+	if err != nil {
+		t.Fatalf("cannot create new instance of AESGCMSIV: %s", err)
+	}
+	testModifyCiphertext(t, a)
+}
+
+func TestAESGCMSIVKeySize(t *testing.T) {
+	for _, keySize := range []uint32{0, 15, 24, 33} {
+		if _, err := aead.NewAESGCMSIV(random.GetRandomBytes(keySize)); err == nil {
+			t.Errorf("expect an error when key size is %d", keySize)
+		}
+	}
+}
+
+func TestAESGCMSIVVectors(t *testing.T) {
+	runWycheproofDecryptionTests(t, "aes_gcm_siv_test.json", func(key []byte, ivSize int) (tink.AEAD, error) {
+		if ivSize != aead.AESGCMSIVNonceSize {
+			return nil, nil
+		}
+		return aead.NewAESGCMSIV(key)
+	})
+}
@@ -20,8 +20,8 @@ import (
 	"crypto/cipher"
 	"errors"
 	"fmt"
-	"math"
 
+	"github.com/tsingson/tink/golang/subtle/internal/cmac"
 	"github.com/tsingson/tink/golang/tink"
 )
 
@@ -45,11 +45,10 @@ import (
 // and RFC 5297 only supports same size encryption and MAC keys this
 // implies that keys must be 64 bytes (2*256 bits) long.
 type AESSIV struct {
-	K1     []byte
-	K2     []byte
-	CmacK1 []byte
-	CmacK2 []byte
-	Cipher cipher.Block
+	K1 []byte
+	K2 []byte
+
+	cmac *cmac.CMAC
 }
 
 // AESSIVKeySize is the key size in bytes.
@@ -71,39 +70,13 @@ func NewAESSIV(key []byte) (*AESSIV, error) {
 		return nil, fmt.Errorf("aes_siv: aes.NewCipher(%s) failed, %v", k1, err)
 	}
 
-	block := make([]byte, aes.BlockSize)
-	c.Encrypt(block, block)
-	multiplyByX(block)
-	cmacK1 := make([]byte, aes.BlockSize)
-	copy(cmacK1, block)
-	multiplyByX(block)
-	cmacK2 := make([]byte, aes.BlockSize)
-	copy(cmacK2, block)
-
 	return &AESSIV{
-		K1:     k1,
-		K2:     k2,
-		CmacK1: cmacK1,
-		CmacK2: cmacK2,
-		Cipher: c,
+		K1:   k1,
+		K2:   k2,
+		cmac: cmac.New(c),
 	}, nil
 }
 
-// multiplyByX multiplies an element in GF(2^128) by its generator.
-// This functions is incorrectly named "doubling" in section 2.3 of RFC 5297.
-func multiplyByX(block []byte) {
-	carry := block[0] >> 7
-	for i := 0; i < aes.BlockSize-1; i++ {
-		block[i] = (block[i] << 1) | (block[i+1] >> 7)
-	}
-
-	if carry == 1 {
-		block[aes.BlockSize-1] = (block[aes.BlockSize-1] << 1) ^ 0x87
-	} else {
-		block[aes.BlockSize-1] = (block[aes.BlockSize-1] << 1)
-	}
-}
-
 // EncryptDeterministically deterministically encrypts plaintext with additionalData as
 // additional authenticated data.
 func (asc *AESSIV) EncryptDeterministically(pt, aad []byte) ([]byte, error) {
@@ -163,85 +136,26 @@ func (asc *AESSIV) ctrCrypt(siv, in, out []byte) error {
 
 // s2v is a Pseudo-Random Function (PRF) construction: https://tools.ietf.org/html/rfc5297.
 func (asc *AESSIV) s2v(msg, aad, siv []byte) {
-	block := make([]byte, aes.BlockSize)
-	asc.cmac(block, block)
-	multiplyByX(block)
-
-	aadMac := make([]byte, aes.BlockSize)
-	asc.cmac(aad, aadMac)
-	xorBlock(aadMac, block)
+	d := asc.cmac.Sum(make([]byte, aes.BlockSize))
+	cmac.MultiplyByX(d)
+	xorBlock(asc.cmac.Sum(aad), d)
 
+	var t []byte
 	if len(msg) >= aes.BlockSize {
-		asc.cmacLong(msg, block, siv)
+		// t is msg with d xored onto its last block.
+		t = make([]byte, len(msg))
+		copy(t, msg)
+		xorBlock(d, t[len(t)-aes.BlockSize:])
 	} else {
-		multiplyByX(block)
+		// t is the padded msg xored with the doubled d.
+		cmac.MultiplyByX(d)
 		for i := 0; i < len(msg); i++ {
-			block[i] ^= msg[i]
+			d[i] ^= msg[i]
 		}
-		block[len(msg)] ^= 0x80
-		asc.cmac(block, siv)
-	}
-}
-
-// cmacLong computes CMAC(XorEnd(data, last)), where XorEnd xors the bytes in last to the last bytes in data.
-// The size of the data must be at least 16 bytes.
-func (asc *AESSIV) cmacLong(data, last, mac []byte) {
-	block := make([]byte, aes.BlockSize)
-	copy(block, data[:aes.BlockSize])
-
-	idx := aes.BlockSize
-	for aes.BlockSize <= len(data)-idx {
-		asc.Cipher.Encrypt(block, block)
-		xorBlock(data[idx:idx+aes.BlockSize], block)
-		idx += aes.BlockSize
-	}
-
-	remaining := len(data) - idx
-	for i := 0; i < aes.BlockSize-remaining; i++ {
-		block[remaining+i] ^= last[i]
+		d[len(msg)] ^= 0x80
+		t = d
 	}
-	if remaining == 0 {
-		xorBlock(asc.CmacK1, block)
-	} else {
-		asc.Cipher.Encrypt(block, block)
-		for i := 0; i < remaining; i++ {
-			block[i] ^= last[aes.BlockSize-remaining+i]
-			block[i] ^= data[idx+i]
-		}
-		block[remaining] ^= 0x80
-		xorBlock(asc.CmacK2, block)
-	}
-
-	asc.Cipher.Encrypt(mac, block)
-}
-
-// cmac computes a CMAC of some data.
-func (asc *AESSIV) cmac(data, mac []byte) {
-	numBs := int(math.Ceil(float64(len(data)) / aes.BlockSize))
-	if numBs == 0 {
-		numBs = 1
-	}
-	lastBSize := len(data) - (numBs-1)*aes.BlockSize
-
-	block := make([]byte, aes.BlockSize)
-	idx := 0
-	for i := 0; i < numBs-1; i++ {
-		xorBlock(data[idx:idx+aes.BlockSize], block)
-		asc.Cipher.Encrypt(block, block)
-		idx += aes.BlockSize
-	}
-	for j := 0; j < lastBSize; j++ {
-		block[j] ^= data[idx+j]
-	}
-
-	if lastBSize == aes.BlockSize {
-		xorBlock(asc.CmacK1, block)
-	} else {
-		block[lastBSize] ^= 0x80
-		xorBlock(asc.CmacK2, block)
-	}
-
-	asc.Cipher.Encrypt(mac, block)
+	copy(siv, asc.cmac.Sum(t))
 }
 
 // xorBlock sets block[i] = x[i] ^ block[i].
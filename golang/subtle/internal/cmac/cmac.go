@@ -0,0 +1,102 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+// Package cmac implements the AES-CMAC construction of RFC 4493 shared by the
+// subtle MAC, AEAD and deterministic AEAD implementations.
+package cmac
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+)
+
+// CMAC computes untruncated AES-CMAC tags with a fixed key.
+type CMAC struct {
+	block cipher.Block
+	k1    []byte
+	k2    []byte
+}
+
+// New returns a CMAC that uses the given AES block cipher.
+func New(block cipher.Block) *CMAC {
+	// Derive the subkeys as described in section 2.3 of RFC 4493.
+	k1 := make([]byte, aes.BlockSize)
+	block.Encrypt(k1, k1)
+	MultiplyByX(k1)
+	k2 := make([]byte, aes.BlockSize)
+	copy(k2, k1)
+	MultiplyByX(k2)
+	return &CMAC{
+		block: block,
+		k1:    k1,
+		k2:    k2,
+	}
+}
+
+// Sum returns the CMAC of data.
+func (c *CMAC) Sum(data []byte) []byte {
+	return c.sum(make([]byte, aes.BlockSize), data)
+}
+
+// SumAfterBlock returns the CMAC of first followed by data, where first is a
+// single full block.
+func (c *CMAC) SumAfterBlock(first, data []byte) []byte {
+	if len(data) == 0 {
+		return c.Sum(first)
+	}
+	x := make([]byte, aes.BlockSize)
+	copy(x, first)
+	c.block.Encrypt(x, x)
+	return c.sum(x, data)
+}
+
+// sum continues the CMAC computation from the chaining value x over data.
+func (c *CMAC) sum(x, data []byte) []byte {
+	// All blocks but the last one are processed in the loop; the last block is
+	// padded if it is incomplete and masked with the corresponding subkey.
+	for len(data) > aes.BlockSize {
+		xorBlock(x, data[:aes.BlockSize])
+		c.block.Encrypt(x, x)
+		data = data[aes.BlockSize:]
+	}
+	xorBlock(x, data)
+	if len(data) == aes.BlockSize {
+		xorBlock(x, c.k1)
+	} else {
+		x[len(data)] ^= 0x80
+		xorBlock(x, c.k2)
+	}
+	c.block.Encrypt(x, x)
+	return x
+}
+
+// MultiplyByX multiplies an element in GF(2^128) by its generator. This is the
+// "doubling" operation of RFC 4493 and RFC 5297.
+func MultiplyByX(block []byte) {
+	carry := block[0] >> 7
+	for i := 0; i < aes.BlockSize-1; i++ {
+		block[i] = (block[i] << 1) | (block[i+1] >> 7)
+	}
+	block[aes.BlockSize-1] <<= 1
+	if carry == 1 {
+		block[aes.BlockSize-1] ^= 0x87
+	}
+}
+
+// xorBlock sets dst[i] ^= src[i] for all bytes of src.
+func xorBlock(dst, src []byte) {
+	for i := range src {
+		dst[i] ^= src[i]
+	}
+}
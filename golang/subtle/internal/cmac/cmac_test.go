@@ -0,0 +1,69 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package cmac_test
+
+import (
+	"bytes"
+	"crypto/aes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/tsingson/tink/golang/subtle/internal/cmac"
+)
+
+const rfc4493Key = "2b7e151628aed2a6abf7158809cf4f3c"
+
+const rfc4493Message = "6bc1bee22e409f96e93d7e117393172aae2d8a571e03ac9c9eb76fac45af8e5130c81c46a35ce411e5fbc1191a0a52eff69f2445df4f9b17ad2b417be66c3710"
+
+func newCMAC(t *testing.T) *cmac.CMAC {
+	t.Helper()
+	key, _ := hex.DecodeString(rfc4493Key)
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		t.Fatalf("aes.NewCipher() err = %v", err)
+	}
+	return cmac.New(block)
+}
+
+func TestSum(t *testing.T) {
+	// Test vectors from RFC 4493, section 4.
+	tests := []struct {
+		length int
+		tag    string
+	}{
+		{0, "bb1d6929e95937287fa37d129b756746"},
+		{16, "070a16b46b4d4144f79bdd9dd04a287c"},
+		{40, "dfa66747de9ae63030ca32611497c827"},
+		{64, "51f0bebf7e3b9d92fc49741779363cfe"},
+	}
+	c := newCMAC(t)
+	msg, _ := hex.DecodeString(rfc4493Message)
+	for _, tc := range tests {
+		if got := hex.EncodeToString(c.Sum(msg[:tc.length])); got != tc.tag {
+			t.Errorf("Sum(msg[:%d]) = %s, want %s", tc.length, got, tc.tag)
+		}
+	}
+}
+
+func TestSumAfterBlock(t *testing.T) {
+	c := newCMAC(t)
+	msg, _ := hex.DecodeString(rfc4493Message)
+	for _, n := range []int{16, 17, 32, 40, 64} {
+		want := c.Sum(msg[:n])
+		if got := c.SumAfterBlock(msg[:aes.BlockSize], msg[aes.BlockSize:n]); !bytes.Equal(got, want) {
+			t.Errorf("SumAfterBlock(msg[:16], msg[16:%d]) = %x, want %x", n, got, want)
+		}
+	}
+}
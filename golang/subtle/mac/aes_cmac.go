@@ -0,0 +1,97 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package mac
+
+import (
+	"crypto/aes"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+
+	"github.com/tsingson/tink/golang/subtle/internal/cmac"
+	"github.com/tsingson/tink/golang/tink"
+)
+
+const (
+	// AESCMACMinTagSizeInBytes is the minimum tag size of AES-CMAC in bytes.
+	AESCMACMinTagSizeInBytes = uint32(10)
+	// AESCMACMaxTagSizeInBytes is the maximum tag size of AES-CMAC in bytes.
+	AESCMACMaxTagSizeInBytes = uint32(aes.BlockSize)
+)
+
+var errAESCMACInvalidInput = errors.New("aes_cmac: invalid input")
+
+// AESCMAC is an implementation of AES-CMAC as defined in RFC 4493.
+type AESCMAC struct {
+	cmac    *cmac.CMAC
+	tagSize uint32
+}
+
+// This makes sure that AESCMAC implements the tink.MAC interface
+var _ tink.MAC = (*AESCMAC)(nil)
+
+// NewAESCMAC creates a new instance of AESCMAC with the specified key and tag size.
+func NewAESCMAC(key []byte, tagSize uint32) (*AESCMAC, error) {
+	if err := ValidateAESCMACParams(uint32(len(key)), tagSize); err != nil {
+		return nil, fmt.Errorf("aes_cmac: %s", err)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("aes_cmac: %s", err)
+	}
+	return &AESCMAC{
+		cmac:    cmac.New(block),
+		tagSize: tagSize,
+	}, nil
+}
+
+// ValidateAESCMACParams validates parameters of AESCMAC constructor. Only 16 and
+// 32 byte keys are supported.
+func ValidateAESCMACParams(keySize uint32, tagSize uint32) error {
+	if keySize != 16 && keySize != 32 {
+		return fmt.Errorf("invalid key size %d", keySize)
+	}
+	if tagSize > AESCMACMaxTagSizeInBytes {
+		return fmt.Errorf("tag size too big")
+	}
+	if tagSize < AESCMACMinTagSizeInBytes {
+		return fmt.Errorf("tag size too small")
+	}
+	return nil
+}
+
+// ComputeMAC computes message authentication code (MAC) for the given data.
+func (a *AESCMAC) ComputeMAC(data []byte) ([]byte, error) {
+	if data == nil {
+		return nil, errAESCMACInvalidInput
+	}
+	return a.cmac.Sum(data)[:a.tagSize], nil
+}
+
+// VerifyMAC verifies whether the given MAC is a correct authentication code (MAC)
+// the given data.
+func (a *AESCMAC) VerifyMAC(mac []byte, data []byte) error {
+	if mac == nil || data == nil {
+		return errAESCMACInvalidInput
+	}
+	expectedMAC, err := a.ComputeMAC(data)
+	if err != nil {
+		return err
+	}
+	if subtle.ConstantTimeCompare(expectedMAC, mac) == 1 {
+		return nil
+	}
+	return errors.New("aes_cmac: invalid MAC")
+}
@@ -0,0 +1,231 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package mac_test
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"testing"
+
+	"github.com/tsingson/tink/golang/subtle/mac"
+	"github.com/tsingson/tink/golang/subtle/random"
+	"github.com/tsingson/tink/golang/testutil"
+)
+
+// Test vectors from RFC 4493, section 4.
+var aesCMACTests = []struct {
+	key         string
+	data        string
+	expectedMac string
+}{
+	{
+		key:         "2b7e151628aed2a6abf7158809cf4f3c",
+		data:        "",
+		expectedMac: "bb1d6929e95937287fa37d129b756746",
+	},
+	{
+		key:         "2b7e151628aed2a6abf7158809cf4f3c",
+		data:        "6bc1bee22e409f96e93d7e117393172a",
+		expectedMac: "070a16b46b4d4144f79bdd9dd04a287c",
+	},
+	{
+		key:         "2b7e151628aed2a6abf7158809cf4f3c",
+		data:        "6bc1bee22e409f96e93d7e117393172aae2d8a571e03ac9c9eb76fac45af8e5130c81c46a35ce411",
+		expectedMac: "dfa66747de9ae63030ca32611497c827",
+	},
+	{
+		key:         "2b7e151628aed2a6abf7158809cf4f3c",
+		data:        "6bc1bee22e409f96e93d7e117393172aae2d8a571e03ac9c9eb76fac45af8e5130c81c46a35ce411e5fbc1191a0a52eff69f2445df4f9b17ad2b417be66c3710",
+		expectedMac: "51f0bebf7e3b9d92fc49741779363cfe",
+	},
+}
+
+func TestAESCMACBasic(t *testing.T) {
+	for i, test := range aesCMACTests {
+		key, _ := hex.DecodeString(test.key)
+		data, _ := hex.DecodeString(test.data)
+		cipher, err := mac.NewAESCMAC(key, 16)
+		if err != nil {
+			t.Fatalf("cannot create new mac in test case %d: %s", i, err)
+		}
+		mac, err := cipher.ComputeMAC(data)
+		if err != nil {
+			t.Errorf("mac computation failed in test case %d: %s", i, err)
+		}
+		if hex.EncodeToString(mac) != test.expectedMac {
+			t.Errorf("incorrect mac in test case %d: expect %s, got %s", i, test.expectedMac, hex.EncodeToString(mac))
+		}
+		if err := cipher.VerifyMAC(mac, data); err != nil {
+			t.Errorf("mac verification failed in test case %d: %s", i, err)
+		}
+	}
+}
+
+func TestAESCMACTruncatedTag(t *testing.T) {
+	key := random.GetRandomBytes(32)
+	data := random.GetRandomBytes(100)
+	full, err := mac.NewAESCMAC(key, 16)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	fullTag, _ := full.ComputeMAC(data)
+	for tagSize := uint32(10); tagSize <= 16; tagSize++ {
+		cipher, err := mac.NewAESCMAC(key, tagSize)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		tag, err := cipher.ComputeMAC(data)
+		if err != nil {
+			t.Fatalf("mac computation failed: %s", err)
+		}
+		if hex.EncodeToString(tag) != hex.EncodeToString(fullTag[:tagSize]) {
+			t.Errorf("tag of size %d is not a prefix of the full tag", tagSize)
+		}
+	}
+}
+
+func TestAESCMACModification(t *testing.T) {
+	cipher, err := mac.NewAESCMAC(random.GetRandomBytes(16), 16)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	data := random.GetRandomBytes(40)
+	tag, _ := cipher.ComputeMAC(data)
+	for i := 0; i < len(tag); i++ {
+		modified := append([]byte{}, tag...)
+		modified[i] ^= 1
+		if err := cipher.VerifyMAC(modified, data); err == nil {
+			t.Errorf("expect an error when byte %d of the tag is modified", i)
+		}
+	}
+	for i := 0; i < len(data); i++ {
+		modified := append([]byte{}, data...)
+		modified[i] ^= 1
+		if err := cipher.VerifyMAC(tag, modified); err == nil {
+			t.Errorf("expect an error when byte %d of the data is modified", i)
+		}
+	}
+	if err := cipher.VerifyMAC(tag[:15], data); err == nil {
+		t.Errorf("expect an error when the tag is truncated")
+	}
+}
+
+func TestAESCMACWithInvalidInput(t *testing.T) {
+	if _, err := mac.NewAESCMAC(random.GetRandomBytes(24), 16); err == nil {
+		t.Errorf("expect an error when key size is 24 bytes")
+	}
+	if _, err := mac.NewAESCMAC(random.GetRandomBytes(15), 16); err == nil {
+		t.Errorf("expect an error when key is too short")
+	}
+	if _, err := mac.NewAESCMAC(random.GetRandomBytes(32), 9); err == nil {
+		t.Errorf("expect an error when tag size is too small")
+	}
+	if _, err := mac.NewAESCMAC(random.GetRandomBytes(32), 17); err == nil {
+		t.Errorf("expect an error when tag size is too big")
+	}
+	cipher, err := mac.NewAESCMAC(random.GetRandomBytes(32), 16)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if _, err := cipher.ComputeMAC(nil); err == nil {
+		t.Errorf("expect an error when input is nil")
+	}
+	if err := cipher.VerifyMAC(nil, []byte{1}); err == nil {
+		t.Errorf("expect an error when mac is nil")
+	}
+	if err := cipher.VerifyMAC([]byte{1}, nil); err == nil {
+		t.Errorf("expect an error when data is nil")
+	}
+}
+
+type aesCMACCase struct {
+	testutil.WycheproofCase
+	Key     string `json:"key"`
+	Message string `json:"msg"`
+	Tag     string `json:"tag"`
+}
+
+type aesCMACGroup struct {
+	testutil.WycheproofGroup
+	KeySize uint32         `json:"keySize"`
+	TagSize uint32         `json:"tagSize"`
+	Tests   []*aesCMACCase `json:"tests"`
+}
+
+type aesCMACSuite struct {
+	testutil.WycheproofSuite
+	Groups []*aesCMACGroup `json:"testGroups"`
+}
+
+func TestAESCMACWycheproofCases(t *testing.T) {
+	suiteBytes, err := ioutil.ReadFile("../../../third_party/wycheproof/testvectors/aes_cmac_test.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	suite := new(aesCMACSuite)
+	if err := json.Unmarshal(suiteBytes, suite); err != nil {
+		t.Fatal(err)
+	}
+	for _, group := range suite.Groups {
+		for _, test := range group.Tests {
+			caseName := fmt.Sprintf("%s-%s(%d,%d):Case-%d",
+				suite.Algorithm, group.Type, group.KeySize, group.TagSize, test.CaseID)
+			t.Run(caseName, func(t *testing.T) { runAESCMACWycheproofCase(t, group, test) })
+		}
+	}
+}
+
+func runAESCMACWycheproofCase(t *testing.T, group *aesCMACGroup, testCase *aesCMACCase) {
+	key, err := hex.DecodeString(testCase.Key)
+	if err != nil {
+		t.Fatalf("hex.DecodeString(testCase.Key) => %v", err)
+	}
+	msg, err := hex.DecodeString(testCase.Message)
+	if err != nil {
+		t.Fatalf("hex.DecodeString(testCase.Message) => %v", err)
+	}
+	tag, err := hex.DecodeString(testCase.Tag)
+	if err != nil {
+		t.Fatalf("hex.DecodeString(testCase.Tag) => %v", err)
+	}
+	cipher, err := mac.NewAESCMAC(key, group.TagSize/8)
+	if err != nil {
+		// 192-bit keys are not supported, neither are invalid key sizes.
+		if group.KeySize == 128 || group.KeySize == 256 {
+			t.Fatalf("cannot create AESCMAC: %v", err)
+		}
+		return
+	}
+	computed, err := cipher.ComputeMAC(msg)
+	if err != nil {
+		t.Fatalf("cannot compute mac: %v", err)
+	}
+	err = cipher.VerifyMAC(tag, msg)
+	switch testCase.Result {
+	case "valid":
+		if hex.EncodeToString(computed) != testCase.Tag {
+			t.Errorf("computed tag mismatches test vector")
+		}
+		if err != nil {
+			t.Errorf("cannot verify mac: %v", err)
+		}
+	case "invalid":
+		if err == nil {
+			t.Errorf("mac verification succeeded for invalid case")
+		}
+	}
+}
@@ -0,0 +1,113 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package signature
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"hash"
+
+	"github.com/tsingson/tink/golang/subtle"
+)
+
+const (
+	// RSAMinModulusSizeInBits is the minimum RSA modulus size in bits.
+	RSAMinModulusSizeInBits = 2048
+	// RSAMinPublicExponent is the minimum RSA public exponent. It is also the
+	// only exponent used when generating new keys (F4).
+	RSAMinPublicExponent = 65537
+	// RSAMaxPublicExponent is the maximum RSA public exponent supported by crypto/rsa.
+	RSAMaxPublicExponent = 1<<31 - 1
+)
+
+var errInvalidRSASignature = errors.New("rsa: invalid signature")
+
+// ValidateRSAModulusSize checks that the given RSA modulus size is large enough.
+func ValidateRSAModulusSize(modulusSizeInBits int) error {
+	if modulusSizeInBits < RSAMinModulusSizeInBits {
+		return fmt.Errorf("modulus size too small, must be at least %d bits", RSAMinModulusSizeInBits)
+	}
+	return nil
+}
+
+// ValidateRSAPublicExponent checks that the given RSA public exponent is odd
+// and within the supported range.
+func ValidateRSAPublicExponent(e int) error {
+	if e < RSAMinPublicExponent || e > RSAMaxPublicExponent {
+		return fmt.Errorf("public exponent must be between %d and %d", RSAMinPublicExponent, RSAMaxPublicExponent)
+	}
+	if e%2 != 1 {
+		return errors.New("public exponent must be odd")
+	}
+	return nil
+}
+
+// ValidateRSAHash checks that the given hash function is safe to use with RSA signatures.
+func ValidateRSAHash(hashAlg string) error {
+	switch hashAlg {
+	case "SHA256", "SHA384", "SHA512":
+		return nil
+	default:
+		return fmt.Errorf("unsupported hash function: %s", hashAlg)
+	}
+}
+
+// ValidateRSAPublicKey checks the modulus size and the public exponent of the given key.
+func ValidateRSAPublicKey(publicKey *rsa.PublicKey) error {
+	if publicKey == nil || publicKey.N == nil {
+		return errors.New("invalid public key")
+	}
+	if err := ValidateRSAModulusSize(publicKey.N.BitLen()); err != nil {
+		return err
+	}
+	return ValidateRSAPublicExponent(publicKey.E)
+}
+
+// rsaHashFunc returns the hash function and its identifier for the given hash name.
+func rsaHashFunc(hashAlg string) (func() hash.Hash, crypto.Hash, error) {
+	if err := ValidateRSAHash(hashAlg); err != nil {
+		return nil, 0, err
+	}
+	switch hashAlg {
+	case "SHA256":
+		return subtle.GetHashFunc(hashAlg), crypto.SHA256, nil
+	case "SHA384":
+		return subtle.GetHashFunc(hashAlg), crypto.SHA384, nil
+	default:
+		return subtle.GetHashFunc(hashAlg), crypto.SHA512, nil
+	}
+}
+
+// ValidateRSASSAPSSParams validates RSA-SSA-PSS parameters.
+// crypto/rsa always uses the signature hash for MGF1, and interprets a zero
+// salt length as "auto", so the MGF1 hash must equal the signature hash and
+// the salt length must be positive.
+func ValidateRSASSAPSSParams(sigHash, mgf1Hash string, saltLength int) error {
+	_, _, err := rsaPSSParams(sigHash, mgf1Hash, saltLength)
+	return err
+}
+
+// rsaPSSParams validates the RSA-SSA-PSS parameters and returns the hash function and its identifier.
+func rsaPSSParams(sigHash, mgf1Hash string, saltLength int) (func() hash.Hash, crypto.Hash, error) {
+	if sigHash != mgf1Hash {
+		return nil, 0, fmt.Errorf("MGF1 hash %s must match signature hash %s", mgf1Hash, sigHash)
+	}
+	if saltLength <= 0 {
+		return nil, 0, fmt.Errorf("invalid salt length: %d", saltLength)
+	}
+	return rsaHashFunc(sigHash)
+}
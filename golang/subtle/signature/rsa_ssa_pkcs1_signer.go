@@ -0,0 +1,73 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package signature
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"fmt"
+	"hash"
+
+	"github.com/tsingson/tink/golang/subtle"
+	"github.com/tsingson/tink/golang/tink"
+)
+
+// RSASSAPKCS1Signer is an implementation of Signer for RSA-SSA-PKCS1 v1.5.
+type RSASSAPKCS1Signer struct {
+	privateKey *rsa.PrivateKey
+	hashFunc   func() hash.Hash
+	hashID     crypto.Hash
+}
+
+// Assert that RSASSAPKCS1Signer implements the Signer interface.
+var _ tink.Signer = (*RSASSAPKCS1Signer)(nil)
+
+// NewRSASSAPKCS1Signer creates a new instance of RSASSAPKCS1Signer.
+func NewRSASSAPKCS1Signer(hashAlg string, privateKey *rsa.PrivateKey) (*RSASSAPKCS1Signer, error) {
+	if privateKey == nil {
+		return nil, fmt.Errorf("rsa_ssa_pkcs1_signer: privateKey can't be nil")
+	}
+	if err := ValidateRSAPublicKey(&privateKey.PublicKey); err != nil {
+		return nil, fmt.Errorf("rsa_ssa_pkcs1_signer: %s", err)
+	}
+	// Precompute uses the CRT values of the key if they are already set.
+	privateKey.Precompute()
+	if err := privateKey.Validate(); err != nil {
+		return nil, fmt.Errorf("rsa_ssa_pkcs1_signer: invalid private key: %s", err)
+	}
+	hashFunc, hashID, err := rsaHashFunc(hashAlg)
+	if err != nil {
+		return nil, fmt.Errorf("rsa_ssa_pkcs1_signer: %s", err)
+	}
+	return &RSASSAPKCS1Signer{
+		privateKey: privateKey,
+		hashFunc:   hashFunc,
+		hashID:     hashID,
+	}, nil
+}
+
+// Sign computes a signature for the given data.
+func (s *RSASSAPKCS1Signer) Sign(data []byte) ([]byte, error) {
+	hashed, err := subtle.ComputeHash(s.hashFunc, data)
+	if err != nil {
+		return nil, err
+	}
+	ret, err := rsa.SignPKCS1v15(rand.Reader, s.privateKey, s.hashID, hashed)
+	if err != nil {
+		return nil, fmt.Errorf("rsa_ssa_pkcs1_signer: signing failed: %s", err)
+	}
+	return ret, nil
+}
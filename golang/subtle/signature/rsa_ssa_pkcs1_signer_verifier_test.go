@@ -0,0 +1,201 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package signature_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/tsingson/tink/golang/subtle"
+	"github.com/tsingson/tink/golang/subtle/random"
+	subtleSignature "github.com/tsingson/tink/golang/subtle/signature"
+)
+
+func TestRSASSAPKCS1SignVerify(t *testing.T) {
+	priv, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("cannot generate RSA key: %s", err)
+	}
+	for _, hash := range []string{"SHA256", "SHA384", "SHA512"} {
+		signer, err := subtleSignature.NewRSASSAPKCS1Signer(hash, priv)
+		if err != nil {
+			t.Errorf("unexpected error when creating RSASSAPKCS1Signer: %s", err)
+		}
+		verifier, err := subtleSignature.NewRSASSAPKCS1Verifier(hash, &priv.PublicKey)
+		if err != nil {
+			t.Errorf("unexpected error when creating RSASSAPKCS1Verifier: %s", err)
+		}
+		data := random.GetRandomBytes(20)
+		signature, err := signer.Sign(data)
+		if err != nil {
+			t.Errorf("unexpected error when signing: %s", err)
+		}
+		if err := verifier.Verify(signature, data); err != nil {
+			t.Errorf("unexpected error when verifying: %s", err)
+		}
+		signature[0] ^= 1
+		if err := verifier.Verify(signature, data); err == nil {
+			t.Errorf("expect an error when verifying a modified signature")
+		}
+		signature[0] ^= 1
+		if err := verifier.Verify(signature, append(data, 0)); err == nil {
+			t.Errorf("expect an error when verifying modified data")
+		}
+	}
+}
+
+func TestRSASSAPKCS1WithInvalidInput(t *testing.T) {
+	priv, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("cannot generate RSA key: %s", err)
+	}
+	// weak or unsupported hash
+	for _, hash := range []string{"SHA1", "SHA224", ""} {
+		if _, err := subtleSignature.NewRSASSAPKCS1Signer(hash, priv); err == nil {
+			t.Errorf("expect an error with hash %q", hash)
+		}
+		if _, err := subtleSignature.NewRSASSAPKCS1Verifier(hash, &priv.PublicKey); err == nil {
+			t.Errorf("expect an error with hash %q", hash)
+		}
+	}
+	// small modulus
+	small, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatalf("cannot generate RSA key: %s", err)
+	}
+	if _, err := subtleSignature.NewRSASSAPKCS1Signer("SHA256", small); err == nil {
+		t.Errorf("expect an error with a 1024-bit modulus")
+	}
+	if _, err := subtleSignature.NewRSASSAPKCS1Verifier("SHA256", &small.PublicKey); err == nil {
+		t.Errorf("expect an error with a 1024-bit modulus")
+	}
+	// small or even public exponent
+	for _, e := range []int{3, 65536, 65538} {
+		pub := &rsa.PublicKey{N: priv.N, E: e}
+		if _, err := subtleSignature.NewRSASSAPKCS1Verifier("SHA256", pub); err == nil {
+			t.Errorf("expect an error with public exponent %d", e)
+		}
+	}
+	// nil keys
+	if _, err := subtleSignature.NewRSASSAPKCS1Signer("SHA256", nil); err == nil {
+		t.Errorf("expect an error with a nil private key")
+	}
+	if _, err := subtleSignature.NewRSASSAPKCS1Verifier("SHA256", nil); err == nil {
+		t.Errorf("expect an error with a nil public key")
+	}
+}
+
+type rsaTestData struct {
+	Algorithm        string
+	GeneratorVersion string
+	NumberOfTests    uint32
+	TestGroups       []*rsaTestGroup
+}
+
+type rsaTestGroup struct {
+	E      string
+	N      string
+	KeyDer string
+	Sha    string
+	MgfSha string
+	SLen   int
+	Type   string
+	Tests  []*testcase
+}
+
+func newRSAPublicKeyFromHex(n, e string) (*rsa.PublicKey, error) {
+	modulus, err := subtle.NewBigIntFromHex(n)
+	if err != nil {
+		return nil, fmt.Errorf("cannot decode n: %s", err)
+	}
+	exponent, err := subtle.NewBigIntFromHex(e)
+	if err != nil {
+		return nil, fmt.Errorf("cannot decode e: %s", err)
+	}
+	if !exponent.IsInt64() || exponent.Cmp(big.NewInt(subtleSignature.RSAMaxPublicExponent)) > 0 {
+		return nil, fmt.Errorf("public exponent too large")
+	}
+	return &rsa.PublicKey{N: modulus, E: int(exponent.Int64())}, nil
+}
+
+func TestRSASSAPKCS1WycheproofVectors(t *testing.T) {
+	filenames := []string{
+		"../../../third_party/wycheproof/testvectors/rsa_signature_2048_sha256_test.json",
+		"../../../third_party/wycheproof/testvectors/rsa_signature_2048_sha512_test.json",
+		"../../../third_party/wycheproof/testvectors/rsa_signature_3072_sha256_test.json",
+		"../../../third_party/wycheproof/testvectors/rsa_signature_3072_sha384_test.json",
+		"../../../third_party/wycheproof/testvectors/rsa_signature_3072_sha512_test.json",
+		"../../../third_party/wycheproof/testvectors/rsa_signature_4096_sha384_test.json",
+		"../../../third_party/wycheproof/testvectors/rsa_signature_4096_sha512_test.json",
+	}
+	for _, filename := range filenames {
+		content := readRSATestData(t, filename)
+		if content == nil {
+			continue
+		}
+		for _, g := range content.TestGroups {
+			hash := subtle.ConvertHashName(g.Sha)
+			pub, err := newRSAPublicKeyFromHex(g.N, g.E)
+			if err != nil {
+				t.Errorf("%s: %s", filename, err)
+				continue
+			}
+			verifier, err := subtleSignature.NewRSASSAPKCS1Verifier(hash, pub)
+			if err != nil {
+				continue
+			}
+			runRSAWycheproofTests(t, verifier.Verify, g.Tests)
+		}
+	}
+}
+
+func readRSATestData(t *testing.T, filename string) *rsaTestData {
+	f, err := os.Open(filename)
+	if err != nil {
+		fmt.Printf("cannot open file: %s, this is typically caused by an older version of Wycheproof.", err)
+		return nil
+	}
+	defer f.Close()
+	content := new(rsaTestData)
+	if err := json.NewDecoder(f).Decode(content); err != nil {
+		t.Errorf("cannot decode content of file %s: %s", filename, err)
+		return nil
+	}
+	return content
+}
+
+func runRSAWycheproofTests(t *testing.T, verify func(signature, data []byte) error, tests []*testcase) {
+	for _, tc := range tests {
+		message, err := hex.DecodeString(tc.Msg)
+		if err != nil {
+			t.Errorf("cannot decode message in test case %d: %s", tc.TcID, err)
+		}
+		sig, err := hex.DecodeString(tc.Sig)
+		if err != nil {
+			t.Errorf("cannot decode signature in test case %d: %s", tc.TcID, err)
+		}
+		err = verify(sig, message)
+		if (tc.Result == "valid" && err != nil) ||
+			(tc.Result == "invalid" && err == nil) {
+			t.Errorf("failed in test case %d with error %q ", tc.TcID, err)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package signature
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"fmt"
+	"hash"
+
+	"github.com/tsingson/tink/golang/subtle"
+	"github.com/tsingson/tink/golang/tink"
+)
+
+// RSASSAPKCS1Verifier is an implementation of Verifier for RSA-SSA-PKCS1 v1.5.
+type RSASSAPKCS1Verifier struct {
+	publicKey *rsa.PublicKey
+	hashFunc  func() hash.Hash
+	hashID    crypto.Hash
+}
+
+// Assert that RSASSAPKCS1Verifier implements the Verifier interface.
+var _ tink.Verifier = (*RSASSAPKCS1Verifier)(nil)
+
+// NewRSASSAPKCS1Verifier creates a new instance of RSASSAPKCS1Verifier.
+func NewRSASSAPKCS1Verifier(hashAlg string, publicKey *rsa.PublicKey) (*RSASSAPKCS1Verifier, error) {
+	if err := ValidateRSAPublicKey(publicKey); err != nil {
+		return nil, fmt.Errorf("rsa_ssa_pkcs1_verifier: %s", err)
+	}
+	hashFunc, hashID, err := rsaHashFunc(hashAlg)
+	if err != nil {
+		return nil, fmt.Errorf("rsa_ssa_pkcs1_verifier: %s", err)
+	}
+	return &RSASSAPKCS1Verifier{
+		publicKey: publicKey,
+		hashFunc:  hashFunc,
+		hashID:    hashID,
+	}, nil
+}
+
+// Verify verifies whether the given signature is valid for the given data.
+// It returns an error if the signature is not valid; nil otherwise.
+func (v *RSASSAPKCS1Verifier) Verify(signature, data []byte) error {
+	hashed, err := subtle.ComputeHash(v.hashFunc, data)
+	if err != nil {
+		return err
+	}
+	if err := rsa.VerifyPKCS1v15(v.publicKey, v.hashID, hashed, signature); err != nil {
+		return errInvalidRSASignature
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package signature
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"fmt"
+	"hash"
+
+	"github.com/tsingson/tink/golang/subtle"
+	"github.com/tsingson/tink/golang/tink"
+)
+
+// RSASSAPSSSigner is an implementation of Signer for RSA-SSA-PSS.
+type RSASSAPSSSigner struct {
+	privateKey *rsa.PrivateKey
+	hashFunc   func() hash.Hash
+	opts       *rsa.PSSOptions
+}
+
+// Assert that RSASSAPSSSigner implements the Signer interface.
+var _ tink.Signer = (*RSASSAPSSSigner)(nil)
+
+// NewRSASSAPSSSigner creates a new instance of RSASSAPSSSigner.
+func NewRSASSAPSSSigner(sigHash, mgf1Hash string, saltLength int, privateKey *rsa.PrivateKey) (*RSASSAPSSSigner, error) {
+	if privateKey == nil {
+		return nil, fmt.Errorf("rsa_ssa_pss_signer: privateKey can't be nil")
+	}
+	if err := ValidateRSAPublicKey(&privateKey.PublicKey); err != nil {
+		return nil, fmt.Errorf("rsa_ssa_pss_signer: %s", err)
+	}
+	// Precompute uses the CRT values of the key if they are already set.
+	privateKey.Precompute()
+	if err := privateKey.Validate(); err != nil {
+		return nil, fmt.Errorf("rsa_ssa_pss_signer: invalid private key: %s", err)
+	}
+	hashFunc, hashID, err := rsaPSSParams(sigHash, mgf1Hash, saltLength)
+	if err != nil {
+		return nil, fmt.Errorf("rsa_ssa_pss_signer: %s", err)
+	}
+	return &RSASSAPSSSigner{
+		privateKey: privateKey,
+		hashFunc:   hashFunc,
+		opts:       &rsa.PSSOptions{SaltLength: saltLength, Hash: hashID},
+	}, nil
+}
+
+// Sign computes a signature for the given data.
+func (s *RSASSAPSSSigner) Sign(data []byte) ([]byte, error) {
+	hashed, err := subtle.ComputeHash(s.hashFunc, data)
+	if err != nil {
+		return nil, err
+	}
+	ret, err := rsa.SignPSS(rand.Reader, s.privateKey, s.opts.Hash, hashed, s.opts)
+	if err != nil {
+		return nil, fmt.Errorf("rsa_ssa_pss_signer: signing failed: %s", err)
+	}
+	return ret, nil
+}
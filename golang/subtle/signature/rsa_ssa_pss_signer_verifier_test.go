@@ -0,0 +1,141 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package signature_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"testing"
+
+	"github.com/tsingson/tink/golang/subtle"
+	"github.com/tsingson/tink/golang/subtle/random"
+	subtleSignature "github.com/tsingson/tink/golang/subtle/signature"
+)
+
+func TestRSASSAPSSSignVerify(t *testing.T) {
+	priv, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("cannot generate RSA key: %s", err)
+	}
+	var testCases = []struct {
+		hash       string
+		saltLength int
+	}{
+		{"SHA256", 32},
+		{"SHA384", 48},
+		{"SHA512", 64},
+		{"SHA256", 1},
+	}
+	for _, tc := range testCases {
+		signer, err := subtleSignature.NewRSASSAPSSSigner(tc.hash, tc.hash, tc.saltLength, priv)
+		if err != nil {
+			t.Errorf("unexpected error when creating RSASSAPSSSigner: %s", err)
+		}
+		verifier, err := subtleSignature.NewRSASSAPSSVerifier(tc.hash, tc.hash, tc.saltLength, &priv.PublicKey)
+		if err != nil {
+			t.Errorf("unexpected error when creating RSASSAPSSVerifier: %s", err)
+		}
+		data := random.GetRandomBytes(20)
+		signature, err := signer.Sign(data)
+		if err != nil {
+			t.Errorf("unexpected error when signing: %s", err)
+		}
+		if err := verifier.Verify(signature, data); err != nil {
+			t.Errorf("unexpected error when verifying: %s", err)
+		}
+		signature[0] ^= 1
+		if err := verifier.Verify(signature, data); err == nil {
+			t.Errorf("expect an error when verifying a modified signature")
+		}
+		signature[0] ^= 1
+		if err := verifier.Verify(signature, append(data, 0)); err == nil {
+			t.Errorf("expect an error when verifying modified data")
+		}
+		// a verifier expecting a different salt length must reject the signature
+		other, err := subtleSignature.NewRSASSAPSSVerifier(tc.hash, tc.hash, tc.saltLength+1, &priv.PublicKey)
+		if err != nil {
+			t.Errorf("unexpected error when creating RSASSAPSSVerifier: %s", err)
+		}
+		if err := other.Verify(signature, data); err == nil {
+			t.Errorf("expect an error when verifying with a different salt length")
+		}
+	}
+}
+
+func TestRSASSAPSSWithInvalidInput(t *testing.T) {
+	priv, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("cannot generate RSA key: %s", err)
+	}
+	var testCases = []struct {
+		sigHash    string
+		mgf1Hash   string
+		saltLength int
+	}{
+		{"SHA1", "SHA1", 20},
+		{"SHA256", "SHA512", 32},
+		{"SHA256", "SHA256", 0},
+		{"SHA256", "SHA256", -1},
+	}
+	for i, tc := range testCases {
+		if _, err := subtleSignature.NewRSASSAPSSSigner(tc.sigHash, tc.mgf1Hash, tc.saltLength, priv); err == nil {
+			t.Errorf("expect an error in test case %d", i)
+		}
+		if _, err := subtleSignature.NewRSASSAPSSVerifier(tc.sigHash, tc.mgf1Hash, tc.saltLength, &priv.PublicKey); err == nil {
+			t.Errorf("expect an error in test case %d", i)
+		}
+	}
+	small, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatalf("cannot generate RSA key: %s", err)
+	}
+	if _, err := subtleSignature.NewRSASSAPSSSigner("SHA256", "SHA256", 32, small); err == nil {
+		t.Errorf("expect an error with a 1024-bit modulus")
+	}
+	if _, err := subtleSignature.NewRSASSAPSSVerifier("SHA256", "SHA256", 32, &small.PublicKey); err == nil {
+		t.Errorf("expect an error with a 1024-bit modulus")
+	}
+}
+
+func TestRSASSAPSSWycheproofVectors(t *testing.T) {
+	filenames := []string{
+		"../../../third_party/wycheproof/testvectors/rsa_pss_2048_sha256_mgf1_32_test.json",
+		"../../../third_party/wycheproof/testvectors/rsa_pss_3072_sha256_mgf1_32_test.json",
+		"../../../third_party/wycheproof/testvectors/rsa_pss_4096_sha256_mgf1_32_test.json",
+		"../../../third_party/wycheproof/testvectors/rsa_pss_4096_sha512_mgf1_32_test.json",
+		"../../../third_party/wycheproof/testvectors/rsa_pss_misc_test.json",
+	}
+	for _, filename := range filenames {
+		content := readRSATestData(t, filename)
+		if content == nil {
+			continue
+		}
+		for _, g := range content.TestGroups {
+			sigHash := subtle.ConvertHashName(g.Sha)
+			mgf1Hash := subtle.ConvertHashName(g.MgfSha)
+			pub, err := newRSAPublicKeyFromHex(g.N, g.E)
+			if err != nil {
+				t.Errorf("%s: %s", filename, err)
+				continue
+			}
+			// groups with parameters that are not supported are skipped
+			verifier, err := subtleSignature.NewRSASSAPSSVerifier(sigHash, mgf1Hash, g.SLen, pub)
+			if err != nil {
+				continue
+			}
+			runRSAWycheproofTests(t, verifier.Verify, g.Tests)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package signature
+
+import (
+	"crypto/rsa"
+	"fmt"
+	"hash"
+
+	"github.com/tsingson/tink/golang/subtle"
+	"github.com/tsingson/tink/golang/tink"
+)
+
+// RSASSAPSSVerifier is an implementation of Verifier for RSA-SSA-PSS.
+type RSASSAPSSVerifier struct {
+	publicKey *rsa.PublicKey
+	hashFunc  func() hash.Hash
+	opts      *rsa.PSSOptions
+}
+
+// Assert that RSASSAPSSVerifier implements the Verifier interface.
+var _ tink.Verifier = (*RSASSAPSSVerifier)(nil)
+
+// NewRSASSAPSSVerifier creates a new instance of RSASSAPSSVerifier.
+func NewRSASSAPSSVerifier(sigHash, mgf1Hash string, saltLength int, publicKey *rsa.PublicKey) (*RSASSAPSSVerifier, error) {
+	if err := ValidateRSAPublicKey(publicKey); err != nil {
+		return nil, fmt.Errorf("rsa_ssa_pss_verifier: %s", err)
+	}
+	hashFunc, hashID, err := rsaPSSParams(sigHash, mgf1Hash, saltLength)
+	if err != nil {
+		return nil, fmt.Errorf("rsa_ssa_pss_verifier: %s", err)
+	}
+	return &RSASSAPSSVerifier{
+		publicKey: publicKey,
+		hashFunc:  hashFunc,
+		opts:      &rsa.PSSOptions{SaltLength: saltLength, Hash: hashID},
+	}, nil
+}
+
+// Verify verifies whether the given signature is valid for the given data.
+// It returns an error if the signature is not valid; nil otherwise.
+func (v *RSASSAPSSVerifier) Verify(signature, data []byte) error {
+	hashed, err := subtle.ComputeHash(v.hashFunc, data)
+	if err != nil {
+		return err
+	}
+	if err := rsa.VerifyPSS(v.publicKey, v.opts.Hash, hashed, signature, v.opts); err != nil {
+		return errInvalidRSASignature
+	}
+	return nil
+}
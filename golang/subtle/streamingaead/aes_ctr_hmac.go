@@ -0,0 +1,229 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package streamingaead
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"fmt"
+	"io"
+
+	"github.com/tsingson/tink/golang/subtle/aead"
+	"github.com/tsingson/tink/golang/subtle/mac"
+	"github.com/tsingson/tink/golang/subtle/random"
+	"github.com/tsingson/tink/golang/tink"
+)
+
+const (
+	// AESCTRHMACNonceSize is the size of the IVs of the AES-CTR segments.
+	AESCTRHMACNonceSize = 16
+	// AESCTRHMACHMACKeySize is the size of the derived HMAC keys.
+	AESCTRHMACHMACKeySize = 32
+)
+
+// AESCTRHMAC implements streaming AEAD encryption using AES-CTR and HMAC.
+//
+// Each ciphertext uses a new AES-CTR key and HMAC key that are derived from the key
+// derivation key, a randomly chosen salt of the same size as the AES key and the
+// associated data, using HKDF. The ciphertext has the format
+//
+//	header || segment_0 || segment_1 || ... || segment_k
+//
+// where the header is
+//
+//	headerLength (1 byte) || salt || noncePrefix (7 bytes)
+//
+// and segment_i is AES-CTR(ct_i) || HMAC(nonce_i || ct_i). The nonce_i is
+// noncePrefix || i || lastSegment || 0x00000000, where i is a 32-bit big endian
+// integer and lastSegment is 1 for the last segment and 0 otherwise. The nonce is
+// used as the initial counter block of AES-CTR.
+type AESCTRHMAC struct {
+	mainKey []byte
+	hkdfAlg string
+	keySize int
+	tagAlg  string
+	tagSize int
+	params  segmentParams
+}
+
+// Assert that AESCTRHMAC implements the StreamingAEAD interface.
+var _ tink.StreamingAEAD = (*AESCTRHMAC)(nil)
+
+// NewAESCTRHMAC returns an AESCTRHMAC instance.
+//
+// mainKey is the input keying material used to derive sub keys; it must be at least
+// 16 bytes and at least keySize bytes long. hkdfAlg and tagAlg are the hash functions
+// used by HKDF and HMAC respectively, e.g. "SHA256". keySize is the size of the derived
+// AES keys, either 16 or 32 bytes, and tagSize the size of the HMAC tag of each segment.
+// The first ciphertext segment is shorter than the others by the header length
+// and firstSegmentOffset.
+func NewAESCTRHMAC(mainKey []byte, hkdfAlg string, keySize int, tagAlg string, tagSize, ciphertextSegmentSize, firstSegmentOffset int) (*AESCTRHMAC, error) {
+	if len(mainKey) < 16 || len(mainKey) < keySize {
+		return nil, fmt.Errorf("aes_ctr_hmac: main key too short")
+	}
+	if err := aead.ValidateAESKeySize(uint32(keySize)); err != nil {
+		return nil, fmt.Errorf("aes_ctr_hmac: %s", err)
+	}
+	if tagSize < 0 {
+		return nil, fmt.Errorf("aes_ctr_hmac: negative tag size")
+	}
+	if err := mac.ValidateHMACParams(tagAlg, AESCTRHMACHMACKeySize, uint32(tagSize)); err != nil {
+		return nil, fmt.Errorf("aes_ctr_hmac: %s", err)
+	}
+	if firstSegmentOffset < 0 {
+		return nil, fmt.Errorf("aes_ctr_hmac: negative first segment offset")
+	}
+	headerLength := 1 + keySize + noncePrefixSize
+	params := segmentParams{
+		nonceSize:             AESCTRHMACNonceSize,
+		headerLength:          headerLength,
+		ciphertextOffset:      headerLength + firstSegmentOffset,
+		ciphertextSegmentSize: ciphertextSegmentSize,
+		ciphertextOverhead:    tagSize,
+	}
+	if err := params.validate(); err != nil {
+		return nil, fmt.Errorf("aes_ctr_hmac: %s", err)
+	}
+	if _, err := deriveKey(hkdfAlg, mainKey, nil, nil, keySize+AESCTRHMACHMACKeySize); err != nil {
+		return nil, fmt.Errorf("aes_ctr_hmac: %s", err)
+	}
+	key := make([]byte, len(mainKey))
+	copy(key, mainKey)
+	return &AESCTRHMAC{
+		mainKey: key,
+		hkdfAlg: hkdfAlg,
+		keySize: keySize,
+		tagAlg:  tagAlg,
+		tagSize: tagSize,
+		params:  params,
+	}, nil
+}
+
+// HeaderLength returns the length of the ciphertext header.
+func (a *AESCTRHMAC) HeaderLength() int {
+	return a.params.headerLength
+}
+
+// NewEncryptingWriter returns a writer that encrypts the data written to it with
+// aad as associated data and writes the ciphertext to w. The header is written to w
+// immediately; the last segment is written when the returned writer is closed.
+func (a *AESCTRHMAC) NewEncryptingWriter(w io.Writer, aad []byte) (io.WriteCloser, error) {
+	salt := random.GetRandomBytes(uint32(a.keySize))
+	noncePrefix := random.GetRandomBytes(noncePrefixSize)
+	enc, err := a.newSegmentCipher(salt, aad)
+	if err != nil {
+		return nil, err
+	}
+	header := make([]byte, 0, a.params.headerLength)
+	header = append(header, byte(a.params.headerLength))
+	header = append(header, salt...)
+	header = append(header, noncePrefix...)
+	return newWriter(w, enc, a.params, header, noncePrefix)
+}
+
+// NewDecryptingReader reads the header from r and returns a reader that decrypts the
+// remaining ciphertext with aad as associated data.
+func (a *AESCTRHMAC) NewDecryptingReader(r io.Reader, aad []byte) (io.Reader, error) {
+	header := make([]byte, a.params.headerLength)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, headerReadError(err)
+	}
+	dec, noncePrefix, err := a.parseHeader(header, aad)
+	if err != nil {
+		return nil, err
+	}
+	return newReader(r, dec, a.params, noncePrefix), nil
+}
+
+// NewDecryptingReaderAt returns a reader that decrypts the ciphertext of the given size
+// read from r with aad as associated data. The plaintext can be read at arbitrary offsets
+// and only the segments covering the requested range are read and authenticated.
+func (a *AESCTRHMAC) NewDecryptingReaderAt(r io.ReaderAt, size int64, aad []byte) (*io.SectionReader, error) {
+	header := make([]byte, a.params.headerLength)
+	if _, err := readFullAt(r, header, 0); err != nil {
+		return nil, headerReadError(err)
+	}
+	dec, noncePrefix, err := a.parseHeader(header, aad)
+	if err != nil {
+		return nil, err
+	}
+	ra, err := newReaderAt(r, size, dec, a.params, noncePrefix)
+	if err != nil {
+		return nil, err
+	}
+	return io.NewSectionReader(ra, 0, ra.Size()), nil
+}
+
+// parseHeader checks the header and returns the segment cipher and nonce prefix it encodes.
+func (a *AESCTRHMAC) parseHeader(header, aad []byte) (*aesCTRHMACSegmentCipher, []byte, error) {
+	if len(header) != a.params.headerLength || int(header[0]) != a.params.headerLength {
+		return nil, nil, ErrAuthentication
+	}
+	salt := header[1 : 1+a.keySize]
+	noncePrefix := header[1+a.keySize:]
+	dec, err := a.newSegmentCipher(salt, aad)
+	if err != nil {
+		return nil, nil, err
+	}
+	return dec, noncePrefix, nil
+}
+
+func (a *AESCTRHMAC) newSegmentCipher(salt, aad []byte) (*aesCTRHMACSegmentCipher, error) {
+	keys, err := deriveKey(a.hkdfAlg, a.mainKey, salt, aad, a.keySize+AESCTRHMACHMACKeySize)
+	if err != nil {
+		return nil, fmt.Errorf("aes_ctr_hmac: %s", err)
+	}
+	block, err := aes.NewCipher(keys[:a.keySize])
+	if err != nil {
+		return nil, fmt.Errorf("aes_ctr_hmac: %s", err)
+	}
+	hmac, err := mac.NewHMAC(a.tagAlg, keys[a.keySize:], uint32(a.tagSize))
+	if err != nil {
+		return nil, fmt.Errorf("aes_ctr_hmac: %s", err)
+	}
+	return &aesCTRHMACSegmentCipher{block: block, hmac: hmac, tagSize: a.tagSize}, nil
+}
+
+// aesCTRHMACSegmentCipher encrypts and decrypts segments with AES-CTR and authenticates
+// them with HMAC under derived keys.
+type aesCTRHMACSegmentCipher struct {
+	block   cipher.Block
+	hmac    *mac.HMAC
+	tagSize int
+}
+
+func (c *aesCTRHMACSegmentCipher) encryptSegment(segment, nonce []byte) ([]byte, error) {
+	ct := make([]byte, len(segment), len(segment)+c.tagSize)
+	cipher.NewCTR(c.block, nonce).XORKeyStream(ct, segment)
+	tag, err := c.hmac.ComputeMAC(append(append([]byte{}, nonce...), ct...))
+	if err != nil {
+		return nil, err
+	}
+	return append(ct, tag...), nil
+}
+
+func (c *aesCTRHMACSegmentCipher) decryptSegment(segment, nonce []byte) ([]byte, error) {
+	if len(segment) < c.tagSize {
+		return nil, ErrAuthentication
+	}
+	ct := segment[:len(segment)-c.tagSize]
+	tag := segment[len(segment)-c.tagSize:]
+	if err := c.hmac.VerifyMAC(tag, append(append([]byte{}, nonce...), ct...)); err != nil {
+		return nil, err
+	}
+	pt := make([]byte, len(ct))
+	cipher.NewCTR(c.block, nonce).XORKeyStream(pt, ct)
+	return pt, nil
+}
@@ -0,0 +1,268 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package streamingaead_test
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"io"
+	"testing"
+
+	"golang.org/x/crypto/hkdf"
+
+	"github.com/tsingson/tink/golang/subtle/random"
+	"github.com/tsingson/tink/golang/subtle/streamingaead"
+)
+
+func TestAESCTRHMACEncryptDecrypt(t *testing.T) {
+	for _, keySize := range []int{16, 32} {
+		for _, firstSegmentOffset := range []int{0, 5} {
+			a, err := streamingaead.NewAESCTRHMAC(random.GetRandomBytes(32), "SHA256", keySize, "SHA256", 32, 256, firstSegmentOffset)
+			if err != nil {
+				t.Fatalf("cannot create AESCTRHMAC: %s", err)
+			}
+			firstSegment := 256 - 32 - a.HeaderLength() - firstSegmentOffset
+			sizes := []int{0, 1, firstSegment - 1, firstSegment, firstSegment + 1, firstSegment + 224, firstSegment + 225, 3000}
+			for _, size := range sizes {
+				for _, chunkSize := range []int{1, 17, 4096} {
+					pt := random.GetRandomBytes(uint32(size))
+					aad := random.GetRandomBytes(20)
+					ct := encrypt(t, a, pt, aad, chunkSize)
+					decrypted, err := decrypt(a, ct, aad)
+					if err != nil {
+						t.Errorf("keySize %d, offset %d, size %d: decryption failed: %s", keySize, firstSegmentOffset, size, err)
+						continue
+					}
+					if !bytes.Equal(pt, decrypted) {
+						t.Errorf("keySize %d, offset %d, size %d: decryption is not inverse of encryption", keySize, firstSegmentOffset, size)
+					}
+				}
+			}
+		}
+	}
+}
+
+// TestAESCTRHMACCiphertextFormat recomputes a ciphertext from its header following the
+// format of the Java implementation and compares it with the ciphertext produced by AESCTRHMAC.
+func TestAESCTRHMACCiphertextFormat(t *testing.T) {
+	ikm := random.GetRandomBytes(16)
+	const keySize, tagSize, segmentSize = 16, 12, 64
+	a, err := streamingaead.NewAESCTRHMAC(ikm, "SHA256", keySize, "SHA256", tagSize, segmentSize, 0)
+	if err != nil {
+		t.Fatalf("cannot create AESCTRHMAC: %s", err)
+	}
+	pt := random.GetRandomBytes(150)
+	aad := []byte("aad")
+	ct := encrypt(t, a, pt, aad, len(pt))
+
+	headerLength := 1 + keySize + 7
+	if int(ct[0]) != headerLength {
+		t.Fatalf("expect header length %d, got %d", headerLength, ct[0])
+	}
+	salt := ct[1 : 1+keySize]
+	noncePrefix := ct[1+keySize : headerLength]
+	keys := make([]byte, keySize+32)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, ikm, salt, aad), keys); err != nil {
+		t.Fatal(err)
+	}
+	block, err := aes.NewCipher(keys[:keySize])
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := append([]byte{}, ct[:headerLength]...)
+	ptSegmentSize := segmentSize - tagSize
+	segments := [][]byte{pt[:ptSegmentSize-headerLength]}
+	for rest := pt[ptSegmentSize-headerLength:]; len(rest) > 0; {
+		n := ptSegmentSize
+		if n > len(rest) {
+			n = len(rest)
+		}
+		segments = append(segments, rest[:n])
+		rest = rest[n:]
+	}
+	for i, segment := range segments {
+		nonce := make([]byte, 16)
+		copy(nonce, noncePrefix)
+		binary.BigEndian.PutUint32(nonce[7:], uint32(i))
+		if i == len(segments)-1 {
+			nonce[11] = 1
+		}
+		segmentCT := make([]byte, len(segment))
+		cipher.NewCTR(block, nonce).XORKeyStream(segmentCT, segment)
+		h := hmac.New(sha256.New, keys[keySize:])
+		h.Write(nonce)
+		h.Write(segmentCT)
+		want = append(want, segmentCT...)
+		want = append(want, h.Sum(nil)[:tagSize]...)
+	}
+	if !bytes.Equal(ct, want) {
+		t.Errorf("ciphertext does not match the expected format:\n got %x\nwant %x", ct, want)
+	}
+}
+
+func TestAESCTRHMACModifiedCiphertext(t *testing.T) {
+	a, err := streamingaead.NewAESCTRHMAC(random.GetRandomBytes(16), "SHA256", 16, "SHA256", 16, 64, 0)
+	if err != nil {
+		t.Fatalf("cannot create AESCTRHMAC: %s", err)
+	}
+	pt := random.GetRandomBytes(100)
+	aad := random.GetRandomBytes(10)
+	ct := encrypt(t, a, pt, aad, len(pt))
+
+	// wrong associated data
+	if _, err := decrypt(a, ct, []byte("wrong aad")); err == nil {
+		t.Errorf("expect an error when associated data is modified")
+	}
+	// flipped bits
+	for i := 0; i < len(ct); i++ {
+		for b := uint(0); b < 8; b++ {
+			modified := append([]byte{}, ct...)
+			modified[i] ^= 1 << b
+			if _, err := decrypt(a, modified, aad); err == nil {
+				t.Errorf("expect an error when byte %d bit %d is flipped", i, b)
+			}
+		}
+	}
+	// truncation
+	for i := 0; i < len(ct); i++ {
+		if _, err := decrypt(a, ct[:i], aad); err == nil {
+			t.Errorf("expect an error when ciphertext is truncated to %d bytes", i)
+		}
+	}
+	// appended data
+	if _, err := decrypt(a, append(append([]byte{}, ct...), 0), aad); err == nil {
+		t.Errorf("expect an error when data is appended to the ciphertext")
+	}
+}
+
+func TestAESCTRHMACReaderAt(t *testing.T) {
+	for _, firstSegmentOffset := range []int{0, 5} {
+		a, err := streamingaead.NewAESCTRHMAC(random.GetRandomBytes(16), "SHA256", 16, "SHA256", 16, 64, firstSegmentOffset)
+		if err != nil {
+			t.Fatalf("cannot create AESCTRHMAC: %s", err)
+		}
+		for _, size := range []int{0, 1, 23, 24, 25, 71, 72, 73, 500} {
+			pt := random.GetRandomBytes(uint32(size))
+			aad := random.GetRandomBytes(10)
+			ct := encrypt(t, a, pt, aad, len(pt))
+			ra, err := a.NewDecryptingReaderAt(bytes.NewReader(ct), int64(len(ct)), aad)
+			if err != nil {
+				t.Fatalf("offset %d, size %d: cannot create decrypting reader: %s", firstSegmentOffset, size, err)
+			}
+			for start := 0; start <= size; start += 7 {
+				for _, length := range []int{0, 1, 30, 100, size} {
+					buf := make([]byte, length)
+					n, err := ra.ReadAt(buf, int64(start))
+					want := pt[start:]
+					if len(want) > length {
+						want = pt[start : start+length]
+					}
+					if n != len(want) || !bytes.Equal(buf[:n], want) {
+						t.Errorf("offset %d, size %d: ReadAt(%d, %d) returned wrong plaintext", firstSegmentOffset, size, length, start)
+					}
+					if n < length && err != io.EOF {
+						t.Errorf("offset %d, size %d: ReadAt(%d, %d) = %d, %v, want io.EOF", firstSegmentOffset, size, length, start, n, err)
+					}
+					if n == length && err != nil && err != io.EOF {
+						t.Errorf("offset %d, size %d: ReadAt(%d, %d): unexpected error: %s", firstSegmentOffset, size, length, start, err)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestAESCTRHMACReaderAtModifiedCiphertext(t *testing.T) {
+	a, err := streamingaead.NewAESCTRHMAC(random.GetRandomBytes(16), "SHA256", 16, "SHA256", 16, 64, 0)
+	if err != nil {
+		t.Fatalf("cannot create AESCTRHMAC: %s", err)
+	}
+	pt := random.GetRandomBytes(200)
+	aad := random.GetRandomBytes(10)
+	ct := encrypt(t, a, pt, aad, len(pt))
+	readAll := func(ct []byte) error {
+		ra, err := a.NewDecryptingReaderAt(bytes.NewReader(ct), int64(len(ct)), aad)
+		if err != nil {
+			return err
+		}
+		buf := make([]byte, len(pt))
+		_, err = ra.ReadAt(buf, 0)
+		return err
+	}
+	if err := readAll(ct); err != nil {
+		t.Fatalf("decryption failed: %s", err)
+	}
+	for i := 0; i < len(ct); i++ {
+		modified := append([]byte{}, ct...)
+		modified[i] ^= 1
+		if err := readAll(modified); err == nil || err == io.EOF {
+			t.Errorf("expect an error when byte %d is modified", i)
+		}
+	}
+	// Dropping whole segments from the end must be detected.
+	for size := 64; size < len(ct); size += 64 {
+		if err := readAll(ct[:size]); err != streamingaead.ErrTruncated {
+			t.Errorf("expect ErrTruncated when ciphertext is truncated to %d bytes, got %v", size, err)
+		}
+	}
+	// Reading inside an unmodified segment succeeds even if another segment is modified.
+	modified := append([]byte{}, ct...)
+	modified[len(modified)-1] ^= 1
+	ra, err := a.NewDecryptingReaderAt(bytes.NewReader(modified), int64(len(modified)), aad)
+	if err != nil {
+		t.Fatalf("cannot create decrypting reader: %s", err)
+	}
+	buf := make([]byte, 10)
+	if _, err := ra.ReadAt(buf, 0); err != nil || !bytes.Equal(buf, pt[:10]) {
+		t.Errorf("cannot read the first segment: %v", err)
+	}
+	if _, err := ra.ReadAt(buf, -1); err == nil {
+		t.Errorf("expect an error with a negative offset")
+	}
+}
+
+func TestNewAESCTRHMACWithInvalidInput(t *testing.T) {
+	var tests = []struct {
+		name          string
+		keyLength     uint32
+		hkdfAlg       string
+		keySize       int
+		tagAlg        string
+		tagSize       int
+		segmentSize   int
+		segmentOffset int
+	}{
+		{"short main key", 15, "SHA256", 16, "SHA256", 16, 4096, 0},
+		{"main key shorter than key size", 16, "SHA256", 32, "SHA256", 16, 4096, 0},
+		{"invalid key size", 24, "SHA256", 24, "SHA256", 16, 4096, 0},
+		{"invalid hkdf hash", 16, "MD5", 16, "SHA256", 16, 4096, 0},
+		{"invalid tag hash", 16, "SHA256", 16, "MD5", 16, 4096, 0},
+		{"tag too short", 16, "SHA256", 16, "SHA256", 9, 4096, 0},
+		{"tag too long", 16, "SHA256", 16, "SHA256", 33, 4096, 0},
+		{"negative tag size", 16, "SHA256", 16, "SHA256", -1, 4096, 0},
+		{"segment too small", 16, "SHA256", 16, "SHA256", 16, 40, 0},
+		{"offset too large", 16, "SHA256", 16, "SHA256", 16, 64, 24},
+		{"negative offset", 16, "SHA256", 16, "SHA256", 16, 4096, -1},
+	}
+	for _, tt := range tests {
+		if _, err := streamingaead.NewAESCTRHMAC(random.GetRandomBytes(tt.keyLength), tt.hkdfAlg, tt.keySize, tt.tagAlg, tt.tagSize, tt.segmentSize, tt.segmentOffset); err == nil {
+			t.Errorf("%s: expect an error", tt.name)
+		}
+	}
+}
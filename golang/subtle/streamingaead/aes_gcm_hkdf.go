@@ -0,0 +1,197 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package streamingaead
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"fmt"
+	"io"
+
+	"github.com/tsingson/tink/golang/subtle/aead"
+	"github.com/tsingson/tink/golang/subtle/random"
+	"github.com/tsingson/tink/golang/tink"
+)
+
+const (
+	// AESGCMHKDFNonceSize is the size of the IVs of the AES-GCM segments.
+	AESGCMHKDFNonceSize = 12
+	// AESGCMHKDFTagSize is the size of the tag of each ciphertext segment.
+	AESGCMHKDFTagSize = 16
+)
+
+// AESGCMHKDF implements streaming AEAD encryption using AES-GCM.
+//
+// Each ciphertext uses a new AES-GCM key that is derived from the key derivation key,
+// a randomly chosen salt of the same size as the key and the associated data,
+// using HKDF. The ciphertext has the format
+//
+//	header || segment_0 || segment_1 || ... || segment_k
+//
+// where the header is
+//
+//	headerLength (1 byte) || salt || noncePrefix (7 bytes)
+//
+// and segment_i is encrypted with the nonce noncePrefix || i || lastSegment,
+// where i is a 32-bit big endian integer and lastSegment is 1 for the last
+// segment and 0 otherwise.
+type AESGCMHKDF struct {
+	mainKey []byte
+	hkdfAlg string
+	keySize int
+	params  segmentParams
+}
+
+// Assert that AESGCMHKDF implements the StreamingAEAD interface.
+var _ tink.StreamingAEAD = (*AESGCMHKDF)(nil)
+
+// NewAESGCMHKDF returns an AESGCMHKDF instance.
+//
+// mainKey is the input keying material used to derive sub keys; it must be at least
+// 16 bytes and at least keySize bytes long. hkdfAlg is the hash function used by HKDF,
+// e.g. "SHA256". keySize is the size of the derived AES keys, either 16 or 32 bytes.
+// The first ciphertext segment is shorter than the others by the header length
+// and firstSegmentOffset.
+func NewAESGCMHKDF(mainKey []byte, hkdfAlg string, keySize, ciphertextSegmentSize, firstSegmentOffset int) (*AESGCMHKDF, error) {
+	if len(mainKey) < 16 || len(mainKey) < keySize {
+		return nil, fmt.Errorf("aes_gcm_hkdf: main key too short")
+	}
+	if err := aead.ValidateAESKeySize(uint32(keySize)); err != nil {
+		return nil, fmt.Errorf("aes_gcm_hkdf: %s", err)
+	}
+	if firstSegmentOffset < 0 {
+		return nil, fmt.Errorf("aes_gcm_hkdf: negative first segment offset")
+	}
+	headerLength := 1 + keySize + noncePrefixSize
+	params := segmentParams{
+		nonceSize:             AESGCMHKDFNonceSize,
+		headerLength:          headerLength,
+		ciphertextOffset:      headerLength + firstSegmentOffset,
+		ciphertextSegmentSize: ciphertextSegmentSize,
+		ciphertextOverhead:    AESGCMHKDFTagSize,
+	}
+	if err := params.validate(); err != nil {
+		return nil, fmt.Errorf("aes_gcm_hkdf: %s", err)
+	}
+	if _, err := deriveKey(hkdfAlg, mainKey, nil, nil, keySize); err != nil {
+		return nil, fmt.Errorf("aes_gcm_hkdf: %s", err)
+	}
+	key := make([]byte, len(mainKey))
+	copy(key, mainKey)
+	return &AESGCMHKDF{
+		mainKey: key,
+		hkdfAlg: hkdfAlg,
+		keySize: keySize,
+		params:  params,
+	}, nil
+}
+
+// HeaderLength returns the length of the ciphertext header.
+func (a *AESGCMHKDF) HeaderLength() int {
+	return a.params.headerLength
+}
+
+// NewEncryptingWriter returns a writer that encrypts the data written to it with
+// aad as associated data and writes the ciphertext to w. The header is written to w
+// immediately; the last segment is written when the returned writer is closed.
+func (a *AESGCMHKDF) NewEncryptingWriter(w io.Writer, aad []byte) (io.WriteCloser, error) {
+	salt := random.GetRandomBytes(uint32(a.keySize))
+	noncePrefix := random.GetRandomBytes(noncePrefixSize)
+	enc, err := a.newSegmentCipher(salt, aad)
+	if err != nil {
+		return nil, err
+	}
+	header := make([]byte, 0, a.params.headerLength)
+	header = append(header, byte(a.params.headerLength))
+	header = append(header, salt...)
+	header = append(header, noncePrefix...)
+	return newWriter(w, enc, a.params, header, noncePrefix)
+}
+
+// NewDecryptingReader reads the header from r and returns a reader that decrypts the
+// remaining ciphertext with aad as associated data.
+func (a *AESGCMHKDF) NewDecryptingReader(r io.Reader, aad []byte) (io.Reader, error) {
+	header := make([]byte, a.params.headerLength)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, headerReadError(err)
+	}
+	dec, noncePrefix, err := a.parseHeader(header, aad)
+	if err != nil {
+		return nil, err
+	}
+	return newReader(r, dec, a.params, noncePrefix), nil
+}
+
+// NewDecryptingReaderAt returns a reader that decrypts the ciphertext of the given size
+// read from r with aad as associated data. The plaintext can be read at arbitrary offsets
+// and only the segments covering the requested range are read and authenticated.
+func (a *AESGCMHKDF) NewDecryptingReaderAt(r io.ReaderAt, size int64, aad []byte) (*io.SectionReader, error) {
+	header := make([]byte, a.params.headerLength)
+	if _, err := readFullAt(r, header, 0); err != nil {
+		return nil, headerReadError(err)
+	}
+	dec, noncePrefix, err := a.parseHeader(header, aad)
+	if err != nil {
+		return nil, err
+	}
+	ra, err := newReaderAt(r, size, dec, a.params, noncePrefix)
+	if err != nil {
+		return nil, err
+	}
+	return io.NewSectionReader(ra, 0, ra.Size()), nil
+}
+
+// parseHeader checks the header and returns the segment cipher and nonce prefix it encodes.
+func (a *AESGCMHKDF) parseHeader(header, aad []byte) (*aesGCMSegmentCipher, []byte, error) {
+	if len(header) != a.params.headerLength || int(header[0]) != a.params.headerLength {
+		return nil, nil, ErrAuthentication
+	}
+	salt := header[1 : 1+a.keySize]
+	noncePrefix := header[1+a.keySize:]
+	dec, err := a.newSegmentCipher(salt, aad)
+	if err != nil {
+		return nil, nil, err
+	}
+	return dec, noncePrefix, nil
+}
+
+func (a *AESGCMHKDF) newSegmentCipher(salt, aad []byte) (*aesGCMSegmentCipher, error) {
+	key, err := deriveKey(a.hkdfAlg, a.mainKey, salt, aad, a.keySize)
+	if err != nil {
+		return nil, fmt.Errorf("aes_gcm_hkdf: %s", err)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("aes_gcm_hkdf: %s", err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("aes_gcm_hkdf: %s", err)
+	}
+	return &aesGCMSegmentCipher{gcm}, nil
+}
+
+// aesGCMSegmentCipher encrypts and decrypts segments with AES-GCM under a derived key.
+type aesGCMSegmentCipher struct {
+	gcm cipher.AEAD
+}
+
+func (c *aesGCMSegmentCipher) encryptSegment(segment, nonce []byte) ([]byte, error) {
+	return c.gcm.Seal(nil, nonce, segment, nil), nil
+}
+
+func (c *aesGCMSegmentCipher) decryptSegment(segment, nonce []byte) ([]byte, error) {
+	return c.gcm.Open(nil, nonce, segment, nil)
+}
@@ -0,0 +1,231 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package streamingaead_test
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha256"
+	"encoding/binary"
+	"io"
+	"io/ioutil"
+	"testing"
+
+	"golang.org/x/crypto/hkdf"
+
+	"github.com/tsingson/tink/golang/subtle/random"
+	"github.com/tsingson/tink/golang/subtle/streamingaead"
+	"github.com/tsingson/tink/golang/tink"
+)
+
+func encrypt(t *testing.T, a tink.StreamingAEAD, pt, aad []byte, chunkSize int) []byte {
+	t.Helper()
+	buf := new(bytes.Buffer)
+	w, err := a.NewEncryptingWriter(buf, aad)
+	if err != nil {
+		t.Fatalf("cannot create encrypting writer: %s", err)
+	}
+	for rest := pt; len(rest) > 0; {
+		n := chunkSize
+		if n > len(rest) {
+			n = len(rest)
+		}
+		if _, err := w.Write(rest[:n]); err != nil {
+			t.Fatalf("cannot write plaintext: %s", err)
+		}
+		rest = rest[n:]
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("cannot close encrypting writer: %s", err)
+	}
+	return buf.Bytes()
+}
+
+func decrypt(a tink.StreamingAEAD, ct, aad []byte) ([]byte, error) {
+	r, err := a.NewDecryptingReader(bytes.NewReader(ct), aad)
+	if err != nil {
+		return nil, err
+	}
+	return ioutil.ReadAll(r)
+}
+
+func TestAESGCMHKDFEncryptDecrypt(t *testing.T) {
+	for _, keySize := range []int{16, 32} {
+		for _, firstSegmentOffset := range []int{0, 5} {
+			a, err := streamingaead.NewAESGCMHKDF(random.GetRandomBytes(32), "SHA256", keySize, 256, firstSegmentOffset)
+			if err != nil {
+				t.Fatalf("cannot create AESGCMHKDF: %s", err)
+			}
+			firstSegment := 256 - 16 - a.HeaderLength() - firstSegmentOffset
+			sizes := []int{0, 1, firstSegment - 1, firstSegment, firstSegment + 1, firstSegment + 240, firstSegment + 241, 3000}
+			for _, size := range sizes {
+				for _, chunkSize := range []int{1, 17, 4096} {
+					pt := random.GetRandomBytes(uint32(size))
+					aad := random.GetRandomBytes(20)
+					ct := encrypt(t, a, pt, aad, chunkSize)
+					decrypted, err := decrypt(a, ct, aad)
+					if err != nil {
+						t.Errorf("keySize %d, offset %d, size %d: decryption failed: %s", keySize, firstSegmentOffset, size, err)
+						continue
+					}
+					if !bytes.Equal(pt, decrypted) {
+						t.Errorf("keySize %d, offset %d, size %d: decryption is not inverse of encryption", keySize, firstSegmentOffset, size)
+					}
+				}
+			}
+		}
+	}
+}
+
+// TestAESGCMHKDFCiphertextFormat recomputes a ciphertext from its header following the
+// format of the Java implementation and compares it with the ciphertext produced by AESGCMHKDF.
+func TestAESGCMHKDFCiphertextFormat(t *testing.T) {
+	ikm := random.GetRandomBytes(16)
+	const keySize, segmentSize = 16, 64
+	a, err := streamingaead.NewAESGCMHKDF(ikm, "SHA256", keySize, segmentSize, 0)
+	if err != nil {
+		t.Fatalf("cannot create AESGCMHKDF: %s", err)
+	}
+	pt := random.GetRandomBytes(150)
+	aad := []byte("aad")
+	ct := encrypt(t, a, pt, aad, len(pt))
+
+	headerLength := 1 + keySize + 7
+	if int(ct[0]) != headerLength {
+		t.Fatalf("expect header length %d, got %d", headerLength, ct[0])
+	}
+	salt := ct[1 : 1+keySize]
+	noncePrefix := ct[1+keySize : headerLength]
+	key := make([]byte, keySize)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, ikm, salt, aad), key); err != nil {
+		t.Fatal(err)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := append([]byte{}, ct[:headerLength]...)
+	ptSegmentSize := segmentSize - 16
+	segments := [][]byte{pt[:ptSegmentSize-headerLength]}
+	for rest := pt[ptSegmentSize-headerLength:]; len(rest) > 0; {
+		n := ptSegmentSize
+		if n > len(rest) {
+			n = len(rest)
+		}
+		segments = append(segments, rest[:n])
+		rest = rest[n:]
+	}
+	for i, segment := range segments {
+		nonce := make([]byte, 12)
+		copy(nonce, noncePrefix)
+		binary.BigEndian.PutUint32(nonce[7:], uint32(i))
+		if i == len(segments)-1 {
+			nonce[11] = 1
+		}
+		want = gcm.Seal(want, nonce, segment, nil)
+	}
+	if !bytes.Equal(ct, want) {
+		t.Errorf("ciphertext does not match the expected format:\n got %x\nwant %x", ct, want)
+	}
+}
+
+func TestAESGCMHKDFModifiedCiphertext(t *testing.T) {
+	a, err := streamingaead.NewAESGCMHKDF(random.GetRandomBytes(16), "SHA256", 16, 64, 0)
+	if err != nil {
+		t.Fatalf("cannot create AESGCMHKDF: %s", err)
+	}
+	pt := random.GetRandomBytes(100)
+	aad := random.GetRandomBytes(10)
+	ct := encrypt(t, a, pt, aad, len(pt))
+
+	// wrong associated data
+	if _, err := decrypt(a, ct, []byte("wrong aad")); err != streamingaead.ErrAuthentication {
+		t.Errorf("got %v when associated data is modified, want ErrAuthentication", err)
+	}
+	// wrong key
+	other, err := streamingaead.NewAESGCMHKDF(random.GetRandomBytes(16), "SHA256", 16, 64, 0)
+	if err != nil {
+		t.Fatalf("cannot create AESGCMHKDF: %s", err)
+	}
+	if _, err := decrypt(other, ct, aad); err != streamingaead.ErrAuthentication {
+		t.Errorf("got %v with the wrong key, want ErrAuthentication", err)
+	}
+	// flipped bits
+	for i := 0; i < len(ct); i++ {
+		for b := uint(0); b < 8; b++ {
+			modified := append([]byte{}, ct...)
+			modified[i] ^= 1 << b
+			if _, err := decrypt(a, modified, aad); err == nil {
+				t.Errorf("expect an error when byte %d bit %d is flipped", i, b)
+			}
+		}
+	}
+	// truncation
+	for i := 0; i < len(ct); i++ {
+		if _, err := decrypt(a, ct[:i], aad); err == nil {
+			t.Errorf("expect an error when ciphertext is truncated to %d bytes", i)
+		}
+	}
+	// appended data
+	if _, err := decrypt(a, append(append([]byte{}, ct...), 0), aad); err == nil {
+		t.Errorf("expect an error when data is appended to the ciphertext")
+	}
+}
+
+func TestAESGCMHKDFClosedWriter(t *testing.T) {
+	a, err := streamingaead.NewAESGCMHKDF(random.GetRandomBytes(16), "SHA256", 16, 64, 0)
+	if err != nil {
+		t.Fatalf("cannot create AESGCMHKDF: %s", err)
+	}
+	w, err := a.NewEncryptingWriter(new(bytes.Buffer), nil)
+	if err != nil {
+		t.Fatalf("cannot create encrypting writer: %s", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("cannot close encrypting writer: %s", err)
+	}
+	if _, err := w.Write([]byte("data")); err == nil {
+		t.Errorf("expect an error when writing to a closed writer")
+	}
+}
+
+func TestNewAESGCMHKDFWithInvalidInput(t *testing.T) {
+	var tests = []struct {
+		name          string
+		keyLength     uint32
+		hkdfAlg       string
+		keySize       int
+		segmentSize   int
+		segmentOffset int
+	}{
+		{"short main key", 15, "SHA256", 16, 4096, 0},
+		{"main key shorter than key size", 16, "SHA256", 32, 4096, 0},
+		{"invalid key size", 24, "SHA256", 24, 4096, 0},
+		{"invalid hash", 16, "MD5", 16, 4096, 0},
+		{"segment too small", 16, "SHA256", 16, 40, 0},
+		{"offset too large", 16, "SHA256", 16, 64, 24},
+		{"negative offset", 16, "SHA256", 16, 4096, -1},
+	}
+	for _, tt := range tests {
+		if _, err := streamingaead.NewAESGCMHKDF(random.GetRandomBytes(tt.keyLength), tt.hkdfAlg, tt.keySize, tt.segmentSize, tt.segmentOffset); err == nil {
+			t.Errorf("%s: expect an error", tt.name)
+		}
+	}
+}
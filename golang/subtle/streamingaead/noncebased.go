@@ -0,0 +1,441 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package streamingaead
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+	"sync"
+)
+
+const (
+	// noncePrefixSize is the size of the random nonce prefix stored in the header.
+	noncePrefixSize = 7
+	// maxSegments is the maximal number of segments of a single ciphertext,
+	// bounded by the 32-bit segment counter in the nonce.
+	maxSegments = math.MaxUint32
+)
+
+// ErrTruncated is returned on decryption when the final segments of a ciphertext are
+// missing, that is when the last segment authenticates only as an intermediate segment.
+var ErrTruncated = errors.New("streamingaead: ciphertext has been truncated")
+
+// ErrAuthentication is returned on decryption when a ciphertext is not valid under the key:
+// its header does not match the key, it is too short, or a segment fails to authenticate,
+// e.g. because the key or the associated data is wrong or the ciphertext has been modified.
+var ErrAuthentication = errors.New("streamingaead: ciphertext does not authenticate")
+
+var (
+	errWriterClosed    = errors.New("streamingaead: write to closed writer")
+	errTooManySegments = errors.New("streamingaead: too many segments")
+	errNegativeOffset  = errors.New("streamingaead: negative offset")
+)
+
+// segmentEncrypter encrypts a single plaintext segment under the given nonce.
+type segmentEncrypter interface {
+	encryptSegment(segment, nonce []byte) ([]byte, error)
+}
+
+// segmentDecrypter decrypts a single ciphertext segment under the given nonce.
+type segmentDecrypter interface {
+	decryptSegment(segment, nonce []byte) ([]byte, error)
+}
+
+// segmentNonce returns the nonce of a segment, which has the format
+// noncePrefix || segmentNr (4 bytes, big endian) || lastSegment (1 byte),
+// padded with zeros to nonceSize.
+func segmentNonce(nonceSize int, noncePrefix []byte, segmentNr uint64, lastSegment bool) []byte {
+	nonce := make([]byte, nonceSize)
+	copy(nonce, noncePrefix)
+	binary.BigEndian.PutUint32(nonce[len(noncePrefix):], uint32(segmentNr))
+	if lastSegment {
+		nonce[len(noncePrefix)+4] = 1
+	}
+	return nonce
+}
+
+// decryptSegment decrypts the given ciphertext segment. If it is expected to be the last
+// segment but authenticates only as an intermediate one, the ciphertext has been truncated
+// at a segment boundary and ErrTruncated is returned. Otherwise a segment that fails to
+// authenticate yields ErrAuthentication.
+func decryptSegment(dec segmentDecrypter, params segmentParams, noncePrefix, segment []byte, segmentNr uint64, lastSegment bool) ([]byte, error) {
+	nonce := segmentNonce(params.nonceSize, noncePrefix, segmentNr, lastSegment)
+	pt, err := dec.decryptSegment(segment, nonce)
+	if err == nil {
+		return pt, nil
+	}
+	if lastSegment {
+		nonce = segmentNonce(params.nonceSize, noncePrefix, segmentNr, false)
+		if _, err := dec.decryptSegment(segment, nonce); err == nil {
+			return nil, ErrTruncated
+		}
+	}
+	return nil, ErrAuthentication
+}
+
+// headerReadError returns the error to report when the header of a ciphertext cannot be
+// read. A ciphertext shorter than the header is not valid under the key; other read
+// errors are returned unchanged.
+func headerReadError(err error) error {
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return ErrAuthentication
+	}
+	return err
+}
+
+// segmentParams describes the segmentation of a ciphertext.
+type segmentParams struct {
+	// nonceSize is the size of the nonce passed to the segment encrypter or decrypter.
+	nonceSize int
+	// headerLength is the size of the header written in front of the first segment.
+	headerLength int
+	// ciphertextOffset is the size of the header plus the offset of the first segment.
+	// The first segment is shorter than the others by this amount.
+	ciphertextOffset int
+	// ciphertextSegmentSize is the size of a full ciphertext segment.
+	ciphertextSegmentSize int
+	// ciphertextOverhead is the difference between ciphertext and plaintext segment size.
+	ciphertextOverhead int
+}
+
+func (p *segmentParams) plaintextSegmentSize() int {
+	return p.ciphertextSegmentSize - p.ciphertextOverhead
+}
+
+// validate checks that the first segment can hold at least one byte of plaintext.
+func (p *segmentParams) validate() error {
+	if p.ciphertextOffset < p.headerLength {
+		return errors.New("invalid first segment offset")
+	}
+	if p.ciphertextSegmentSize <= p.ciphertextOffset+p.ciphertextOverhead {
+		return errors.New("ciphertext segment size too small")
+	}
+	return nil
+}
+
+// writer encrypts the data written to it segment by segment and writes the
+// ciphertext to the underlying io.Writer.
+type writer struct {
+	w           io.Writer
+	enc         segmentEncrypter
+	params      segmentParams
+	noncePrefix []byte
+
+	pt        []byte
+	ptLimit   int
+	segmentNr uint64
+	closed    bool
+}
+
+// newWriter writes header to w and returns a writer that encrypts to w.
+func newWriter(w io.Writer, enc segmentEncrypter, params segmentParams, header, noncePrefix []byte) (*writer, error) {
+	if _, err := w.Write(header); err != nil {
+		return nil, err
+	}
+	return &writer{
+		w:           w,
+		enc:         enc,
+		params:      params,
+		noncePrefix: noncePrefix,
+		pt:          make([]byte, 0, params.plaintextSegmentSize()),
+		ptLimit:     params.plaintextSegmentSize() - params.ciphertextOffset,
+	}, nil
+}
+
+// Write encrypts p. Full segments are written to the underlying writer once it is
+// known that they are not the last segment; the remaining data is buffered until
+// more data is written or the writer is closed.
+func (w *writer) Write(p []byte) (int, error) {
+	if w.closed {
+		return 0, errWriterClosed
+	}
+	n := 0
+	for len(p) > 0 {
+		if len(w.pt) == w.ptLimit {
+			if err := w.flushSegment(false); err != nil {
+				return n, err
+			}
+		}
+		c := w.ptLimit - len(w.pt)
+		if c > len(p) {
+			c = len(p)
+		}
+		w.pt = append(w.pt, p[:c]...)
+		p = p[c:]
+		n += c
+	}
+	return n, nil
+}
+
+// Close encrypts the buffered data as the last segment. It does not close the
+// underlying writer.
+func (w *writer) Close() error {
+	if w.closed {
+		return nil
+	}
+	if err := w.flushSegment(true); err != nil {
+		return err
+	}
+	w.closed = true
+	return nil
+}
+
+func (w *writer) flushSegment(lastSegment bool) error {
+	if w.segmentNr >= maxSegments {
+		return errTooManySegments
+	}
+	nonce := segmentNonce(w.params.nonceSize, w.noncePrefix, w.segmentNr, lastSegment)
+	ct, err := w.enc.encryptSegment(w.pt, nonce)
+	if err != nil {
+		return err
+	}
+	if _, err := w.w.Write(ct); err != nil {
+		return err
+	}
+	w.segmentNr++
+	w.pt = w.pt[:0]
+	w.ptLimit = w.params.plaintextSegmentSize()
+	return nil
+}
+
+// reader reads ciphertext segments from the underlying io.Reader and returns
+// the decrypted plaintext.
+type reader struct {
+	r           io.Reader
+	dec         segmentDecrypter
+	params      segmentParams
+	noncePrefix []byte
+
+	// ct holds the ciphertext of the next segment followed by one byte of lookahead,
+	// which tells whether the segment is the last one.
+	ct        []byte
+	ctLen     int
+	pt        []byte
+	segmentNr uint64
+	done      bool
+}
+
+func newReader(r io.Reader, dec segmentDecrypter, params segmentParams, noncePrefix []byte) *reader {
+	return &reader{
+		r:           r,
+		dec:         dec,
+		params:      params,
+		noncePrefix: noncePrefix,
+		ct:          make([]byte, params.ciphertextSegmentSize+1),
+	}
+}
+
+// Read decrypts data from the underlying reader. It returns an error if a segment
+// fails to authenticate, including when segments have been reordered or the
+// ciphertext has been truncated.
+func (r *reader) Read(p []byte) (int, error) {
+	if len(r.pt) > 0 {
+		n := copy(p, r.pt)
+		r.pt = r.pt[n:]
+		return n, nil
+	}
+	if r.done {
+		return 0, io.EOF
+	}
+	if err := r.readSegment(); err != nil {
+		return 0, err
+	}
+	n := copy(p, r.pt)
+	r.pt = r.pt[n:]
+	return n, nil
+}
+
+func (r *reader) readSegment() error {
+	if r.segmentNr >= maxSegments {
+		return errTooManySegments
+	}
+	segmentSize := r.params.ciphertextSegmentSize
+	if r.segmentNr == 0 {
+		segmentSize -= r.params.ciphertextOffset
+	}
+	n, err := io.ReadFull(r.r, r.ct[r.ctLen:segmentSize+1])
+	r.ctLen += n
+	lastSegment := false
+	switch err {
+	case nil:
+	case io.EOF, io.ErrUnexpectedEOF:
+		lastSegment = true
+		segmentSize = r.ctLen
+	default:
+		return err
+	}
+	if segmentSize < r.params.ciphertextOverhead {
+		return ErrAuthentication
+	}
+	pt, err := decryptSegment(r.dec, r.params, r.noncePrefix, r.ct[:segmentSize], r.segmentNr, lastSegment)
+	if err != nil {
+		return err
+	}
+	if lastSegment {
+		r.done = true
+		r.ctLen = 0
+	} else {
+		r.ct[0] = r.ct[segmentSize]
+		r.ctLen = 1
+	}
+	r.segmentNr++
+	r.pt = pt
+	return nil
+}
+
+// readerAt decrypts the segments of a ciphertext of known size on demand, so that the
+// plaintext can be read at arbitrary offsets. Only the segments overlapping a read are
+// authenticated. The most recently decrypted segment is cached.
+type readerAt struct {
+	r           io.ReaderAt
+	dec         segmentDecrypter
+	params      segmentParams
+	noncePrefix []byte
+
+	// ctSize is the size of the ciphertext including the header.
+	ctSize      int64
+	numSegments int64
+	ptSize      int64
+
+	mu       sync.Mutex
+	cachedNr int64
+	cachedPt []byte
+}
+
+// newReaderAt returns a readerAt for the ciphertext of the given size in r, whose header
+// has already been read and parsed. The first segment is authenticated eagerly, so that
+// a wrong key or wrong associated data is reported here rather than on the first read.
+func newReaderAt(r io.ReaderAt, size int64, dec segmentDecrypter, params segmentParams, noncePrefix []byte) (*readerAt, error) {
+	if size < int64(params.headerLength+params.ciphertextOverhead) {
+		return nil, ErrAuthentication
+	}
+	segmentSize := int64(params.ciphertextSegmentSize)
+	// Positions are shifted by the first segment offset so that segment i starts
+	// at i*segmentSize, except for the first segment which starts after the header.
+	total := size + int64(params.ciphertextOffset-params.headerLength)
+	numSegments := total / segmentSize
+	lastSegmentSize := total % segmentSize
+	if lastSegmentSize > 0 {
+		numSegments++
+	} else {
+		lastSegmentSize = segmentSize
+	}
+	if numSegments == 1 {
+		lastSegmentSize -= int64(params.ciphertextOffset)
+	}
+	if lastSegmentSize < int64(params.ciphertextOverhead) {
+		return nil, ErrAuthentication
+	}
+	if numSegments > maxSegments {
+		return nil, errTooManySegments
+	}
+	ra := &readerAt{
+		r:           r,
+		dec:         dec,
+		params:      params,
+		noncePrefix: noncePrefix,
+		ctSize:      size,
+		numSegments: numSegments,
+		ptSize:      size - int64(params.headerLength) - numSegments*int64(params.ciphertextOverhead),
+		cachedNr:    -1,
+	}
+	if _, err := ra.segment(0); err != nil {
+		return nil, err
+	}
+	return ra, nil
+}
+
+// Size returns the size of the plaintext.
+func (ra *readerAt) Size() int64 {
+	return ra.ptSize
+}
+
+// ReadAt decrypts len(p) bytes of plaintext starting at offset off. Every segment that
+// overlaps the requested range is authenticated before any of its data is returned.
+func (ra *readerAt) ReadAt(p []byte, off int64) (int, error) {
+	if off < 0 {
+		return 0, errNegativeOffset
+	}
+	ptSegmentSize := int64(ra.params.plaintextSegmentSize())
+	n := 0
+	for n < len(p) {
+		pos := off + int64(n)
+		if pos >= ra.ptSize {
+			return n, io.EOF
+		}
+		pos += int64(ra.params.ciphertextOffset)
+		segmentNr := pos / ptSegmentSize
+		segmentOffset := pos % ptSegmentSize
+		if segmentNr == 0 {
+			segmentOffset -= int64(ra.params.ciphertextOffset)
+		}
+		pt, err := ra.segment(segmentNr)
+		if err != nil {
+			return n, err
+		}
+		n += copy(p[n:], pt[segmentOffset:])
+	}
+	return n, nil
+}
+
+// segment returns the plaintext of the given segment.
+func (ra *readerAt) segment(segmentNr int64) ([]byte, error) {
+	ra.mu.Lock()
+	if ra.cachedNr == segmentNr {
+		pt := ra.cachedPt
+		ra.mu.Unlock()
+		return pt, nil
+	}
+	ra.mu.Unlock()
+
+	segmentSize := int64(ra.params.ciphertextSegmentSize)
+	firstSegmentOffset := int64(ra.params.ciphertextOffset - ra.params.headerLength)
+	start := segmentNr*segmentSize - firstSegmentOffset
+	if segmentNr == 0 {
+		start = int64(ra.params.headerLength)
+	}
+	end := (segmentNr+1)*segmentSize - firstSegmentOffset
+	if end > ra.ctSize {
+		end = ra.ctSize
+	}
+	ct := make([]byte, end-start)
+	if _, err := readFullAt(ra.r, ct, start); err != nil {
+		return nil, err
+	}
+	pt, err := decryptSegment(ra.dec, ra.params, ra.noncePrefix, ct, uint64(segmentNr), segmentNr == ra.numSegments-1)
+	if err != nil {
+		return nil, err
+	}
+
+	ra.mu.Lock()
+	ra.cachedNr = segmentNr
+	ra.cachedPt = pt
+	ra.mu.Unlock()
+	return pt, nil
+}
+
+// readFullAt reads exactly len(p) bytes from r at offset off.
+func readFullAt(r io.ReaderAt, p []byte, off int64) (int, error) {
+	n, err := r.ReadAt(p, off)
+	if n == len(p) {
+		return n, nil
+	}
+	if err == nil || err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	return n, err
+}
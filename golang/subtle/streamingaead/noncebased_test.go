@@ -0,0 +1,141 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package streamingaead_test
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"testing"
+
+	"github.com/tsingson/tink/golang/subtle/random"
+	"github.com/tsingson/tink/golang/subtle/streamingaead"
+	"github.com/tsingson/tink/golang/tink"
+)
+
+// seekableCiphers returns ciphers with 64 byte segments and the given first segment offset.
+func seekableCiphers(t *testing.T, firstSegmentOffset int) map[string]tink.StreamingAEAD {
+	t.Helper()
+	gcm, err := streamingaead.NewAESGCMHKDF(random.GetRandomBytes(16), "SHA256", 16, 64, firstSegmentOffset)
+	if err != nil {
+		t.Fatalf("cannot create AESGCMHKDF: %s", err)
+	}
+	ctr, err := streamingaead.NewAESCTRHMAC(random.GetRandomBytes(16), "SHA256", 16, "SHA256", 16, 64, firstSegmentOffset)
+	if err != nil {
+		t.Fatalf("cannot create AESCTRHMAC: %s", err)
+	}
+	return map[string]tink.StreamingAEAD{"AESGCMHKDF": gcm, "AESCTRHMAC": ctr}
+}
+
+func TestDecryptingReaderAtSeek(t *testing.T) {
+	for _, firstSegmentOffset := range []int{0, 3} {
+		for name, a := range seekableCiphers(t, firstSegmentOffset) {
+			for _, size := range []int{0, 1, 20, 100, 1000} {
+				pt := random.GetRandomBytes(uint32(size))
+				aad := random.GetRandomBytes(10)
+				ct := encrypt(t, a, pt, aad, len(pt))
+				sr, err := a.NewDecryptingReaderAt(bytes.NewReader(ct), int64(len(ct)), aad)
+				if err != nil {
+					t.Fatalf("%s, size %d: cannot create decrypting reader: %s", name, size, err)
+				}
+				if sr.Size() != int64(size) {
+					t.Errorf("%s: Size() = %d, want %d", name, sr.Size(), size)
+				}
+				decrypted, err := ioutil.ReadAll(sr)
+				if err != nil || !bytes.Equal(decrypted, pt) {
+					t.Errorf("%s, size %d: sequential read through the reader failed: %v", name, size, err)
+				}
+				for _, off := range []int64{0, int64(size) / 3, int64(size) / 2, int64(size)} {
+					if _, err := sr.Seek(off, io.SeekStart); err != nil {
+						t.Fatalf("%s: cannot seek: %s", name, err)
+					}
+					rest, err := ioutil.ReadAll(sr)
+					if err != nil || !bytes.Equal(rest, pt[off:]) {
+						t.Errorf("%s, size %d: read after seeking to %d failed: %v", name, size, off, err)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestDecryptingReaderAtWrongInput(t *testing.T) {
+	for name, a := range seekableCiphers(t, 0) {
+		pt := random.GetRandomBytes(100)
+		aad := random.GetRandomBytes(10)
+		ct := encrypt(t, a, pt, aad, len(pt))
+		if _, err := a.NewDecryptingReaderAt(bytes.NewReader(ct), int64(len(ct)), []byte("wrong aad")); err == nil {
+			t.Errorf("%s: expect an error with wrong associated data", name)
+		}
+		// The last segment is only read when it is accessed.
+		sr, err := a.NewDecryptingReaderAt(bytes.NewReader(ct), int64(len(ct))+1, aad)
+		if err == nil {
+			_, err = sr.ReadAt(make([]byte, 1), sr.Size()-1)
+		}
+		if err == nil || err == io.EOF {
+			t.Errorf("%s: expect an error when the size is larger than the ciphertext", name)
+		}
+		if _, err := a.NewDecryptingReaderAt(bytes.NewReader(ct[:10]), 10, aad); err == nil {
+			t.Errorf("%s: expect an error when the ciphertext is shorter than the header", name)
+		}
+	}
+}
+
+func TestTruncationIsReported(t *testing.T) {
+	for name, a := range seekableCiphers(t, 0) {
+		pt := random.GetRandomBytes(300)
+		aad := random.GetRandomBytes(10)
+		ct := encrypt(t, a, pt, aad, len(pt))
+		for size := 64; size < len(ct); size += 64 {
+			truncated := ct[:size]
+			if _, err := decrypt(a, truncated, aad); err != streamingaead.ErrTruncated {
+				t.Errorf("%s: sequential decryption of ciphertext truncated to %d bytes: got %v, want ErrTruncated", name, size, err)
+			}
+			sr, err := a.NewDecryptingReaderAt(bytes.NewReader(truncated), int64(size), aad)
+			if err == nil {
+				_, err = sr.ReadAt(make([]byte, 1), sr.Size()-1)
+			}
+			if err != streamingaead.ErrTruncated {
+				t.Errorf("%s: random access to ciphertext truncated to %d bytes: got %v, want ErrTruncated", name, size, err)
+			}
+		}
+	}
+}
+
+func TestDecryptingReaderAtAuthenticatesOnlyTouchedSegments(t *testing.T) {
+	for name, a := range seekableCiphers(t, 0) {
+		pt := random.GetRandomBytes(300)
+		aad := random.GetRandomBytes(10)
+		ct := encrypt(t, a, pt, aad, len(pt))
+		// Corrupt the third segment.
+		ct[64+64+10] ^= 1
+		sr, err := a.NewDecryptingReaderAt(bytes.NewReader(ct), int64(len(ct)), aad)
+		if err != nil {
+			t.Fatalf("%s: cannot create decrypting reader: %s", name, err)
+		}
+		// The plaintext of the first two segments is 24 + 48 bytes long.
+		buf := make([]byte, 72)
+		if _, err := sr.ReadAt(buf, 0); err != nil || !bytes.Equal(buf, pt[:72]) {
+			t.Errorf("%s: cannot read intact segments: %v", name, err)
+		}
+		if _, err := sr.ReadAt(buf[:1], 72); err == nil {
+			t.Errorf("%s: expect an error when reading a modified segment", name)
+		}
+		buf = make([]byte, 20)
+		if _, err := sr.ReadAt(buf, 130); err != nil || !bytes.Equal(buf, pt[130:150]) {
+			t.Errorf("%s: cannot read segments after the modified one: %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+// Package streamingaead provides subtle implementations of the streaming AEAD primitive.
+//
+// The ciphertexts are segmented and follow the format of the Java implementation:
+// a header (header length, salt and nonce prefix) followed by a sequence of
+// independently authenticated segments. The nonce of each segment encodes the
+// segment number and whether it is the last segment of the stream, so that
+// reordering and truncation of segments are detected.
+package streamingaead
+
+import (
+	"fmt"
+	"io"
+
+	"golang.org/x/crypto/hkdf"
+
+	"github.com/tsingson/tink/golang/subtle"
+)
+
+// deriveKey derives a key of the given size from ikm with HKDF, using the given salt and
+// the associated data as info.
+func deriveKey(hashAlg string, ikm, salt, aad []byte, keySize int) ([]byte, error) {
+	hashFunc := subtle.GetHashFunc(hashAlg)
+	if hashFunc == nil {
+		return nil, fmt.Errorf("invalid hash algorithm %q", hashAlg)
+	}
+	key := make([]byte, keySize)
+	if _, err := io.ReadFull(hkdf.New(hashFunc, ikm, salt, aad), key); err != nil {
+		return nil, fmt.Errorf("compute of hkdf failed: %s", err)
+	}
+	return key, nil
+}
@@ -27,16 +27,36 @@ const (
 	// AESGCMTypeURL is the type URL of AES-GCM keys that Tink supports.
 	AESGCMTypeURL = "type.googleapis.com/google.crypto.tink.AesGcmKey"
 
+	// AESGCMSIVKeyVersion is the maxmimal version of AES-GCM-SIV keys.
+	AESGCMSIVKeyVersion = 0
+	// AESGCMSIVTypeURL is the type URL of AES-GCM-SIV keys that Tink supports.
+	AESGCMSIVTypeURL = "type.googleapis.com/google.crypto.tink.AesGcmSivKey"
+
+	// AESEAXKeyVersion is the maxmimal version of AES-EAX keys.
+	AESEAXKeyVersion = 0
+	// AESEAXTypeURL is the type URL of AES-EAX keys that Tink supports.
+	AESEAXTypeURL = "type.googleapis.com/google.crypto.tink.AesEaxKey"
+
 	// ChaCha20Poly1305KeyVersion is the maxmimal version of ChaCha20Poly1305 keys that Tink supports.
 	ChaCha20Poly1305KeyVersion = 0
 	// ChaCha20Poly1305TypeURL is the type URL of ChaCha20Poly1305 keys.
 	ChaCha20Poly1305TypeURL = "type.googleapis.com/google.crypto.tink.ChaCha20Poly1305Key"
 
+	// AESKWPKeyVersion is the maxmimal version of AES-KWP keys that Tink supports.
+	AESKWPKeyVersion = 0
+	// AESKWPTypeURL is the type URL of AES-KWP keys.
+	AESKWPTypeURL = "type.googleapis.com/tsingson.tink.AesKwpKey"
+
 	// KMSEnvelopeAEADKeyVersion is the maxmimal version of KMSEnvelopeAEAD keys that Tink supports.
 	KMSEnvelopeAEADKeyVersion = 0
 	// KMSEnvelopeAEADTypeURL is the type URL of KMSEnvelopeAEAD keys.
 	KMSEnvelopeAEADTypeURL = "type.googleapis.com/google.crypto.tink.KmsEnvelopeAeadKey"
 
+	// KMSAEADKeyVersion is the maxmimal version of KMSAEAD keys that Tink supports.
+	KMSAEADKeyVersion = 0
+	// KMSAEADTypeURL is the type URL of KMSAEAD keys.
+	KMSAEADTypeURL = "type.googleapis.com/google.crypto.tink.KmsAeadKey"
+
 	// XChaCha20Poly1305KeyVersion is the maxmimal version of XChaCha20Poly1305 keys that Tink supports.
 	XChaCha20Poly1305KeyVersion = 0
 	// XChaCha20Poly1305TypeURL is the type URL of XChaCha20Poly1305 keys.
@@ -61,8 +81,24 @@ const (
 	// AESSIVTypeURL is the type URL of AES-SIV keys.
 	AESSIVTypeURL = "type.googleapis.com/google.crypto.tink.AesSivKey"
 
+	// Streaming AEAD
+
+	// AESGCMHKDFKeyVersion is the maxmimal version of AES-GCM-HKDF streaming keys that Tink supports.
+	AESGCMHKDFKeyVersion = 0
+	// AESGCMHKDFTypeURL is the type URL of AES-GCM-HKDF streaming keys.
+	AESGCMHKDFTypeURL = "type.googleapis.com/google.crypto.tink.AesGcmHkdfStreamingKey"
+	// AESCTRHMACKeyVersion is the maxmimal version of AES-CTR-HMAC streaming keys that Tink supports.
+	AESCTRHMACKeyVersion = 0
+	// AESCTRHMACTypeURL is the type URL of AES-CTR-HMAC streaming keys.
+	AESCTRHMACTypeURL = "type.googleapis.com/google.crypto.tink.AesCtrHmacStreamingKey"
+
 	// MAC
 
+	// AESCMACKeyVersion is the maxmimal version of AES-CMAC keys that Tink supports.
+	AESCMACKeyVersion = 0
+	// AESCMACTypeURL is the type URL of AES-CMAC keys that Tink supports.
+	AESCMACTypeURL = "type.googleapis.com/google.crypto.tink.AesCmacKey"
+
 	// HMACKeyVersion is the maxmimal version of HMAC keys that Tink supports.
 	HMACKeyVersion = 0
 	// HMACTypeURL is the type URL of HMAC keys.
@@ -89,4 +125,24 @@ const (
 	ED25519VerifierKeyVersion = 0
 	// ED25519VerifierTypeURL is the type URL of ED25519 public keys.
 	ED25519VerifierTypeURL = "type.googleapis.com/google.crypto.tink.Ed25519PublicKey"
+
+	// RSASSAPKCS1SignerKeyVersion is the maximum version of RSA-SSA-PKCS1 private keys that Tink supports.
+	RSASSAPKCS1SignerKeyVersion = 0
+	// RSASSAPKCS1SignerTypeURL is the type URL of RSA-SSA-PKCS1 private keys.
+	RSASSAPKCS1SignerTypeURL = "type.googleapis.com/google.crypto.tink.RsaSsaPkcs1PrivateKey"
+
+	// RSASSAPKCS1VerifierKeyVersion is the maximum version of RSA-SSA-PKCS1 public keys that Tink supports.
+	RSASSAPKCS1VerifierKeyVersion = 0
+	// RSASSAPKCS1VerifierTypeURL is the type URL of RSA-SSA-PKCS1 public keys.
+	RSASSAPKCS1VerifierTypeURL = "type.googleapis.com/google.crypto.tink.RsaSsaPkcs1PublicKey"
+
+	// RSASSAPSSSignerKeyVersion is the maximum version of RSA-SSA-PSS private keys that Tink supports.
+	RSASSAPSSSignerKeyVersion = 0
+	// RSASSAPSSSignerTypeURL is the type URL of RSA-SSA-PSS private keys.
+	RSASSAPSSSignerTypeURL = "type.googleapis.com/google.crypto.tink.RsaSsaPssPrivateKey"
+
+	// RSASSAPSSVerifierKeyVersion is the maximum version of RSA-SSA-PSS public keys that Tink supports.
+	RSASSAPSSVerifierKeyVersion = 0
+	// RSASSAPSSVerifierTypeURL is the type URL of RSA-SSA-PSS public keys.
+	RSASSAPSSVerifierTypeURL = "type.googleapis.com/google.crypto.tink.RsaSsaPssPublicKey"
 )
@@ -15,6 +15,8 @@
 // Package tink provides the abstract interfaces of the primitives which Tink supports.
 package tink
 
+import "context"
+
 /*
 AEAD is the interface for authenticated encryption with additional authenticated data.
 Implementations of this interface are secure against adaptive chosen ciphertext attacks.
@@ -33,3 +35,16 @@ type AEAD interface {
 	// of the additional data, but there are no guarantees wrt. secrecy of that data.
 	Decrypt(ciphertext, additionalData []byte) ([]byte, error)
 }
+
+// AEADWithContext is the interface for AEADs whose operations can block, e.g. because
+// they call a remote key management service. The given context controls the deadline
+// and the cancellation of each operation.
+type AEADWithContext interface {
+	// EncryptWithContext encrypts plaintext with additionalData as additional
+	// authenticated data, like AEAD.Encrypt.
+	EncryptWithContext(ctx context.Context, plaintext, additionalData []byte) ([]byte, error)
+
+	// DecryptWithContext decrypts ciphertext with additionalData as additional
+	// authenticated data, like AEAD.Decrypt.
+	DecryptWithContext(ctx context.Context, ciphertext, additionalData []byte) ([]byte, error)
+}
@@ -0,0 +1,35 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package tink
+
+/*
+KeyWrap is the interface for wrapping keys with a key-encryption key.
+
+Key wrapping is intended for protecting key material, e.g. when exporting keys
+or importing them into an HSM. Implementations are deterministic and do not take
+associated data, so they should not be used to encrypt arbitrary data.
+
+References:
+https://nvlpubs.nist.gov/nistpubs/SpecialPublications/NIST.SP.800-38F.pdf
+https://tools.ietf.org/html/rfc5649
+*/
+type KeyWrap interface {
+	// Wrap wraps the given key material.
+	Wrap(data []byte) ([]byte, error)
+
+	// Unwrap unwraps the given wrapped key material. It returns an error if
+	// the wrapped data fails the integrity check.
+	Unwrap(data []byte) ([]byte, error)
+}
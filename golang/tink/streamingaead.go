@@ -0,0 +1,57 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package tink
+
+import "io"
+
+/*
+StreamingAEAD is the interface for streaming authenticated encryption with associated data.
+
+Streaming encryption is typically used for encrypting large plaintexts such as big files.
+The plaintext is split into segments that are encrypted and authenticated individually,
+so that neither the plaintext nor the ciphertext has to be held in memory.
+
+Security guarantees:
+Implementations of this interface are secure against adaptive chosen ciphertext attacks
+and protect the order of the segments and the end of the stream: reordering, dropping
+or appending segments, and truncating the ciphertext, are detected on decryption.
+
+Encryption with associated data ensures authenticity and integrity of that data, but not
+its secrecy. The associated data is not included in the ciphertext.
+
+References:
+https://eprint.iacr.org/2015/189.pdf
+*/
+type StreamingAEAD interface {
+	// NewEncryptingWriter returns a wrapper around w such that any data written to the
+	// wrapper is encrypted with additionalData as additional authenticated data and
+	// the ciphertext is written to w. The wrapper must be closed to write the final
+	// segment of the ciphertext; closing it does not close w.
+	NewEncryptingWriter(w io.Writer, additionalData []byte) (io.WriteCloser, error)
+
+	// NewDecryptingReader returns a wrapper around r such that any data read from the
+	// wrapper is the decryption of the ciphertext read from r, authenticated with
+	// additionalData as additional authenticated data. Reads return an error as soon as
+	// a segment fails to authenticate, so callers must not use data read before an error
+	// is returned if the integrity of the whole plaintext matters.
+	NewDecryptingReader(r io.Reader, additionalData []byte) (io.Reader, error)
+
+	// NewDecryptingReaderAt returns a reader over the decryption of the ciphertext of the
+	// given size in r, authenticated with additionalData as additional authenticated data.
+	// The returned reader supports reads and seeks at arbitrary plaintext offsets; only
+	// the segments overlapping a read are decrypted and authenticated. Size returns the
+	// size of the plaintext.
+	NewDecryptingReaderAt(r io.ReaderAt, size int64, additionalData []byte) (*io.SectionReader, error)
+}
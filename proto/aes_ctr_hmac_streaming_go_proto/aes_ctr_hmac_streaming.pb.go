@@ -0,0 +1,242 @@
+// Copyright 2018 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: third_party/tink/proto/aes_ctr_hmac_streaming.proto
+
+package aes_ctr_hmac_streaming_go_proto
+
+import (
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	common_go_proto "github.com/tsingson/tink/proto/common_go_proto"
+	hmac_go_proto "github.com/tsingson/tink/proto/hmac_go_proto"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+type AesCtrHmacStreamingParams struct {
+	CiphertextSegmentSize uint32                    `protobuf:"varint,1,opt,name=ciphertext_segment_size,json=ciphertextSegmentSize,proto3" json:"ciphertext_segment_size,omitempty"`
+	DerivedKeySize        uint32                    `protobuf:"varint,2,opt,name=derived_key_size,json=derivedKeySize,proto3" json:"derived_key_size,omitempty"`
+	HkdfHashType          common_go_proto.HashType  `protobuf:"varint,3,opt,name=hkdf_hash_type,json=hkdfHashType,proto3,enum=google.crypto.tink.HashType" json:"hkdf_hash_type,omitempty"`
+	HmacParams            *hmac_go_proto.HmacParams `protobuf:"bytes,4,opt,name=hmac_params,json=hmacParams,proto3" json:"hmac_params,omitempty"`
+	XXX_NoUnkeyedLiteral  struct{}                  `json:"-"`
+	XXX_unrecognized      []byte                    `json:"-"`
+	XXX_sizecache         int32                     `json:"-"`
+}
+
+func (m *AesCtrHmacStreamingParams) Reset()         { *m = AesCtrHmacStreamingParams{} }
+func (m *AesCtrHmacStreamingParams) String() string { return proto.CompactTextString(m) }
+func (*AesCtrHmacStreamingParams) ProtoMessage()    {}
+func (*AesCtrHmacStreamingParams) Descriptor() ([]byte, []int) {
+	return fileDescriptor_eaa520d3eb9ab35e, []int{0}
+}
+
+func (m *AesCtrHmacStreamingParams) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AesCtrHmacStreamingParams.Unmarshal(m, b)
+}
+func (m *AesCtrHmacStreamingParams) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AesCtrHmacStreamingParams.Marshal(b, m, deterministic)
+}
+func (m *AesCtrHmacStreamingParams) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AesCtrHmacStreamingParams.Merge(m, src)
+}
+func (m *AesCtrHmacStreamingParams) XXX_Size() int {
+	return xxx_messageInfo_AesCtrHmacStreamingParams.Size(m)
+}
+func (m *AesCtrHmacStreamingParams) XXX_DiscardUnknown() {
+	xxx_messageInfo_AesCtrHmacStreamingParams.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AesCtrHmacStreamingParams proto.InternalMessageInfo
+
+func (m *AesCtrHmacStreamingParams) GetCiphertextSegmentSize() uint32 {
+	if m != nil {
+		return m.CiphertextSegmentSize
+	}
+	return 0
+}
+
+func (m *AesCtrHmacStreamingParams) GetDerivedKeySize() uint32 {
+	if m != nil {
+		return m.DerivedKeySize
+	}
+	return 0
+}
+
+func (m *AesCtrHmacStreamingParams) GetHkdfHashType() common_go_proto.HashType {
+	if m != nil {
+		return m.HkdfHashType
+	}
+	return common_go_proto.HashType_UNKNOWN_HASH
+}
+
+func (m *AesCtrHmacStreamingParams) GetHmacParams() *hmac_go_proto.HmacParams {
+	if m != nil {
+		return m.HmacParams
+	}
+	return nil
+}
+
+type AesCtrHmacStreamingKeyFormat struct {
+	Params               *AesCtrHmacStreamingParams `protobuf:"bytes,1,opt,name=params,proto3" json:"params,omitempty"`
+	KeySize              uint32                     `protobuf:"varint,2,opt,name=key_size,json=keySize,proto3" json:"key_size,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                   `json:"-"`
+	XXX_unrecognized     []byte                     `json:"-"`
+	XXX_sizecache        int32                      `json:"-"`
+}
+
+func (m *AesCtrHmacStreamingKeyFormat) Reset()         { *m = AesCtrHmacStreamingKeyFormat{} }
+func (m *AesCtrHmacStreamingKeyFormat) String() string { return proto.CompactTextString(m) }
+func (*AesCtrHmacStreamingKeyFormat) ProtoMessage()    {}
+func (*AesCtrHmacStreamingKeyFormat) Descriptor() ([]byte, []int) {
+	return fileDescriptor_eaa520d3eb9ab35e, []int{1}
+}
+
+func (m *AesCtrHmacStreamingKeyFormat) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AesCtrHmacStreamingKeyFormat.Unmarshal(m, b)
+}
+func (m *AesCtrHmacStreamingKeyFormat) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AesCtrHmacStreamingKeyFormat.Marshal(b, m, deterministic)
+}
+func (m *AesCtrHmacStreamingKeyFormat) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AesCtrHmacStreamingKeyFormat.Merge(m, src)
+}
+func (m *AesCtrHmacStreamingKeyFormat) XXX_Size() int {
+	return xxx_messageInfo_AesCtrHmacStreamingKeyFormat.Size(m)
+}
+func (m *AesCtrHmacStreamingKeyFormat) XXX_DiscardUnknown() {
+	xxx_messageInfo_AesCtrHmacStreamingKeyFormat.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AesCtrHmacStreamingKeyFormat proto.InternalMessageInfo
+
+func (m *AesCtrHmacStreamingKeyFormat) GetParams() *AesCtrHmacStreamingParams {
+	if m != nil {
+		return m.Params
+	}
+	return nil
+}
+
+func (m *AesCtrHmacStreamingKeyFormat) GetKeySize() uint32 {
+	if m != nil {
+		return m.KeySize
+	}
+	return 0
+}
+
+// key_type: type.googleapis.com/google.crypto.tink.AesCtrHmacStreamingKey
+type AesCtrHmacStreamingKey struct {
+	Version              uint32                     `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	Params               *AesCtrHmacStreamingParams `protobuf:"bytes,2,opt,name=params,proto3" json:"params,omitempty"`
+	KeyValue             []byte                     `protobuf:"bytes,3,opt,name=key_value,json=keyValue,proto3" json:"key_value,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                   `json:"-"`
+	XXX_unrecognized     []byte                     `json:"-"`
+	XXX_sizecache        int32                      `json:"-"`
+}
+
+func (m *AesCtrHmacStreamingKey) Reset()         { *m = AesCtrHmacStreamingKey{} }
+func (m *AesCtrHmacStreamingKey) String() string { return proto.CompactTextString(m) }
+func (*AesCtrHmacStreamingKey) ProtoMessage()    {}
+func (*AesCtrHmacStreamingKey) Descriptor() ([]byte, []int) {
+	return fileDescriptor_eaa520d3eb9ab35e, []int{2}
+}
+
+func (m *AesCtrHmacStreamingKey) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AesCtrHmacStreamingKey.Unmarshal(m, b)
+}
+func (m *AesCtrHmacStreamingKey) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AesCtrHmacStreamingKey.Marshal(b, m, deterministic)
+}
+func (m *AesCtrHmacStreamingKey) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AesCtrHmacStreamingKey.Merge(m, src)
+}
+func (m *AesCtrHmacStreamingKey) XXX_Size() int {
+	return xxx_messageInfo_AesCtrHmacStreamingKey.Size(m)
+}
+func (m *AesCtrHmacStreamingKey) XXX_DiscardUnknown() {
+	xxx_messageInfo_AesCtrHmacStreamingKey.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AesCtrHmacStreamingKey proto.InternalMessageInfo
+
+func (m *AesCtrHmacStreamingKey) GetVersion() uint32 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+func (m *AesCtrHmacStreamingKey) GetParams() *AesCtrHmacStreamingParams {
+	if m != nil {
+		return m.Params
+	}
+	return nil
+}
+
+func (m *AesCtrHmacStreamingKey) GetKeyValue() []byte {
+	if m != nil {
+		return m.KeyValue
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*AesCtrHmacStreamingParams)(nil), "google.crypto.tink.AesCtrHmacStreamingParams")
+	proto.RegisterType((*AesCtrHmacStreamingKeyFormat)(nil), "google.crypto.tink.AesCtrHmacStreamingKeyFormat")
+	proto.RegisterType((*AesCtrHmacStreamingKey)(nil), "google.crypto.tink.AesCtrHmacStreamingKey")
+}
+
+func init() { proto.RegisterFile("proto/aes_ctr_hmac_streaming.proto", fileDescriptor_eaa520d3eb9ab35e) }
+
+var fileDescriptor_eaa520d3eb9ab35e = []byte{
+	// 400 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x52, 0x4f, 0x8b, 0xd4, 0x30,
+	0x1c, 0x25, 0x55, 0x66, 0x35, 0xbb, 0x0e, 0x12, 0x50, 0xbb, 0xeb, 0x20, 0xe3, 0x78, 0xe9, 0xc5,
+	0x16, 0x76, 0xc1, 0x93, 0x20, 0x8e, 0x28, 0x2b, 0x03, 0x32, 0x74, 0x16, 0x0f, 0x5e, 0x42, 0x36,
+	0xfd, 0xd9, 0x84, 0x4e, 0x9a, 0x92, 0x64, 0x06, 0x3b, 0x27, 0xf1, 0x43, 0xf8, 0x01, 0xfc, 0xa4,
+	0xd2, 0xa4, 0xa3, 0xa2, 0x1d, 0x3c, 0xec, 0xad, 0xaf, 0xef, 0x4f, 0x5e, 0x1e, 0xc1, 0x17, 0x4e,
+	0x48, 0x53, 0xd0, 0x86, 0x19, 0xd7, 0x66, 0x4e, 0xd6, 0x55, 0xd6, 0x18, 0xed, 0x74, 0xc6, 0xc0,
+	0x52, 0xee, 0x0c, 0x15, 0x8a, 0x71, 0x6a, 0x9d, 0x01, 0xa6, 0x64, 0x5d, 0xa6, 0x9e, 0x24, 0xa4,
+	0xd4, 0xba, 0x5c, 0x43, 0xca, 0x4d, 0xdb, 0x38, 0x9d, 0x76, 0xb6, 0xb3, 0x67, 0x07, 0x82, 0xb8,
+	0x56, 0x4a, 0xd7, 0xc1, 0x78, 0xf6, 0xf4, 0x80, 0xa8, 0x3b, 0x25, 0x48, 0x66, 0xdf, 0x22, 0x7c,
+	0xfa, 0x1a, 0xec, 0x1b, 0x67, 0x2e, 0x15, 0xe3, 0xab, 0xfd, 0xc9, 0x4b, 0x66, 0x98, 0xb2, 0xe4,
+	0x05, 0x7e, 0xc4, 0x65, 0x23, 0xc0, 0x38, 0xf8, 0xe2, 0xa8, 0x85, 0x52, 0x41, 0xed, 0xa8, 0x95,
+	0x3b, 0x88, 0xd1, 0x14, 0x25, 0xf7, 0xf2, 0x07, 0xbf, 0xe9, 0x55, 0x60, 0x57, 0x72, 0x07, 0x24,
+	0xc1, 0xf7, 0x0b, 0x30, 0x72, 0x0b, 0x05, 0xad, 0xa0, 0x0d, 0x86, 0xc8, 0x1b, 0xc6, 0xfd, 0xff,
+	0x05, 0xb4, 0x5e, 0x39, 0xc7, 0x63, 0x51, 0x15, 0x9f, 0xa9, 0x60, 0x56, 0x50, 0xd7, 0x36, 0x10,
+	0xdf, 0x9a, 0xa2, 0x64, 0x7c, 0x3e, 0x49, 0xff, 0xbd, 0x74, 0x7a, 0xc9, 0xac, 0xb8, 0x6a, 0x1b,
+	0xc8, 0x4f, 0x3a, 0xcf, 0x1e, 0x91, 0x57, 0xf8, 0xd8, 0xef, 0xd6, 0xf8, 0xd2, 0xf1, 0xed, 0x29,
+	0x4a, 0x8e, 0xcf, 0x9f, 0x0c, 0x06, 0x28, 0xc6, 0xc3, 0xd5, 0x72, 0x2c, 0x7e, 0x7d, 0xcf, 0xbe,
+	0x22, 0x3c, 0x19, 0x18, 0x61, 0x01, 0xed, 0x3b, 0x6d, 0x14, 0x73, 0xe4, 0x2d, 0x1e, 0xf5, 0xe1,
+	0xc8, 0x87, 0x3f, 0x1f, 0x0a, 0x3f, 0x38, 0x63, 0xde, 0x9b, 0xc9, 0x29, 0xbe, 0xf3, 0xd7, 0x1c,
+	0x47, 0x55, 0xd8, 0x61, 0xf6, 0x1d, 0xe1, 0x87, 0xc3, 0x15, 0x48, 0x8c, 0x8f, 0xb6, 0x60, 0xac,
+	0xd4, 0x75, 0x3f, 0xfa, 0x1e, 0xfe, 0x51, 0x2b, 0xba, 0x49, 0xad, 0xc7, 0xf8, 0x6e, 0x57, 0x6b,
+	0xcb, 0xd6, 0x9b, 0x30, 0xff, 0x49, 0xde, 0xf5, 0xfc, 0xd8, 0xe1, 0x79, 0x89, 0x27, 0x5c, 0xab,
+	0xa1, 0x60, 0xff, 0x80, 0x96, 0xe8, 0xd3, 0xcb, 0x52, 0x3a, 0xb1, 0xb9, 0x4e, 0xb9, 0x56, 0x59,
+	0x90, 0xfd, 0xff, 0x65, 0xd3, 0x52, 0x53, 0xcf, 0xff, 0x88, 0x46, 0x57, 0xef, 0x3f, 0x2c, 0x96,
+	0xf3, 0xeb, 0x91, 0xc7, 0x17, 0x3f, 0x07, 0x00, 0xa4, 0x52, 0x2f, 0x93, 0x23, 0x03, 0x00, 0x00,
+}
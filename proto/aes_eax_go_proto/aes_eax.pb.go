@@ -0,0 +1,210 @@
+// Copyright 2018 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: third_party/tink/proto/aes_eax.proto
+
+package aes_eax_go_proto
+
+import (
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+// only allowing tag size in bytes = 16
+type AesEaxParams struct {
+	// possible value is 12 or 16 bytes.
+	IvSize               uint32   `protobuf:"varint,1,opt,name=iv_size,json=ivSize,proto3" json:"iv_size,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AesEaxParams) Reset()         { *m = AesEaxParams{} }
+func (m *AesEaxParams) String() string { return proto.CompactTextString(m) }
+func (*AesEaxParams) ProtoMessage()    {}
+func (*AesEaxParams) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a50268b5ad4a79df, []int{0}
+}
+
+func (m *AesEaxParams) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AesEaxParams.Unmarshal(m, b)
+}
+func (m *AesEaxParams) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AesEaxParams.Marshal(b, m, deterministic)
+}
+func (m *AesEaxParams) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AesEaxParams.Merge(m, src)
+}
+func (m *AesEaxParams) XXX_Size() int {
+	return xxx_messageInfo_AesEaxParams.Size(m)
+}
+func (m *AesEaxParams) XXX_DiscardUnknown() {
+	xxx_messageInfo_AesEaxParams.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AesEaxParams proto.InternalMessageInfo
+
+func (m *AesEaxParams) GetIvSize() uint32 {
+	if m != nil {
+		return m.IvSize
+	}
+	return 0
+}
+
+type AesEaxKeyFormat struct {
+	Params               *AesEaxParams `protobuf:"bytes,1,opt,name=params,proto3" json:"params,omitempty"`
+	KeySize              uint32        `protobuf:"varint,2,opt,name=key_size,json=keySize,proto3" json:"key_size,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *AesEaxKeyFormat) Reset()         { *m = AesEaxKeyFormat{} }
+func (m *AesEaxKeyFormat) String() string { return proto.CompactTextString(m) }
+func (*AesEaxKeyFormat) ProtoMessage()    {}
+func (*AesEaxKeyFormat) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a50268b5ad4a79df, []int{1}
+}
+
+func (m *AesEaxKeyFormat) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AesEaxKeyFormat.Unmarshal(m, b)
+}
+func (m *AesEaxKeyFormat) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AesEaxKeyFormat.Marshal(b, m, deterministic)
+}
+func (m *AesEaxKeyFormat) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AesEaxKeyFormat.Merge(m, src)
+}
+func (m *AesEaxKeyFormat) XXX_Size() int {
+	return xxx_messageInfo_AesEaxKeyFormat.Size(m)
+}
+func (m *AesEaxKeyFormat) XXX_DiscardUnknown() {
+	xxx_messageInfo_AesEaxKeyFormat.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AesEaxKeyFormat proto.InternalMessageInfo
+
+func (m *AesEaxKeyFormat) GetParams() *AesEaxParams {
+	if m != nil {
+		return m.Params
+	}
+	return nil
+}
+
+func (m *AesEaxKeyFormat) GetKeySize() uint32 {
+	if m != nil {
+		return m.KeySize
+	}
+	return 0
+}
+
+// key_type: type.googleapis.com/google.crypto.tink.AesEaxKey
+type AesEaxKey struct {
+	Version              uint32        `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	Params               *AesEaxParams `protobuf:"bytes,2,opt,name=params,proto3" json:"params,omitempty"`
+	KeyValue             []byte        `protobuf:"bytes,3,opt,name=key_value,json=keyValue,proto3" json:"key_value,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *AesEaxKey) Reset()         { *m = AesEaxKey{} }
+func (m *AesEaxKey) String() string { return proto.CompactTextString(m) }
+func (*AesEaxKey) ProtoMessage()    {}
+func (*AesEaxKey) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a50268b5ad4a79df, []int{2}
+}
+
+func (m *AesEaxKey) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AesEaxKey.Unmarshal(m, b)
+}
+func (m *AesEaxKey) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AesEaxKey.Marshal(b, m, deterministic)
+}
+func (m *AesEaxKey) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AesEaxKey.Merge(m, src)
+}
+func (m *AesEaxKey) XXX_Size() int {
+	return xxx_messageInfo_AesEaxKey.Size(m)
+}
+func (m *AesEaxKey) XXX_DiscardUnknown() {
+	xxx_messageInfo_AesEaxKey.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AesEaxKey proto.InternalMessageInfo
+
+func (m *AesEaxKey) GetVersion() uint32 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+func (m *AesEaxKey) GetParams() *AesEaxParams {
+	if m != nil {
+		return m.Params
+	}
+	return nil
+}
+
+func (m *AesEaxKey) GetKeyValue() []byte {
+	if m != nil {
+		return m.KeyValue
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*AesEaxParams)(nil), "google.crypto.tink.AesEaxParams")
+	proto.RegisterType((*AesEaxKeyFormat)(nil), "google.crypto.tink.AesEaxKeyFormat")
+	proto.RegisterType((*AesEaxKey)(nil), "google.crypto.tink.AesEaxKey")
+}
+
+func init() { proto.RegisterFile("proto/aes_eax.proto", fileDescriptor_a50268b5ad4a79df) }
+
+var fileDescriptor_a50268b5ad4a79df = []byte{
+	// 265 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x90, 0x41, 0x4b, 0xc3, 0x30,
+	0x14, 0x80, 0xe9, 0x84, 0xd6, 0x3d, 0x27, 0x42, 0x2e, 0x56, 0xf4, 0x50, 0x86, 0xe0, 0x2e, 0xa6,
+	0xa0, 0x17, 0xaf, 0x0e, 0x14, 0x64, 0x20, 0xa5, 0x8a, 0x88, 0x97, 0x92, 0xd5, 0x67, 0x17, 0xba,
+	0xee, 0x95, 0x24, 0x2b, 0xcb, 0xf0, 0xd7, 0xf8, 0x4b, 0xa5, 0xe9, 0x94, 0x81, 0xbb, 0x78, 0xfc,
+	0xc2, 0x97, 0xf7, 0x3d, 0x1e, 0x9c, 0x9b, 0x99, 0x54, 0xef, 0x59, 0x2d, 0x94, 0xb1, 0xb1, 0x91,
+	0x8b, 0x32, 0xae, 0x15, 0x19, 0x8a, 0x05, 0xea, 0x0c, 0xc5, 0x8a, 0x3b, 0x62, 0xac, 0x20, 0x2a,
+	0xe6, 0xc8, 0x73, 0x65, 0x6b, 0x43, 0xbc, 0xf5, 0x86, 0x17, 0x30, 0xb8, 0x45, 0x7d, 0x27, 0x56,
+	0x89, 0x50, 0xa2, 0xd2, 0xec, 0x18, 0x02, 0xd9, 0x64, 0x5a, 0xae, 0x31, 0xf4, 0x22, 0x6f, 0x74,
+	0x98, 0xfa, 0xb2, 0x79, 0x92, 0x6b, 0x1c, 0x7e, 0xc0, 0x51, 0x27, 0x4e, 0xd0, 0xde, 0x93, 0xaa,
+	0x84, 0x61, 0x37, 0xe0, 0xd7, 0xee, 0x97, 0x53, 0x0f, 0xae, 0x22, 0xfe, 0x37, 0xc0, 0xb7, 0xa7,
+	0xa7, 0x1b, 0x9f, 0x9d, 0xc0, 0x7e, 0x89, 0xb6, 0xcb, 0xf4, 0x5c, 0x26, 0x28, 0xd1, 0xba, 0xce,
+	0x27, 0xf4, 0x7f, 0x3b, 0x2c, 0x84, 0xa0, 0x41, 0xa5, 0x25, 0x2d, 0x36, 0xdb, 0xfc, 0xe0, 0x56,
+	0xbb, 0xf7, 0xcf, 0xf6, 0x29, 0xf4, 0xdb, 0x76, 0x23, 0xe6, 0x4b, 0x0c, 0xf7, 0x22, 0x6f, 0x34,
+	0x48, 0xdb, 0x65, 0x5e, 0x5a, 0x1e, 0xbf, 0xc2, 0x59, 0x4e, 0xd5, 0xae, 0x59, 0xee, 0x84, 0x89,
+	0xf7, 0x76, 0x59, 0x48, 0x33, 0x5b, 0x4e, 0x79, 0x4e, 0x55, 0xdc, 0x69, 0x3b, 0x0e, 0x9e, 0x15,
+	0x94, 0xb9, 0x87, 0xaf, 0x9e, 0xff, 0xfc, 0xf0, 0x38, 0x49, 0xc6, 0x53, 0xdf, 0xf1, 0xf5, 0xf7,
+	0x00, 0x6f, 0xe6, 0xef, 0xff, 0xab, 0x01, 0x00, 0x00,
+}
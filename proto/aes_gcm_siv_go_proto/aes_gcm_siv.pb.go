@@ -0,0 +1,151 @@
+// Copyright 2018 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: third_party/tink/proto/aes_gcm_siv.proto
+
+package aes_gcm_siv_go_proto
+
+import (
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+// The only allowed IV size is 12 bytes and tag size is 16 bytes.
+// Thus, accept no params.
+type AesGcmSivKeyFormat struct {
+	KeySize              uint32   `protobuf:"varint,2,opt,name=key_size,json=keySize,proto3" json:"key_size,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AesGcmSivKeyFormat) Reset()         { *m = AesGcmSivKeyFormat{} }
+func (m *AesGcmSivKeyFormat) String() string { return proto.CompactTextString(m) }
+func (*AesGcmSivKeyFormat) ProtoMessage()    {}
+func (*AesGcmSivKeyFormat) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c4615ad813f89d6c, []int{0}
+}
+
+func (m *AesGcmSivKeyFormat) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AesGcmSivKeyFormat.Unmarshal(m, b)
+}
+func (m *AesGcmSivKeyFormat) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AesGcmSivKeyFormat.Marshal(b, m, deterministic)
+}
+func (m *AesGcmSivKeyFormat) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AesGcmSivKeyFormat.Merge(m, src)
+}
+func (m *AesGcmSivKeyFormat) XXX_Size() int {
+	return xxx_messageInfo_AesGcmSivKeyFormat.Size(m)
+}
+func (m *AesGcmSivKeyFormat) XXX_DiscardUnknown() {
+	xxx_messageInfo_AesGcmSivKeyFormat.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AesGcmSivKeyFormat proto.InternalMessageInfo
+
+func (m *AesGcmSivKeyFormat) GetKeySize() uint32 {
+	if m != nil {
+		return m.KeySize
+	}
+	return 0
+}
+
+// key_type: type.googleapis.com/google.crypto.tink.AesGcmSivKey
+type AesGcmSivKey struct {
+	Version              uint32   `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	KeyValue             []byte   `protobuf:"bytes,3,opt,name=key_value,json=keyValue,proto3" json:"key_value,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AesGcmSivKey) Reset()         { *m = AesGcmSivKey{} }
+func (m *AesGcmSivKey) String() string { return proto.CompactTextString(m) }
+func (*AesGcmSivKey) ProtoMessage()    {}
+func (*AesGcmSivKey) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c4615ad813f89d6c, []int{1}
+}
+
+func (m *AesGcmSivKey) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AesGcmSivKey.Unmarshal(m, b)
+}
+func (m *AesGcmSivKey) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AesGcmSivKey.Marshal(b, m, deterministic)
+}
+func (m *AesGcmSivKey) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AesGcmSivKey.Merge(m, src)
+}
+func (m *AesGcmSivKey) XXX_Size() int {
+	return xxx_messageInfo_AesGcmSivKey.Size(m)
+}
+func (m *AesGcmSivKey) XXX_DiscardUnknown() {
+	xxx_messageInfo_AesGcmSivKey.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AesGcmSivKey proto.InternalMessageInfo
+
+func (m *AesGcmSivKey) GetVersion() uint32 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+func (m *AesGcmSivKey) GetKeyValue() []byte {
+	if m != nil {
+		return m.KeyValue
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*AesGcmSivKeyFormat)(nil), "google.crypto.tink.AesGcmSivKeyFormat")
+	proto.RegisterType((*AesGcmSivKey)(nil), "google.crypto.tink.AesGcmSivKey")
+}
+
+func init() { proto.RegisterFile("proto/aes_gcm_siv.proto", fileDescriptor_c4615ad813f89d6c) }
+
+var fileDescriptor_c4615ad813f89d6c = []byte{
+	// 224 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x74, 0x8f, 0xc1, 0x4a, 0x03, 0x31,
+	0x10, 0x86, 0xd9, 0x0a, 0xad, 0x86, 0x7a, 0xc9, 0x69, 0x45, 0x0f, 0xa5, 0xa7, 0x3d, 0x25, 0x88,
+	0x4f, 0x60, 0x41, 0x45, 0x0a, 0x52, 0x5a, 0xf1, 0x20, 0x42, 0x48, 0xe3, 0x90, 0x0e, 0xdb, 0x74,
+	0x96, 0x24, 0x0d, 0xa4, 0x8f, 0xe3, 0x93, 0x4a, 0x76, 0x3d, 0x08, 0xe2, 0xf1, 0xfb, 0xf9, 0xe6,
+	0xe7, 0x1f, 0xd6, 0xc4, 0x1d, 0xfa, 0x4f, 0xd5, 0x69, 0x1f, 0xb3, 0x8c, 0x78, 0x68, 0x65, 0xe7,
+	0x29, 0x92, 0xd4, 0x10, 0x94, 0x35, 0x4e, 0x05, 0x4c, 0xa2, 0x4f, 0x38, 0xb7, 0x44, 0x76, 0x0f,
+	0xc2, 0xf8, 0xdc, 0x45, 0x12, 0xc5, 0x9d, 0x4b, 0xc6, 0xef, 0x21, 0x3c, 0x19, 0xb7, 0xc1, 0xb4,
+	0x84, 0xfc, 0x48, 0xde, 0xe9, 0xc8, 0xaf, 0xd8, 0x79, 0x0b, 0x59, 0x05, 0x3c, 0x41, 0x3d, 0x9a,
+	0x55, 0xcd, 0xe5, 0x7a, 0xd2, 0x42, 0xde, 0xe0, 0x09, 0xe6, 0x0f, 0x6c, 0xfa, 0xfb, 0x80, 0xd7,
+	0x6c, 0x92, 0xc0, 0x07, 0xa4, 0x43, 0x5d, 0x0d, 0xe6, 0x0f, 0xf2, 0x6b, 0x76, 0x51, 0x4a, 0x92,
+	0xde, 0x1f, 0xa1, 0x3e, 0x9b, 0x55, 0xcd, 0x74, 0x5d, 0x5a, 0xdf, 0x0a, 0x2f, 0x3e, 0xd8, 0x8d,
+	0x21, 0x27, 0xfe, 0x2e, 0x1a, 0xb6, 0xae, 0xaa, 0xf7, 0x5b, 0x8b, 0x71, 0x77, 0xdc, 0x0a, 0x43,
+	0x4e, 0x0e, 0xda, 0x3f, 0xdf, 0x29, 0x4b, 0xaa, 0x0f, 0xbf, 0x46, 0xe3, 0xd7, 0xe7, 0x97, 0xe5,
+	0x6a, 0xb1, 0x1d, 0xf7, 0x7c, 0xf7, 0x3d, 0x00, 0x0b, 0x36, 0xa1, 0x8e, 0x1c, 0x01, 0x00, 0x00,
+}
@@ -0,0 +1,151 @@
+// Copyright 2018 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: third_party/tink/proto/aes_kwp.proto
+
+package aes_kwp_go_proto
+
+import (
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+type AesKwpKeyFormat struct {
+	// Size of the AES wrapping key in bytes. Valid values are: 16 and 32.
+	KeySize              uint32   `protobuf:"varint,1,opt,name=key_size,json=keySize,proto3" json:"key_size,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AesKwpKeyFormat) Reset()         { *m = AesKwpKeyFormat{} }
+func (m *AesKwpKeyFormat) String() string { return proto.CompactTextString(m) }
+func (*AesKwpKeyFormat) ProtoMessage()    {}
+func (*AesKwpKeyFormat) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6e8fa95e99cc4fa6, []int{0}
+}
+
+func (m *AesKwpKeyFormat) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AesKwpKeyFormat.Unmarshal(m, b)
+}
+func (m *AesKwpKeyFormat) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AesKwpKeyFormat.Marshal(b, m, deterministic)
+}
+func (m *AesKwpKeyFormat) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AesKwpKeyFormat.Merge(m, src)
+}
+func (m *AesKwpKeyFormat) XXX_Size() int {
+	return xxx_messageInfo_AesKwpKeyFormat.Size(m)
+}
+func (m *AesKwpKeyFormat) XXX_DiscardUnknown() {
+	xxx_messageInfo_AesKwpKeyFormat.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AesKwpKeyFormat proto.InternalMessageInfo
+
+func (m *AesKwpKeyFormat) GetKeySize() uint32 {
+	if m != nil {
+		return m.KeySize
+	}
+	return 0
+}
+
+// key_type: type.googleapis.com/tsingson.tink.AesKwpKey
+type AesKwpKey struct {
+	Version uint32 `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	// AES wrapping key used with KWP (NIST SP 800-38F).
+	KeyValue             []byte   `protobuf:"bytes,2,opt,name=key_value,json=keyValue,proto3" json:"key_value,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AesKwpKey) Reset()         { *m = AesKwpKey{} }
+func (m *AesKwpKey) String() string { return proto.CompactTextString(m) }
+func (*AesKwpKey) ProtoMessage()    {}
+func (*AesKwpKey) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6e8fa95e99cc4fa6, []int{1}
+}
+
+func (m *AesKwpKey) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AesKwpKey.Unmarshal(m, b)
+}
+func (m *AesKwpKey) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AesKwpKey.Marshal(b, m, deterministic)
+}
+func (m *AesKwpKey) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AesKwpKey.Merge(m, src)
+}
+func (m *AesKwpKey) XXX_Size() int {
+	return xxx_messageInfo_AesKwpKey.Size(m)
+}
+func (m *AesKwpKey) XXX_DiscardUnknown() {
+	xxx_messageInfo_AesKwpKey.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AesKwpKey proto.InternalMessageInfo
+
+func (m *AesKwpKey) GetVersion() uint32 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+func (m *AesKwpKey) GetKeyValue() []byte {
+	if m != nil {
+		return m.KeyValue
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*AesKwpKeyFormat)(nil), "tsingson.tink.AesKwpKeyFormat")
+	proto.RegisterType((*AesKwpKey)(nil), "tsingson.tink.AesKwpKey")
+}
+
+func init() { proto.RegisterFile("proto/aes_kwp.proto", fileDescriptor_6e8fa95e99cc4fa6) }
+
+var fileDescriptor_6e8fa95e99cc4fa6 = []byte{
+	// 223 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0x52, 0x29, 0xc9, 0xc8, 0x2c,
+	0x4a, 0x89, 0x2f, 0x48, 0x2c, 0x2a, 0xa9, 0xd4, 0x2f, 0xc9, 0xcc, 0xcb, 0xd6, 0x2f, 0x28, 0xca,
+	0x2f, 0xc9, 0xd7, 0x4f, 0x4c, 0x2d, 0x8e, 0xcf, 0x2e, 0x2f, 0xd0, 0x03, 0xf3, 0x84, 0x78, 0x4b,
+	0x8a, 0x33, 0xf3, 0xd2, 0x8b, 0xf3, 0xf3, 0xf4, 0x40, 0x4a, 0x94, 0x74, 0xb8, 0xf8, 0x1d, 0x53,
+	0x8b, 0xbd, 0xcb, 0x0b, 0xbc, 0x53, 0x2b, 0xdd, 0xf2, 0x8b, 0x72, 0x13, 0x4b, 0x84, 0x24, 0xb9,
+	0x38, 0xb2, 0x53, 0x2b, 0xe3, 0x8b, 0x33, 0xab, 0x52, 0x25, 0x18, 0x15, 0x18, 0x35, 0x78, 0x83,
+	0xd8, 0xb3, 0x53, 0x2b, 0x83, 0x33, 0xab, 0x52, 0x95, 0x9c, 0xb8, 0x38, 0xe1, 0xaa, 0x85, 0x24,
+	0xb8, 0xd8, 0xcb, 0x52, 0x8b, 0x8a, 0x33, 0xf3, 0xf3, 0x60, 0xca, 0xa0, 0x5c, 0x21, 0x69, 0x2e,
+	0x4e, 0x90, 0x09, 0x65, 0x89, 0x39, 0xa5, 0xa9, 0x12, 0x4c, 0x0a, 0x8c, 0x1a, 0x3c, 0x41, 0x20,
+	0x23, 0xc3, 0x40, 0x7c, 0xa7, 0x08, 0x2e, 0x99, 0xe4, 0xfc, 0x5c, 0xbd, 0xf4, 0xfc, 0xfc, 0xf4,
+	0x9c, 0x54, 0xbd, 0xe4, 0xa2, 0xca, 0x82, 0x92, 0x7c, 0xb0, 0x5b, 0x20, 0x0e, 0x0c, 0x60, 0x8c,
+	0xd2, 0x4d, 0xcf, 0x2c, 0xc9, 0x28, 0x4d, 0xd2, 0x4b, 0xce, 0xcf, 0xd5, 0x87, 0x28, 0xc3, 0xe2,
+	0x9d, 0xf8, 0xf4, 0xfc, 0x78, 0xb0, 0xc0, 0x22, 0x26, 0xb6, 0x10, 0x4f, 0x3f, 0xef, 0x00, 0xa7,
+	0x24, 0x36, 0x30, 0xdf, 0x18, 0x30, 0x00, 0x80, 0xb1, 0xfb, 0x64, 0x09, 0x01, 0x00, 0x00,
+}
@@ -0,0 +1,192 @@
+// Copyright 2018 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: third_party/tink/proto/config.proto
+
+package config_go_proto
+
+import (
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+// An entry that describes a key type to be used with Tink library,
+// specifying the corresponding primitive, key manager, and deprecation status.
+// All fields are required.
+type KeyTypeEntry struct {
+	PrimitiveName        string   `protobuf:"bytes,1,opt,name=primitive_name,json=primitiveName,proto3" json:"primitive_name,omitempty"`
+	TypeUrl              string   `protobuf:"bytes,2,opt,name=type_url,json=typeUrl,proto3" json:"type_url,omitempty"`
+	KeyManagerVersion    uint32   `protobuf:"varint,3,opt,name=key_manager_version,json=keyManagerVersion,proto3" json:"key_manager_version,omitempty"`
+	NewKeyAllowed        bool     `protobuf:"varint,4,opt,name=new_key_allowed,json=newKeyAllowed,proto3" json:"new_key_allowed,omitempty"`
+	CatalogueName        string   `protobuf:"bytes,5,opt,name=catalogue_name,json=catalogueName,proto3" json:"catalogue_name,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *KeyTypeEntry) Reset()         { *m = KeyTypeEntry{} }
+func (m *KeyTypeEntry) String() string { return proto.CompactTextString(m) }
+func (*KeyTypeEntry) ProtoMessage()    {}
+func (*KeyTypeEntry) Descriptor() ([]byte, []int) {
+	return fileDescriptor_7f932e476593c636, []int{0}
+}
+
+func (m *KeyTypeEntry) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_KeyTypeEntry.Unmarshal(m, b)
+}
+func (m *KeyTypeEntry) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_KeyTypeEntry.Marshal(b, m, deterministic)
+}
+func (m *KeyTypeEntry) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_KeyTypeEntry.Merge(m, src)
+}
+func (m *KeyTypeEntry) XXX_Size() int {
+	return xxx_messageInfo_KeyTypeEntry.Size(m)
+}
+func (m *KeyTypeEntry) XXX_DiscardUnknown() {
+	xxx_messageInfo_KeyTypeEntry.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_KeyTypeEntry proto.InternalMessageInfo
+
+func (m *KeyTypeEntry) GetPrimitiveName() string {
+	if m != nil {
+		return m.PrimitiveName
+	}
+	return ""
+}
+
+func (m *KeyTypeEntry) GetTypeUrl() string {
+	if m != nil {
+		return m.TypeUrl
+	}
+	return ""
+}
+
+func (m *KeyTypeEntry) GetKeyManagerVersion() uint32 {
+	if m != nil {
+		return m.KeyManagerVersion
+	}
+	return 0
+}
+
+func (m *KeyTypeEntry) GetNewKeyAllowed() bool {
+	if m != nil {
+		return m.NewKeyAllowed
+	}
+	return false
+}
+
+func (m *KeyTypeEntry) GetCatalogueName() string {
+	if m != nil {
+		return m.CatalogueName
+	}
+	return ""
+}
+
+// A complete configuration of Tink library: a list of key types
+// to be available via the Registry after initialization.
+// All fields are required.
+type RegistryConfig struct {
+	ConfigName           string          `protobuf:"bytes,1,opt,name=config_name,json=configName,proto3" json:"config_name,omitempty"`
+	Entry                []*KeyTypeEntry `protobuf:"bytes,2,rep,name=entry,proto3" json:"entry,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
+}
+
+func (m *RegistryConfig) Reset()         { *m = RegistryConfig{} }
+func (m *RegistryConfig) String() string { return proto.CompactTextString(m) }
+func (*RegistryConfig) ProtoMessage()    {}
+func (*RegistryConfig) Descriptor() ([]byte, []int) {
+	return fileDescriptor_7f932e476593c636, []int{1}
+}
+
+func (m *RegistryConfig) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RegistryConfig.Unmarshal(m, b)
+}
+func (m *RegistryConfig) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RegistryConfig.Marshal(b, m, deterministic)
+}
+func (m *RegistryConfig) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RegistryConfig.Merge(m, src)
+}
+func (m *RegistryConfig) XXX_Size() int {
+	return xxx_messageInfo_RegistryConfig.Size(m)
+}
+func (m *RegistryConfig) XXX_DiscardUnknown() {
+	xxx_messageInfo_RegistryConfig.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RegistryConfig proto.InternalMessageInfo
+
+func (m *RegistryConfig) GetConfigName() string {
+	if m != nil {
+		return m.ConfigName
+	}
+	return ""
+}
+
+func (m *RegistryConfig) GetEntry() []*KeyTypeEntry {
+	if m != nil {
+		return m.Entry
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*KeyTypeEntry)(nil), "google.crypto.tink.KeyTypeEntry")
+	proto.RegisterType((*RegistryConfig)(nil), "google.crypto.tink.RegistryConfig")
+}
+
+func init() { proto.RegisterFile("proto/config.proto", fileDescriptor_7f932e476593c636) }
+
+var fileDescriptor_7f932e476593c636 = []byte{
+	// 319 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x91, 0x41, 0x4b, 0xc3, 0x30,
+	0x1c, 0xc5, 0xe9, 0xe6, 0xe6, 0xcc, 0xdc, 0xc4, 0x78, 0xa9, 0x20, 0x58, 0x26, 0x4a, 0x0f, 0x92,
+	0x82, 0x82, 0x77, 0x27, 0x1e, 0x64, 0x38, 0x46, 0x99, 0x0a, 0x5e, 0x42, 0xd6, 0xfd, 0xcd, 0xc2,
+	0xda, 0xa4, 0x64, 0xd9, 0x4a, 0xbe, 0x8e, 0x5f, 0xc8, 0xaf, 0x24, 0x4d, 0x64, 0x0c, 0xe6, 0xf1,
+	0xbd, 0xff, 0x2f, 0xf0, 0xde, 0x0b, 0xba, 0x32, 0x0b, 0xa1, 0xe7, 0xb4, 0x64, 0xda, 0xd8, 0xc4,
+	0x08, 0xb9, 0x4c, 0x4a, 0xad, 0x8c, 0x4a, 0x32, 0x25, 0xbf, 0x04, 0x27, 0x4e, 0x60, 0xcc, 0x95,
+	0xe2, 0x39, 0x90, 0x4c, 0xdb, 0xd2, 0x28, 0x52, 0x63, 0x83, 0x9f, 0x00, 0x1d, 0x8f, 0xc0, 0x4e,
+	0x6d, 0x09, 0xcf, 0xd2, 0x68, 0x8b, 0xaf, 0x51, 0xbf, 0xd4, 0xa2, 0x10, 0x46, 0x6c, 0x80, 0x4a,
+	0x56, 0x40, 0x18, 0x44, 0x41, 0x7c, 0x94, 0xf6, 0xb6, 0xee, 0x98, 0x15, 0x80, 0xcf, 0x51, 0xc7,
+	0xd8, 0x12, 0xe8, 0x5a, 0xe7, 0x61, 0xc3, 0x01, 0x87, 0xb5, 0x7e, 0xd3, 0x39, 0x26, 0xe8, 0x6c,
+	0x09, 0x96, 0x16, 0x4c, 0x32, 0x0e, 0x9a, 0x6e, 0x40, 0xaf, 0x84, 0x92, 0x61, 0x33, 0x0a, 0xe2,
+	0x5e, 0x7a, 0xba, 0x04, 0xfb, 0xea, 0x2f, 0xef, 0xfe, 0x80, 0x6f, 0xd0, 0x89, 0x84, 0x8a, 0xd6,
+	0x6f, 0x58, 0x9e, 0xab, 0x0a, 0xe6, 0xe1, 0x41, 0x14, 0xc4, 0x9d, 0xb4, 0x27, 0xa1, 0x1a, 0x81,
+	0x7d, 0xf4, 0x66, 0x9d, 0x2c, 0x63, 0x86, 0xe5, 0x8a, 0xaf, 0xff, 0x92, 0xb5, 0x7c, 0xb2, 0xad,
+	0x5b, 0x27, 0x1b, 0x08, 0xd4, 0x4f, 0x81, 0x8b, 0x95, 0xd1, 0xf6, 0xc9, 0xb5, 0xc7, 0x97, 0xa8,
+	0xeb, 0x77, 0xd8, 0xed, 0x83, 0xbc, 0xe5, 0xca, 0x3c, 0xa0, 0x16, 0xd4, 0xe5, 0xc3, 0x46, 0xd4,
+	0x8c, 0xbb, 0x77, 0x11, 0xd9, 0x1f, 0x8a, 0xec, 0x8e, 0x94, 0x7a, 0x7c, 0xf8, 0x81, 0x2e, 0x32,
+	0x55, 0xfc, 0x47, 0xbb, 0xc1, 0x27, 0xc1, 0xe7, 0x2d, 0x17, 0x66, 0xb1, 0x9e, 0x91, 0x4c, 0x15,
+	0x89, 0xc7, 0xf6, 0x7f, 0x87, 0x72, 0x45, 0x9d, 0xfe, 0x6e, 0xb4, 0xa7, 0x2f, 0xe3, 0xd1, 0x64,
+	0x38, 0x6b, 0x3b, 0x7d, 0xff, 0x3b, 0x00, 0xbb, 0x89, 0x89, 0x22, 0xd7, 0x01, 0x00, 0x00,
+}
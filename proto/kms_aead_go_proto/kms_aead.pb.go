@@ -0,0 +1,156 @@
+// Copyright 2018 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: third_party/tink/proto/kms_aead.proto
+
+package kms_aead_go_proto
+
+import (
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+type KmsAeadKeyFormat struct {
+	// Required.
+	// The location of a KMS key.
+	// With Google Cloud KMS, valid values have this format:
+	// gcp-kms://projects/*/locations/*/keyRings/*/cryptoKeys/*.
+	// With AWS KMS, valid values have this format:
+	// aws-kms://arn:aws:kms:<region>:<account-id>:key/<key-id>
+	KeyUri               string   `protobuf:"bytes,1,opt,name=key_uri,json=keyUri,proto3" json:"key_uri,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *KmsAeadKeyFormat) Reset()         { *m = KmsAeadKeyFormat{} }
+func (m *KmsAeadKeyFormat) String() string { return proto.CompactTextString(m) }
+func (*KmsAeadKeyFormat) ProtoMessage()    {}
+func (*KmsAeadKeyFormat) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8fa9c4ffb34240de, []int{0}
+}
+
+func (m *KmsAeadKeyFormat) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_KmsAeadKeyFormat.Unmarshal(m, b)
+}
+func (m *KmsAeadKeyFormat) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_KmsAeadKeyFormat.Marshal(b, m, deterministic)
+}
+func (m *KmsAeadKeyFormat) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_KmsAeadKeyFormat.Merge(m, src)
+}
+func (m *KmsAeadKeyFormat) XXX_Size() int {
+	return xxx_messageInfo_KmsAeadKeyFormat.Size(m)
+}
+func (m *KmsAeadKeyFormat) XXX_DiscardUnknown() {
+	xxx_messageInfo_KmsAeadKeyFormat.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_KmsAeadKeyFormat proto.InternalMessageInfo
+
+func (m *KmsAeadKeyFormat) GetKeyUri() string {
+	if m != nil {
+		return m.KeyUri
+	}
+	return ""
+}
+
+// There is no actual key material in the key.
+type KmsAeadKey struct {
+	Version uint32 `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	// The key format also contains the params.
+	Params               *KmsAeadKeyFormat `protobuf:"bytes,2,opt,name=params,proto3" json:"params,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *KmsAeadKey) Reset()         { *m = KmsAeadKey{} }
+func (m *KmsAeadKey) String() string { return proto.CompactTextString(m) }
+func (*KmsAeadKey) ProtoMessage()    {}
+func (*KmsAeadKey) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8fa9c4ffb34240de, []int{1}
+}
+
+func (m *KmsAeadKey) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_KmsAeadKey.Unmarshal(m, b)
+}
+func (m *KmsAeadKey) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_KmsAeadKey.Marshal(b, m, deterministic)
+}
+func (m *KmsAeadKey) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_KmsAeadKey.Merge(m, src)
+}
+func (m *KmsAeadKey) XXX_Size() int {
+	return xxx_messageInfo_KmsAeadKey.Size(m)
+}
+func (m *KmsAeadKey) XXX_DiscardUnknown() {
+	xxx_messageInfo_KmsAeadKey.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_KmsAeadKey proto.InternalMessageInfo
+
+func (m *KmsAeadKey) GetVersion() uint32 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+func (m *KmsAeadKey) GetParams() *KmsAeadKeyFormat {
+	if m != nil {
+		return m.Params
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*KmsAeadKeyFormat)(nil), "google.crypto.tink.KmsAeadKeyFormat")
+	proto.RegisterType((*KmsAeadKey)(nil), "google.crypto.tink.KmsAeadKey")
+}
+
+func init() { proto.RegisterFile("proto/kms_aead.proto", fileDescriptor_8fa9c4ffb34240de) }
+
+var fileDescriptor_8fa9c4ffb34240de = []byte{
+	// 221 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0x52, 0x2d, 0xc9, 0xc8, 0x2c,
+	0x4a, 0x89, 0x2f, 0x48, 0x2c, 0x2a, 0xa9, 0xd4, 0x2f, 0xc9, 0xcc, 0xcb, 0xd6, 0x2f, 0x28, 0xca,
+	0x2f, 0xc9, 0xd7, 0xcf, 0xce, 0x2d, 0x8e, 0x4f, 0x4c, 0x4d, 0x4c, 0xd1, 0x03, 0x73, 0x85, 0x84,
+	0xd2, 0xf3, 0xf3, 0xd3, 0x73, 0x52, 0xf5, 0x92, 0x8b, 0x2a, 0x0b, 0x4a, 0xf2, 0xf5, 0x40, 0x0a,
+	0x95, 0xb4, 0xb9, 0x04, 0xbc, 0x73, 0x8b, 0x1d, 0x53, 0x13, 0x53, 0xbc, 0x53, 0x2b, 0xdd, 0xf2,
+	0x8b, 0x72, 0x13, 0x4b, 0x84, 0xc4, 0xb9, 0xd8, 0xb3, 0x53, 0x2b, 0xe3, 0x4b, 0x8b, 0x32, 0x25,
+	0x18, 0x15, 0x18, 0x35, 0x38, 0x83, 0xd8, 0xb2, 0x53, 0x2b, 0x43, 0x8b, 0x32, 0x95, 0x52, 0xb8,
+	0xb8, 0x10, 0x8a, 0x85, 0x24, 0xb8, 0xd8, 0xcb, 0x52, 0x8b, 0x8a, 0x33, 0xf3, 0xf3, 0xc0, 0xca,
+	0x78, 0x83, 0x60, 0x5c, 0x21, 0x1b, 0x2e, 0xb6, 0x82, 0xc4, 0xa2, 0xc4, 0xdc, 0x62, 0x09, 0x26,
+	0x05, 0x46, 0x0d, 0x6e, 0x23, 0x15, 0x3d, 0x4c, 0x9b, 0xf5, 0xd0, 0xad, 0x0d, 0x82, 0xea, 0x71,
+	0x8a, 0xe4, 0x92, 0x49, 0xce, 0xcf, 0xc5, 0xa6, 0x05, 0xec, 0x8d, 0x00, 0xc6, 0x28, 0xbd, 0xf4,
+	0xcc, 0x92, 0x8c, 0xd2, 0x24, 0xbd, 0xe4, 0xfc, 0x5c, 0x7d, 0x88, 0x32, 0x6c, 0xbe, 0x8e, 0x4f,
+	0xcf, 0x8f, 0x07, 0x8b, 0x2c, 0x62, 0x62, 0x0b, 0xf1, 0xf4, 0xf3, 0x0e, 0x70, 0x4a, 0x62, 0x03,
+	0xf3, 0x8d, 0x01, 0x03, 0x00, 0x31, 0x53, 0x0c, 0xef, 0x31, 0x01, 0x00, 0x00,
+}
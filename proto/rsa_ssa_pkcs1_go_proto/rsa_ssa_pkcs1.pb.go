@@ -0,0 +1,365 @@
+// Copyright 2018 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: third_party/tink/proto/rsa_ssa_pkcs1.proto
+
+package rsa_ssa_pkcs1_go_proto
+
+import (
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	common_go_proto "github.com/tsingson/tink/proto/common_go_proto"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+type RsaSsaPkcs1Params struct {
+	// Hash function used in computing hash of the signing message
+	// (see https://tools.ietf.org/html/rfc8017#section-9.2).
+	// Required.
+	HashType             common_go_proto.HashType `protobuf:"varint,1,opt,name=hash_type,json=hashType,proto3,enum=google.crypto.tink.HashType" json:"hash_type,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                 `json:"-"`
+	XXX_unrecognized     []byte                   `json:"-"`
+	XXX_sizecache        int32                    `json:"-"`
+}
+
+func (m *RsaSsaPkcs1Params) Reset()         { *m = RsaSsaPkcs1Params{} }
+func (m *RsaSsaPkcs1Params) String() string { return proto.CompactTextString(m) }
+func (*RsaSsaPkcs1Params) ProtoMessage()    {}
+func (*RsaSsaPkcs1Params) Descriptor() ([]byte, []int) {
+	return fileDescriptor_294a4bbabf091722, []int{0}
+}
+
+func (m *RsaSsaPkcs1Params) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RsaSsaPkcs1Params.Unmarshal(m, b)
+}
+func (m *RsaSsaPkcs1Params) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RsaSsaPkcs1Params.Marshal(b, m, deterministic)
+}
+func (m *RsaSsaPkcs1Params) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RsaSsaPkcs1Params.Merge(m, src)
+}
+func (m *RsaSsaPkcs1Params) XXX_Size() int {
+	return xxx_messageInfo_RsaSsaPkcs1Params.Size(m)
+}
+func (m *RsaSsaPkcs1Params) XXX_DiscardUnknown() {
+	xxx_messageInfo_RsaSsaPkcs1Params.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RsaSsaPkcs1Params proto.InternalMessageInfo
+
+func (m *RsaSsaPkcs1Params) GetHashType() common_go_proto.HashType {
+	if m != nil {
+		return m.HashType
+	}
+	return common_go_proto.HashType_UNKNOWN_HASH
+}
+
+// key_type: type.googleapis.com/google.crypto.tink.RsaSsaPkcs1PublicKey
+type RsaSsaPkcs1PublicKey struct {
+	// Required.
+	Version uint32 `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	// Required.
+	Params *RsaSsaPkcs1Params `protobuf:"bytes,2,opt,name=params,proto3" json:"params,omitempty"`
+	// Modulus.
+	// Unsigned big integer in bigendian representation.
+	N []byte `protobuf:"bytes,3,opt,name=n,proto3" json:"n,omitempty"`
+	// Public exponent.
+	// Unsigned big integer in bigendian representation.
+	E                    []byte   `protobuf:"bytes,4,opt,name=e,proto3" json:"e,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RsaSsaPkcs1PublicKey) Reset()         { *m = RsaSsaPkcs1PublicKey{} }
+func (m *RsaSsaPkcs1PublicKey) String() string { return proto.CompactTextString(m) }
+func (*RsaSsaPkcs1PublicKey) ProtoMessage()    {}
+func (*RsaSsaPkcs1PublicKey) Descriptor() ([]byte, []int) {
+	return fileDescriptor_294a4bbabf091722, []int{1}
+}
+
+func (m *RsaSsaPkcs1PublicKey) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RsaSsaPkcs1PublicKey.Unmarshal(m, b)
+}
+func (m *RsaSsaPkcs1PublicKey) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RsaSsaPkcs1PublicKey.Marshal(b, m, deterministic)
+}
+func (m *RsaSsaPkcs1PublicKey) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RsaSsaPkcs1PublicKey.Merge(m, src)
+}
+func (m *RsaSsaPkcs1PublicKey) XXX_Size() int {
+	return xxx_messageInfo_RsaSsaPkcs1PublicKey.Size(m)
+}
+func (m *RsaSsaPkcs1PublicKey) XXX_DiscardUnknown() {
+	xxx_messageInfo_RsaSsaPkcs1PublicKey.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RsaSsaPkcs1PublicKey proto.InternalMessageInfo
+
+func (m *RsaSsaPkcs1PublicKey) GetVersion() uint32 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+func (m *RsaSsaPkcs1PublicKey) GetParams() *RsaSsaPkcs1Params {
+	if m != nil {
+		return m.Params
+	}
+	return nil
+}
+
+func (m *RsaSsaPkcs1PublicKey) GetN() []byte {
+	if m != nil {
+		return m.N
+	}
+	return nil
+}
+
+func (m *RsaSsaPkcs1PublicKey) GetE() []byte {
+	if m != nil {
+		return m.E
+	}
+	return nil
+}
+
+// key_type: type.googleapis.com/google.crypto.tink.RsaSsaPkcs1PrivateKey
+type RsaSsaPkcs1PrivateKey struct {
+	// Required.
+	Version uint32 `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	// Required.
+	PublicKey *RsaSsaPkcs1PublicKey `protobuf:"bytes,2,opt,name=public_key,json=publicKey,proto3" json:"public_key,omitempty"`
+	// Private exponent.
+	// Unsigned big integer in bigendian representation.
+	// Required.
+	D []byte `protobuf:"bytes,3,opt,name=d,proto3" json:"d,omitempty"`
+	// The following parameters are used to optimize RSA signature computation.
+	// The prime factor p of n.
+	// Unsigned big integer in bigendian representation.
+	// Required.
+	P []byte `protobuf:"bytes,4,opt,name=p,proto3" json:"p,omitempty"`
+	// The prime factor q of n.
+	// Unsigned big integer in bigendian representation.
+	// Required.
+	Q []byte `protobuf:"bytes,5,opt,name=q,proto3" json:"q,omitempty"`
+	// d mod (p - 1).
+	// Unsigned big integer in bigendian representation.
+	// Required.
+	Dp []byte `protobuf:"bytes,6,opt,name=dp,proto3" json:"dp,omitempty"`
+	// d mod (q - 1).
+	// Unsigned big integer in bigendian representation.
+	// Required.
+	Dq []byte `protobuf:"bytes,7,opt,name=dq,proto3" json:"dq,omitempty"`
+	// Chinese Remainder Theorem coefficient q^(-1) mod p.
+	// Unsigned big integer in bigendian representation.
+	// Required.
+	Crt                  []byte   `protobuf:"bytes,8,opt,name=crt,proto3" json:"crt,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RsaSsaPkcs1PrivateKey) Reset()         { *m = RsaSsaPkcs1PrivateKey{} }
+func (m *RsaSsaPkcs1PrivateKey) String() string { return proto.CompactTextString(m) }
+func (*RsaSsaPkcs1PrivateKey) ProtoMessage()    {}
+func (*RsaSsaPkcs1PrivateKey) Descriptor() ([]byte, []int) {
+	return fileDescriptor_294a4bbabf091722, []int{2}
+}
+
+func (m *RsaSsaPkcs1PrivateKey) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RsaSsaPkcs1PrivateKey.Unmarshal(m, b)
+}
+func (m *RsaSsaPkcs1PrivateKey) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RsaSsaPkcs1PrivateKey.Marshal(b, m, deterministic)
+}
+func (m *RsaSsaPkcs1PrivateKey) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RsaSsaPkcs1PrivateKey.Merge(m, src)
+}
+func (m *RsaSsaPkcs1PrivateKey) XXX_Size() int {
+	return xxx_messageInfo_RsaSsaPkcs1PrivateKey.Size(m)
+}
+func (m *RsaSsaPkcs1PrivateKey) XXX_DiscardUnknown() {
+	xxx_messageInfo_RsaSsaPkcs1PrivateKey.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RsaSsaPkcs1PrivateKey proto.InternalMessageInfo
+
+func (m *RsaSsaPkcs1PrivateKey) GetVersion() uint32 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+func (m *RsaSsaPkcs1PrivateKey) GetPublicKey() *RsaSsaPkcs1PublicKey {
+	if m != nil {
+		return m.PublicKey
+	}
+	return nil
+}
+
+func (m *RsaSsaPkcs1PrivateKey) GetD() []byte {
+	if m != nil {
+		return m.D
+	}
+	return nil
+}
+
+func (m *RsaSsaPkcs1PrivateKey) GetP() []byte {
+	if m != nil {
+		return m.P
+	}
+	return nil
+}
+
+func (m *RsaSsaPkcs1PrivateKey) GetQ() []byte {
+	if m != nil {
+		return m.Q
+	}
+	return nil
+}
+
+func (m *RsaSsaPkcs1PrivateKey) GetDp() []byte {
+	if m != nil {
+		return m.Dp
+	}
+	return nil
+}
+
+func (m *RsaSsaPkcs1PrivateKey) GetDq() []byte {
+	if m != nil {
+		return m.Dq
+	}
+	return nil
+}
+
+func (m *RsaSsaPkcs1PrivateKey) GetCrt() []byte {
+	if m != nil {
+		return m.Crt
+	}
+	return nil
+}
+
+type RsaSsaPkcs1KeyFormat struct {
+	// Required.
+	Params *RsaSsaPkcs1Params `protobuf:"bytes,1,opt,name=params,proto3" json:"params,omitempty"`
+	// Required.
+	ModulusSizeInBits uint32 `protobuf:"varint,2,opt,name=modulus_size_in_bits,json=modulusSizeInBits,proto3" json:"modulus_size_in_bits,omitempty"`
+	// Required.
+	PublicExponent       []byte   `protobuf:"bytes,3,opt,name=public_exponent,json=publicExponent,proto3" json:"public_exponent,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RsaSsaPkcs1KeyFormat) Reset()         { *m = RsaSsaPkcs1KeyFormat{} }
+func (m *RsaSsaPkcs1KeyFormat) String() string { return proto.CompactTextString(m) }
+func (*RsaSsaPkcs1KeyFormat) ProtoMessage()    {}
+func (*RsaSsaPkcs1KeyFormat) Descriptor() ([]byte, []int) {
+	return fileDescriptor_294a4bbabf091722, []int{3}
+}
+
+func (m *RsaSsaPkcs1KeyFormat) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RsaSsaPkcs1KeyFormat.Unmarshal(m, b)
+}
+func (m *RsaSsaPkcs1KeyFormat) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RsaSsaPkcs1KeyFormat.Marshal(b, m, deterministic)
+}
+func (m *RsaSsaPkcs1KeyFormat) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RsaSsaPkcs1KeyFormat.Merge(m, src)
+}
+func (m *RsaSsaPkcs1KeyFormat) XXX_Size() int {
+	return xxx_messageInfo_RsaSsaPkcs1KeyFormat.Size(m)
+}
+func (m *RsaSsaPkcs1KeyFormat) XXX_DiscardUnknown() {
+	xxx_messageInfo_RsaSsaPkcs1KeyFormat.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RsaSsaPkcs1KeyFormat proto.InternalMessageInfo
+
+func (m *RsaSsaPkcs1KeyFormat) GetParams() *RsaSsaPkcs1Params {
+	if m != nil {
+		return m.Params
+	}
+	return nil
+}
+
+func (m *RsaSsaPkcs1KeyFormat) GetModulusSizeInBits() uint32 {
+	if m != nil {
+		return m.ModulusSizeInBits
+	}
+	return 0
+}
+
+func (m *RsaSsaPkcs1KeyFormat) GetPublicExponent() []byte {
+	if m != nil {
+		return m.PublicExponent
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*RsaSsaPkcs1Params)(nil), "google.crypto.tink.RsaSsaPkcs1Params")
+	proto.RegisterType((*RsaSsaPkcs1PublicKey)(nil), "google.crypto.tink.RsaSsaPkcs1PublicKey")
+	proto.RegisterType((*RsaSsaPkcs1PrivateKey)(nil), "google.crypto.tink.RsaSsaPkcs1PrivateKey")
+	proto.RegisterType((*RsaSsaPkcs1KeyFormat)(nil), "google.crypto.tink.RsaSsaPkcs1KeyFormat")
+}
+
+func init() { proto.RegisterFile("proto/rsa_ssa_pkcs1.proto", fileDescriptor_294a4bbabf091722) }
+
+var fileDescriptor_294a4bbabf091722 = []byte{
+	// 428 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x92, 0xdf, 0x6e, 0xd3, 0x30,
+	0x14, 0xc6, 0xe5, 0x0e, 0xba, 0xed, 0xd0, 0x15, 0x66, 0x0d, 0xc9, 0x42, 0xbb, 0xa8, 0x8a, 0x10,
+	0x15, 0x17, 0x89, 0xd8, 0xae, 0xb8, 0xe0, 0xa6, 0x12, 0x7f, 0xa6, 0x4a, 0x53, 0x95, 0xed, 0x8a,
+	0x0b, 0x2c, 0x37, 0xb1, 0x12, 0xab, 0x8d, 0xed, 0xd8, 0xce, 0x44, 0xf6, 0x0a, 0xbc, 0x05, 0x2f,
+	0xc0, 0xdb, 0xf0, 0x3c, 0x28, 0x8e, 0x83, 0x8a, 0xb6, 0x82, 0xb4, 0x3b, 0xff, 0xbe, 0xf8, 0x9c,
+	0xf3, 0x9d, 0x2f, 0x86, 0x37, 0xae, 0x10, 0x26, 0xa3, 0x9a, 0x19, 0xd7, 0xc4, 0x4e, 0xc8, 0x75,
+	0xac, 0x8d, 0x72, 0x2a, 0x36, 0x96, 0x51, 0x6b, 0x19, 0xd5, 0xeb, 0xd4, 0xbe, 0x8d, 0xbc, 0x86,
+	0x71, 0xae, 0x54, 0xbe, 0xe1, 0x51, 0x6a, 0x1a, 0xed, 0x54, 0xd4, 0xde, 0x7e, 0xf1, 0x72, 0x47,
+	0x7d, 0xaa, 0xca, 0x52, 0xc9, 0xae, 0x70, 0x7a, 0x09, 0xc7, 0x89, 0x65, 0x57, 0x96, 0x2d, 0xdb,
+	0x6e, 0x4b, 0x66, 0x58, 0x69, 0xf1, 0x3b, 0x38, 0x2c, 0x98, 0x2d, 0xa8, 0x6b, 0x34, 0x27, 0x68,
+	0x82, 0x66, 0xe3, 0xb3, 0xd3, 0xe8, 0xee, 0x84, 0xe8, 0x33, 0xb3, 0xc5, 0x75, 0xa3, 0x79, 0x72,
+	0x50, 0x84, 0xd3, 0xf4, 0x3b, 0x82, 0x93, 0xed, 0x86, 0xf5, 0x6a, 0x23, 0xd2, 0x05, 0x6f, 0x30,
+	0x81, 0xfd, 0x1b, 0x6e, 0xac, 0x50, 0xd2, 0x77, 0x3c, 0x4a, 0x7a, 0xc4, 0xef, 0x61, 0xa8, 0xfd,
+	0x5c, 0x32, 0x98, 0xa0, 0xd9, 0x93, 0xb3, 0x57, 0xf7, 0x8d, 0xba, 0x63, 0x32, 0x09, 0x45, 0x78,
+	0x04, 0x48, 0x92, 0xbd, 0x09, 0x9a, 0x8d, 0x12, 0x24, 0x5b, 0xe2, 0xe4, 0x51, 0x47, 0x7c, 0xfa,
+	0x0b, 0xc1, 0xf3, 0xed, 0x4a, 0x23, 0x6e, 0x98, 0xe3, 0xff, 0xb6, 0xf3, 0x09, 0x40, 0x7b, 0xd7,
+	0x74, 0xcd, 0x9b, 0x60, 0x69, 0xf6, 0x3f, 0x4b, 0xfd, 0x9a, 0xc9, 0xa1, 0xfe, 0xb3, 0xf1, 0x08,
+	0x50, 0xd6, 0x1b, 0xcb, 0x5a, 0xd2, 0xbd, 0x31, 0xdd, 0x52, 0x45, 0x1e, 0x77, 0x54, 0xe1, 0x31,
+	0x0c, 0x32, 0x4d, 0x86, 0x1e, 0x07, 0x99, 0xf6, 0x5c, 0x91, 0xfd, 0xc0, 0x15, 0x7e, 0x06, 0x7b,
+	0xa9, 0x71, 0xe4, 0xc0, 0x0b, 0xed, 0x71, 0xfa, 0xf3, 0xef, 0x98, 0x17, 0xbc, 0xf9, 0xa8, 0x4c,
+	0xc9, 0xdc, 0x56, 0x98, 0xe8, 0x21, 0x61, 0xc6, 0x70, 0x52, 0xaa, 0xac, 0xde, 0xd4, 0x96, 0x5a,
+	0x71, 0xcb, 0xa9, 0x90, 0x74, 0x25, 0x5c, 0xf7, 0x67, 0x8e, 0x92, 0xe3, 0xf0, 0xed, 0x4a, 0xdc,
+	0xf2, 0x0b, 0x39, 0x17, 0xce, 0xe2, 0xd7, 0xf0, 0x34, 0xa4, 0xc5, 0xbf, 0x69, 0x25, 0xb9, 0x74,
+	0x61, 0xe5, 0x71, 0x27, 0x7f, 0x08, 0xea, 0xfc, 0x2b, 0x9c, 0xa6, 0xaa, 0xbc, 0xcf, 0x8d, 0x7f,
+	0x88, 0x4b, 0xf4, 0xe5, 0x3c, 0x17, 0xae, 0xa8, 0x57, 0x51, 0xaa, 0xca, 0xb8, 0xbb, 0xb6, 0xf3,
+	0xd5, 0xd3, 0x5c, 0x51, 0x2f, 0xff, 0x18, 0x0c, 0xaf, 0x2f, 0x2e, 0x17, 0xcb, 0xf9, 0x6a, 0xe8,
+	0xf9, 0xfc, 0xf7, 0x00, 0x22, 0x1e, 0x7b, 0x27, 0x36, 0x03, 0x00, 0x00,
+}
@@ -0,0 +1,388 @@
+// Copyright 2018 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: third_party/tink/proto/rsa_ssa_pss.proto
+
+package rsa_ssa_pss_go_proto
+
+import (
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	common_go_proto "github.com/tsingson/tink/proto/common_go_proto"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+type RsaSsaPssParams struct {
+	// Hash function used in computing hash of the signing message
+	// (see https://tools.ietf.org/html/rfc8017#section-9.1.1).
+	// Required.
+	SigHash common_go_proto.HashType `protobuf:"varint,1,opt,name=sig_hash,json=sigHash,proto3,enum=google.crypto.tink.HashType" json:"sig_hash,omitempty"`
+	// Hash function used in MGF1 (a mask generation function based on a
+	// hash function) (see https://tools.ietf.org/html/rfc8017#appendix-B.2.1).
+	// Required.
+	Mgf1Hash common_go_proto.HashType `protobuf:"varint,2,opt,name=mgf1_hash,json=mgf1Hash,proto3,enum=google.crypto.tink.HashType" json:"mgf1_hash,omitempty"`
+	// Salt length (see https://tools.ietf.org/html/rfc8017#section-9.1.1)
+	// Required.
+	SaltLength           int32    `protobuf:"varint,3,opt,name=salt_length,json=saltLength,proto3" json:"salt_length,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RsaSsaPssParams) Reset()         { *m = RsaSsaPssParams{} }
+func (m *RsaSsaPssParams) String() string { return proto.CompactTextString(m) }
+func (*RsaSsaPssParams) ProtoMessage()    {}
+func (*RsaSsaPssParams) Descriptor() ([]byte, []int) {
+	return fileDescriptor_aacfb9eade71ca83, []int{0}
+}
+
+func (m *RsaSsaPssParams) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RsaSsaPssParams.Unmarshal(m, b)
+}
+func (m *RsaSsaPssParams) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RsaSsaPssParams.Marshal(b, m, deterministic)
+}
+func (m *RsaSsaPssParams) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RsaSsaPssParams.Merge(m, src)
+}
+func (m *RsaSsaPssParams) XXX_Size() int {
+	return xxx_messageInfo_RsaSsaPssParams.Size(m)
+}
+func (m *RsaSsaPssParams) XXX_DiscardUnknown() {
+	xxx_messageInfo_RsaSsaPssParams.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RsaSsaPssParams proto.InternalMessageInfo
+
+func (m *RsaSsaPssParams) GetSigHash() common_go_proto.HashType {
+	if m != nil {
+		return m.SigHash
+	}
+	return common_go_proto.HashType_UNKNOWN_HASH
+}
+
+func (m *RsaSsaPssParams) GetMgf1Hash() common_go_proto.HashType {
+	if m != nil {
+		return m.Mgf1Hash
+	}
+	return common_go_proto.HashType_UNKNOWN_HASH
+}
+
+func (m *RsaSsaPssParams) GetSaltLength() int32 {
+	if m != nil {
+		return m.SaltLength
+	}
+	return 0
+}
+
+// key_type: type.googleapis.com/google.crypto.tink.RsaSsaPssPublicKey
+type RsaSsaPssPublicKey struct {
+	// Required.
+	Version uint32 `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	// Required.
+	Params *RsaSsaPssParams `protobuf:"bytes,2,opt,name=params,proto3" json:"params,omitempty"`
+	// Modulus.
+	// Unsigned big integer in bigendian representation.
+	N []byte `protobuf:"bytes,3,opt,name=n,proto3" json:"n,omitempty"`
+	// Public exponent.
+	// Unsigned big integer in bigendian representation.
+	E                    []byte   `protobuf:"bytes,4,opt,name=e,proto3" json:"e,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RsaSsaPssPublicKey) Reset()         { *m = RsaSsaPssPublicKey{} }
+func (m *RsaSsaPssPublicKey) String() string { return proto.CompactTextString(m) }
+func (*RsaSsaPssPublicKey) ProtoMessage()    {}
+func (*RsaSsaPssPublicKey) Descriptor() ([]byte, []int) {
+	return fileDescriptor_aacfb9eade71ca83, []int{1}
+}
+
+func (m *RsaSsaPssPublicKey) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RsaSsaPssPublicKey.Unmarshal(m, b)
+}
+func (m *RsaSsaPssPublicKey) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RsaSsaPssPublicKey.Marshal(b, m, deterministic)
+}
+func (m *RsaSsaPssPublicKey) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RsaSsaPssPublicKey.Merge(m, src)
+}
+func (m *RsaSsaPssPublicKey) XXX_Size() int {
+	return xxx_messageInfo_RsaSsaPssPublicKey.Size(m)
+}
+func (m *RsaSsaPssPublicKey) XXX_DiscardUnknown() {
+	xxx_messageInfo_RsaSsaPssPublicKey.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RsaSsaPssPublicKey proto.InternalMessageInfo
+
+func (m *RsaSsaPssPublicKey) GetVersion() uint32 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+func (m *RsaSsaPssPublicKey) GetParams() *RsaSsaPssParams {
+	if m != nil {
+		return m.Params
+	}
+	return nil
+}
+
+func (m *RsaSsaPssPublicKey) GetN() []byte {
+	if m != nil {
+		return m.N
+	}
+	return nil
+}
+
+func (m *RsaSsaPssPublicKey) GetE() []byte {
+	if m != nil {
+		return m.E
+	}
+	return nil
+}
+
+// key_type: type.googleapis.com/google.crypto.tink.RsaSsaPssPrivateKey
+type RsaSsaPssPrivateKey struct {
+	// Required.
+	Version uint32 `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	// Required.
+	PublicKey *RsaSsaPssPublicKey `protobuf:"bytes,2,opt,name=public_key,json=publicKey,proto3" json:"public_key,omitempty"`
+	// Private exponent.
+	// Unsigned big integer in bigendian representation.
+	// Required.
+	D []byte `protobuf:"bytes,3,opt,name=d,proto3" json:"d,omitempty"`
+	// The following parameters are used to optimize RSA signature computation.
+	// The prime factor p of n.
+	// Unsigned big integer in bigendian representation.
+	// Required.
+	P []byte `protobuf:"bytes,4,opt,name=p,proto3" json:"p,omitempty"`
+	// The prime factor q of n.
+	// Unsigned big integer in bigendian representation.
+	// Required.
+	Q []byte `protobuf:"bytes,5,opt,name=q,proto3" json:"q,omitempty"`
+	// d mod (p - 1).
+	// Unsigned big integer in bigendian representation.
+	// Required.
+	Dp []byte `protobuf:"bytes,6,opt,name=dp,proto3" json:"dp,omitempty"`
+	// d mod (q - 1).
+	// Unsigned big integer in bigendian representation.
+	// Required.
+	Dq []byte `protobuf:"bytes,7,opt,name=dq,proto3" json:"dq,omitempty"`
+	// Chinese Remainder Theorem coefficient q^(-1) mod p.
+	// Unsigned big integer in bigendian representation.
+	// Required.
+	Crt                  []byte   `protobuf:"bytes,8,opt,name=crt,proto3" json:"crt,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RsaSsaPssPrivateKey) Reset()         { *m = RsaSsaPssPrivateKey{} }
+func (m *RsaSsaPssPrivateKey) String() string { return proto.CompactTextString(m) }
+func (*RsaSsaPssPrivateKey) ProtoMessage()    {}
+func (*RsaSsaPssPrivateKey) Descriptor() ([]byte, []int) {
+	return fileDescriptor_aacfb9eade71ca83, []int{2}
+}
+
+func (m *RsaSsaPssPrivateKey) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RsaSsaPssPrivateKey.Unmarshal(m, b)
+}
+func (m *RsaSsaPssPrivateKey) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RsaSsaPssPrivateKey.Marshal(b, m, deterministic)
+}
+func (m *RsaSsaPssPrivateKey) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RsaSsaPssPrivateKey.Merge(m, src)
+}
+func (m *RsaSsaPssPrivateKey) XXX_Size() int {
+	return xxx_messageInfo_RsaSsaPssPrivateKey.Size(m)
+}
+func (m *RsaSsaPssPrivateKey) XXX_DiscardUnknown() {
+	xxx_messageInfo_RsaSsaPssPrivateKey.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RsaSsaPssPrivateKey proto.InternalMessageInfo
+
+func (m *RsaSsaPssPrivateKey) GetVersion() uint32 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+func (m *RsaSsaPssPrivateKey) GetPublicKey() *RsaSsaPssPublicKey {
+	if m != nil {
+		return m.PublicKey
+	}
+	return nil
+}
+
+func (m *RsaSsaPssPrivateKey) GetD() []byte {
+	if m != nil {
+		return m.D
+	}
+	return nil
+}
+
+func (m *RsaSsaPssPrivateKey) GetP() []byte {
+	if m != nil {
+		return m.P
+	}
+	return nil
+}
+
+func (m *RsaSsaPssPrivateKey) GetQ() []byte {
+	if m != nil {
+		return m.Q
+	}
+	return nil
+}
+
+func (m *RsaSsaPssPrivateKey) GetDp() []byte {
+	if m != nil {
+		return m.Dp
+	}
+	return nil
+}
+
+func (m *RsaSsaPssPrivateKey) GetDq() []byte {
+	if m != nil {
+		return m.Dq
+	}
+	return nil
+}
+
+func (m *RsaSsaPssPrivateKey) GetCrt() []byte {
+	if m != nil {
+		return m.Crt
+	}
+	return nil
+}
+
+type RsaSsaPssKeyFormat struct {
+	// Required.
+	Params *RsaSsaPssParams `protobuf:"bytes,1,opt,name=params,proto3" json:"params,omitempty"`
+	// Required.
+	ModulusSizeInBits uint32 `protobuf:"varint,2,opt,name=modulus_size_in_bits,json=modulusSizeInBits,proto3" json:"modulus_size_in_bits,omitempty"`
+	// Required.
+	PublicExponent       []byte   `protobuf:"bytes,3,opt,name=public_exponent,json=publicExponent,proto3" json:"public_exponent,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RsaSsaPssKeyFormat) Reset()         { *m = RsaSsaPssKeyFormat{} }
+func (m *RsaSsaPssKeyFormat) String() string { return proto.CompactTextString(m) }
+func (*RsaSsaPssKeyFormat) ProtoMessage()    {}
+func (*RsaSsaPssKeyFormat) Descriptor() ([]byte, []int) {
+	return fileDescriptor_aacfb9eade71ca83, []int{3}
+}
+
+func (m *RsaSsaPssKeyFormat) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RsaSsaPssKeyFormat.Unmarshal(m, b)
+}
+func (m *RsaSsaPssKeyFormat) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RsaSsaPssKeyFormat.Marshal(b, m, deterministic)
+}
+func (m *RsaSsaPssKeyFormat) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RsaSsaPssKeyFormat.Merge(m, src)
+}
+func (m *RsaSsaPssKeyFormat) XXX_Size() int {
+	return xxx_messageInfo_RsaSsaPssKeyFormat.Size(m)
+}
+func (m *RsaSsaPssKeyFormat) XXX_DiscardUnknown() {
+	xxx_messageInfo_RsaSsaPssKeyFormat.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RsaSsaPssKeyFormat proto.InternalMessageInfo
+
+func (m *RsaSsaPssKeyFormat) GetParams() *RsaSsaPssParams {
+	if m != nil {
+		return m.Params
+	}
+	return nil
+}
+
+func (m *RsaSsaPssKeyFormat) GetModulusSizeInBits() uint32 {
+	if m != nil {
+		return m.ModulusSizeInBits
+	}
+	return 0
+}
+
+func (m *RsaSsaPssKeyFormat) GetPublicExponent() []byte {
+	if m != nil {
+		return m.PublicExponent
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*RsaSsaPssParams)(nil), "google.crypto.tink.RsaSsaPssParams")
+	proto.RegisterType((*RsaSsaPssPublicKey)(nil), "google.crypto.tink.RsaSsaPssPublicKey")
+	proto.RegisterType((*RsaSsaPssPrivateKey)(nil), "google.crypto.tink.RsaSsaPssPrivateKey")
+	proto.RegisterType((*RsaSsaPssKeyFormat)(nil), "google.crypto.tink.RsaSsaPssKeyFormat")
+}
+
+func init() { proto.RegisterFile("proto/rsa_ssa_pss.proto", fileDescriptor_aacfb9eade71ca83) }
+
+var fileDescriptor_aacfb9eade71ca83 = []byte{
+	// 457 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x93, 0xc1, 0x6a, 0xd4, 0x40,
+	0x18, 0xc7, 0x99, 0xad, 0xdd, 0xdd, 0x7e, 0xdd, 0x6e, 0x75, 0xf4, 0x10, 0xa4, 0x60, 0xd9, 0x82,
+	0xee, 0x29, 0xa1, 0xf5, 0x20, 0xe2, 0x6d, 0xa1, 0x62, 0x59, 0x91, 0x25, 0xed, 0x49, 0x84, 0x61,
+	0x36, 0x19, 0x93, 0xa1, 0xc9, 0xcc, 0xec, 0x7c, 0xb3, 0xc5, 0xf4, 0x05, 0x7c, 0x0f, 0x0f, 0x5e,
+	0x7c, 0x1b, 0x9f, 0x48, 0x32, 0x49, 0x6c, 0xd1, 0x76, 0xd1, 0x5b, 0x7e, 0xff, 0xcc, 0x3f, 0xfc,
+	0xbe, 0x2f, 0x09, 0x4c, 0x5d, 0x2e, 0x6d, 0xca, 0x0c, 0xb7, 0xae, 0x8a, 0x9c, 0x54, 0x97, 0x91,
+	0xb1, 0xda, 0xe9, 0xc8, 0x22, 0x67, 0x88, 0x9c, 0x19, 0xc4, 0xd0, 0x27, 0x94, 0x66, 0x5a, 0x67,
+	0x85, 0x08, 0x13, 0x5b, 0x19, 0xa7, 0xc3, 0xfa, 0xec, 0xd3, 0xa3, 0x7b, 0xda, 0x89, 0x2e, 0x4b,
+	0xad, 0x9a, 0xe2, 0xe4, 0x3b, 0x81, 0xfd, 0x18, 0xf9, 0x39, 0xf2, 0x05, 0xe2, 0x82, 0x5b, 0x5e,
+	0x22, 0x7d, 0x05, 0x43, 0x94, 0x19, 0xcb, 0x39, 0xe6, 0x01, 0x39, 0x24, 0xd3, 0xf1, 0xc9, 0x41,
+	0xf8, 0xf7, 0xf3, 0xc3, 0x77, 0x1c, 0xf3, 0x8b, 0xca, 0x88, 0x78, 0x80, 0x32, 0xab, 0x81, 0xbe,
+	0x86, 0x9d, 0x32, 0xfb, 0x7c, 0xdc, 0x34, 0x7b, 0xff, 0xd0, 0x1c, 0xd6, 0xc7, 0x7d, 0xf5, 0x19,
+	0xec, 0x22, 0x2f, 0x1c, 0x2b, 0x84, 0xca, 0x5c, 0x1e, 0x6c, 0x1d, 0x92, 0xe9, 0x76, 0x0c, 0x75,
+	0xf4, 0xde, 0x27, 0x93, 0xaf, 0x04, 0xe8, 0x8d, 0xe8, 0x7a, 0x59, 0xc8, 0x64, 0x2e, 0x2a, 0x1a,
+	0xc0, 0xe0, 0x4a, 0x58, 0x94, 0x5a, 0x79, 0xd5, 0xbd, 0xb8, 0x43, 0xfa, 0x06, 0xfa, 0xc6, 0xcf,
+	0xe3, 0x4d, 0x76, 0x4f, 0x8e, 0xee, 0x32, 0xf9, 0x63, 0xf4, 0xb8, 0xad, 0xd0, 0x11, 0x10, 0xe5,
+	0x25, 0x46, 0x31, 0x51, 0x35, 0x89, 0xe0, 0x41, 0x43, 0x62, 0xf2, 0x93, 0xc0, 0xe3, 0x9b, 0x9e,
+	0x95, 0x57, 0xdc, 0x89, 0xcd, 0x2a, 0xa7, 0x00, 0xc6, 0x1b, 0xb3, 0x4b, 0x51, 0xb5, 0x3a, 0xcf,
+	0x37, 0xeb, 0x74, 0x03, 0xc6, 0x3b, 0xe6, 0xf7, 0xac, 0x23, 0x20, 0x69, 0x27, 0x95, 0xd6, 0x64,
+	0x3a, 0x29, 0x53, 0xd3, 0x2a, 0xd8, 0x6e, 0x68, 0x45, 0xc7, 0xd0, 0x4b, 0x4d, 0xd0, 0xf7, 0xd8,
+	0x4b, 0x8d, 0xe7, 0x55, 0x30, 0x68, 0x79, 0x45, 0x1f, 0xc2, 0x56, 0x62, 0x5d, 0x30, 0xf4, 0x41,
+	0x7d, 0x39, 0xf9, 0x71, 0x7b, 0xbd, 0x73, 0x51, 0xbd, 0xd5, 0xb6, 0xe4, 0xee, 0xd6, 0x12, 0xc9,
+	0xff, 0x2f, 0x31, 0x82, 0x27, 0xa5, 0x4e, 0xd7, 0xc5, 0x1a, 0x19, 0xca, 0x6b, 0xc1, 0xa4, 0x62,
+	0x4b, 0xe9, 0x9a, 0xf7, 0xb1, 0x17, 0x3f, 0x6a, 0xef, 0x9d, 0xcb, 0x6b, 0x71, 0xa6, 0x66, 0xd2,
+	0x21, 0x7d, 0x01, 0xfb, 0xed, 0x9e, 0xc4, 0x17, 0xa3, 0x95, 0x50, 0xae, 0x1d, 0x77, 0xdc, 0xc4,
+	0xa7, 0x6d, 0x3a, 0xfb, 0x04, 0x07, 0x89, 0x2e, 0xef, 0x72, 0xf1, 0x5f, 0xf5, 0x82, 0x7c, 0x3c,
+	0xce, 0xa4, 0xcb, 0xd7, 0xcb, 0x30, 0xd1, 0x65, 0xd4, 0x1c, 0xbb, 0xe7, 0x07, 0x62, 0x99, 0x66,
+	0x3e, 0xfc, 0xd6, 0xeb, 0x5f, 0x9c, 0x7d, 0x98, 0x2f, 0x66, 0xcb, 0xbe, 0xe7, 0x97, 0xbf, 0x06,
+	0x00, 0xbb, 0x37, 0x0e, 0x07, 0x7f, 0x03, 0x00, 0x00,
+}
@@ -33,6 +33,7 @@ import (
 	"github.com/tsingson/tink/golang/core/registry"
 	"github.com/tsingson/tink/golang/integration/awskms"
 	"github.com/tsingson/tink/golang/integration/gcpkms"
+	"github.com/tsingson/tink/golang/integration/localkms"
 	"github.com/tsingson/tink/golang/keyset"
 	"github.com/tsingson/tink/golang/testkeyset"
 )
@@ -42,28 +43,41 @@ const (
 	gcpCredFile = "testdata/credential.json"
 	awsURI      = "aws-kms://arn:aws:kms:us-east-2:235739564943:key/3ee50705-5a82-4f5b-9753-05c4f473922f"
 	awsCredFile = "testdata/credentials_aws.csv"
+	localPrefix = "local-kms://"
 )
 
 func init() {
-	gcpclient, err := gcpkms.NewGCPClient(gcpURI)
+	// Local master keys need no credentials, so the CLI also works where the
+	// credentials of the cloud KMSes are not available.
+	localclient, err := localkms.NewLocalClient(localPrefix)
 	if err != nil {
 		log.Fatal(err)
 	}
-	_, err = gcpclient.LoadCredentials(gcpCredFile)
-	if err != nil {
-		log.Fatal(err)
+	registry.RegisterKMSClient(localclient)
+
+	if _, err := os.Stat(gcpCredFile); err == nil {
+		gcpclient, err := gcpkms.NewGCPClient(gcpURI)
+		if err != nil {
+			log.Fatal(err)
+		}
+		_, err = gcpclient.LoadCredentials(gcpCredFile)
+		if err != nil {
+			log.Fatal(err)
+		}
+		registry.RegisterKMSClient(gcpclient)
 	}
-	registry.RegisterKMSClient(gcpclient)
 
-	awsclient, err := awskms.NewAWSClient(awsURI)
-	if err != nil {
-		log.Fatal(err)
-	}
-	_, err = awsclient.LoadCredentials(awsCredFile)
-	if err != nil {
-		log.Fatal(err)
+	if _, err := os.Stat(awsCredFile); err == nil {
+		awsclient, err := awskms.NewAWSClient(awsURI)
+		if err != nil {
+			log.Fatal(err)
+		}
+		_, err = awsclient.LoadCredentials(awsCredFile)
+		if err != nil {
+			log.Fatal(err)
+		}
+		registry.RegisterKMSClient(awsclient)
 	}
-	registry.RegisterKMSClient(awsclient)
 }
 
 func main() {
@@ -31,6 +31,7 @@ import (
 	"github.com/tsingson/tink/golang/insecurecleartextkeyset"
 	"github.com/tsingson/tink/golang/integration/awskms"
 	"github.com/tsingson/tink/golang/integration/gcpkms"
+	"github.com/tsingson/tink/golang/integration/localkms"
 	"github.com/tsingson/tink/golang/keyset"
 
 	tinkpb "github.com/tsingson/tink/proto/tink_go_proto"
@@ -42,6 +43,8 @@ var (
 	gcpCredFile = os.Getenv("TEST_SRCDIR") + "/" + os.Getenv("TEST_WORKSPACE") + "/" + "testdata/credential.json"
 	awsURI      = "aws-kms://arn:aws:kms:us-east-2:235739564943:key/3ee50705-5a82-4f5b-9753-05c4f473922f"
 	awsCredFile = os.Getenv("TEST_SRCDIR") + "/" + os.Getenv("TEST_WORKSPACE") + "/" + "testdata/credentials_aws.csv"
+	// localURI is the URI of the master key for the LOCAL KMS, e.g. local-kms:///tmp/master.json.
+	localURI = os.Getenv("LOCAL_KMS_KEY_URI")
 )
 
 func init() {
@@ -99,8 +102,18 @@ func main() {
 		if err != nil {
 			log.Fatal(err)
 		}
+	case "LOCAL":
+		localclient, err := localkms.NewLocalClient(localURI)
+		if err != nil {
+			log.Fatalf("LOCAL_KMS_KEY_URI: %v", err)
+		}
+		registry.RegisterKMSClient(localclient)
+		kh, err = keyset.NewHandle(aead.KMSEnvelopeAEADKeyTemplate(localURI, dekT))
+		if err != nil {
+			log.Fatal(err)
+		}
 	default:
-		log.Fatalf("KMS %s, is not supported. Expecting AWS or GCP", kms)
+		log.Fatalf("KMS %s, is not supported. Expecting AWS, GCP or LOCAL", kms)
 	}
 	ks := insecurecleartextkeyset.KeysetMaterial(kh)
 	h, err := insecurecleartextkeyset.Read(&keyset.MemReaderWriter{Keyset: ks})
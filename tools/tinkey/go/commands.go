@@ -0,0 +1,248 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/tsingson/tink/golang/keyset"
+	tinkpb "github.com/tsingson/tink/proto/tink_go_proto"
+)
+
+// newFlagSet returns a flag set for the named command.
+func newFlagSet(name string) *flag.FlagSet {
+	return flag.NewFlagSet("tinkey "+name, flag.ContinueOnError)
+}
+
+// parse parses args and rejects positional arguments, which no command takes.
+func parse(fs *flag.FlagSet, args []string) error {
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 0 {
+		return fmt.Errorf("unexpected argument %q", fs.Arg(0))
+	}
+	return nil
+}
+
+func createKeyset(args []string, stdin io.Reader, stdout io.Writer) error {
+	var out outOptions
+	var masterKey masterKeyOptions
+	var templateName string
+	fs := newFlagSet("create-keyset")
+	out.register(fs)
+	masterKey.register(fs, "")
+	fs.StringVar(&templateName, "key-template", "", "the key template name, run list-key-templates to see the supported templates")
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+	kt, err := keyTemplate(templateName)
+	if err != nil {
+		return err
+	}
+	mk, err := masterKey.key()
+	if err != nil {
+		return err
+	}
+	h, err := keyset.NewHandle(kt)
+	if err != nil {
+		return err
+	}
+	return out.write(stdout, h, mk)
+}
+
+func addKey(args []string, stdin io.Reader, stdout io.Writer) error {
+	return updateWithTemplate("add-key", args, stdin, stdout, func(m *keyset.Manager, kt *tinkpb.KeyTemplate) error {
+		_, err := m.Add(kt)
+		return err
+	})
+}
+
+func rotateKeyset(args []string, stdin io.Reader, stdout io.Writer) error {
+	return updateWithTemplate("rotate-keyset", args, stdin, stdout, func(m *keyset.Manager, kt *tinkpb.KeyTemplate) error {
+		return m.Rotate(kt)
+	})
+}
+
+// updateWithTemplate reads a keyset, applies op with the template given by
+// --key-template and writes the result encrypted with the same master key.
+func updateWithTemplate(name string, args []string, stdin io.Reader, stdout io.Writer, op func(*keyset.Manager, *tinkpb.KeyTemplate) error) error {
+	var in inOptions
+	var out outOptions
+	var templateName string
+	fs := newFlagSet(name)
+	in.register(fs)
+	out.register(fs)
+	fs.StringVar(&templateName, "key-template", "", "the key template name, run list-key-templates to see the supported templates")
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+	kt, err := keyTemplate(templateName)
+	if err != nil {
+		return err
+	}
+	return update(&in, &out, stdin, stdout, func(m *keyset.Manager) error {
+		return op(m, kt)
+	})
+}
+
+func promoteKey(args []string, stdin io.Reader, stdout io.Writer) error {
+	return updateKey("promote-key", args, stdin, stdout, (*keyset.Manager).SetPrimary)
+}
+
+func enableKey(args []string, stdin io.Reader, stdout io.Writer) error {
+	return updateKey("enable-key", args, stdin, stdout, (*keyset.Manager).Enable)
+}
+
+func disableKey(args []string, stdin io.Reader, stdout io.Writer) error {
+	return updateKey("disable-key", args, stdin, stdout, (*keyset.Manager).Disable)
+}
+
+func destroyKey(args []string, stdin io.Reader, stdout io.Writer) error {
+	return updateKey("destroy-key", args, stdin, stdout, (*keyset.Manager).Destroy)
+}
+
+func deleteKey(args []string, stdin io.Reader, stdout io.Writer) error {
+	return updateKey("delete-key", args, stdin, stdout, (*keyset.Manager).Delete)
+}
+
+// updateKey reads a keyset, applies op to the key given by --key-id and writes
+// the result encrypted with the same master key.
+func updateKey(name string, args []string, stdin io.Reader, stdout io.Writer, op func(*keyset.Manager, uint32) error) error {
+	var in inOptions
+	var out outOptions
+	var keyID uint
+	fs := newFlagSet(name)
+	in.register(fs)
+	out.register(fs)
+	fs.UintVar(&keyID, "key-id", 0, "the target key id")
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+	hasKeyID := false
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name == "key-id" {
+			hasKeyID = true
+		}
+	})
+	if !hasKeyID {
+		return fmt.Errorf("--key-id is required")
+	}
+	if uint(uint32(keyID)) != keyID {
+		return fmt.Errorf("invalid key id %d", keyID)
+	}
+	return update(&in, &out, stdin, stdout, func(m *keyset.Manager) error {
+		return op(m, uint32(keyID))
+	})
+}
+
+func update(in *inOptions, out *outOptions, stdin io.Reader, stdout io.Writer, op func(*keyset.Manager) error) error {
+	mk, err := in.masterKey.key()
+	if err != nil {
+		return err
+	}
+	h, err := in.readWithMasterKey(stdin, mk)
+	if err != nil {
+		return err
+	}
+	m := keyset.NewManagerFromHandle(h)
+	if err := op(m); err != nil {
+		return err
+	}
+	h, err = m.Handle()
+	if err != nil {
+		return err
+	}
+	return out.write(stdout, h, mk)
+}
+
+func convertKeyset(args []string, stdin io.Reader, stdout io.Writer) error {
+	var in inOptions
+	var out outOptions
+	var newMasterKey masterKeyOptions
+	fs := newFlagSet("convert-keyset")
+	in.register(fs)
+	out.register(fs)
+	newMasterKey.register(fs, "new-")
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+	h, err := in.read(stdin)
+	if err != nil {
+		return err
+	}
+	mk, err := newMasterKey.key()
+	if err != nil {
+		return err
+	}
+	return out.write(stdout, h, mk)
+}
+
+func createPublicKeyset(args []string, stdin io.Reader, stdout io.Writer) error {
+	var in inOptions
+	var out outOptions
+	fs := newFlagSet("create-public-keyset")
+	in.register(fs)
+	out.register(fs)
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+	h, err := in.read(stdin)
+	if err != nil {
+		return err
+	}
+	pub, err := h.Public()
+	if err != nil {
+		return err
+	}
+	return out.writeWithNoSecrets(stdout, pub)
+}
+
+func listKeyset(args []string, stdin io.Reader, stdout io.Writer) error {
+	var in inOptions
+	fs := newFlagSet("list-keyset")
+	in.register(fs)
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+	h, err := in.read(stdin)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(stdout, h.String())
+	return err
+}
+
+func listKeyTemplates(args []string, stdin io.Reader, stdout io.Writer) error {
+	fs := newFlagSet("list-key-templates")
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+	names := make([]string, 0, len(keyTemplates))
+	for name := range keyTemplates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	fmt.Fprintln(stdout, "The following key templates are supported:")
+	for _, name := range names {
+		if _, err := fmt.Fprintln(stdout, name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,337 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/tsingson/tink/golang/aead"
+	"github.com/tsingson/tink/golang/core/registry"
+	"github.com/tsingson/tink/golang/insecurecleartextkeyset"
+	"github.com/tsingson/tink/golang/integration/awskms"
+	"github.com/tsingson/tink/golang/integration/gcpkms"
+	"github.com/tsingson/tink/golang/integration/localkms"
+	"github.com/tsingson/tink/golang/keyset"
+	"github.com/tsingson/tink/golang/tink"
+)
+
+const (
+	formatJSON   = "json"
+	formatBinary = "binary"
+
+	kdfScrypt   = "scrypt"
+	kdfArgon2id = "argon2id"
+
+	awsPrefix   = "aws-kms://"
+	gcpPrefix   = "gcp-kms://"
+	localPrefix = "local-kms://"
+)
+
+// masterKeyOptions selects the master key used to encrypt or decrypt a keyset:
+// either a master key in a KMS, a local cleartext AEAD keyset, or a key derived
+// from a password. If none is given, the keyset is read or written in cleartext.
+type masterKeyOptions struct {
+	keyURI       string
+	credential   string
+	keyset       string
+	keysetFormat string
+	passwordFile string
+	passwordKDF  string
+}
+
+// register adds the master key flags to fs. The prefix distinguishes the new
+// master key from the current one in convert-keyset.
+func (o *masterKeyOptions) register(fs *flag.FlagSet, prefix string) {
+	fs.StringVar(&o.keyURI, prefix+"master-key-uri", "",
+		"URI of the master key in a KMS, e.g. gcp-kms://projects/*/locations/*/keyRings/*/cryptoKeys/*, aws-kms://arn:aws:kms:<region>:<account-id>:key/<key-id> or local-kms://path/to/keyset.json")
+	fs.StringVar(&o.credential, prefix+"credential", "",
+		"credentials file for the KMS holding the master key; default credentials are used if missing")
+	fs.StringVar(&o.keyset, prefix+"master-keyset", "",
+		"path of a cleartext AEAD keyset used as the master key")
+	fs.StringVar(&o.keysetFormat, prefix+"master-keyset-format", formatJSON,
+		"format of the master keyset: json or binary")
+	fs.StringVar(&o.passwordFile, prefix+"password-file", "",
+		"file holding a password from which the master key is derived")
+	fs.StringVar(&o.passwordKDF, prefix+"password-kdf", kdfScrypt,
+		"function deriving the master key from the password when writing: scrypt or argon2id")
+}
+
+// masterKey encrypts and decrypts keysets.
+type masterKey interface {
+	read(r keyset.Reader) (*keyset.Handle, error)
+	write(h *keyset.Handle, w keyset.Writer) error
+}
+
+// aeadMasterKey is a master key in a KMS or in a local AEAD keyset.
+type aeadMasterKey struct {
+	aead tink.AEAD
+}
+
+func (k *aeadMasterKey) read(r keyset.Reader) (*keyset.Handle, error) {
+	return keyset.Read(r, k.aead)
+}
+
+func (k *aeadMasterKey) write(h *keyset.Handle, w keyset.Writer) error {
+	return h.Write(w, k.aead)
+}
+
+// passwordMasterKey is a master key derived from a password.
+type passwordMasterKey struct {
+	password []byte
+	params   *keyset.PasswordParams
+}
+
+func (k *passwordMasterKey) read(r keyset.Reader) (*keyset.Handle, error) {
+	return keyset.ReadWithPassword(r, k.password)
+}
+
+func (k *passwordMasterKey) write(h *keyset.Handle, w keyset.Writer) error {
+	return h.WriteWithPassword(w, k.password, k.params)
+}
+
+// key returns the master key, or nil if no master key was specified.
+func (o *masterKeyOptions) key() (masterKey, error) {
+	n := 0
+	for _, s := range []string{o.keyURI, o.keyset, o.passwordFile} {
+		if s != "" {
+			n++
+		}
+	}
+	if n > 1 {
+		return nil, fmt.Errorf("only one of a master key URI, a master keyset and a password file can be specified")
+	}
+	if o.passwordFile != "" {
+		return o.passwordKey()
+	}
+	a, err := o.aead()
+	if err != nil || a == nil {
+		return nil, err
+	}
+	return &aeadMasterKey{aead: a}, nil
+}
+
+// passwordKey returns the master key derived from the password in the password file.
+func (o *masterKeyOptions) passwordKey() (masterKey, error) {
+	var params *keyset.PasswordParams
+	switch strings.ToLower(o.passwordKDF) {
+	case kdfScrypt:
+		params = keyset.ScryptPasswordParams()
+	case kdfArgon2id:
+		params = keyset.Argon2idPasswordParams()
+	default:
+		return nil, fmt.Errorf("unknown password KDF %q, expecting %s or %s", o.passwordKDF, kdfScrypt, kdfArgon2id)
+	}
+	b, err := ioutil.ReadFile(o.passwordFile)
+	if err != nil {
+		return nil, err
+	}
+	password := bytes.TrimRight(b, "\r\n")
+	if len(password) == 0 {
+		return nil, fmt.Errorf("password file %s is empty", o.passwordFile)
+	}
+	return &passwordMasterKey{password: password, params: params}, nil
+}
+
+// aead returns the master key AEAD, or nil if no master key URI or keyset was
+// specified.
+func (o *masterKeyOptions) aead() (tink.AEAD, error) {
+	switch {
+	case o.keyURI != "":
+		return kmsAEAD(o.keyURI, o.credential)
+	case o.keyset != "":
+		b, err := ioutil.ReadFile(o.keyset)
+		if err != nil {
+			return nil, err
+		}
+		r, err := newReader(bytes.NewReader(b), o.keysetFormat)
+		if err != nil {
+			return nil, err
+		}
+		h, err := insecurecleartextkeyset.Read(r)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read master keyset: %v", err)
+		}
+		return aead.New(h)
+	}
+	return nil, nil
+}
+
+// kmsAEAD returns the AEAD for keyURI. Clients for AWS KMS, Google Cloud KMS and
+// local master keysets are created on demand; other KMSes must have a client
+// registered with the registry.
+func kmsAEAD(keyURI, credential string) (tink.AEAD, error) {
+	switch {
+	case strings.HasPrefix(strings.ToLower(keyURI), awsPrefix):
+		c, err := awskms.NewAWSClient(keyURI)
+		if err != nil {
+			return nil, err
+		}
+		if credential != "" {
+			_, err = c.LoadCredentials(credential)
+		} else {
+			_, err = c.LoadDefaultCredentials()
+		}
+		if err != nil {
+			return nil, err
+		}
+		registry.RegisterKMSClient(c)
+	case strings.HasPrefix(strings.ToLower(keyURI), gcpPrefix):
+		c, err := gcpkms.NewGCPClient(keyURI)
+		if err != nil {
+			return nil, err
+		}
+		if credential != "" {
+			_, err = c.LoadCredentials(credential)
+		} else {
+			_, err = c.LoadDefaultCredentials()
+		}
+		if err != nil {
+			return nil, err
+		}
+		registry.RegisterKMSClient(c)
+	case strings.HasPrefix(strings.ToLower(keyURI), localPrefix):
+		c, err := localkms.NewLocalClient(keyURI)
+		if err != nil {
+			return nil, err
+		}
+		registry.RegisterKMSClient(c)
+	}
+	c, err := registry.GetKMSClient(keyURI)
+	if err != nil {
+		return nil, err
+	}
+	return c.GetAEAD(keyURI)
+}
+
+// inOptions describes where and how the input keyset is read.
+type inOptions struct {
+	in        string
+	inFormat  string
+	masterKey masterKeyOptions
+}
+
+func (o *inOptions) register(fs *flag.FlagSet) {
+	fs.StringVar(&o.in, "in", "", "the input filename to read the keyset from, or standard input if not specified")
+	fs.StringVar(&o.inFormat, "in-format", formatJSON, "the input format: json or binary")
+	o.masterKey.register(fs, "")
+}
+
+// read reads the input keyset, decrypting it if a master key was specified.
+func (o *inOptions) read(stdin io.Reader) (*keyset.Handle, error) {
+	masterKey, err := o.masterKey.key()
+	if err != nil {
+		return nil, err
+	}
+	return o.readWithMasterKey(stdin, masterKey)
+}
+
+func (o *inOptions) readWithMasterKey(stdin io.Reader, masterKey masterKey) (*keyset.Handle, error) {
+	var b []byte
+	var err error
+	if o.in == "" {
+		b, err = ioutil.ReadAll(stdin)
+	} else {
+		b, err = ioutil.ReadFile(o.in)
+	}
+	if err != nil {
+		return nil, err
+	}
+	r, err := newReader(bytes.NewReader(b), o.inFormat)
+	if err != nil {
+		return nil, err
+	}
+	if masterKey == nil {
+		return insecurecleartextkeyset.Read(r)
+	}
+	return masterKey.read(r)
+}
+
+// outOptions describes where and how the output keyset is written.
+type outOptions struct {
+	out       string
+	outFormat string
+}
+
+func (o *outOptions) register(fs *flag.FlagSet) {
+	fs.StringVar(&o.out, "out", "", "the output filename to write the keyset to, or standard output if not specified")
+	fs.StringVar(&o.outFormat, "out-format", formatJSON, "the output format: json or binary")
+}
+
+// write writes h, encrypted with masterKey if it is not nil.
+func (o *outOptions) write(stdout io.Writer, h *keyset.Handle, masterKey masterKey) error {
+	var b bytes.Buffer
+	w, err := newWriter(&b, o.outFormat)
+	if err != nil {
+		return err
+	}
+	if masterKey == nil {
+		err = insecurecleartextkeyset.Write(h, w)
+	} else {
+		err = masterKey.write(h, w)
+	}
+	if err != nil {
+		return err
+	}
+	return o.flush(stdout, b.Bytes())
+}
+
+// writeWithNoSecrets writes h, which must not contain secret key material.
+func (o *outOptions) writeWithNoSecrets(stdout io.Writer, h *keyset.Handle) error {
+	var b bytes.Buffer
+	w, err := newWriter(&b, o.outFormat)
+	if err != nil {
+		return err
+	}
+	if err := h.WriteWithNoSecrets(w); err != nil {
+		return err
+	}
+	return o.flush(stdout, b.Bytes())
+}
+
+// flush writes the serialized keyset only once it is complete, so that --in
+// and --out can name the same file.
+func (o *outOptions) flush(stdout io.Writer, b []byte) error {
+	if o.out == "" {
+		_, err := stdout.Write(b)
+		return err
+	}
+	return ioutil.WriteFile(o.out, b, os.FileMode(0600))
+}
+
+func newReader(r io.Reader, format string) (keyset.Reader, error) {
+	switch strings.ToLower(format) {
+	case formatJSON:
+		return keyset.NewJSONReader(r), nil
+	case formatBinary:
+		return keyset.NewBinaryReader(r), nil
+	}
+	return nil, fmt.Errorf("unknown format %q, expected json or binary", format)
+}
+
+func newWriter(w io.Writer, format string) (keyset.Writer, error) {
+	switch strings.ToLower(format) {
+	case formatJSON:
+		return keyset.NewJSONWriter(w), nil
+	case formatBinary:
+		return keyset.NewBinaryWriter(w), nil
+	}
+	return nil, fmt.Errorf("unknown format %q, expected json or binary", format)
+}
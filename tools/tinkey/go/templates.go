@@ -0,0 +1,83 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/tsingson/tink/golang/aead"
+	"github.com/tsingson/tink/golang/daead"
+	"github.com/tsingson/tink/golang/hybrid"
+	"github.com/tsingson/tink/golang/keywrap"
+	"github.com/tsingson/tink/golang/mac"
+	"github.com/tsingson/tink/golang/signature"
+	"github.com/tsingson/tink/golang/streamingaead"
+	tinkpb "github.com/tsingson/tink/proto/tink_go_proto"
+)
+
+// keyTemplates maps the template names accepted by --key-template to the
+// corresponding key template constructors.
+var keyTemplates = map[string]func() *tinkpb.KeyTemplate{
+	"AES128_GCM":                             aead.AES128GCMKeyTemplate,
+	"AES256_GCM":                             aead.AES256GCMKeyTemplate,
+	"AES128_GCM_SIV":                         aead.AES128GCMSIVKeyTemplate,
+	"AES256_GCM_SIV":                         aead.AES256GCMSIVKeyTemplate,
+	"AES128_EAX":                             aead.AES128EAXKeyTemplate,
+	"AES256_EAX":                             aead.AES256EAXKeyTemplate,
+	"AES128_CTR_HMAC_SHA256":                 aead.AES128CTRHMACSHA256KeyTemplate,
+	"AES256_CTR_HMAC_SHA256":                 aead.AES256CTRHMACSHA256KeyTemplate,
+	"CHACHA20_POLY1305":                      aead.ChaCha20Poly1305KeyTemplate,
+	"XCHACHA20_POLY1305":                     aead.XChaCha20Poly1305KeyTemplate,
+	"AES256_SIV":                             daead.AESSIVKeyTemplate,
+	"ECIES_P256_HKDF_HMAC_SHA256_AES128_GCM": hybrid.ECIESHKDFAES128GCMKeyTemplate,
+	"ECIES_P256_HKDF_HMAC_SHA256_AES128_CTR_HMAC_SHA256": hybrid.ECIESHKDFAES128CTRHMACSHA256KeyTemplate,
+	"AES128_KWP":                   keywrap.AES128KWPKeyTemplate,
+	"AES256_KWP":                   keywrap.AES256KWPKeyTemplate,
+	"AES128_KWP_RAW":               keywrap.AES128KWPRawKeyTemplate,
+	"AES256_KWP_RAW":               keywrap.AES256KWPRawKeyTemplate,
+	"HMAC_SHA256_128BITTAG":        mac.HMACSHA256Tag128KeyTemplate,
+	"HMAC_SHA256_256BITTAG":        mac.HMACSHA256Tag256KeyTemplate,
+	"HMAC_SHA512_256BITTAG":        mac.HMACSHA512Tag256KeyTemplate,
+	"HMAC_SHA512_512BITTAG":        mac.HMACSHA512Tag512KeyTemplate,
+	"AES_CMAC":                     mac.AESCMACTag128KeyTemplate,
+	"ECDSA_P256":                   signature.ECDSAP256KeyTemplate,
+	"ECDSA_P384":                   signature.ECDSAP384KeyTemplate,
+	"ECDSA_P521":                   signature.ECDSAP521KeyTemplate,
+	"ED25519":                      signature.ED25519KeyTemplate,
+	"RSA_SSA_PKCS1_3072_SHA256_F4": signature.RSASSAPKCS13072SHA256F4KeyTemplate,
+	"RSA_SSA_PKCS1_4096_SHA512_F4": signature.RSASSAPKCS14096SHA512F4KeyTemplate,
+	"RSA_SSA_PKCS1_2048_SHA256_F4": signature.RSASSAPKCS12048SHA256F4KeyTemplate,
+	"RSA_SSA_PSS_2048_SHA256_F4":   signature.RSASSAPSS2048SHA256F4KeyTemplate,
+	"RSA_SSA_PSS_3072_SHA256_F4":   signature.RSASSAPSS3072SHA256F4KeyTemplate,
+	"RSA_SSA_PSS_4096_SHA512_F4":   signature.RSASSAPSS4096SHA512F4KeyTemplate,
+	"AES128_GCM_HKDF_4KB":          streamingaead.AES128GCMHKDF4KBKeyTemplate,
+	"AES256_GCM_HKDF_4KB":          streamingaead.AES256GCMHKDF4KBKeyTemplate,
+	"AES256_GCM_HKDF_1MB":          streamingaead.AES256GCMHKDF1MBKeyTemplate,
+	"AES128_CTR_HMAC_SHA256_4KB":   streamingaead.AES128CTRHMACSHA256Segment4KBKeyTemplate,
+	"AES256_CTR_HMAC_SHA256_4KB":   streamingaead.AES256CTRHMACSHA256Segment4KBKeyTemplate,
+	"AES256_CTR_HMAC_SHA256_1MB":   streamingaead.AES256CTRHMACSHA256Segment1MBKeyTemplate,
+}
+
+// keyTemplate returns the key template with the given name.
+func keyTemplate(name string) (*tinkpb.KeyTemplate, error) {
+	if name == "" {
+		return nil, fmt.Errorf("--key-template is required")
+	}
+	f, ok := keyTemplates[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown key template %q, run list-key-templates to see the supported templates", name)
+	}
+	return f(), nil
+}
@@ -0,0 +1,92 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+// Tinkey is a command-line utility for generating and manipulating Tink keysets.
+//
+// Usage:
+//
+//	tinkey <command> [<args>]
+//
+// Keysets are read from --in (or standard input) and written to --out (or
+// standard output), in either json (the default) or binary format. They can be
+// encrypted with a master key residing in a remote KMS (--master-key-uri), with
+// a local cleartext AEAD keyset (--master-keyset) or with a key derived from a
+// password (--password-file). Run a command with -h to list its options.
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+)
+
+// command is a Tinkey subcommand. It parses its own arguments and reports any
+// failure as an error.
+type command struct {
+	usage string
+	run   func(args []string, stdin io.Reader, stdout io.Writer) error
+}
+
+var commands = map[string]command{
+	"add-key":              {"Generates and adds a new key to a keyset.", addKey},
+	"convert-keyset":       {"Changes format, encrypts, decrypts a keyset.", convertKeyset},
+	"create-keyset":        {"Creates a new keyset.", createKeyset},
+	"create-public-keyset": {"Creates a public keyset from a private keyset.", createPublicKeyset},
+	"delete-key":           {"Deletes a specified key in a keyset.", deleteKey},
+	"destroy-key":          {"Destroys the key material of a specified key in a keyset.", destroyKey},
+	"disable-key":          {"Disables a specified key in a keyset.", disableKey},
+	"enable-key":           {"Enables a specified key in a keyset.", enableKey},
+	"list-key-templates":   {"Lists all supported key templates.", listKeyTemplates},
+	"list-keyset":          {"Lists keys in a keyset.", listKeyset},
+	"promote-key":          {"Promotes a specified key to primary.", promoteKey},
+	"rotate-keyset":        {"Performs a key rotation in a keyset.", rotateKeyset},
+}
+
+var errUsage = errors.New("usage: tinkey <command> [<args>]")
+
+func main() {
+	if err := run(os.Args[1:], os.Stdin, os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "tinkey: %v\n", err)
+		if err == errUsage {
+			printCommands(os.Stderr)
+		}
+		os.Exit(1)
+	}
+}
+
+// run executes the command named by args[0] with the remaining arguments.
+func run(args []string, stdin io.Reader, stdout io.Writer) error {
+	if len(args) == 0 {
+		return errUsage
+	}
+	cmd, ok := commands[args[0]]
+	if !ok {
+		return errUsage
+	}
+	return cmd.run(args[1:], stdin, stdout)
+}
+
+func printCommands(w io.Writer) {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	fmt.Fprintln(w, "Available commands:")
+	for _, name := range names {
+		fmt.Fprintf(w, "  %-22s %s\n", name, commands[name].usage)
+	}
+}
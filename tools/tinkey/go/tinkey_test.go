@@ -0,0 +1,307 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tsingson/tink/golang/aead"
+	"github.com/tsingson/tink/golang/core/registry"
+	"github.com/tsingson/tink/golang/insecurecleartextkeyset"
+	"github.com/tsingson/tink/golang/keyset"
+	"github.com/tsingson/tink/golang/signature"
+	"github.com/tsingson/tink/golang/tink"
+	tinkpb "github.com/tsingson/tink/proto/tink_go_proto"
+)
+
+const fakeKeyURI = "fake-kms://master-key"
+
+// fakeKMSClient serves a single AES-GCM master key for fakeKeyURI.
+type fakeKMSClient struct {
+	a tink.AEAD
+}
+
+func (c *fakeKMSClient) Supported(keyURI string) bool {
+	return keyURI == fakeKeyURI
+}
+
+func (c *fakeKMSClient) GetAEAD(keyURI string) (tink.AEAD, error) {
+	if !c.Supported(keyURI) {
+		return nil, fmt.Errorf("unsupported key URI %s", keyURI)
+	}
+	return c.a, nil
+}
+
+func init() {
+	h, err := keyset.NewHandle(aead.AES128GCMKeyTemplate())
+	if err != nil {
+		panic(err)
+	}
+	a, err := aead.New(h)
+	if err != nil {
+		panic(err)
+	}
+	registry.RegisterKMSClient(&fakeKMSClient{a})
+}
+
+func tempDir(t *testing.T) string {
+	t.Helper()
+	dir, err := ioutil.TempDir("", "tinkey")
+	if err != nil {
+		t.Fatalf("ioutil.TempDir() failed: %v", err)
+	}
+	return dir
+}
+
+func runTinkey(t *testing.T, args ...string) string {
+	t.Helper()
+	var stdout bytes.Buffer
+	if err := run(args, strings.NewReader(""), &stdout); err != nil {
+		t.Fatalf("tinkey %s failed: %v", strings.Join(args, " "), err)
+	}
+	return stdout.String()
+}
+
+func readCleartext(t *testing.T, path, format string) *tinkpb.Keyset {
+	t.Helper()
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ioutil.ReadFile() failed: %v", err)
+	}
+	r, err := newReader(bytes.NewReader(b), format)
+	if err != nil {
+		t.Fatalf("newReader() failed: %v", err)
+	}
+	h, err := insecurecleartextkeyset.Read(r)
+	if err != nil {
+		t.Fatalf("insecurecleartextkeyset.Read() failed: %v", err)
+	}
+	return insecurecleartextkeyset.KeysetMaterial(h)
+}
+
+func TestManageKeys(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "keyset.json")
+
+	runTinkey(t, "create-keyset", "--key-template", "AES128_GCM", "--out", path)
+	ks := readCleartext(t, path, formatJSON)
+	if len(ks.Key) != 1 || ks.Key[0].KeyData.TypeUrl != "type.googleapis.com/google.crypto.tink.AesGcmKey" {
+		t.Fatalf("unexpected keyset: %v", ks)
+	}
+	first := ks.PrimaryKeyId
+
+	runTinkey(t, "add-key", "--key-template", "AES256_GCM", "--in", path, "--out", path)
+	ks = readCleartext(t, path, formatJSON)
+	if len(ks.Key) != 2 || ks.PrimaryKeyId != first {
+		t.Fatalf("add-key must add a non-primary key: %v", ks)
+	}
+	second := ks.Key[1].KeyId
+
+	runTinkey(t, "rotate-keyset", "--key-template", "CHACHA20_POLY1305", "--in", path, "--out", path)
+	ks = readCleartext(t, path, formatJSON)
+	if len(ks.Key) != 3 || ks.PrimaryKeyId != ks.Key[2].KeyId {
+		t.Fatalf("rotate-keyset must add a primary key: %v", ks)
+	}
+
+	id := fmt.Sprint(second)
+	runTinkey(t, "promote-key", "--key-id", id, "--in", path, "--out", path)
+	if ks = readCleartext(t, path, formatJSON); ks.PrimaryKeyId != second {
+		t.Errorf("promote-key: primary is %d, want %d", ks.PrimaryKeyId, second)
+	}
+
+	id = fmt.Sprint(first)
+	runTinkey(t, "disable-key", "--key-id", id, "--in", path, "--out", path)
+	if ks = readCleartext(t, path, formatJSON); ks.Key[0].Status != tinkpb.KeyStatusType_DISABLED {
+		t.Errorf("disable-key: status is %v", ks.Key[0].Status)
+	}
+	runTinkey(t, "enable-key", "--key-id", id, "--in", path, "--out", path)
+	if ks = readCleartext(t, path, formatJSON); ks.Key[0].Status != tinkpb.KeyStatusType_ENABLED {
+		t.Errorf("enable-key: status is %v", ks.Key[0].Status)
+	}
+	runTinkey(t, "destroy-key", "--key-id", id, "--in", path, "--out", path)
+	if ks = readCleartext(t, path, formatJSON); ks.Key[0].Status != tinkpb.KeyStatusType_DESTROYED || ks.Key[0].KeyData != nil {
+		t.Errorf("destroy-key: key material was not destroyed: %v", ks.Key[0])
+	}
+	runTinkey(t, "delete-key", "--key-id", id, "--in", path, "--out", path)
+	if ks = readCleartext(t, path, formatJSON); len(ks.Key) != 2 || ks.Key[0].KeyId == first {
+		t.Errorf("delete-key: key was not deleted: %v", ks)
+	}
+
+	out := runTinkey(t, "list-keyset", "--in", path)
+	if !strings.Contains(out, fmt.Sprint(second)) || strings.Contains(out, "key_value") {
+		t.Errorf("unexpected list-keyset output: %s", out)
+	}
+}
+
+func TestConvertKeyset(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	jsonPath := filepath.Join(dir, "keyset.json")
+	binaryPath := filepath.Join(dir, "keyset.bin")
+	encryptedPath := filepath.Join(dir, "keyset.enc")
+	masterPath := filepath.Join(dir, "master.json")
+	decryptedPath := filepath.Join(dir, "decrypted.json")
+
+	runTinkey(t, "create-keyset", "--key-template", "HMAC_SHA256_128BITTAG", "--out", jsonPath)
+	runTinkey(t, "convert-keyset", "--in", jsonPath, "--out", binaryPath, "--out-format", "binary")
+	want := readCleartext(t, jsonPath, formatJSON)
+	if got := readCleartext(t, binaryPath, formatBinary); got.String() != want.String() {
+		t.Errorf("json to binary conversion changed the keyset: got %v, want %v", got, want)
+	}
+
+	// encrypt with a local master keyset, then decrypt it again
+	runTinkey(t, "create-keyset", "--key-template", "AES256_GCM", "--out", masterPath)
+	runTinkey(t, "convert-keyset", "--in", binaryPath, "--in-format", "binary", "--out", encryptedPath, "--new-master-keyset", masterPath)
+	if _, err := insecurecleartextkeyset.Read(keyset.NewJSONReader(mustOpen(t, encryptedPath))); err == nil {
+		t.Errorf("convert-keyset with --new-master-keyset must encrypt the keyset")
+	}
+	runTinkey(t, "convert-keyset", "--in", encryptedPath, "--master-keyset", masterPath, "--out", decryptedPath)
+	if got := readCleartext(t, decryptedPath, formatJSON); got.String() != want.String() {
+		t.Errorf("decrypted keyset differs: got %v, want %v", got, want)
+	}
+}
+
+func TestMasterKeyURI(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "keyset.enc")
+
+	runTinkey(t, "create-keyset", "--key-template", "ECDSA_P256", "--out", path, "--master-key-uri", fakeKeyURI)
+	runTinkey(t, "add-key", "--key-template", "ED25519", "--in", path, "--out", path, "--master-key-uri", fakeKeyURI)
+	if err := run([]string{"list-keyset", "--in", path}, nil, ioutil.Discard); err == nil {
+		t.Errorf("reading an encrypted keyset without its master key must fail")
+	}
+	out := runTinkey(t, "list-keyset", "--in", path, "--master-key-uri", fakeKeyURI)
+	if strings.Count(out, " key_id:") != 2 {
+		t.Errorf("unexpected list-keyset output: %s", out)
+	}
+
+	// the public keyset is written in cleartext and can be used for verification
+	pub := runTinkey(t, "create-public-keyset", "--in", path, "--master-key-uri", fakeKeyURI)
+	h, err := keyset.ReadWithNoSecrets(keyset.NewJSONReader(strings.NewReader(pub)))
+	if err != nil {
+		t.Fatalf("keyset.ReadWithNoSecrets() failed: %v", err)
+	}
+	if _, err := signature.NewVerifier(h); err != nil {
+		t.Errorf("signature.NewVerifier() failed: %v", err)
+	}
+}
+
+func TestLocalMasterKeyURI(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	masterPath := filepath.Join(dir, "master.json")
+	path := filepath.Join(dir, "keyset.enc")
+	keyURI := "local-kms://" + masterPath
+
+	runTinkey(t, "create-keyset", "--key-template", "AES256_GCM", "--out", masterPath)
+	runTinkey(t, "create-keyset", "--key-template", "AES128_GCM", "--out", path, "--master-key-uri", keyURI)
+	if err := run([]string{"list-keyset", "--in", path}, nil, ioutil.Discard); err == nil {
+		t.Errorf("reading an encrypted keyset without its master key must fail")
+	}
+	out := runTinkey(t, "list-keyset", "--in", path, "--master-key-uri", keyURI)
+	if strings.Count(out, " key_id:") != 1 {
+		t.Errorf("unexpected list-keyset output: %s", out)
+	}
+}
+
+func TestPasswordFile(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "keyset.enc")
+	passwordPath := filepath.Join(dir, "password")
+	newPasswordPath := filepath.Join(dir, "new-password")
+	if err := ioutil.WriteFile(passwordPath, []byte("password\n"), 0600); err != nil {
+		t.Fatalf("ioutil.WriteFile() failed: %v", err)
+	}
+	if err := ioutil.WriteFile(newPasswordPath, []byte("new password"), 0600); err != nil {
+		t.Fatalf("ioutil.WriteFile() failed: %v", err)
+	}
+
+	runTinkey(t, "create-keyset", "--key-template", "AES128_GCM", "--out", path, "--password-file", passwordPath)
+	if err := run([]string{"list-keyset", "--in", path}, nil, ioutil.Discard); err == nil {
+		t.Errorf("reading a password-encrypted keyset without the password must fail")
+	}
+	out := runTinkey(t, "list-keyset", "--in", path, "--password-file", passwordPath)
+	if strings.Count(out, " key_id:") != 1 {
+		t.Errorf("unexpected list-keyset output: %s", out)
+	}
+
+	// change the password and the KDF
+	runTinkey(t, "convert-keyset", "--in", path, "--out", path, "--password-file", passwordPath,
+		"--new-password-file", newPasswordPath, "--new-password-kdf", "argon2id")
+	if err := run([]string{"list-keyset", "--in", path, "--password-file", passwordPath}, nil, ioutil.Discard); err == nil {
+		t.Errorf("reading a keyset with its old password must fail")
+	}
+	runTinkey(t, "list-keyset", "--in", path, "--password-file", newPasswordPath)
+}
+
+func TestInvalidArguments(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "keyset.json")
+	runTinkey(t, "create-keyset", "--key-template", "AES128_GCM", "--out", path)
+
+	testCases := [][]string{
+		{},
+		{"unknown-command"},
+		{"create-keyset"},
+		{"create-keyset", "--key-template", "UNKNOWN"},
+		{"create-keyset", "--key-template", "AES128_GCM", "--out-format", "xml"},
+		{"create-keyset", "--key-template", "AES128_GCM", "extra"},
+		{"create-keyset", "--key-template", "AES128_GCM", "--master-key-uri", "unknown-kms://key"},
+		{"create-keyset", "--key-template", "AES128_GCM", "--master-key-uri", fakeKeyURI, "--master-keyset", path},
+		{"create-keyset", "--key-template", "AES128_GCM", "--master-keyset", path, "--password-file", path},
+		{"create-keyset", "--key-template", "AES128_GCM", "--password-file", path, "--password-kdf", "pbkdf2"},
+		{"create-keyset", "--key-template", "AES128_GCM", "--password-file", filepath.Join(dir, "missing")},
+		{"promote-key", "--in", path},
+		{"promote-key", "--in", path, "--key-id", "42"},
+		{"list-keyset", "--in", filepath.Join(dir, "missing.json")},
+		{"list-keyset", "--in", path, "--in-format", "binary"},
+	}
+	for _, args := range testCases {
+		if err := run(args, strings.NewReader(""), ioutil.Discard); err == nil {
+			t.Errorf("tinkey %s: expected an error", strings.Join(args, " "))
+		}
+	}
+}
+
+func TestListKeyTemplates(t *testing.T) {
+	out := runTinkey(t, "list-key-templates")
+	for name := range keyTemplates {
+		if !strings.Contains(out, name+"\n") {
+			t.Errorf("template %s is not listed", name)
+		}
+		if _, err := keyset.NewHandle(keyTemplates[name]()); err != nil {
+			t.Errorf("template %s: keyset.NewHandle() failed: %v", name, err)
+		}
+	}
+}
+
+func mustOpen(t *testing.T, path string) *os.File {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("os.Open() failed: %v", err)
+	}
+	return f
+}